@@ -0,0 +1,5 @@
+//go:build mysql || drivers
+
+package main
+
+import _ "github.com/go-sql-driver/mysql" // registers "mysql"
@@ -0,0 +1,5 @@
+//go:build postgres || drivers
+
+package main
+
+import _ "github.com/lib/pq" // registers "postgres"
@@ -0,0 +1,5 @@
+//go:build sqlite || drivers
+
+package main
+
+import _ "github.com/mattn/go-sqlite3" // registers "sqlite3", needs cgo
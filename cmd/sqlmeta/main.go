@@ -0,0 +1,24 @@
+// Command sqlmeta dumps, diffs and applies database schemas; see package
+// github.com/genelet/sqlmeta/xmeta/cmd. The database/sql drivers are linked
+// by build tag, postgres, mysql and sqlite, or drivers for all three:
+//
+//	go build -tags drivers ./cmd/sqlmeta
+//
+// Without a tag the binary diffs schema files and dumps BigQuery projects
+// only.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/genelet/sqlmeta/xmeta/cmd"
+)
+
+func main() {
+	if err := cmd.Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "sqlmeta:", err)
+		os.Exit(1)
+	}
+}
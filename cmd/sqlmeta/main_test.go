@@ -0,0 +1,79 @@
+//go:build sqlite || drivers
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/genelet/sqlmeta/xmeta"
+	"github.com/genelet/sqlmeta/xmeta/cmd"
+)
+
+func TestApplyDumpSQLite(t *testing.T) {
+	dir := t.TempDir()
+	dsn := filepath.Join(dir, "app.db")
+	ctx := context.Background()
+	run := func(args ...string) string {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if err := cmd.Run(ctx, args, &stdout, &stderr); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, stderr.String())
+		}
+		return stdout.String()
+	}
+	columns := func(path string) []string {
+		t.Helper()
+		db, err := xmeta.LoadMetaDatabaseFromFile(path)
+		if err != nil {
+			t.Fatalf("loading %s: %v", path, err)
+		}
+		if len(db.Tables) != 1 || !slices.Equal(db.Tables[0].GetName().GetIdents(), []string{"users"}) {
+			t.Fatalf("Expected the users table, got %v", db.Tables)
+		}
+		var names []string
+		for _, elem := range db.Tables[0].Elements {
+			if col := elem.GetColumnDefElement(); col != nil {
+				names = append(names, col.Name)
+			}
+		}
+		return names
+	}
+
+	desired := filepath.Join(dir, "desired.sql")
+	if err := os.WriteFile(desired, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE);\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("apply", "-dialect", "sqlite", "-dsn", dsn, "-from", desired)
+
+	// Applying the same schema again is a no-op
+	if got := run("apply", "-dialect", "sqlite", "-dsn", dsn, "-from", desired); got != "no changes\n" {
+		t.Errorf("Expected no changes on the second apply, got %q", got)
+	}
+
+	dump := filepath.Join(dir, "dump.json")
+	run("dump", "-dialect", "sqlite", "-dsn", dsn, "-o", dump)
+	if got, want := columns(dump), []string{"id", "email"}; !slices.Equal(got, want) {
+		t.Errorf("Expected columns %v, got %v", want, got)
+	}
+	if got := run("diff", dump, desired); got != "no changes\n" {
+		t.Errorf("Expected no changes between the dump and the schema, got %q", got)
+	}
+
+	// A new column is added to the live table
+	if err := os.WriteFile(desired, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, name TEXT);\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("apply", "-dialect", "sqlite", "-dsn", dsn, "-from", desired)
+	run("dump", "-dialect", "sqlite", "-dsn", dsn, "-o", dump)
+	if got, want := columns(dump), []string{"id", "email", "name"}; !slices.Equal(got, want) {
+		t.Errorf("Expected columns %v, got %v", want, got)
+	}
+	if got := run("diff", dump, desired); got != "no changes\n" {
+		t.Errorf("Expected no changes between the dump and the schema, got %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package xmeta
+
+// apply.go executes schema changes against a live database.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ApplyOptions controls how ApplyChanges executes a migration.
+type ApplyOptions struct {
+	// DryRun prints the generated SQL instead of executing it.
+	DryRun bool
+	// AllowDestructive permits changes whose IsDestructive() is true.
+	AllowDestructive bool
+	// Output receives the SQL in dry-run mode. Defaults to os.Stdout.
+	Output io.Writer
+	// Priorities overrides the default execution order of change types.
+	Priorities PriorityPolicy
+	// DDL controls how the statements are generated.
+	DDL DDLOptions
+	// DeferForeignKeys adds the foreign keys of new tables that reference
+	// each other after creating the tables; see DeferForeignKeys.
+	DeferForeignKeys bool
+}
+
+// ApplyChanges renders the changes to SQL for the dialect and executes them
+// inside a single transaction in priority order. The transaction is rolled
+// back on the first error, which undoes the whole migration on Postgres and
+// SQLite only: MySQL commits each DDL statement implicitly, so the
+// statements before the failing one stay applied. Destructive changes are
+// refused unless opts.AllowDestructive is set; in that case nothing is
+// executed. In dry-run mode opts.DDL.CommentOutDestructive prints them as
+// comments instead; it is ignored when executing, so that no change is
+// silently skipped.
+func ApplyChanges(ctx context.Context, db *sql.DB, dialect Dialect, changes []SchemaChange, opts ApplyOptions) error {
+	ordered := make([]SchemaChange, len(changes))
+	copy(ordered, changes)
+	SortChangesWithPolicy(ordered, opts.Priorities)
+	if opts.DeferForeignKeys {
+		ordered = DeferForeignKeys(ordered)
+	}
+
+	ddl := opts.DDL
+	if !opts.DryRun {
+		ddl.CommentOutDestructive = false
+	}
+	if !opts.AllowDestructive && !ddl.CommentOutDestructive {
+		for _, change := range ordered {
+			if change.IsDestructive() {
+				return fmt.Errorf("refusing destructive change %T without AllowDestructive", change)
+			}
+		}
+	}
+
+	// Render everything up front so a generation error aborts before touching the database
+	var stmts []string
+	for _, change := range ordered {
+		sqls, err := GenerateSQLWithOptions(change, dialect, ddl)
+		if err != nil {
+			return fmt.Errorf("generating SQL for %T: %w", change, err)
+		}
+		stmts = append(stmts, sqls...)
+	}
+
+	if opts.DryRun {
+		out := opts.Output
+		if out == nil {
+			out = os.Stdout
+		}
+		for _, stmt := range stmts {
+			if _, err := fmt.Fprintf(out, "%s;\n", stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("executing %q: %w", stmt, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
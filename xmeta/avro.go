@@ -0,0 +1,153 @@
+package xmeta
+
+// avro.go exports MetaTables as Avro record schemas.
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var avroNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+type avroRecord struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Doc       string      `json:"doc,omitempty"`
+	Fields    []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    any             `json:"type"`
+	Doc     string          `json:"doc,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// MetaTableToAvro returns an Avro record schema for t, named after the
+// table with its schema or dataset as namespace. Each column becomes a
+// field: integers map to long, floating point to double, text to string,
+// bytes to bytes, STRUCT to a nested record and ARRAY to array. NUMERIC,
+// TIMESTAMP, DATE and TIME use the matching Avro logical types. Nullable
+// columns become ["null", T] unions defaulting to null; arrays, which
+// BigQuery never stores as NULL, are not wrapped.
+func MetaTableToAvro(t *MetaTable) ([]byte, error) {
+	if t == nil || len(t.GetName().GetIdents()) == 0 {
+		return nil, fmt.Errorf("table without name")
+	}
+	idents := t.Name.Idents
+	name := avroName(idents[len(idents)-1])
+
+	var namespace []string
+	for _, ident := range idents[:len(idents)-1] {
+		namespace = append(namespace, avroName(ident))
+	}
+
+	record, err := avroRecordFor(name, t.Comment, orderedColumns(t.Elements))
+	if err != nil {
+		return nil, fmt.Errorf("table %s: %w", formatObjectName(t.Name), err)
+	}
+	record.Namespace = strings.Join(namespace, ".")
+	return json.MarshalIndent(record, "", "  ")
+}
+
+// avroRecordFor builds a record from columns. Nested records are named
+// after their parent and field so that names stay unique in the schema.
+func avroRecordFor(name, doc string, cols []*ColumnDef) (*avroRecord, error) {
+	record := &avroRecord{Type: "record", Name: name, Doc: doc, Fields: []avroField{}}
+	for _, col := range cols {
+		field := avroField{Name: avroName(col.Name), Doc: col.Comment}
+		typ, err := avroType(col.DataType, name+"_"+field.Name)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		if !isNotNull(col) && col.GetDataType().GetArrayData() == nil {
+			typ = []any{"null", typ}
+			field.Default = json.RawMessage("null")
+		}
+		field.Type = typ
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+// avroType maps a DataType to an Avro type. recordName names the record
+// generated for a STRUCT.
+func avroType(dt *DataType, recordName string) (any, error) {
+	switch dt.GetTypeClause().(type) {
+	case *DataType_IntData, *DataType_SmallIntData, *DataType_BigIntData, *DataType_TinyIntData, *DataType_MediumIntData:
+		return "long", nil
+	case *DataType_FloatData, *DataType_DoubleData, *DataType_RealData:
+		return "double", nil
+	case *DataType_BooleanData:
+		return "boolean", nil
+	case *DataType_TextData, *DataType_CharData, *DataType_VarcharData, *DataType_JSONData, *DataType_XMLData,
+		*DataType_EnumData, *DataType_SetData, *DataType_NetworkData:
+		return "string", nil
+	case *DataType_ByteaData, *DataType_BitData:
+		return "bytes", nil
+	case *DataType_UUIDData:
+		return map[string]any{"type": "string", "logicalType": "uuid"}, nil
+	case *DataType_DateData:
+		return map[string]any{"type": "int", "logicalType": "date"}, nil
+	case *DataType_TimeData, *DataType_TimeTypeData:
+		return map[string]any{"type": "long", "logicalType": "time-micros"}, nil
+	case *DataType_IntervalData:
+		return "string", nil
+	case *DataType_TimestampData:
+		return map[string]any{"type": "long", "logicalType": "timestamp-micros"}, nil
+	case *DataType_DecimalData:
+		d := dt.GetDecimalData()
+		if d.Precision == 0 {
+			return avroDecimal(38, 9), nil
+		}
+		return avroDecimal(d.Precision, d.Scale), nil
+	case *DataType_ArrayData:
+		items, err := avroType(dt.GetArrayData().Type, recordName)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case *DataType_StructData:
+		return avroRecordFor(recordName, "", dt.GetStructData().Fields)
+	case *DataType_CustomData:
+		return avroBigQueryType(formatObjectName(dt.GetCustomData()))
+	}
+	return nil, fmt.Errorf("no Avro type for %s", FormatDataType(dt))
+}
+
+// avroBigQueryType maps the BigQuery types the loader keeps by name.
+func avroBigQueryType(name string) (any, error) {
+	switch strings.ToUpper(name) {
+	case "NUMERIC":
+		return avroDecimal(38, 9), nil
+	case "BIGNUMERIC":
+		return avroDecimal(76, 38), nil
+	case "TIMESTAMP":
+		return map[string]any{"type": "long", "logicalType": "timestamp-micros"}, nil
+	case "DATETIME":
+		return map[string]any{"type": "long", "logicalType": "local-timestamp-micros"}, nil
+	case "DATE":
+		return map[string]any{"type": "int", "logicalType": "date"}, nil
+	case "TIME":
+		return map[string]any{"type": "long", "logicalType": "time-micros"}, nil
+	case "GEOGRAPHY", "JSON", "INTERVAL":
+		return "string", nil
+	}
+	return nil, fmt.Errorf("no Avro type for %s", name)
+}
+
+func avroDecimal(precision, scale uint32) map[string]any {
+	return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": precision, "scale": scale}
+}
+
+// avroName turns an identifier into a valid Avro name.
+func avroName(s string) string {
+	s = avroNameUnsafe.ReplaceAllString(s, "_")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "_" + s
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package xmeta
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestMetaTableToAvro(t *testing.T) {
+	table := BQTableToMetaTable(&BQTable{
+		Name: &ObjectName{Idents: []string{"analytics", "events"}},
+		Schema: mapBQSchema(bigquery.Schema{
+			{Name: "id", Type: bigquery.IntegerFieldType, Required: true},
+			{Name: "amount", Type: bigquery.NumericFieldType},
+			{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+			{
+				Name: "device",
+				Type: bigquery.RecordFieldType,
+				Schema: bigquery.Schema{
+					{Name: "os", Type: bigquery.StringFieldType, Required: true},
+				},
+			},
+		}),
+	})
+
+	data, err := MetaTableToAvro(table)
+	if err != nil {
+		t.Fatalf("MetaTableToAvro failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	want := map[string]any{
+		"type":      "record",
+		"name":      "events",
+		"namespace": "analytics",
+		"fields": []any{
+			map[string]any{"name": "id", "type": "long"},
+			map[string]any{"name": "amount", "default": nil, "type": []any{"null",
+				map[string]any{"type": "bytes", "logicalType": "decimal", "precision": 38.0, "scale": 9.0}}},
+			map[string]any{"name": "tags", "type": map[string]any{"type": "array", "items": "string"}},
+			map[string]any{"name": "device", "default": nil, "type": []any{"null", map[string]any{
+				"type":   "record",
+				"name":   "events_device",
+				"fields": []any{map[string]any{"name": "os", "type": "string"}},
+			}}},
+		},
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Unexpected schema:\n got %s\nwant %s", gotJSON, wantJSON)
+	}
+
+	table.Elements[0].GetColumnDefElement().DataType = &DataType{TypeClause: &DataType_CustomData{
+		CustomData: &ObjectName{Idents: []string{"RANGE"}}}}
+	if _, err := MetaTableToAvro(table); err == nil {
+		t.Error("Expected an error for an unmapped type")
+	}
+}
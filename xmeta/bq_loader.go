@@ -2,38 +2,121 @@ package xmeta
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
+const (
+	defaultBQMaxRetries  = 5
+	defaultBQConcurrency = 8
+)
+
+// bqBackoff is the delay before the first retry; it doubles on every
+// further attempt up to bqMaxBackoff.
+var (
+	bqBackoff    = 500 * time.Millisecond
+	bqMaxBackoff = 30 * time.Second
+)
+
+// LoadWarning records an object that could not be loaded and was skipped.
+type LoadWarning struct {
+	Object string // qualified name of the skipped object
+	Err    error
+}
+
+func (w LoadWarning) Error() string {
+	return fmt.Sprintf("%s: %v", w.Object, w.Err)
+}
+
+func (w LoadWarning) Unwrap() error {
+	return w.Err
+}
+
+// BQLoadOptions controls LoadBigQueryWithOptions.
+type BQLoadOptions struct {
+	// FailOnMetadataError makes a dataset or table whose metadata cannot be
+	// read a fatal error instead of a LoadWarning.
+	FailOnMetadataError bool
+	// Filter selects datasets (by schema pattern) and tables to load.
+	Filter LoadFilter
+	// MaxRetries is how often a listing or metadata call failing with a
+	// rate-limit or server error is retried with exponential backoff.
+	// Zero uses the default of 5; a negative value disables retries.
+	MaxRetries int
+	// Concurrency bounds the table metadata fetched in parallel per
+	// dataset. Zero uses the default of 8.
+	Concurrency int
+}
+
+func (o BQLoadOptions) maxRetries() int {
+	if o.MaxRetries == 0 {
+		return defaultBQMaxRetries
+	}
+	return max(o.MaxRetries, 0)
+}
+
+func (o BQLoadOptions) concurrency() int {
+	if o.Concurrency <= 0 {
+		return defaultBQConcurrency
+	}
+	return o.Concurrency
+}
+
 // LoadBigQuery metadata into a BQProject structure.
-// Uses the official BigQuery client.
+// Uses the official BigQuery client. Datasets and tables whose metadata
+// cannot be read are skipped; use LoadBigQueryWithOptions to see which.
 func LoadBigQuery(ctx context.Context, client *bigquery.Client, projectID string) (*BQProject, error) {
+	proj, _, err := LoadBigQueryWithOptions(ctx, client, projectID, BQLoadOptions{})
+	return proj, err
+}
+
+// LoadMetaDatabaseBigQuery loads every dataset of a project with
+// LoadBigQuery and converts the result to a MetaDatabase.
+func LoadMetaDatabaseBigQuery(ctx context.Context, client *bigquery.Client, projectID string) (*MetaDatabase, error) {
+	proj, err := LoadBigQuery(ctx, client, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return BQProjectToMetaDatabase(proj), nil
+}
+
+// LoadBigQueryWithOptions is LoadBigQuery that also returns a warning for
+// every dataset or table that was skipped because its metadata could not be
+// read.
+func LoadBigQueryWithOptions(ctx context.Context, client *bigquery.Client, projectID string, opts BQLoadOptions) (*BQProject, []LoadWarning, error) {
 	bqProj := &BQProject{
 		ProjectId:    projectID,
 		FriendlyName: projectID,
 	}
 
 	// List Datasets
-	it := client.Datasets(ctx)
-	it.ProjectID = projectID
+	dsList, err := listBQDatasets(ctx, client, projectID, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var datasets []*BQDataset
-	for {
-		ds, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to list datasets: %w", err)
-		}
-
+	var warnings []LoadWarning
+	for _, ds := range dsList {
 		// Get Dataset Metadata
-		md, err := ds.Metadata(ctx)
+		var md *bigquery.DatasetMetadata
+		err := bqRetry(ctx, opts.maxRetries(), func() (err error) {
+			md, err = ds.Metadata(ctx)
+			return err
+		})
 		if err != nil {
-			// skip or error? generic loader should probably continue
+			w := LoadWarning{Object: ds.ProjectID + "." + ds.DatasetID, Err: err}
+			if opts.FailOnMetadataError {
+				return nil, warnings, fmt.Errorf("failed to get dataset metadata: %w", w)
+			}
+			warnings = append(warnings, w)
 			continue
 		}
 
@@ -45,9 +128,10 @@ func LoadBigQuery(ctx context.Context, client *bigquery.Client, projectID string
 		}
 
 		// Load Tables
-		tables, err := loadBQTables(ctx, ds)
+		tables, tableWarnings, err := loadBQTables(ctx, ds, opts)
+		warnings = append(warnings, tableWarnings...)
 		if err != nil {
-			return nil, err
+			return nil, warnings, err
 		}
 		bqDS.Tables = tables
 
@@ -55,74 +139,195 @@ func LoadBigQuery(ctx context.Context, client *bigquery.Client, projectID string
 	}
 	bqProj.Datasets = datasets
 
-	return bqProj, nil
+	return bqProj, warnings, nil
 }
 
-func loadBQTables(ctx context.Context, ds *bigquery.Dataset) ([]*BQTable, error) {
-	it := ds.Tables(ctx)
-	var tables []*BQTable
-
-	for {
-		t, err := it.Next()
-		if err == iterator.Done {
-			break
+// listBQDatasets lists the datasets of a project that pass the filter.
+// Iterator errors are sticky, so a retry restarts the listing.
+func listBQDatasets(ctx context.Context, client *bigquery.Client, projectID string, opts BQLoadOptions) ([]*bigquery.Dataset, error) {
+	var datasets []*bigquery.Dataset
+	err := bqRetry(ctx, opts.maxRetries(), func() error {
+		datasets = nil
+		it := client.Datasets(ctx)
+		it.ProjectID = projectID
+		for {
+			ds, err := it.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if opts.Filter.matchSchema(ds.DatasetID) {
+				datasets = append(datasets, ds)
+			}
 		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to list tables in %s: %w", ds.DatasetID, err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+	return datasets, nil
+}
+
+// listBQTables lists the tables of a dataset that pass the filter.
+func listBQTables(ctx context.Context, ds *bigquery.Dataset, opts BQLoadOptions) ([]*bigquery.Table, error) {
+	var tables []*bigquery.Table
+	err := bqRetry(ctx, opts.maxRetries(), func() error {
+		tables = nil
+		it := ds.Tables(ctx)
+		for {
+			t, err := it.Next()
+			if err == iterator.Done {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if opts.Filter.matchTable(t.TableID) {
+				tables = append(tables, t)
+			}
 		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables in %s: %w", ds.DatasetID, err)
+	}
+	return tables, nil
+}
 
-		md, err := t.Metadata(ctx)
-		if err != nil {
+// loadBQTables fetches the metadata of the tables in ds, opts.Concurrency
+// at a time. Tables keep their listing order.
+func loadBQTables(ctx context.Context, ds *bigquery.Dataset, opts BQLoadOptions) ([]*BQTable, []LoadWarning, error) {
+	list, err := listBQTables(ctx, ds, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]*BQTable, len(list))
+	errs := make([]error, len(list))
+	sem := make(chan struct{}, opts.concurrency())
+	var wg sync.WaitGroup
+	for i, t := range list {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = loadBQTable(ctx, t, opts)
+		}()
+	}
+	wg.Wait()
+
+	var tables []*BQTable
+	var warnings []LoadWarning
+	for i, t := range list {
+		if errs[i] != nil {
+			w := LoadWarning{Object: t.ProjectID + "." + t.DatasetID + "." + t.TableID, Err: errs[i]}
+			if opts.FailOnMetadataError {
+				return nil, warnings, fmt.Errorf("failed to get table metadata: %w", w)
+			}
+			warnings = append(warnings, w)
 			continue
 		}
+		tables = append(tables, results[i])
+	}
+	return tables, warnings, nil
+}
 
-		bqT := &BQTable{
-			Name:        &ObjectName{Idents: []string{t.ProjectID, t.DatasetID, t.TableID}},
-			Type:        string(md.Type), // TABLE, VIEW, EXTERNAL
-			NumRows:     int64(md.NumRows),
-			TotalBytes:  md.NumBytes,
-			Description: md.Description,
-			Labels:      md.Labels,
-		}
+func loadBQTable(ctx context.Context, t *bigquery.Table, opts BQLoadOptions) (*BQTable, error) {
+	var md *bigquery.TableMetadata
+	err := bqRetry(ctx, opts.maxRetries(), func() (err error) {
+		md, err = t.Metadata(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// Schema
-		if md.Schema != nil {
-			bqT.Schema = mapBQSchema(md.Schema)
-		}
+	bqT := &BQTable{
+		Name:        &ObjectName{Idents: []string{t.ProjectID, t.DatasetID, t.TableID}},
+		Type:        string(md.Type), // TABLE, VIEW, EXTERNAL
+		NumRows:     int64(md.NumRows),
+		TotalBytes:  md.NumBytes,
+		Description: md.Description,
+		Labels:      md.Labels,
+	}
 
-		if md.ViewQuery != "" {
-			bqT.ViewQuery = md.ViewQuery
-		}
+	// Schema
+	if md.Schema != nil {
+		bqT.Schema = mapBQSchema(md.Schema)
+	}
 
-		tables = append(tables, bqT)
+	if md.ViewQuery != "" {
+		bqT.ViewQuery = md.ViewQuery
 	}
-	return tables, nil
+	return bqT, nil
 }
 
-func mapBQSchema(schema bigquery.Schema) []*BQColumn {
-	var cols []*BQColumn
-	for _, field := range schema {
-		mode := "NULLABLE"
-		if field.Required {
-			mode = "REQUIRED"
-		}
-		if field.Repeated {
-			mode = "REPEATED"
+// bqRetry calls fn until it succeeds, fails with an error that is not
+// transient, or has been retried maxRetries times.
+func bqRetry(ctx context.Context, maxRetries int, fn func() error) error {
+	delay := bqBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxRetries || !isTransientBQError(err) {
+			return err
 		}
-		col := &BQColumn{
-			Name:        field.Name,
-			Mode:        mode,
-			Description: field.Description,
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
 		}
+		delay = min(delay*2, bqMaxBackoff)
+	}
+}
 
-		// Map Type
-		col.DataType = mapBQType(field)
+// isTransientBQError reports whether err is a rate-limit or server error
+// worth retrying.
+func isTransientBQError(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	for _, item := range apiErr.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "backendError", "internalError":
+			return true
+		}
+	}
+	return false
+}
 
-		cols = append(cols, col)
+func mapBQSchema(schema bigquery.Schema) []*BQColumn {
+	var cols []*BQColumn
+	for _, field := range schema {
+		cols = append(cols, mapBQField(field))
 	}
 	return cols
 }
 
+// mapBQField maps a single schema field, recursing into STRUCT/RECORD fields.
+func mapBQField(field *bigquery.FieldSchema) *BQColumn {
+	mode := "NULLABLE"
+	if field.Required {
+		mode = "REQUIRED"
+	}
+	if field.Repeated {
+		mode = "REPEATED"
+	}
+	return &BQColumn{
+		Name:                   field.Name,
+		Mode:                   mode,
+		Description:            field.Description,
+		DefaultValueExpression: field.DefaultValueExpression,
+		DataType:               mapBQType(field),
+	}
+}
+
 func mapBQType(field *bigquery.FieldSchema) *DataType {
 	t := &DataType{}
 	fts := string(field.Type) // STRING, INTEGER, etc.
@@ -139,13 +344,11 @@ func mapBQType(field *bigquery.FieldSchema) *DataType {
 	case "BYTES":
 		t.TypeClause = &DataType_ByteaData{ByteaData: DataTypeSingle_Bytea}
 	case "STRUCT", "RECORD":
-		// Recursive mapping for STRUCT
+		// Recursive mapping for STRUCT; sub-fields go through the same
+		// column conversion so REQUIRED modes become NOT NULL at every level
 		var subCols []*ColumnDef
 		for _, sub := range field.Schema {
-			subCols = append(subCols, &ColumnDef{
-				Name:     sub.Name,
-				DataType: mapBQType(sub),
-			})
+			subCols = append(subCols, BQColumnToColumnDef(mapBQField(sub)))
 		}
 		t.TypeClause = &DataType_StructData{StructData: &StructData{Fields: subCols}}
 	case "ARRAY":
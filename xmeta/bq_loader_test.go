@@ -0,0 +1,146 @@
+package xmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+func TestMapBQSchema_NestedModes(t *testing.T) {
+	schema := bigquery.Schema{
+		{
+			Name:     "address",
+			Type:     bigquery.RecordFieldType,
+			Required: true,
+			Schema: bigquery.Schema{
+				{Name: "street", Type: bigquery.StringFieldType, Required: true},
+				{Name: "zip", Type: bigquery.StringFieldType},
+			},
+		},
+		{
+			Name:     "items",
+			Type:     bigquery.RecordFieldType,
+			Repeated: true,
+			Schema: bigquery.Schema{
+				{Name: "sku", Type: bigquery.StringFieldType, Required: true, Description: "Stock unit"},
+				{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+			},
+		},
+	}
+
+	cols := mapBQSchema(schema)
+	if len(cols) != 2 || cols[0].Mode != "REQUIRED" || cols[1].Mode != "REPEATED" {
+		t.Fatalf("Unexpected columns %v", cols)
+	}
+
+	address := BQColumnToColumnDef(cols[0])
+	if !isNotNull(address) {
+		t.Error("Expected address to be NOT NULL")
+	}
+	fields := address.DataType.GetStructData().GetFields()
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 address fields, got %d", len(fields))
+	}
+	if !isNotNull(fields[0]) {
+		t.Error("Expected address.street to be NOT NULL")
+	}
+	if isNotNull(fields[1]) {
+		t.Error("Expected address.zip to be nullable")
+	}
+
+	// Arrays of structs keep the element field modes
+	items := cols[1].DataType.GetArrayData().GetType().GetStructData().GetFields()
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 item fields, got %d", len(items))
+	}
+	if !isNotNull(items[0]) || items[0].Comment != "Stock unit" {
+		t.Errorf("Unexpected sku field %v", items[0])
+	}
+	if items[1].DataType.GetArrayData().GetType().GetTextData() != DataTypeSingle_Text {
+		t.Errorf("Expected tags to be ARRAY<STRING>, got %v", items[1].DataType)
+	}
+}
+
+func TestMapBQSchema_DefaultValue(t *testing.T) {
+	cols := mapBQSchema(bigquery.Schema{
+		{Name: "status", Type: bigquery.StringFieldType, DefaultValueExpression: "'new'"},
+		{Name: "note", Type: bigquery.StringFieldType},
+	})
+
+	status := BQColumnToColumnDef(cols[0])
+	if got := anyToString(status.Default); got != "'new'" {
+		t.Errorf("Expected default 'new', got %q", got)
+	}
+	if note := BQColumnToColumnDef(cols[1]); note.Default != nil {
+		t.Errorf("Expected no default, got %v", note.Default)
+	}
+}
+
+func TestLoadWarning(t *testing.T) {
+	cause := errors.New("access denied")
+	err := fmt.Errorf("failed to get table metadata: %w", LoadWarning{Object: "p.ds.t", Err: cause})
+	if !errors.Is(err, cause) {
+		t.Error("Expected LoadWarning to unwrap to its cause")
+	}
+	if err.Error() != "failed to get table metadata: p.ds.t: access denied" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestBQRetry(t *testing.T) {
+	defer func(d time.Duration) { bqBackoff = d }(bqBackoff)
+	bqBackoff = time.Millisecond
+
+	unavailable := &googleapi.Error{Code: 503}
+	calls := 0
+	err := bqRetry(context.Background(), 3, func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("listing: %w", unavailable)
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success after 3 calls, got %d calls and %v", calls, err)
+	}
+
+	calls = 0
+	err = bqRetry(context.Background(), 2, func() error {
+		calls++
+		return unavailable
+	})
+	if !errors.Is(err, unavailable) || calls != 3 {
+		t.Errorf("Expected to give up after 3 calls, got %d calls and %v", calls, err)
+	}
+
+	calls = 0
+	notFound := &googleapi.Error{Code: 404}
+	if err := bqRetry(context.Background(), 5, func() error { calls++; return notFound }); err != notFound || calls != 1 {
+		t.Errorf("Expected no retry for 404, got %d calls", calls)
+	}
+
+	rateLimited := &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}
+	if !isTransientBQError(rateLimited) {
+		t.Error("Expected rateLimitExceeded to be transient")
+	}
+	if isTransientBQError(errors.New("boom")) {
+		t.Error("Expected a plain error not to be transient")
+	}
+}
+
+func TestBQLoadOptionsDefaults(t *testing.T) {
+	if got := (BQLoadOptions{}).maxRetries(); got != defaultBQMaxRetries {
+		t.Errorf("maxRetries() = %d", got)
+	}
+	if got := (BQLoadOptions{MaxRetries: -1}).maxRetries(); got != 0 {
+		t.Errorf("maxRetries() = %d for -1", got)
+	}
+	if got := (BQLoadOptions{Concurrency: 3}).concurrency(); got != 3 {
+		t.Errorf("concurrency() = %d", got)
+	}
+}
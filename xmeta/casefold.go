@@ -0,0 +1,63 @@
+package xmeta
+
+// casefold.go folds the case of identifiers, so that a schema read from
+// catalogs that report names differently compares equal.
+
+import "strings"
+
+// CaseFolding is how identifiers are folded as they are loaded.
+type CaseFolding int
+
+const (
+	// CaseFoldingDefault is the default of the dialect, see
+	// DefaultCaseFolding. FoldIdentifiers treats it as CaseFoldingAsIs.
+	CaseFoldingDefault CaseFolding = iota
+	// CaseFoldingAsIs keeps identifiers as the catalog reports them.
+	CaseFoldingAsIs
+	// CaseFoldingLower lower-cases identifiers.
+	CaseFoldingLower
+	// CaseFoldingUpper upper-cases identifiers.
+	CaseFoldingUpper
+)
+
+// DefaultCaseFolding returns the folding of dialect when nothing is known
+// about the server. Postgres folds unquoted identifiers itself and keeps
+// quoted ones, so its names are kept as they are, as are BigQuery's.
+// SQLite compares identifiers without case, so its names are lower-cased.
+// MySQL table names are case-sensitive under lower_case_table_names=0, the
+// default on Linux, so they are kept too; the live loaders read the setting
+// and lower-case names when it is 1 or 2.
+func DefaultCaseFolding(dialect Dialect) CaseFolding {
+	if dialect == DialectSQLite {
+		return CaseFoldingLower
+	}
+	return CaseFoldingAsIs
+}
+
+func (f CaseFolding) fold(s string) string {
+	switch f {
+	case CaseFoldingLower:
+		return strings.ToLower(s)
+	case CaseFoldingUpper:
+		return strings.ToUpper(s)
+	}
+	return s
+}
+
+// FoldIdentifiers folds, in place, the names of the tables of db, their
+// columns, constraints and indexes, the names these refer to, and the names
+// of the views, sequences and triggers. Unlike NormalizeMetaDatabase it
+// does not reorder anything. Use it to fold a schema loaded from a file
+// like the live database it is diffed against.
+func FoldIdentifiers(db *MetaDatabase, folding CaseFolding) {
+	if db == nil || folding == CaseFoldingDefault || folding == CaseFoldingAsIs {
+		return
+	}
+	n := normalizer{folding: folding}
+	for _, t := range db.Tables {
+		if t != nil {
+			n.normalizeNames(t)
+		}
+	}
+	n.normalizeObjects(db)
+}
@@ -0,0 +1,119 @@
+package xmeta
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestFoldIdentifiers(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE Users (ID INTEGER PRIMARY KEY, Name TEXT);
+CREATE INDEX Users_Name ON Users (Name);
+CREATE TABLE Orders (ID INTEGER PRIMARY KEY, User_ID INTEGER,
+  CONSTRAINT FK_User FOREIGN KEY (User_ID) REFERENCES Users (ID));`, DialectMySQL)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	FoldIdentifiers(db, CaseFoldingLower)
+	if got := objectNameKey(db.Tables[0].Name); got != "users" {
+		t.Errorf("Expected users, got %s", got)
+	}
+	if got := objectNameKey(db.Tables[1].Name); got != "orders" {
+		t.Errorf("Expected tables to keep their order, got %s second", got)
+	}
+	if cols := columnsFromElements(db.Tables[0].Elements); cols["id"] == nil || cols["name"] == nil {
+		t.Errorf("Expected lower-case columns, got %v", cols)
+	}
+	if idx := db.Tables[0].Indexes[0]; idx.Name != "users_name" || idx.Columns[0] != "name" {
+		t.Errorf("Expected a lower-case index, got %v", idx)
+	}
+	for _, elem := range db.Tables[1].Elements {
+		if tc := elem.GetTableConstraintElement(); tc.GetSpec().GetReferenceItem() != nil {
+			ref := tc.GetSpec().GetReferenceItem()
+			if tc.Name != "fk_user" || ref.Columns[0] != "user_id" || ref.KeyExpr.TableName != "users" || ref.KeyExpr.Columns[0] != "id" {
+				t.Errorf("Expected a lower-case foreign key, got %v", tc)
+			}
+		}
+	}
+
+	FoldIdentifiers(db, CaseFoldingUpper)
+	if got := objectNameKey(db.Tables[0].Name); got != "USERS" {
+		t.Errorf("Expected USERS, got %s", got)
+	}
+	FoldIdentifiers(db, CaseFoldingAsIs)
+	if got := objectNameKey(db.Tables[0].Name); got != "USERS" {
+		t.Errorf("Expected AsIs to keep USERS, got %s", got)
+	}
+}
+
+func TestDefaultCaseFolding(t *testing.T) {
+	for dialect, want := range map[Dialect]CaseFolding{
+		DialectPostgres: CaseFoldingAsIs,
+		DialectMySQL:    CaseFoldingAsIs,
+		DialectSQLite:   CaseFoldingLower,
+		DialectBigQuery: CaseFoldingAsIs,
+	} {
+		if got := DefaultCaseFolding(dialect); got != want {
+			t.Errorf("%s: expected %d, got %d", dialect, want, got)
+		}
+	}
+	ctx := context.Background()
+	if got, err := liveCaseFolding(ctx, nil, DialectSQLite, CaseFoldingDefault); err != nil || got != CaseFoldingLower {
+		t.Errorf("Expected the SQLite default to resolve to lower, got %d (%v)", got, err)
+	}
+
+	// MySQL reads lower_case_table_names from the server
+	for setting, want := range map[int]CaseFolding{0: CaseFoldingAsIs, 1: CaseFoldingLower, 2: CaseFoldingLower} {
+		db := sql.OpenDB(valueConnector{strconv.Itoa(setting)})
+		if got, err := liveCaseFolding(ctx, db, DialectMySQL, CaseFoldingDefault); err != nil || got != want {
+			t.Errorf("lower_case_table_names=%d: expected %d, got %d (%v)", setting, want, got, err)
+		}
+		if got, err := liveCaseFolding(ctx, db, DialectMySQL, CaseFoldingAsIs); err != nil || got != CaseFoldingAsIs {
+			t.Errorf("Expected an explicit folding to win, got %d (%v)", got, err)
+		}
+		db.Close()
+	}
+}
+
+// valueConnector is a database/sql connector whose every query returns a
+// single row holding value.
+type valueConnector struct{ value string }
+
+func (c valueConnector) Connect(context.Context) (driver.Conn, error) { return valueConn(c), nil }
+func (c valueConnector) Driver() driver.Driver                        { return nil }
+
+type valueConn struct{ value string }
+
+func (c valueConn) Prepare(string) (driver.Stmt, error) { return valueStmt(c), nil }
+func (c valueConn) Close() error                        { return nil }
+func (c valueConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type valueStmt struct{ value string }
+
+func (s valueStmt) Close() error                               { return nil }
+func (s valueStmt) NumInput() int                              { return -1 }
+func (s valueStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s valueStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &valueRows{value: s.value}, nil
+}
+
+type valueRows struct {
+	value string
+	done  bool
+}
+
+func (r *valueRows) Columns() []string { return []string{"value"} }
+func (r *valueRows) Close() error      { return nil }
+func (r *valueRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
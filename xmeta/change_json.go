@@ -0,0 +1,261 @@
+package xmeta
+
+// change_json.go gives schema changes a string form and a JSON form that
+// round-trips, so diffs can be shown and stored without type switches.
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// changeTypes maps the type names used in the JSON form to the change types.
+var changeTypes = make(map[string]reflect.Type)
+
+func init() {
+	for _, c := range []SchemaChange{
+		AlterDatabase{}, AddSchema{}, DropSchema{}, AddTable{}, DropTable{}, RenameTable{}, AlterTableOptions{}, AlterTags{},
+		AddColumn{}, DropColumn{}, AlterColumn{}, AlterColumnPosition{},
+		SetColumnDefault{}, DropColumnDefault{}, SetColumnNullability{},
+		AddConstraint{}, DropConstraint{}, AlterConstraint{}, ValidateConstraint{},
+		AddIndex{}, DropIndex{}, AddView{}, DropView{}, AddTrigger{}, DropTrigger{},
+	} {
+		changeTypes[reflect.TypeOf(c).Name()] = reflect.TypeOf(c)
+	}
+}
+
+// =============================================================================
+// String Form
+// =============================================================================
+
+func (c AlterDatabase) String() string        { return changeString(c) }
+func (c AddSchema) String() string            { return changeString(c) }
+func (c DropSchema) String() string           { return changeString(c) }
+func (c AddTable) String() string             { return changeString(c) }
+func (c DropTable) String() string            { return changeString(c) }
+func (c RenameTable) String() string          { return changeString(c) }
+func (c AlterTableOptions) String() string    { return changeString(c) }
+func (c AlterTags) String() string            { return changeString(c) }
+func (c AddColumn) String() string            { return changeString(c) }
+func (c DropColumn) String() string           { return changeString(c) }
+func (c AlterColumn) String() string          { return changeString(c) }
+func (c AlterColumnPosition) String() string  { return changeString(c) }
+func (c SetColumnDefault) String() string     { return changeString(c) }
+func (c DropColumnDefault) String() string    { return changeString(c) }
+func (c SetColumnNullability) String() string { return changeString(c) }
+func (c AddConstraint) String() string        { return changeString(c) }
+func (c DropConstraint) String() string       { return changeString(c) }
+func (c AlterConstraint) String() string      { return changeString(c) }
+func (c ValidateConstraint) String() string   { return changeString(c) }
+func (c AddIndex) String() string             { return changeString(c) }
+func (c DropIndex) String() string            { return changeString(c) }
+func (c AddView) String() string              { return changeString(c) }
+func (c DropView) String() string             { return changeString(c) }
+func (c AddTrigger) String() string           { return changeString(c) }
+func (c DropTrigger) String() string          { return changeString(c) }
+
+// changeString describes c by its type and target, e.g.
+// "DROP COLUMN users.legacy_field (destructive)". DescribeChange gives the
+// longer form with the details of the change.
+func changeString(c SchemaChange) string {
+	var words []string
+	name := reflect.TypeOf(c).Name()
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, strings.ToUpper(name[start:i]))
+			start = i
+		}
+	}
+	words = append(words, strings.ToUpper(name[start:]))
+
+	target := objectNameKey(changeTableName(c))
+	switch c := c.(type) {
+	case AlterDatabase:
+		target = c.OldName
+	case AddSchema:
+		target = objectNameKey(c.SchemaName)
+	case DropSchema:
+		target = objectNameKey(c.SchemaName)
+	case RenameTable:
+		target = objectNameKey(c.OldName) + " TO " + target
+	}
+	if object := changeObjectName(c); object != "" {
+		target += "." + object
+	}
+
+	s := strings.Join(words, " ") + " " + target
+	if c.IsDestructive() {
+		s += " (destructive)"
+	}
+	return s
+}
+
+// changeObjectName returns the name of the column, constraint, index or
+// trigger a change applies to within its table, or "".
+func changeObjectName(c SchemaChange) string {
+	switch c := c.(type) {
+	case AlterTags:
+		return c.ColumnName
+	case AddColumn:
+		return c.Column.GetName()
+	case DropColumn:
+		return c.ColumnName
+	case AlterColumn:
+		return c.OldColumn.GetName()
+	case AlterColumnPosition:
+		return c.Column.GetName()
+	case SetColumnDefault:
+		return c.Column.GetName()
+	case DropColumnDefault:
+		return c.Column.GetName()
+	case SetColumnNullability:
+		return c.Column.GetName()
+	case AddConstraint:
+		return c.Constraint.GetName()
+	case DropConstraint:
+		return c.ConstraintName
+	case AlterConstraint:
+		return c.NewConstraint.GetName()
+	case ValidateConstraint:
+		return c.ConstraintName
+	case AddIndex:
+		return c.Index.GetName()
+	case DropIndex:
+		return c.IndexName
+	case AddTrigger:
+		return c.Trigger.GetName()
+	case DropTrigger:
+		return c.TriggerName
+	}
+	return ""
+}
+
+// =============================================================================
+// JSON Form
+// =============================================================================
+
+func (c AlterDatabase) MarshalJSON() ([]byte, error)        { return ChangeToJSON(c) }
+func (c AddSchema) MarshalJSON() ([]byte, error)            { return ChangeToJSON(c) }
+func (c DropSchema) MarshalJSON() ([]byte, error)           { return ChangeToJSON(c) }
+func (c AddTable) MarshalJSON() ([]byte, error)             { return ChangeToJSON(c) }
+func (c DropTable) MarshalJSON() ([]byte, error)            { return ChangeToJSON(c) }
+func (c RenameTable) MarshalJSON() ([]byte, error)          { return ChangeToJSON(c) }
+func (c AlterTableOptions) MarshalJSON() ([]byte, error)    { return ChangeToJSON(c) }
+func (c AlterTags) MarshalJSON() ([]byte, error)            { return ChangeToJSON(c) }
+func (c AddColumn) MarshalJSON() ([]byte, error)            { return ChangeToJSON(c) }
+func (c DropColumn) MarshalJSON() ([]byte, error)           { return ChangeToJSON(c) }
+func (c AlterColumn) MarshalJSON() ([]byte, error)          { return ChangeToJSON(c) }
+func (c AlterColumnPosition) MarshalJSON() ([]byte, error)  { return ChangeToJSON(c) }
+func (c SetColumnDefault) MarshalJSON() ([]byte, error)     { return ChangeToJSON(c) }
+func (c DropColumnDefault) MarshalJSON() ([]byte, error)    { return ChangeToJSON(c) }
+func (c SetColumnNullability) MarshalJSON() ([]byte, error) { return ChangeToJSON(c) }
+func (c AddConstraint) MarshalJSON() ([]byte, error)        { return ChangeToJSON(c) }
+func (c DropConstraint) MarshalJSON() ([]byte, error)       { return ChangeToJSON(c) }
+func (c AlterConstraint) MarshalJSON() ([]byte, error)      { return ChangeToJSON(c) }
+func (c ValidateConstraint) MarshalJSON() ([]byte, error)   { return ChangeToJSON(c) }
+func (c AddIndex) MarshalJSON() ([]byte, error)             { return ChangeToJSON(c) }
+func (c DropIndex) MarshalJSON() ([]byte, error)            { return ChangeToJSON(c) }
+func (c AddView) MarshalJSON() ([]byte, error)              { return ChangeToJSON(c) }
+func (c DropView) MarshalJSON() ([]byte, error)             { return ChangeToJSON(c) }
+func (c AddTrigger) MarshalJSON() ([]byte, error)           { return ChangeToJSON(c) }
+func (c DropTrigger) MarshalJSON() ([]byte, error)          { return ChangeToJSON(c) }
+
+// changeJSON is the JSON form of a change. Table, Destructive and Priority
+// are informational; Details holds the fields of the change struct by name,
+// with protobuf messages in their protojson form.
+type changeJSON struct {
+	Type        string                     `json:"type"`
+	Table       string                     `json:"table,omitempty"`
+	Destructive bool                       `json:"destructive"`
+	Priority    int                        `json:"priority"`
+	Details     map[string]json.RawMessage `json:"details"`
+}
+
+// ChangeToJSON returns the JSON form of c:
+//
+//	{"type": "DropColumn", "table": "users", "destructive": true, "priority": 20,
+//	 "details": {"TableName": {"Idents": ["users"]}, "ColumnName": "legacy"}}
+//
+// Every change type also implements json.Marshaler this way, so a []SchemaChange
+// marshals as an array that ParseChangesJSON reads back.
+func ChangeToJSON(c SchemaChange) ([]byte, error) {
+	v := reflect.ValueOf(c)
+	if _, ok := changeTypes[v.Type().Name()]; !ok || v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported schema change %T", c)
+	}
+	out := changeJSON{
+		Type:        v.Type().Name(),
+		Table:       objectNameKey(changeTableName(c)),
+		Destructive: c.IsDestructive(),
+		Priority:    c.Priority(),
+		Details:     make(map[string]json.RawMessage),
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		var data []byte
+		var err error
+		if m, ok := field.Interface().(proto.Message); ok {
+			data, err = protojson.Marshal(m)
+		} else {
+			data, err = json.Marshal(field.Interface())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", out.Type, v.Type().Field(i).Name, err)
+		}
+		out.Details[v.Type().Field(i).Name] = data
+	}
+	return json.Marshal(out)
+}
+
+// ParseChangesJSON parses a JSON array of changes in the form written by
+// ChangeToJSON.
+func ParseChangesJSON(data []byte) ([]SchemaChange, error) {
+	var items []changeJSON
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse changes: %w", err)
+	}
+	changes := make([]SchemaChange, 0, len(items))
+	for i, item := range items {
+		c, err := changeFromJSON(item)
+		if err != nil {
+			return nil, fmt.Errorf("change %d: %w", i, err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func changeFromJSON(item changeJSON) (SchemaChange, error) {
+	typ, ok := changeTypes[item.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown change type %q", item.Type)
+	}
+	v := reflect.New(typ).Elem()
+	for name, raw := range item.Details {
+		field := v.FieldByName(name)
+		if !field.IsValid() {
+			return nil, fmt.Errorf("%s has no field %s", item.Type, name)
+		}
+		var err error
+		if field.Type().Implements(reflect.TypeOf((*proto.Message)(nil)).Elem()) {
+			m := reflect.New(field.Type().Elem())
+			err = protojson.Unmarshal(raw, m.Interface().(proto.Message))
+			field.Set(m)
+		} else {
+			err = json.Unmarshal(raw, field.Addr().Interface())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", item.Type, name, err)
+		}
+	}
+	return v.Interface().(SchemaChange), nil
+}
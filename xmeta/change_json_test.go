@@ -0,0 +1,76 @@
+package xmeta
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSchemaChangeString(t *testing.T) {
+	users := &ObjectName{Idents: []string{"public", "users"}}
+	tests := []struct {
+		change SchemaChange
+		want   string
+	}{
+		{DropColumn{TableName: users, ColumnName: "legacy_field"}, "DROP COLUMN public.users.legacy_field (destructive)"},
+		{AddIndex{TableName: users, Index: &MetaIndex{Name: "idx_email"}}, "ADD INDEX public.users.idx_email"},
+		{RenameTable{OldName: &ObjectName{Idents: []string{"people"}}, NewName: users}, "RENAME TABLE people TO public.users"},
+		{AddSchema{SchemaName: &ObjectName{Idents: []string{"audit"}}}, "ADD SCHEMA audit"},
+		{AlterTableOptions{TableName: users}, "ALTER TABLE OPTIONS public.users"},
+	}
+	for _, tt := range tests {
+		if got := tt.change.(interface{ String() string }).String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestChangesJSONRoundTrip(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INT PRIMARY KEY, legacy TEXT, email TEXT);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INT PRIMARY KEY, email VARCHAR(100) NOT NULL);
+CREATE INDEX idx_users_email ON users (email);
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT REFERENCES users (id));`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := DiffDatabase(current, desired)
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var raw []map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw[0]["type"] != "DropColumn" || raw[0]["table"] != "users" || raw[0]["destructive"] != true || raw[0]["priority"] != 20.0 {
+		t.Errorf("Unexpected first change %v", raw[0])
+	}
+
+	parsed, err := ParseChangesJSON(data)
+	if err != nil {
+		t.Fatalf("ParseChangesJSON failed: %v", err)
+	}
+	if len(parsed) != len(changes) {
+		t.Fatalf("Expected %d changes, got %d", len(changes), len(parsed))
+	}
+	for i := range changes {
+		want, _ := GenerateSQL(changes[i], DialectPostgres)
+		got, err := GenerateSQL(parsed[i], DialectPostgres)
+		if err != nil || strings.Join(got, ";") != strings.Join(want, ";") || DescribeChange(parsed[i]) != DescribeChange(changes[i]) {
+			t.Errorf("Change %d: got %v, %v, want %v", i, got, err, want)
+		}
+		if add, ok := changes[i].(AddTable); ok && !proto.Equal(add.Table, parsed[i].(AddTable).Table) {
+			t.Errorf("Expected the table to round-trip, got %v", parsed[i])
+		}
+	}
+
+	if _, err := ParseChangesJSON([]byte(`[{"type": "DropEverything"}]`)); err == nil {
+		t.Error("Expected an error for an unknown change type")
+	}
+}
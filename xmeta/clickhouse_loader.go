@@ -0,0 +1,256 @@
+package xmeta
+
+// clickhouse_loader.go loads a ClickHouse database straight into the unified
+// model from its system.tables and system.columns tables.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LoadClickHouse loads the tables, columns and views of the ClickHouse
+// database dbName into a MetaDatabase, or of the connection's current
+// database when dbName is empty. db is a database/sql handle opened with the
+// ClickHouse driver (github.com/ClickHouse/clickhouse-go). ClickHouse has no
+// constraints to speak of: a table records its engine in Options["Engine"],
+// its ORDER BY, PARTITION BY and SAMPLE BY expressions in Options["OrderBy"],
+// Options["PartitionBy"] and Options["SampleBy"], and a PRIMARY KEY that
+// differs from the sorting key in Options["PrimaryKey"]. A column is NOT
+// NULL unless its type is Nullable(...).
+func LoadClickHouse(ctx context.Context, db *sql.DB, dbName string) (*MetaDatabase, error) {
+	if dbName == "" {
+		if err := db.QueryRowContext(ctx, "SELECT currentDatabase()").Scan(&dbName); err != nil {
+			return nil, fmt.Errorf("failed to query current database: %w", err)
+		}
+	}
+	meta := &MetaDatabase{Options: map[string]string{"SourceDialect": "clickhouse"}}
+
+	tables, views, err := loadClickHouseTables(ctx, db, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadClickHouseColumns(ctx, db, dbName, tableLookup(tables)); err != nil {
+		return nil, err
+	}
+	meta.Tables = tables
+	meta.Views = views
+	return meta, nil
+}
+
+// loadClickHouseTables returns the tables of dbName, and its views and
+// materialized views, which system.tables lists alongside them.
+func loadClickHouseTables(ctx context.Context, db *sql.DB, dbName string) ([]*MetaTable, []*MetaView, error) {
+	query := `
+		SELECT name, engine, sorting_key, partition_key, primary_key, sampling_key,
+		       as_select, comment
+		FROM system.tables
+		WHERE database = ? AND NOT is_temporary
+		ORDER BY name`
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []*MetaTable
+	var views []*MetaView
+	for rows.Next() {
+		var name, engine, sortingKey, partitionKey, primaryKey, samplingKey, asSelect, comment sql.NullString
+		if err := rows.Scan(&name, &engine, &sortingKey, &partitionKey, &primaryKey, &samplingKey, &asSelect, &comment); err != nil {
+			return nil, nil, err
+		}
+
+		switch engine.String {
+		case "View", "MaterializedView", "LiveView", "WindowView":
+			view := &MetaView{
+				Name:       &ObjectName{Idents: []string{name.String}},
+				Definition: asSelect.String,
+				Comment:    comment.String,
+			}
+			if engine.String == "MaterializedView" {
+				view.Options = map[string]string{"Materialized": "true"}
+			}
+			views = append(views, view)
+			continue
+		}
+
+		table := &MetaTable{
+			Name:    &ObjectName{Idents: []string{name.String}},
+			Type:    "BASE TABLE",
+			Comment: comment.String,
+			Options: map[string]string{"Engine": engine.String},
+		}
+		if sortingKey.String != "" {
+			table.Options["OrderBy"] = sortingKey.String
+		}
+		if partitionKey.String != "" {
+			table.Options["PartitionBy"] = partitionKey.String
+		}
+		if samplingKey.String != "" {
+			table.Options["SampleBy"] = samplingKey.String
+		}
+		if primaryKey.String != "" && primaryKey.String != sortingKey.String {
+			table.Options["PrimaryKey"] = primaryKey.String
+		}
+		tables = append(tables, table)
+	}
+	return tables, views, rows.Err()
+}
+
+func loadClickHouseColumns(ctx context.Context, db *sql.DB, dbName string, lookup func(string) *MetaTable) error {
+	query := `
+		SELECT table, name, type, default_kind, default_expression, comment
+		FROM system.columns
+		WHERE database = ?
+		ORDER BY table, position`
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, typ, defaultKind, defaultExpr, comment sql.NullString
+		if err := rows.Scan(&tableName, &name, &typ, &defaultKind, &defaultExpr, &comment); err != nil {
+			return err
+		}
+		table := lookup(tableName.String)
+		if table == nil {
+			continue // a view column
+		}
+
+		dataType, nullable := mapClickHouseType(typ.String)
+		col := &ColumnDef{
+			Name:     name.String,
+			DataType: dataType,
+			Comment:  comment.String,
+			Options:  make(map[string]string),
+		}
+		switch defaultKind.String {
+		case "DEFAULT":
+			col.Default = stringToAny(defaultExpr.String)
+		case "MATERIALIZED", "ALIAS":
+			col.Options["IsGenerated"] = "true"
+			col.Options["GenerationExpression"] = defaultExpr.String
+			if defaultKind.String == "MATERIALIZED" {
+				col.Options["GenerationKind"] = "STORED"
+			} else {
+				col.Options["GenerationKind"] = "VIRTUAL"
+			}
+		case "EPHEMERAL":
+			col.Options["Ephemeral"] = "true"
+			col.Default = stringToAny(defaultExpr.String)
+		}
+		if !nullable {
+			col.Constraints = append(col.Constraints, &ColumnConstraint{
+				Spec: &ColumnConstraintSpec{
+					ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{
+						NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm,
+					},
+				},
+			})
+		}
+		table.Elements = append(table.Elements, &TableElement{
+			TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col},
+		})
+	}
+	return rows.Err()
+}
+
+// mapClickHouseType maps a ClickHouse column type such as "UInt32",
+// "LowCardinality(Nullable(String))" or "Array(DateTime64(3))" to the unified
+// DataType, and reports whether it is Nullable. The LowCardinality and
+// Nullable wrappers are unwrapped, Array(T) becomes an ArrayData of T, and
+// unknown types are kept as custom.
+func mapClickHouseType(s string) (*DataType, bool) {
+	s = strings.TrimSpace(s)
+	if inner, ok := clickHouseWrapped(s, "LowCardinality"); ok {
+		return mapClickHouseType(inner)
+	}
+	if inner, ok := clickHouseWrapped(s, "Nullable"); ok {
+		dt, _ := mapClickHouseType(inner)
+		return dt, true
+	}
+	if inner, ok := clickHouseWrapped(s, "Array"); ok {
+		elem, _ := mapClickHouseType(inner)
+		return &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: elem}}}, false
+	}
+
+	base, args := s, []string(nil)
+	if open := strings.IndexByte(s, '('); open > 0 && strings.HasSuffix(s, ")") {
+		base = s[:open]
+		for _, arg := range strings.Split(s[open+1:len(s)-1], ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	}
+	arg := func(i int) (uint32, bool) {
+		if i >= len(args) {
+			return 0, false
+		}
+		n, err := strconv.ParseUint(args[i], 10, 32)
+		return uint32(n), err == nil
+	}
+
+	switch base {
+	case "Int8", "UInt8":
+		return &DataType{TypeClause: &DataType_TinyIntData{TinyIntData: &TinyInt{IsUnsigned: base == "UInt8"}}}, false
+	case "Int16", "UInt16":
+		return &DataType{TypeClause: &DataType_SmallIntData{SmallIntData: &SmallInt{IsUnsigned: base == "UInt16"}}}, false
+	case "Int32", "UInt32":
+		return &DataType{TypeClause: &DataType_IntData{IntData: &Int{IsUnsigned: base == "UInt32"}}}, false
+	case "Int64", "UInt64":
+		return &DataType{TypeClause: &DataType_BigIntData{BigIntData: &BigInt{IsUnsigned: base == "UInt64"}}}, false
+	case "Float32":
+		return &DataType{TypeClause: &DataType_RealData{RealData: &Real{}}}, false
+	case "Float64":
+		return &DataType{TypeClause: &DataType_FloatData{FloatData: &Float{}}}, false
+	case "Bool":
+		return &DataType{TypeClause: &DataType_BooleanData{BooleanData: DataTypeSingle_Boolean}}, false
+	case "String":
+		return &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}, false
+	case "FixedString":
+		if n, ok := arg(0); ok {
+			return &DataType{TypeClause: &DataType_CharData{CharData: &CharType{Size: n}}}, false
+		}
+	case "UUID":
+		return &DataType{TypeClause: &DataType_UUIDData{UUIDData: DataTypeSingle_UUID}}, false
+	case "JSON":
+		return &DataType{TypeClause: &DataType_JSONData{JSONData: DataTypeSingle_JSON}}, false
+	case "Date", "Date32":
+		return &DataType{TypeClause: &DataType_DateData{DateData: DataTypeSingle_Date}}, false
+	case "DateTime":
+		return &DataType{TypeClause: &DataType_TimestampData{TimestampData: &Timestamp{WithTimeZone: len(args) > 0}}}, false
+	case "DateTime64":
+		ts := &Timestamp{WithTimeZone: len(args) > 1}
+		if p, ok := arg(0); ok {
+			ts.Precision = &p
+		}
+		return &DataType{TypeClause: &DataType_TimestampData{TimestampData: ts}}, false
+	case "Decimal":
+		p, okP := arg(0)
+		scale, _ := arg(1)
+		if okP {
+			return &DataType{TypeClause: &DataType_DecimalData{DecimalData: &Decimal{Precision: p, Scale: scale}}}, false
+		}
+	case "Decimal32", "Decimal64", "Decimal128", "Decimal256":
+		precision := map[string]uint32{"Decimal32": 9, "Decimal64": 18, "Decimal128": 38, "Decimal256": 76}[base]
+		if scale, ok := arg(0); ok {
+			return &DataType{TypeClause: &DataType_DecimalData{DecimalData: &Decimal{Precision: precision, Scale: scale}}}, false
+		}
+	case "IPv4", "IPv6":
+		return &DataType{TypeClause: &DataType_NetworkData{NetworkData: &NetworkType{Kind: NetworkKind_NetworkKind_Inet}}}, false
+	}
+	return &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: []string{s}}}}, false
+}
+
+// clickHouseWrapped returns T of a type written wrapper(T).
+func clickHouseWrapped(s, wrapper string) (string, bool) {
+	inner, ok := strings.CutPrefix(s, wrapper+"(")
+	if !ok || !strings.HasSuffix(inner, ")") {
+		return "", false
+	}
+	return inner[:len(inner)-1], true
+}
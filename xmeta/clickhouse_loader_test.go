@@ -0,0 +1,40 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestMapClickHouseType(t *testing.T) {
+	tests := []struct {
+		clickhouse string
+		sql        string
+		nullable   bool
+	}{
+		{"Int32", "INTEGER", false},
+		{"UInt64", "BIGINT", false},
+		{"String", "TEXT", false},
+		{"FixedString(16)", "CHAR(16)", false},
+		{"Nullable(String)", "TEXT", true},
+		{"LowCardinality(Nullable(String))", "TEXT", true},
+		{"DateTime64(3)", "TIMESTAMP(3)", false},
+		{"Decimal(10, 2)", "NUMERIC(10,2)", false},
+		{"Array(Nullable(Int64))", "BIGINT[]", false},
+	}
+	for _, tt := range tests {
+		dt, nullable := mapClickHouseType(tt.clickhouse)
+		got, err := dataTypeSQL(dt, DialectPostgres)
+		if err != nil || got != tt.sql || nullable != tt.nullable {
+			t.Errorf("mapClickHouseType(%q) = %q, %v (%v), want %q, %v", tt.clickhouse, got, nullable, err, tt.sql, tt.nullable)
+		}
+	}
+
+	if dt, _ := mapClickHouseType("Map(String, UInt64)"); dt.GetCustomData() == nil {
+		t.Errorf("Expected Map(String, UInt64) to stay custom, got %v", dt)
+	}
+	if dt, _ := mapClickHouseType("Decimal64(4)"); dt.GetDecimalData().GetPrecision() != 18 || dt.GetDecimalData().GetScale() != 4 {
+		t.Errorf("Expected Decimal64(4) as DECIMAL(18, 4), got %v", dt)
+	}
+	if dt, _ := mapClickHouseType("DateTime('UTC')"); !dt.GetTimestampData().GetWithTimeZone() {
+		t.Errorf("Expected DateTime('UTC') with a time zone, got %v", dt)
+	}
+}
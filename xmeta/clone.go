@@ -0,0 +1,28 @@
+package xmeta
+
+// clone.go deep-copies schema models, so that callers can normalize, merge
+// or otherwise mutate a copy without touching the original.
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+// CloneMetaDatabase returns a deep copy of db, or nil if db is nil. The
+// copy shares no memory with db: tables, their elements, options, indexes
+// and the Any values of defaults and checks are all copied, so either may
+// be modified freely.
+func CloneMetaDatabase(db *MetaDatabase) *MetaDatabase {
+	if db == nil {
+		return nil
+	}
+	return proto.Clone(db).(*MetaDatabase)
+}
+
+// CloneMetaTable returns a deep copy of t, or nil if t is nil, with the
+// same independence as CloneMetaDatabase.
+func CloneMetaTable(t *MetaTable) *MetaTable {
+	if t == nil {
+		return nil
+	}
+	return proto.Clone(t).(*MetaTable)
+}
@@ -0,0 +1,38 @@
+package xmeta
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCloneMetaDatabase(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT DEFAULT 'anon');
+CREATE INDEX users_name ON users (name);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	SetTag(db.Tables[0], "pii", "low")
+
+	clone := CloneMetaDatabase(db)
+	if !proto.Equal(clone, db) {
+		t.Fatal("Expected the clone to equal the original")
+	}
+	table := clone.Tables[0]
+	table.Name.Idents[0] = "people"
+	table.Options["tag:pii"] = "high"
+	table.Indexes[0].Columns[0] = "id"
+	orderedColumns(table.Elements)[1].Default = stringToAny("'someone'")
+	if objectNameKey(db.Tables[0].Name) != "users" || GetTag(db.Tables[0], "pii") != "low" ||
+		db.Tables[0].Indexes[0].Columns[0] != "name" || anyToString(orderedColumns(db.Tables[0].Elements)[1].Default) != "'anon'" {
+		t.Error("Expected changes to the clone to leave the original alone")
+	}
+
+	if !proto.Equal(CloneMetaTable(db.Tables[0]), db.Tables[0]) {
+		t.Error("Expected the table clone to equal the original")
+	}
+	if CloneMetaDatabase(nil) != nil || CloneMetaTable(nil) != nil {
+		t.Error("Expected nil clones of nil")
+	}
+}
@@ -0,0 +1,269 @@
+// Package cmd implements the sqlmeta command line: dumping a live database
+// to a schema file, diffing two schema files and applying a schema file to
+// a live database.
+//
+//	sqlmeta dump  -dialect postgres -dsn "$DSN" -o schema.json
+//	sqlmeta diff  [-sql -dialect postgres] current.json desired.json
+//	sqlmeta apply -dialect postgres -dsn "$DSN" -from desired.json [-dry-run]
+//
+// Schema files use any format xmeta.LoadMetaDatabaseFromFile reads. The
+// database/sql driver is chosen by name with -driver, defaulting to
+// "postgres", "mysql" or "sqlite3" for the dialect. The drivers are not
+// linked into this package; the sqlmeta command links them by build tag,
+// and any other binary that needs one wraps Run:
+//
+//	package main
+//
+//	import (
+//		"context"
+//		"fmt"
+//		"os"
+//
+//		_ "github.com/lib/pq"
+//
+//		"github.com/genelet/sqlmeta/xmeta/cmd"
+//	)
+//
+//	func main() {
+//		if err := cmd.Run(context.Background(), os.Args[1:], os.Stdout, os.Stderr); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//	}
+//
+// BigQuery is loaded through its client library and needs no driver:
+// sqlmeta dump -dialect bigquery -project my-project -o schema.json.
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/bigquery"
+
+	"github.com/genelet/sqlmeta/xmeta"
+)
+
+const usage = `usage:
+  sqlmeta dump  -dialect DIALECT (-dsn DSN | -project ID) [-driver NAME] [-database NAME] [-o FILE] [-format FORMAT]
+  sqlmeta diff  [-sql] [-dialect DIALECT] CURRENT DESIRED
+  sqlmeta apply -dialect DIALECT -dsn DSN -from FILE [-driver NAME] [-database NAME] [-dry-run] [-allow-destructive]`
+
+// defaultDrivers names the usual database/sql driver of each dialect.
+var defaultDrivers = map[xmeta.Dialect]string{
+	xmeta.DialectPostgres: "postgres",
+	xmeta.DialectMySQL:    "mysql",
+	xmeta.DialectSQLite:   "sqlite3",
+}
+
+// Run executes the subcommand named by args[0] with the remaining
+// arguments, writing results to stdout and usage messages to stderr.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return errors.New("missing subcommand")
+	}
+	switch args[0] {
+	case "dump":
+		return runDump(ctx, args[1:], stdout, stderr)
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	case "apply":
+		return runApply(ctx, args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprintln(stdout, usage)
+		return nil
+	}
+	fmt.Fprintln(stderr, usage)
+	return fmt.Errorf("unknown subcommand %q", args[0])
+}
+
+// connection holds the flags that locate a live database.
+type connection struct {
+	dialect  string
+	dsn      string
+	driver   string
+	database string
+}
+
+func (c *connection) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.dialect, "dialect", "", "postgres, mysql, sqlite or bigquery")
+	fs.StringVar(&c.dsn, "dsn", "", "data source name passed to the driver")
+	fs.StringVar(&c.driver, "driver", "", "database/sql driver name (default depends on the dialect)")
+	fs.StringVar(&c.database, "database", "", "MySQL database to load")
+}
+
+// load opens the database and loads it as a MetaDatabase.
+func (c *connection) load(ctx context.Context) (*xmeta.MetaDatabase, xmeta.Dialect, error) {
+	dialect := xmeta.ParseDialect(c.dialect)
+	if dialect == xmeta.DialectUnknown || dialect == xmeta.DialectBigQuery {
+		return nil, dialect, fmt.Errorf("-dialect must be postgres, mysql or sqlite, got %q", c.dialect)
+	}
+	db, err := c.open(dialect)
+	if err != nil {
+		return nil, dialect, err
+	}
+	defer db.Close()
+	meta, err := xmeta.LoadMetaDatabase(ctx, db, dialect, c.database)
+	return meta, dialect, err
+}
+
+func (c *connection) open(dialect xmeta.Dialect) (*sql.DB, error) {
+	if c.dsn == "" {
+		return nil, errors.New("-dsn is required")
+	}
+	driver := c.driver
+	if driver == "" {
+		driver = defaultDrivers[dialect]
+	}
+	db, err := sql.Open(driver, c.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%w; link the driver into a binary that wraps cmd.Run", err)
+	}
+	return db, nil
+}
+
+func runDump(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dump", stderr)
+	var conn connection
+	conn.register(fs)
+	project := fs.String("project", "", "BigQuery project to load")
+	output := fs.String("o", "", "schema file to write, in the format of its extension (default stdout)")
+	format := fs.String("format", "json", "format written to stdout: json, yaml, textpb or sql")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var meta *xmeta.MetaDatabase
+	var err error
+	if xmeta.ParseDialect(conn.dialect) == xmeta.DialectBigQuery {
+		if *project == "" {
+			return errors.New("-project is required for bigquery")
+		}
+		client, cerr := bigquery.NewClient(ctx, *project)
+		if cerr != nil {
+			return fmt.Errorf("creating BigQuery client: %w", cerr)
+		}
+		defer client.Close()
+		meta, err = xmeta.LoadMetaDatabaseBigQuery(ctx, client, *project)
+	} else {
+		meta, _, err = conn.load(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("loading database: %w", err)
+	}
+
+	if *output != "" {
+		return xmeta.SaveMetaDatabaseToFile(meta, *output)
+	}
+	f, err := xmeta.FormatFromPath("schema." + *format)
+	if err != nil {
+		return fmt.Errorf("-format: %w", err)
+	}
+	return xmeta.SaveMetaDatabaseToWriter(meta, stdout, f)
+}
+
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("diff", stderr)
+	asSQL := fs.Bool("sql", false, "print the DDL of the changes instead of a description")
+	dialectName := fs.String("dialect", "postgres", "dialect of the DDL printed with -sql")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(stderr, usage)
+		return errors.New("diff takes a current and a desired schema file")
+	}
+	dialect := xmeta.ParseDialect(*dialectName)
+	if *asSQL && dialect == xmeta.DialectUnknown {
+		return fmt.Errorf("unknown dialect %q", *dialectName)
+	}
+
+	current, err := xmeta.LoadMetaDatabaseFromFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", fs.Arg(0), err)
+	}
+	desired, err := xmeta.LoadMetaDatabaseFromFile(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", fs.Arg(1), err)
+	}
+
+	changes := xmeta.DiffDatabaseWithOptions(current, desired, xmeta.DiffOptions{Dialect: dialect})
+	return writeChanges(stdout, changes, dialect, *asSQL)
+}
+
+func runApply(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("apply", stderr)
+	var conn connection
+	conn.register(fs)
+	from := fs.String("from", "", "desired schema file")
+	dryRun := fs.Bool("dry-run", false, "print the SQL instead of executing it")
+	allowDestructive := fs.Bool("allow-destructive", false, "permit changes that can lose data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" {
+		return errors.New("-from is required")
+	}
+
+	desired, err := xmeta.LoadMetaDatabaseFromFile(*from)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", *from, err)
+	}
+	current, dialect, err := conn.load(ctx)
+	if err != nil {
+		return fmt.Errorf("loading database: %w", err)
+	}
+
+	changes := xmeta.DiffDatabaseWithOptions(current, desired, xmeta.DiffOptions{Dialect: dialect})
+	if len(changes) == 0 {
+		fmt.Fprintln(stdout, "no changes")
+		return nil
+	}
+	db, err := conn.open(dialect)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return xmeta.ApplyChanges(ctx, db, dialect, changes, xmeta.ApplyOptions{
+		DryRun:           *dryRun,
+		AllowDestructive: *allowDestructive,
+		Output:           stdout,
+	})
+}
+
+// writeChanges prints one description per change, or their DDL.
+func writeChanges(w io.Writer, changes []xmeta.SchemaChange, dialect xmeta.Dialect, asSQL bool) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+	for _, change := range changes {
+		if !asSQL {
+			if _, err := fmt.Fprintln(w, xmeta.DescribeChange(change)); err != nil {
+				return err
+			}
+			continue
+		}
+		stmts, err := xmeta.GenerateSQL(change, dialect)
+		if err != nil {
+			return fmt.Errorf("generating SQL for %T: %w", change, err)
+		}
+		for _, stmt := range stmts {
+			if _, err := fmt.Fprintf(w, "%s;\n", stmt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("sqlmeta "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
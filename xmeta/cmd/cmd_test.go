@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "current.sql")
+	desired := filepath.Join(dir, "desired.sql")
+	if err := os.WriteFile(current, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(desired, []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := Run(context.Background(), []string{"diff", current, desired}, &stdout, &stderr); err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	if got := stdout.String(); got != "+ column users.email TEXT\n" {
+		t.Errorf("Unexpected diff %q", got)
+	}
+
+	stdout.Reset()
+	if err := Run(context.Background(), []string{"diff", "-sql", "-dialect", "mysql", current, desired}, &stdout, &stderr); err != nil {
+		t.Fatalf("diff -sql failed: %v", err)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "ALTER TABLE `users` ADD COLUMN `email` TEXT") {
+		t.Errorf("Unexpected SQL %q", got)
+	}
+
+	stdout.Reset()
+	if err := Run(context.Background(), []string{"diff", current, current}, &stdout, &stderr); err != nil || stdout.String() != "no changes\n" {
+		t.Errorf("Expected no changes, got %q, %v", stdout.String(), err)
+	}
+
+	if err := Run(context.Background(), []string{"diff", current}, &stdout, &stderr); err == nil {
+		t.Error("Expected an error for a missing schema file")
+	}
+	if err := Run(context.Background(), []string{"migrate"}, &stdout, &stderr); err == nil {
+		t.Error("Expected an error for an unknown subcommand")
+	}
+}
+
+func TestRunDump_MissingDriver(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := Run(context.Background(), []string{"dump", "-dialect", "postgres", "-dsn", "host=localhost"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "unknown driver") {
+		t.Errorf("Expected an unknown driver error, got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package xmeta
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
@@ -28,10 +31,104 @@ func stringToAny(s string) *anypb.Any {
 	return anyVal
 }
 
+// anyToString unpacks a wrapperspb.StringValue from anypb.Any.
+// It returns "" if the Any is nil or does not hold a StringValue.
+func anyToString(a *anypb.Any) string {
+	if a == nil {
+		return ""
+	}
+	sVal := &wrapperspb.StringValue{}
+	if err := a.UnmarshalTo(sVal); err != nil {
+		return ""
+	}
+	return sVal.Value
+}
+
 // =============================================================================
 // Postgres Conversion
 // =============================================================================
 
+// PGDatabaseToMetaDatabase converts a PGDatabase to a unified MetaDatabase,
+// collecting the tables of every schema.
+func PGDatabaseToMetaDatabase(d *PGDatabase) *MetaDatabase {
+	return PGDatabaseToMetaDatabaseWithOptions(d, PGConvertOptions{})
+}
+
+// PGConvertOptions controls PGDatabaseToMetaDatabaseWithOptions.
+type PGConvertOptions struct {
+	// FoldPartitions leaves partitions out of the tables and lists their
+	// names in the parent's Options["Partitions"], comma-separated. By
+	// default partitions are separate tables marked with
+	// Options["PartitionOf"].
+	FoldPartitions bool
+	// InlineForeignKeys attaches single-column foreign keys to their column
+	// as REFERENCES constraints instead of keeping them as table
+	// constraints.
+	InlineForeignKeys bool
+}
+
+// PGDatabaseToMetaDatabaseWithOptions is PGDatabaseToMetaDatabase with
+// explicit options.
+func PGDatabaseToMetaDatabaseWithOptions(d *PGDatabase, opts PGConvertOptions) *MetaDatabase {
+	if d == nil {
+		return nil
+	}
+
+	meta := &MetaDatabase{
+		Name:    d.Name,
+		Options: sourceOptions(DialectPostgres, d.Version),
+	}
+	partitions := make(map[string][]string)
+	for _, schema := range d.Schemas {
+		for _, t := range schema.Tables {
+			if opts.FoldPartitions && t.PartitionOf != nil {
+				parent := objectNameKey(t.PartitionOf)
+				partitions[parent] = append(partitions[parent], objectNameKey(t.Name))
+				continue
+			}
+			meta.Tables = append(meta.Tables, PGTableToMetaTable(t))
+		}
+		for _, v := range schema.Views {
+			meta.Views = append(meta.Views, PGViewToMetaView(v))
+		}
+	}
+	for _, t := range meta.Tables {
+		if names := partitions[objectNameKey(t.Name)]; len(names) > 0 {
+			slices.Sort(names)
+			t.Options["Partitions"] = strings.Join(names, ",")
+		}
+		if opts.InlineForeignKeys {
+			inlineForeignKeys(t)
+		}
+	}
+	return meta
+}
+
+// PGViewToMetaView converts a PGView to a unified MetaView. The check
+// option, security_barrier and materialization are kept in Options.
+func PGViewToMetaView(v *PGView) *MetaView {
+	if v == nil {
+		return nil
+	}
+
+	meta := &MetaView{
+		Name:       v.Name,
+		Definition: v.Definition,
+		Comment:    v.Comment,
+		Options:    make(map[string]string),
+	}
+	if v.CheckOption != "" {
+		meta.Options["CheckOption"] = strings.ToUpper(v.CheckOption)
+	}
+	if v.SecurityBarrier {
+		meta.Options["SecurityBarrier"] = "true"
+	}
+	if v.IsMaterialized {
+		meta.Options["Materialized"] = "true"
+	}
+	return meta
+}
+
 // PGTableToMetaTable converts a PGTable to a unified MetaTable.
 func PGTableToMetaTable(t *PGTable) *MetaTable {
 	if t == nil {
@@ -57,20 +154,42 @@ func PGTableToMetaTable(t *PGTable) *MetaTable {
 	if t.RowSecurityForced {
 		meta.Options["RowSecurityForced"] = "true"
 	}
+	// pg_get_partkeydef gives e.g. "RANGE (created_at)"
+	if strategy, key, ok := strings.Cut(t.PartitionKey, " "); ok {
+		meta.Options["PartitionStrategy"] = strings.ToUpper(strategy)
+		meta.Options["PartitionKey"] = trimOuterParens(strings.TrimSpace(key))
+	}
+	if t.PartitionOf != nil {
+		meta.Options["PartitionOf"] = objectNameKey(t.PartitionOf)
+		meta.Options["PartitionBound"] = t.PartitionBound
+	}
+	if len(t.InheritsFrom) > 0 {
+		var parents []string
+		for _, parent := range t.InheritsFrom {
+			parents = append(parents, objectNameKey(parent))
+		}
+		meta.Options["InheritsFrom"] = strings.Join(parents, ",")
+	}
+	if t.Tablespace != "" {
+		meta.Options["Tablespace"] = t.Tablespace
+	}
 
 	var elements []*TableElement
 
 	// Columns
+	colDefs := make(map[string]*ColumnDef, len(t.Columns))
 	for _, col := range t.Columns {
+		colDef := PGColumnToColumnDef(col)
+		colDefs[col.Name] = colDef
 		elements = append(elements, &TableElement{
 			TableElementClause: &TableElement_ColumnDefElement{
-				ColumnDefElement: PGColumnToColumnDef(col),
+				ColumnDefElement: colDef,
 			},
 		})
 	}
 
-	// Constraints (Non-FK)
-	for _, con := range t.Constraints {
+	// Constraints (Non-FK); single-column checks go inline
+	for _, con := range attachColumnChecks(colDefs, t.Constraints) {
 		tc := PGConstraintToTableConstraint(con)
 		if tc != nil {
 			elements = append(elements, &TableElement{
@@ -93,14 +212,15 @@ func PGTableToMetaTable(t *PGTable) *MetaTable {
 		}
 	}
 
-	// Indexes (PK/Unique)
+	// Indexes; primary keys are already constraints
 	for _, idx := range t.Indexes {
-		if idx.IsPrimary || idx.IsUnique {
-			// Logic to check duplicates omitted for brevity
+		if !idx.IsPrimary {
+			meta.Indexes = append(meta.Indexes, PGIndexToMetaIndex(idx))
 		}
 	}
 
 	meta.Elements = elements
+	nameUnnamedConstraints(meta)
 	return meta
 }
 
@@ -121,10 +241,15 @@ func PGColumnToColumnDef(c *PGColumn) *ColumnDef {
 	if c.IsIdentity {
 		colDef.Options["IsIdentity"] = "true"
 		colDef.Options["IdentityGeneration"] = c.IdentityGeneration
+		if c.IdentityIncrement != 0 {
+			setIdentityOptions(colDef.Options, c.IdentityStart, c.IdentityIncrement)
+		}
 	}
 	if c.IsGenerated {
+		// Postgres generated columns are always stored
 		colDef.Options["IsGenerated"] = "true"
 		colDef.Options["GenerationExpression"] = c.GenerationExpression
+		colDef.Options["GenerationKind"] = "STORED"
 	}
 	if c.IdentitySequence != "" {
 		colDef.Options["IdentitySequence"] = c.IdentitySequence
@@ -135,7 +260,6 @@ func PGColumnToColumnDef(c *PGColumn) *ColumnDef {
 	// Let's add it as a ColumnConstraint if it's a simple PK on this column.
 	if c.IsPrimaryKey {
 		colDef.Constraints = append(colDef.Constraints, &ColumnConstraint{
-			Name: "PRIMARY KEY", // Or generated name
 			Spec: &ColumnConstraintSpec{
 				ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{
 					UniqueItem: &UniqueColumnSpec{IsPrimaryKey: true},
@@ -159,6 +283,52 @@ func PGColumnToColumnDef(c *PGColumn) *ColumnDef {
 	return colDef
 }
 
+// attachColumnChecks adds every CHECK constraint in constraints that
+// references exactly one column of colDefs to that column, keeping its name,
+// and returns the remaining constraints.
+func attachColumnChecks(colDefs map[string]*ColumnDef, constraints []*PGConstraint) []*PGConstraint {
+	var rest []*PGConstraint
+	for _, con := range constraints {
+		if con.GetType() == "c" && len(con.Columns) == 1 {
+			if colDef := colDefs[con.Columns[0]]; colDef != nil {
+				colDef.Constraints = append(colDef.Constraints, &ColumnConstraint{
+					Name: con.Name,
+					Spec: &ColumnConstraintSpec{
+						ColumnConstraintSpecClause: &ColumnConstraintSpec_CheckItem{
+							CheckItem: stringToAny(con.Definition),
+						},
+					},
+				})
+				continue
+			}
+		}
+		rest = append(rest, con)
+	}
+	return rest
+}
+
+// setIdentityOptions records the start and increment of an identity
+// column's sequence in options["IdentityStart"] and
+// options["IdentityIncrement"], leaving out the Postgres defaults so a column
+// declared without sequence options compares equal to a loaded one.
+func setIdentityOptions(options map[string]string, start, increment int64) {
+	if increment != 1 {
+		options["IdentityIncrement"] = strconv.FormatInt(increment, 10)
+	}
+	if start != identityDefaultStart(increment) {
+		options["IdentityStart"] = strconv.FormatInt(start, 10)
+	}
+}
+
+// identityDefaultStart is the START WITH Postgres gives a sequence of the
+// given increment: 1 counting up, -1 counting down.
+func identityDefaultStart(increment int64) int64 {
+	if increment < 0 {
+		return -1
+	}
+	return 1
+}
+
 // PGConstraintToTableConstraint converts a PGConstraint to a unified TableConstraint.
 func PGConstraintToTableConstraint(c *PGConstraint) *TableConstraint {
 	if c == nil {
@@ -176,6 +346,7 @@ func PGConstraintToTableConstraint(c *PGConstraint) *TableConstraint {
 				UniqueItem: &UniqueTableConstraint{
 					IsPrimary: true,
 					Columns:   c.Columns,
+					IndexName: c.IndexName,
 				},
 			},
 		}
@@ -183,8 +354,10 @@ func PGConstraintToTableConstraint(c *PGConstraint) *TableConstraint {
 		tc.Spec = &TableConstraintSpec{
 			TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
 				UniqueItem: &UniqueTableConstraint{
-					IsPrimary: false,
-					Columns:   c.Columns,
+					IsPrimary:        false,
+					Columns:          c.Columns,
+					IndexName:        c.IndexName,
+					NullsNotDistinct: c.NullsNotDistinct,
 				},
 			},
 		}
@@ -194,17 +367,31 @@ func PGConstraintToTableConstraint(c *PGConstraint) *TableConstraint {
 				CheckItem: stringToAny(c.Definition), // Definition usually contains the check expression
 			},
 		}
+	case "x": // Exclusion
+		ex, err := parseExclusionDefinition(c.Definition)
+		if err != nil {
+			// Keep the raw definition so the constraint still round-trips
+			ex = &ExcludeTableConstraint{}
+		}
+		ex.Definition = c.Definition
+		tc.Spec = &TableConstraintSpec{
+			TableConstraintSpecClause: &TableConstraintSpec_ExcludeItem{
+				ExcludeItem: ex,
+			},
+		}
 	default:
-		// Exclusion ("x") or trigger ("t") not fully supported in simple types yet?
+		// Constraint triggers ("t") have no table constraint equivalent
 		return nil
 	}
 
 	return tc
 }
 
-// PGForeignKeyToTableConstraint converts a PGForeignKey to a unified TableConstraint.
+// PGForeignKeyToTableConstraint converts a PGForeignKey to a unified
+// TableConstraint. It returns nil for a key whose local and foreign column
+// counts differ, which no database would accept.
 func PGForeignKeyToTableConstraint(fk *PGForeignKey) *TableConstraint {
-	if fk == nil {
+	if fk == nil || !foreignKeyColumnsMatch(fk.LocalColumns, fk.ForeignColumns) {
 		return nil
 	}
 
@@ -227,6 +414,28 @@ func PGForeignKeyToTableConstraint(fk *PGForeignKey) *TableConstraint {
 	}
 }
 
+// foreignKeyColumnsMatch reports whether a foreign key has local columns
+// and as many foreign columns, or none for the referenced primary key.
+func foreignKeyColumnsMatch(local, foreign []string) bool {
+	return len(local) > 0 && (len(foreign) == 0 || len(foreign) == len(local))
+}
+
+// PGIndexToMetaIndex converts a PGIndex to a unified MetaIndex.
+func PGIndexToMetaIndex(idx *PGIndex) *MetaIndex {
+	if idx == nil {
+		return nil
+	}
+	return &MetaIndex{
+		Name:       idx.Name,
+		Columns:    idx.Columns,
+		Expression: idx.Expression,
+		Predicate:  idx.Predicate,
+		IsUnique:   idx.IsUnique,
+		Method:     idx.AccessMethod,
+		Comment:    idx.Comment,
+	}
+}
+
 // Helpers
 
 func formatObjectName(o *ObjectName) string {
@@ -236,6 +445,53 @@ func formatObjectName(o *ObjectName) string {
 	return strings.Join(o.Idents, ".")
 }
 
+// sourceOptions records where a MetaDatabase was loaded from.
+func sourceOptions(dialect Dialect, version string) map[string]string {
+	options := map[string]string{"SourceDialect": dialect.String()}
+	if version != "" {
+		options["ServerVersion"] = version
+	}
+	return options
+}
+
+// SourceDialect reports the dialect a MetaDatabase was loaded from, as
+// recorded in Options["SourceDialect"] by the database converters, or
+// DialectUnknown.
+func SourceDialect(db *MetaDatabase) Dialect {
+	return ParseDialect(db.GetOptions()["SourceDialect"])
+}
+
+// ServerVersionAtLeast reports whether the server version recorded in
+// Options["ServerVersion"] is at least major.minor, for feature gating such
+// as MySQL 8.0.16 CHECK constraints. Suffixes like "-0ubuntu" or
+// " (Debian ...)" are ignored. It returns false when no version is known.
+func ServerVersionAtLeast(db *MetaDatabase, major, minor int) bool {
+	version := db.GetOptions()["ServerVersion"]
+	if version == "" {
+		return false
+	}
+	var parts [2]int
+	fields := strings.SplitN(version, ".", 3)
+	for i := 0; i < len(parts) && i < len(fields); i++ {
+		digits := fields[i]
+		if end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			digits = digits[:end]
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			if i == 0 {
+				return false
+			}
+			break
+		}
+		parts[i] = n
+	}
+	if parts[0] != major {
+		return parts[0] > major
+	}
+	return parts[1] >= minor
+}
+
 func mapReferentialAction(s string) ReferentialAction {
 	switch strings.ToUpper(s) {
 	case "CASCADE":
@@ -266,10 +522,113 @@ func mapMatchOption(s string) MatchOption {
 	}
 }
 
+// ReferentialActionToSQL returns the SQL keyword for a referential action,
+// the inverse of mapReferentialAction. It returns "" for
+// ReferentialAction_Unknown so generators can omit the clause.
+func ReferentialActionToSQL(a ReferentialAction) string {
+	switch a {
+	case ReferentialAction_ReferentialAction_Cascade:
+		return "CASCADE"
+	case ReferentialAction_ReferentialAction_SetNull:
+		return "SET NULL"
+	case ReferentialAction_ReferentialAction_SetDefault:
+		return "SET DEFAULT"
+	case ReferentialAction_ReferentialAction_Restrict:
+		return "RESTRICT"
+	case ReferentialAction_ReferentialAction_NoAction:
+		return "NO ACTION"
+	default:
+		return ""
+	}
+}
+
+// MatchOptionToSQL returns the SQL keyword for a MATCH option, the inverse of
+// mapMatchOption. It returns "" for MatchOption_Unknown.
+func MatchOptionToSQL(m MatchOption) string {
+	switch m {
+	case MatchOption_MatchOption_Full:
+		return "FULL"
+	case MatchOption_MatchOption_Partial:
+		return "PARTIAL"
+	case MatchOption_MatchOption_Simple:
+		return "SIMPLE"
+	default:
+		return ""
+	}
+}
+
 // =============================================================================
 // MySQL Conversion
 // =============================================================================
 
+// MYDatabaseToMetaDatabase converts a MYDatabase to a unified MetaDatabase.
+func MYDatabaseToMetaDatabase(d *MYDatabase) *MetaDatabase {
+	return MYDatabaseToMetaDatabaseWithOptions(d, MYConvertOptions{})
+}
+
+// MYConvertOptions controls MYDatabaseToMetaDatabaseWithOptions.
+type MYConvertOptions struct {
+	// InlineForeignKeys attaches single-column foreign keys to their column
+	// as REFERENCES constraints instead of keeping them as table
+	// constraints.
+	InlineForeignKeys bool
+}
+
+// MYDatabaseToMetaDatabaseWithOptions is MYDatabaseToMetaDatabase with
+// explicit options.
+func MYDatabaseToMetaDatabaseWithOptions(d *MYDatabase, opts MYConvertOptions) *MetaDatabase {
+	if d == nil {
+		return nil
+	}
+
+	meta := &MetaDatabase{
+		Name:    d.Name,
+		Options: sourceOptions(DialectMySQL, d.Version),
+	}
+	if d.Charset != "" {
+		meta.Options["Charset"] = d.Charset
+	}
+	if d.Collation != "" {
+		meta.Options["Collation"] = d.Collation
+	}
+	for _, t := range d.Tables {
+		table := MYTableToMetaTable(t)
+		if opts.InlineForeignKeys {
+			inlineForeignKeys(table)
+		}
+		meta.Tables = append(meta.Tables, table)
+	}
+	for _, v := range d.Views {
+		meta.Views = append(meta.Views, MYViewToMetaView(v))
+	}
+	return meta
+}
+
+// MYViewToMetaView converts a MYView to a unified MetaView. The check
+// option, SQL SECURITY and updatability are kept in Options; the defaults
+// NONE and DEFINER are left out.
+func MYViewToMetaView(v *MYView) *MetaView {
+	if v == nil {
+		return nil
+	}
+
+	meta := &MetaView{
+		Name:       v.Name,
+		Definition: v.Definition,
+		Options:    make(map[string]string),
+	}
+	if v.CheckOption != "" && !strings.EqualFold(v.CheckOption, "NONE") {
+		meta.Options["CheckOption"] = v.CheckOption
+	}
+	if v.SecurityType != "" && !strings.EqualFold(v.SecurityType, "DEFINER") {
+		meta.Options["SqlSecurity"] = strings.ToUpper(v.SecurityType)
+	}
+	if v.IsUpdatable {
+		meta.Options["IsUpdatable"] = "true"
+	}
+	return meta
+}
+
 // MYTableToMetaTable converts a MYTable to a unified MetaTable.
 func MYTableToMetaTable(t *MYTable) *MetaTable {
 	if t == nil {
@@ -291,6 +650,20 @@ func MYTableToMetaTable(t *MYTable) *MetaTable {
 	if t.Collation != "" {
 		meta.Options["Collation"] = t.Collation
 	}
+	if t.RowFormat != "" {
+		meta.Options["RowFormat"] = strings.ToUpper(t.RowFormat)
+	}
+	if t.KeyBlockSize != 0 {
+		meta.Options["KeyBlockSize"] = strconv.FormatUint(uint64(t.KeyBlockSize), 10)
+	}
+	if t.CreateOptions != "" {
+		meta.Options["CreateOptions"] = t.CreateOptions
+	}
+	if p := t.Partitioning; p != nil && p.Method != "" {
+		meta.Options["PartitionStrategy"] = p.Method
+		meta.Options["PartitionKey"] = p.Expression
+		meta.Options["PartitionDefinitions"] = myPartitionDefinitions(p)
+	}
 
 	var elements []*TableElement
 
@@ -315,24 +688,47 @@ func MYTableToMetaTable(t *MYTable) *MetaTable {
 		}
 	}
 
-	// Indexes (Primary/Unique)
+	// Indexes: primary and unique keys are constraints, the others
+	// (including FULLTEXT and SPATIAL) plain indexes
 	for _, idx := range t.Indexes {
-		if idx.IsUnique || strings.ToUpper(idx.IndexType) == "PRIMARY" {
-			tc := MYIndexToTableConstraint(idx)
-			if tc != nil {
-				elements = append(elements, &TableElement{
-					TableElementClause: &TableElement_TableConstraintElement{
-						TableConstraintElement: tc,
-					},
-				})
-			}
+		tc := MYIndexToTableConstraint(idx)
+		if tc == nil {
+			meta.Indexes = append(meta.Indexes, MYIndexToMetaIndex(idx))
+			continue
 		}
+		elements = append(elements, &TableElement{
+			TableElementClause: &TableElement_TableConstraintElement{
+				TableConstraintElement: tc,
+			},
+		})
 	}
 
 	meta.Elements = elements
+	nameUnnamedConstraints(meta)
 	return meta
 }
 
+// myPartitionDefinitions renders the partitions of a MySQL table as the
+// list that follows PARTITION BY, e.g. "PARTITION p0 VALUES LESS THAN (100),
+// PARTITION p1 VALUES LESS THAN MAXVALUE".
+func myPartitionDefinitions(p *MYPartitioning) string {
+	method := strings.ToUpper(p.Method)
+	defs := make([]string, 0, len(p.Partitions))
+	for _, part := range p.Partitions {
+		def := "PARTITION " + part.Name
+		switch {
+		case strings.HasPrefix(method, "RANGE") && part.Description == "MAXVALUE":
+			def += " VALUES LESS THAN MAXVALUE"
+		case strings.HasPrefix(method, "RANGE"):
+			def += " VALUES LESS THAN (" + part.Description + ")"
+		case strings.HasPrefix(method, "LIST"):
+			def += " VALUES IN (" + part.Description + ")"
+		}
+		defs = append(defs, def)
+	}
+	return strings.Join(defs, ", ")
+}
+
 // MYColumnToColumnDef converts a MYColumn to a unified ColumnDef.
 func MYColumnToColumnDef(c *MYColumn) *ColumnDef {
 	if c == nil {
@@ -356,11 +752,15 @@ func MYColumnToColumnDef(c *MYColumn) *ColumnDef {
 	if c.IsUnsigned {
 		colDef.Options["IsUnsigned"] = "true"
 	}
+	if c.IsGenerated {
+		colDef.Options["IsGenerated"] = "true"
+		colDef.Options["GenerationExpression"] = c.GenerationExpression
+		colDef.Options["GenerationKind"] = c.GenerationKind
+	}
 
 	// Primary Key
 	if c.IsPrimaryKey {
 		colDef.Constraints = append(colDef.Constraints, &ColumnConstraint{
-			Name: "PRIMARY KEY",
 			Spec: &ColumnConstraintSpec{
 				ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{
 					UniqueItem: &UniqueColumnSpec{IsPrimaryKey: true},
@@ -395,9 +795,11 @@ func MYColumnToColumnDef(c *MYColumn) *ColumnDef {
 	return colDef
 }
 
-// MYForeignKeyToTableConstraint converts a MYForeignKey to a unified TableConstraint.
+// MYForeignKeyToTableConstraint converts a MYForeignKey to a unified
+// TableConstraint. It returns nil for a key whose local and foreign column
+// counts differ, which no database would accept.
 func MYForeignKeyToTableConstraint(fk *MYForeignKey) *TableConstraint {
-	if fk == nil {
+	if fk == nil || !foreignKeyColumnsMatch(fk.LocalColumns, fk.ForeignColumns) {
 		return nil
 	}
 
@@ -444,10 +846,75 @@ func MYIndexToTableConstraint(idx *MYIndex) *TableConstraint {
 	}
 }
 
+// MYIndexToMetaIndex converts a MYIndex to a unified MetaIndex. The index
+// type (BTREE, HASH, FULLTEXT or SPATIAL) becomes the Method.
+func MYIndexToMetaIndex(idx *MYIndex) *MetaIndex {
+	if idx == nil {
+		return nil
+	}
+
+	meta := &MetaIndex{
+		Name:     idx.Name,
+		Columns:  idx.Columns,
+		IsUnique: idx.IsUnique,
+		Method:   strings.ToUpper(idx.IndexType),
+		Comment:  idx.IndexComment,
+	}
+	return meta
+}
+
 // =============================================================================
 // SQLite Conversion
 // =============================================================================
 
+// SQLiteDatabaseToMetaDatabase converts a SQLiteDatabase to a unified MetaDatabase.
+func SQLiteDatabaseToMetaDatabase(d *SQLiteDatabase) *MetaDatabase {
+	if d == nil {
+		return nil
+	}
+
+	meta := &MetaDatabase{
+		Name:    d.Name,
+		Options: sourceOptions(DialectSQLite, d.Version),
+	}
+	for _, t := range d.Tables {
+		meta.Tables = append(meta.Tables, SQLiteTableToMetaTable(t))
+	}
+	for _, v := range d.Views {
+		meta.Views = append(meta.Views, SQLiteViewToMetaView(v))
+	}
+	for _, tr := range d.Triggers {
+		meta.Triggers = append(meta.Triggers, SQLiteTriggerToMetaTrigger(tr))
+	}
+	return meta
+}
+
+// SQLiteViewToMetaView converts a SQLiteView to a unified MetaView. The
+// definition is the SELECT of the stored CREATE VIEW statement, or the
+// whole statement if it cannot be parsed.
+func SQLiteViewToMetaView(v *SQLiteView) *MetaView {
+	if v == nil {
+		return nil
+	}
+	meta := &MetaView{Name: &ObjectName{Idents: []string{v.Name}}, Definition: v.Definition}
+	if db, err := LoadMetaDatabaseFromSQL(v.Definition, DialectSQLite); err == nil && len(db.Views) == 1 {
+		meta.Definition = db.Views[0].Definition
+	}
+	return meta
+}
+
+// SQLiteTriggerToMetaTrigger converts a SQLiteTrigger to a unified MetaTrigger.
+func SQLiteTriggerToMetaTrigger(t *SQLiteTrigger) *MetaTrigger {
+	if t == nil {
+		return nil
+	}
+	return &MetaTrigger{
+		Name:       t.Name,
+		TableName:  &ObjectName{Idents: []string{t.TableName}},
+		Definition: t.Definition,
+	}
+}
+
 // SQLiteTableToMetaTable converts a SQLiteTable to unified MetaTable.
 func SQLiteTableToMetaTable(t *SQLiteTable) *MetaTable {
 	if t == nil {
@@ -461,22 +928,156 @@ func SQLiteTableToMetaTable(t *SQLiteTable) *MetaTable {
 	}
 	// Definition SQL could be stored in options if needed?
 	// meta.Options["Definition"] = t.Definition
+	if t.WithoutRowId {
+		meta.Options["WithoutRowID"] = "true"
+	}
+	if t.Strict {
+		meta.Options["Strict"] = "true"
+	}
 
 	var elements []*TableElement
 
+	// Constraint names, CHECK constraints and the UNIQUE, CHECK and
+	// REFERENCES constraints written on a column are only visible in the
+	// CREATE statement. A primary key over several columns becomes a table
+	// constraint even when the definition cannot be parsed, as PRAGMA
+	// table_info flags each column.
+	parsed := sqliteParsedTable(t)
+	var constraints []*TableConstraint
+	parsedCols := make(map[string]*ColumnDef)
+	for _, elem := range parsed.GetElements() {
+		if tc := elem.GetTableConstraintElement(); tc != nil {
+			constraints = append(constraints, tc)
+		}
+		if col := elem.GetColumnDefElement(); col != nil {
+			parsedCols[strings.ToLower(col.Name)] = col
+		}
+	}
+	if len(t.ForeignKeys) > 0 {
+		constraints = sqliteForeignKeys(t.ForeignKeys, constraints, parsedCols)
+	}
+	tablePK := false
+	for _, tc := range constraints {
+		tablePK = tablePK || tc.GetSpec().GetUniqueItem().GetIsPrimary()
+	}
+	var pkColumns []string
+	for _, col := range t.Columns {
+		if col.IsPrimaryKey {
+			pkColumns = append(pkColumns, col.Name)
+		}
+	}
+	if !tablePK && len(pkColumns) > 1 {
+		tablePK = true
+		constraints = append([]*TableConstraint{{
+			Spec: &TableConstraintSpec{
+				TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+					UniqueItem: &UniqueTableConstraint{IsPrimary: true, Columns: pkColumns},
+				},
+			},
+		}}, constraints...)
+	}
+
 	// Columns
 	for _, col := range t.Columns {
+		colDef := SQLiteColumnToColumnDef(col)
+		for _, con := range parsedCols[strings.ToLower(col.Name)].GetConstraints() {
+			spec := con.GetSpec()
+			switch {
+			case spec.GetUniqueItem() != nil && !spec.GetUniqueItem().IsPrimaryKey,
+				spec.GetCheckItem() != nil,
+				spec.GetReferenceItem() != nil && len(t.ForeignKeys) == 0:
+				colDef.Constraints = append(colDef.Constraints, con)
+			}
+		}
+		if tablePK {
+			colDef.Constraints = slices.DeleteFunc(colDef.Constraints, func(con *ColumnConstraint) bool {
+				return con.GetSpec().GetUniqueItem().GetIsPrimaryKey()
+			})
+		}
 		elements = append(elements, &TableElement{
 			TableElementClause: &TableElement_ColumnDefElement{
-				ColumnDefElement: SQLiteColumnToColumnDef(col),
+				ColumnDefElement: colDef,
+			},
+		})
+	}
+
+	for _, tc := range constraints {
+		elements = append(elements, &TableElement{
+			TableElementClause: &TableElement_TableConstraintElement{
+				TableConstraintElement: tc,
 			},
 		})
 	}
 
 	meta.Elements = elements
+	nameUnnamedConstraints(meta)
+
+	// Indexes created with CREATE INDEX; the others back a constraint
+	for _, idx := range t.Indexes {
+		if idx.Origin != "c" {
+			continue
+		}
+		i := slices.IndexFunc(parsed.GetIndexes(), func(p *MetaIndex) bool { return p.Name == idx.Name })
+		if i >= 0 {
+			meta.Indexes = append(meta.Indexes, parsed.Indexes[i])
+			continue
+		}
+		meta.Indexes = append(meta.Indexes, &MetaIndex{Name: idx.Name, Columns: idx.Columns, IsUnique: idx.IsUnique, Predicate: idx.PartialWhere})
+	}
 	return meta
 }
 
+// sqliteForeignKeys returns constraints with its foreign keys, and those
+// written on a column of parsedCols, replaced by the keys PRAGMA
+// foreign_key_list reported. A reported key keeps the name and
+// deferrability of the parsed key on the same columns. NO ACTION, the
+// default, is left unspecified unless it was written out.
+func sqliteForeignKeys(fks []*SQLiteForeignKey, constraints []*TableConstraint, parsedCols map[string]*ColumnDef) []*TableConstraint {
+	parsed := make(map[string]*TableConstraint)
+	constraints = slices.DeleteFunc(constraints, func(tc *TableConstraint) bool {
+		if ref := tc.GetSpec().GetReferenceItem(); ref != nil {
+			parsed[strings.ToLower(strings.Join(ref.Columns, ","))] = tc
+			return true
+		}
+		return false
+	})
+	for _, col := range parsedCols {
+		for _, con := range col.Constraints {
+			if con.GetSpec().GetReferenceItem() != nil {
+				parsed[strings.ToLower(col.Name)] = columnForeignKey(col, con)
+			}
+		}
+	}
+
+	for _, fk := range fks {
+		tc := &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+			ReferenceItem: &ReferentialTableConstraint{},
+		}}}
+		if p := parsed[strings.ToLower(strings.Join(fk.From, ","))]; p != nil {
+			tc = proto.Clone(p).(*TableConstraint)
+		}
+		ref := tc.Spec.GetReferenceItem()
+		ref.Columns = fk.From
+		ref.KeyExpr = &ReferenceKeyExpr{TableName: fk.Table, Columns: fk.To}
+		ref.OnDelete = sqliteReferentialAction(fk.OnDelete, ref.OnDelete)
+		ref.OnUpdate = sqliteReferentialAction(fk.OnUpdate, ref.OnUpdate)
+		if m := mapMatchOption(fk.Match); m != MatchOption_MatchOption_Unknown {
+			ref.Match = m
+		}
+		constraints = append(constraints, tc)
+	}
+	return constraints
+}
+
+// sqliteReferentialAction maps an action reported by PRAGMA
+// foreign_key_list, given the action parsed from the definition.
+func sqliteReferentialAction(action string, parsed ReferentialAction) ReferentialAction {
+	if strings.EqualFold(action, "NO ACTION") && parsed != ReferentialAction_ReferentialAction_NoAction {
+		return ReferentialAction_ReferentialAction_Unknown
+	}
+	return mapReferentialAction(action)
+}
+
 // SQLiteColumnToColumnDef converts a SQLiteColumn to a unified ColumnDef.
 func SQLiteColumnToColumnDef(c *SQLiteColumn) *ColumnDef {
 	if c == nil {
@@ -492,7 +1093,6 @@ func SQLiteColumnToColumnDef(c *SQLiteColumn) *ColumnDef {
 	// Primary Key
 	if c.IsPrimaryKey {
 		colDef.Constraints = append(colDef.Constraints, &ColumnConstraint{
-			Name: "PRIMARY KEY",
 			Spec: &ColumnConstraintSpec{
 				ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{
 					UniqueItem: &UniqueColumnSpec{IsPrimaryKey: true},
@@ -501,6 +1101,11 @@ func SQLiteColumnToColumnDef(c *SQLiteColumn) *ColumnDef {
 		})
 	}
 
+	// AUTOINCREMENT maps to the same marker as MySQL AUTO_INCREMENT
+	if c.AutoIncrement {
+		colDef.MyDecos = append(colDef.MyDecos, AutoIncrement_AutoIncrementConfirm)
+	}
+
 	// Not Null
 	if !c.IsNullable {
 		colDef.Constraints = append(colDef.Constraints, &ColumnConstraint{
@@ -519,6 +1124,46 @@ func SQLiteColumnToColumnDef(c *SQLiteColumn) *ColumnDef {
 // BigQuery Conversion
 // =============================================================================
 
+// BQProjectToMetaDatabase converts a BQProject to a unified MetaDatabase
+// named after the project, collecting the tables of every dataset. Table
+// names stay qualified as project.dataset.table.
+func BQProjectToMetaDatabase(p *BQProject) *MetaDatabase {
+	if p == nil {
+		return nil
+	}
+
+	meta := &MetaDatabase{
+		Name:    p.ProjectId,
+		Options: sourceOptions(DialectBigQuery, ""),
+	}
+	for _, d := range p.Datasets {
+		for _, t := range d.Tables {
+			meta.Tables = append(meta.Tables, BQTableToMetaTable(t))
+		}
+	}
+	return meta
+}
+
+// BQDatasetToMetaDatabase converts a BQDataset to a unified MetaDatabase
+// named after the dataset.
+func BQDatasetToMetaDatabase(d *BQDataset) *MetaDatabase {
+	if d == nil {
+		return nil
+	}
+
+	meta := &MetaDatabase{
+		Name:    formatObjectName(d.Name),
+		Options: sourceOptions(DialectBigQuery, ""),
+	}
+	if d.Location != "" {
+		meta.Options["Location"] = d.Location
+	}
+	for _, t := range d.Tables {
+		meta.Tables = append(meta.Tables, BQTableToMetaTable(t))
+	}
+	return meta
+}
+
 // BQTableToMetaTable converts a BQTable to unified MetaTable.
 func BQTableToMetaTable(t *BQTable) *MetaTable {
 	if t == nil {
@@ -530,6 +1175,9 @@ func BQTableToMetaTable(t *BQTable) *MetaTable {
 		Comment: t.Description,
 		Options: make(map[string]string),
 	}
+	for key, value := range t.Labels {
+		SetTag(meta, key, value)
+	}
 
 	var elements []*TableElement
 
@@ -554,6 +1202,7 @@ func BQColumnToColumnDef(c *BQColumn) *ColumnDef {
 	colDef := &ColumnDef{
 		Name:     c.Name,
 		DataType: c.DataType,
+		Default:  stringToAny(c.DefaultValueExpression),
 		Comment:  c.Description,
 		Options:  make(map[string]string),
 	}
@@ -0,0 +1,190 @@
+package xmeta
+
+// convert_report.go reports what the bulk converters drop or keep only
+// approximately, table by table, for auditing a conversion before its
+// result is diffed or applied.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConvertWarning is a detail of a table that a conversion dropped or kept
+// approximately.
+type ConvertWarning struct {
+	Table   string // Qualified table name
+	Message string
+}
+
+// ConvertReport lists the warnings of a conversion, grouped by table in
+// the order the tables were converted.
+type ConvertReport struct {
+	Warnings []ConvertWarning
+}
+
+func (r *ConvertReport) warn(table *ObjectName, format string, args ...any) {
+	r.Warnings = append(r.Warnings, ConvertWarning{Table: objectNameKey(table), Message: fmt.Sprintf(format, args...)})
+}
+
+// TableWarnings returns the warning messages of the table with the
+// qualified name table.
+func (r *ConvertReport) TableWarnings(table string) []string {
+	var messages []string
+	for _, w := range r.Warnings {
+		if w.Table == table {
+			messages = append(messages, w.Message)
+		}
+	}
+	return messages
+}
+
+// String lists the warnings one per line as "table: message".
+func (r *ConvertReport) String() string {
+	var b strings.Builder
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&b, "%s: %s\n", w.Table, w.Message)
+	}
+	return b.String()
+}
+
+// =============================================================================
+// Bulk Converters
+// =============================================================================
+
+// PGDatabaseToMetaDatabaseWithReport is PGDatabaseToMetaDatabaseWithOptions
+// with a report of the constraints and foreign keys skipped, the exclusion
+// constraints kept as raw definitions, the triggers left out and the
+// columns kept as custom types.
+func PGDatabaseToMetaDatabaseWithReport(d *PGDatabase, opts PGConvertOptions) (*MetaDatabase, *ConvertReport) {
+	meta := PGDatabaseToMetaDatabaseWithOptions(d, opts)
+	report := &ConvertReport{}
+	tables := metaTablesByName(meta)
+	for _, schema := range d.GetSchemas() {
+		for _, t := range schema.Tables {
+			for _, con := range t.Constraints {
+				switch {
+				case PGConstraintToTableConstraint(con) == nil:
+					report.warn(t.Name, "constraint %s of type %q skipped", con.Name, con.Type)
+				case con.Type == "x":
+					if _, err := parseExclusionDefinition(con.Definition); err != nil {
+						report.warn(t.Name, "exclusion constraint %s kept as its raw definition: %v", con.Name, err)
+					}
+				}
+			}
+			for _, fk := range t.ForeignKeys {
+				warnForeignKeyColumns(report, t.Name, fk.Name, fk.LocalColumns, fk.ForeignColumns)
+			}
+			if len(t.Triggers) > 0 {
+				report.warn(t.Name, "triggers not converted: %s", strings.Join(t.Triggers, ", "))
+			}
+			auditColumns(report, tables[objectNameKey(t.Name)])
+		}
+	}
+	return meta, report
+}
+
+// MYDatabaseToMetaDatabaseWithReport is MYDatabaseToMetaDatabaseWithOptions
+// with a report of the foreign keys skipped and the columns kept as custom
+// types.
+func MYDatabaseToMetaDatabaseWithReport(d *MYDatabase, opts MYConvertOptions) (*MetaDatabase, *ConvertReport) {
+	meta := MYDatabaseToMetaDatabaseWithOptions(d, opts)
+	report := &ConvertReport{}
+	tables := metaTablesByName(meta)
+	for _, t := range d.GetTables() {
+		for _, fk := range t.ForeignKeys {
+			warnForeignKeyColumns(report, t.Name, fk.Name, fk.LocalColumns, fk.ForeignColumns)
+		}
+		auditColumns(report, tables[objectNameKey(t.Name)])
+	}
+	return meta, report
+}
+
+// SQLiteDatabaseToMetaDatabaseWithReport is SQLiteDatabaseToMetaDatabase
+// with a report of the CREATE statements whose table constraints could not
+// be parsed and the columns kept as custom types.
+func SQLiteDatabaseToMetaDatabaseWithReport(d *SQLiteDatabase) (*MetaDatabase, *ConvertReport) {
+	meta := SQLiteDatabaseToMetaDatabase(d)
+	report := &ConvertReport{}
+	for i, t := range d.GetTables() {
+		table := meta.Tables[i]
+		if t.Definition != "" {
+			if db, err := LoadMetaDatabaseFromSQL(t.Definition, DialectSQLite); err != nil || len(db.Tables) != 1 {
+				report.warn(table.Name, "table constraints not read from an unparsable CREATE statement")
+			}
+		}
+		auditColumns(report, table)
+	}
+	return meta, report
+}
+
+// BQProjectToMetaDatabaseWithReport is BQProjectToMetaDatabase with a
+// report of the partitioning, clustering, view queries and column policy
+// tags left out and the columns kept as custom types.
+func BQProjectToMetaDatabaseWithReport(p *BQProject) (*MetaDatabase, *ConvertReport) {
+	meta := BQProjectToMetaDatabase(p)
+	report := &ConvertReport{}
+	i := 0
+	for _, d := range p.GetDatasets() {
+		for _, t := range d.Tables {
+			if t.GetPartitioning() != nil {
+				report.warn(t.Name, "partitioning not converted")
+			}
+			if len(t.Clustering) > 0 {
+				report.warn(t.Name, "clustering not converted: %s", strings.Join(t.Clustering, ", "))
+			}
+			if t.ViewQuery != "" {
+				report.warn(t.Name, "view query not converted")
+			}
+			for _, col := range t.Schema {
+				if len(col.PolicyTags) > 0 {
+					report.warn(t.Name, "column %s: policy tags not converted", col.Name)
+				}
+			}
+			auditColumns(report, meta.Tables[i])
+			i++
+		}
+	}
+	return meta, report
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+func metaTablesByName(db *MetaDatabase) map[string]*MetaTable {
+	tables := make(map[string]*MetaTable, len(db.GetTables()))
+	for _, t := range db.GetTables() {
+		tables[objectNameKey(t.Name)] = t
+	}
+	return tables
+}
+
+// warnForeignKeyColumns warns of a foreign key the converters skip for its
+// column counts.
+func warnForeignKeyColumns(report *ConvertReport, table *ObjectName, name string, local, foreign []string) {
+	if !foreignKeyColumnsMatch(local, foreign) {
+		report.warn(table, "foreign key %s skipped: %d local columns but %d foreign columns", name, len(local), len(foreign))
+	}
+}
+
+// auditColumns warns of the columns of table without a data type and those
+// whose type, or array element type, the loader kept as a custom type.
+// table is nil for a folded partition.
+func auditColumns(report *ConvertReport, table *MetaTable) {
+	if table == nil {
+		return
+	}
+	for _, col := range orderedColumns(table.Elements) {
+		dt := col.GetDataType()
+		if dt == nil {
+			report.warn(table.Name, "column %s has no data type", col.Name)
+			continue
+		}
+		if elem := dt.GetArrayData().GetType(); elem != nil {
+			dt = elem
+		}
+		if custom := dt.GetCustomData(); custom != nil {
+			report.warn(table.Name, "column %s: type %s kept as a custom type", col.Name, formatObjectName(custom))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package xmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPGDatabaseToMetaDatabaseWithReport(t *testing.T) {
+	pg := &PGDatabase{Schemas: []*PGSchema{{Name: "public", Tables: []*PGTable{
+		{
+			Name: &ObjectName{Idents: []string{"public", "places"}},
+			Columns: []*PGColumn{
+				{Name: "id", DataType: mapPostgresTypeForProto("integer", "int4")},
+				{Name: "location", DataType: mapPostgresTypeForProto("geometry", "")},
+			},
+			Constraints: []*PGConstraint{
+				{Name: "places_check_trigger", Type: "t"},
+				{Name: "places_pkey", Type: "p", Columns: []string{"id"}},
+			},
+			Triggers: []string{"places_audit"},
+		},
+		{
+			Name:    &ObjectName{Idents: []string{"public", "users"}},
+			Columns: []*PGColumn{{Name: "id", DataType: mapPostgresTypeForProto("integer", "int4")}},
+		},
+	}}}}
+
+	meta, report := PGDatabaseToMetaDatabaseWithReport(pg, PGConvertOptions{})
+	if len(meta.Tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(meta.Tables))
+	}
+	warnings := report.TableWarnings("public.places")
+	if len(warnings) != 3 || len(report.Warnings) != 3 {
+		t.Fatalf("Expected 3 warnings for places only, got:\n%s", report)
+	}
+	for i, want := range []string{`constraint places_check_trigger of type "t" skipped`, "triggers not converted: places_audit", "column location: type geometry kept as a custom type"} {
+		if warnings[i] != want {
+			t.Errorf("Expected warning %q, got %q", want, warnings[i])
+		}
+	}
+	if !strings.HasPrefix(report.String(), "public.places: constraint") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+}
+
+func TestBQProjectToMetaDatabaseWithReport(t *testing.T) {
+	p := &BQProject{ProjectId: "proj", Datasets: []*BQDataset{{Tables: []*BQTable{{
+		Name:         &ObjectName{Idents: []string{"proj", "ds", "events"}},
+		Schema:       []*BQColumn{{Name: "ts", DataType: &DataType{TypeClause: &DataType_TimestampData{TimestampData: &Timestamp{}}}, PolicyTags: []string{"pii"}}},
+		Partitioning: &BQTable_TimePartitioning{TimePartitioning: &BQTimePartitioning{Type: "DAY", Field: "ts"}},
+		Clustering:   []string{"ts"},
+	}}}}}
+
+	_, report := BQProjectToMetaDatabaseWithReport(p)
+	if got := report.TableWarnings("proj.ds.events"); len(got) != 3 {
+		t.Errorf("Expected partitioning, clustering and policy tag warnings, got %v", got)
+	}
+}
+
+func TestForeignKeyColumnCounts(t *testing.T) {
+	name := &ObjectName{Idents: []string{"app", "orders"}}
+	fk := &MYForeignKey{
+		Name:           "fk_order_item",
+		TableName:      name,
+		LocalColumns:   []string{"order_id", "item_id"},
+		ForeignTable:   &ObjectName{Idents: []string{"app", "items"}},
+		ForeignColumns: []string{"id"},
+	}
+	if MYForeignKeyToTableConstraint(fk) != nil {
+		t.Error("Expected a foreign key with mismatched columns to be skipped")
+	}
+
+	my := &MYDatabase{Tables: []*MYTable{{
+		Name:        name,
+		Columns:     []*MYColumn{{Name: "order_id", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}}},
+		ForeignKeys: []*MYForeignKey{fk},
+	}}}
+	meta, report := MYDatabaseToMetaDatabaseWithReport(my, MYConvertOptions{})
+	if len(meta.Tables[0].Elements) != 1 {
+		t.Errorf("Expected only the column, got %v", meta.Tables[0].Elements)
+	}
+	want := "foreign key fk_order_item skipped: 2 local columns but 1 foreign columns"
+	if got := report.TableWarnings("app.orders"); len(got) != 1 || got[0] != want {
+		t.Errorf("Expected %q, got %v", want, got)
+	}
+
+	// No foreign columns references the primary key
+	fk.ForeignColumns = nil
+	if MYForeignKeyToTableConstraint(fk) == nil {
+		t.Error("Expected a foreign key to the primary key to convert")
+	}
+}
@@ -1,6 +1,8 @@
 package xmeta
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -89,6 +91,109 @@ func TestPGConstraintToTableConstraint(t *testing.T) {
 	}
 }
 
+func TestPGConstraintToTableConstraint_IndexName(t *testing.T) {
+	pgDB := func(indexName string) *PGDatabase {
+		return &PGDatabase{Schemas: []*PGSchema{{Name: "public", Tables: []*PGTable{{
+			Name:    &ObjectName{Idents: []string{"public", "users"}},
+			Columns: []*PGColumn{{Name: "email", DataType: mapPostgresTypeForProto("text", ""), IsNullable: true}},
+			Constraints: []*PGConstraint{
+				{Name: "users_email_key", Type: "u", Columns: []string{"email"}, IndexName: indexName},
+			},
+		}}}}}
+	}
+
+	meta := PGDatabaseToMetaDatabase(pgDB("users_email_key"))
+	tc := meta.Tables[0].Elements[1].GetTableConstraintElement()
+	if tc.GetSpec().GetUniqueItem().GetIndexName() != "users_email_key" {
+		t.Fatalf("Expected the backing index name, got %v", tc)
+	}
+
+	// Sources that do not name the index match any backing index
+	fromSQL, err := LoadMetaDatabaseFromSQL(`CREATE TABLE public.users (email text, CONSTRAINT users_email_key UNIQUE (email));`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := DiffDatabase(meta, fromSQL); len(changes) != 0 {
+		t.Errorf("Expected no changes against SQL, got %v", changes)
+	}
+
+	changes := DiffDatabase(meta, PGDatabaseToMetaDatabase(pgDB("users_email_uniq")))
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change for the renamed index, got %v", changes)
+	}
+	if _, ok := changes[0].(AlterConstraint); !ok {
+		t.Fatalf("Expected AlterConstraint, got %T", changes[0])
+	}
+	stmts, err := GenerateSQL(changes[0], DialectPostgres)
+	want := `ALTER INDEX "public"."users_email_key" RENAME TO "users_email_uniq"`
+	if err != nil || len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("Unexpected SQL %v, %v", stmts, err)
+	}
+}
+
+func TestPGConstraintToTableConstraint_Exclusion(t *testing.T) {
+	pgCon := &PGConstraint{
+		Name:       "no_overlap",
+		Type:       "x",
+		Definition: "EXCLUDE USING gist (room_id WITH =, during WITH &&) WHERE ((NOT cancelled))",
+	}
+
+	tc := PGConstraintToTableConstraint(pgCon)
+	ex := tc.GetSpec().GetExcludeItem()
+	if ex == nil {
+		t.Fatal("Expected ExcludeItem")
+	}
+	if ex.Method != "gist" || len(ex.Elements) != 2 || ex.Elements[1].Operator != "&&" || anyToString(ex.Elements[1].Expr) != "during" {
+		t.Errorf("Unexpected exclusion constraint %v", ex)
+	}
+	if anyToString(ex.Where) != "(NOT cancelled)" || ex.Definition != pgCon.Definition {
+		t.Errorf("Unexpected exclusion constraint %v", ex)
+	}
+
+	// An unparseable definition is carried through verbatim
+	pgCon.Definition = "EXCLUDE USING gist"
+	tc = PGConstraintToTableConstraint(pgCon)
+	body, err := tableConstraintSQL(tc, DialectPostgres)
+	if err != nil {
+		t.Fatalf("tableConstraintSQL failed: %v", err)
+	}
+	if body != `CONSTRAINT "no_overlap" EXCLUDE USING gist` {
+		t.Errorf("Unexpected SQL: %s", body)
+	}
+}
+
+func TestPGTableToMetaTable_ColumnChecks(t *testing.T) {
+	pgTbl := &PGTable{
+		Name: &ObjectName{Idents: []string{"public", "accounts"}},
+		Columns: []*PGColumn{
+			{Name: "balance", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}, IsNullable: true},
+			{Name: "limit_amount", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}, IsNullable: true},
+		},
+		Constraints: []*PGConstraint{
+			{Name: "balance_positive", Type: "c", Columns: []string{"balance"}, Definition: "CHECK ((balance >= 0))"},
+			{Name: "within_limit", Type: "c", Columns: []string{"balance", "limit_amount"}, Definition: "CHECK ((balance <= limit_amount))"},
+		},
+	}
+
+	meta := PGTableToMetaTable(pgTbl)
+	constraints := constraintsFromElements(meta.Elements)
+	if _, ok := constraints["balance_positive"]; ok || constraints["within_limit"] == nil {
+		t.Errorf("Expected only the two-column check at table level, got %v", constraints)
+	}
+	balance := columnsFromElements(meta.Elements)["balance"]
+	if len(balance.Constraints) != 1 || balance.Constraints[0].Name != "balance_positive" {
+		t.Fatalf("Expected inline check on balance, got %v", balance.Constraints)
+	}
+
+	def, err := columnDefSQL(balance, DialectPostgres, false)
+	if err != nil {
+		t.Fatalf("columnDefSQL failed: %v", err)
+	}
+	if def != `"balance" INTEGER CONSTRAINT "balance_positive" CHECK ((balance >= 0))` {
+		t.Errorf("Unexpected SQL: %s", def)
+	}
+}
+
 func TestMYIndexToTableConstraint(t *testing.T) {
 	idx := &MYIndex{
 		Name:     "PRIMARY",
@@ -130,3 +235,428 @@ func TestSQLiteColumnToColumnDef(t *testing.T) {
 		t.Errorf("Expected no constraints for nullable column, got %d", len(colDef.Constraints))
 	}
 }
+
+func TestColumnToColumnDef_PrimaryKeyUnnamed(t *testing.T) {
+	fromFile, err := LoadMetaDatabaseFromSQL("CREATE TABLE t (id INTEGER PRIMARY KEY);", DialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, col := range map[string]*ColumnDef{
+		"postgres": PGColumnToColumnDef(&PGColumn{Name: "id", IsPrimaryKey: true}),
+		"mysql":    MYColumnToColumnDef(&MYColumn{Name: "id", IsPrimaryKey: true}),
+		"sqlite":   SQLiteColumnToColumnDef(&SQLiteColumn{Name: "id", IsPrimaryKey: true}),
+		"sql":      fromFile.Tables[0].Elements[0].GetColumnDefElement(),
+	} {
+		var found bool
+		for _, con := range col.Constraints {
+			if con.GetSpec().GetUniqueItem().GetIsPrimaryKey() {
+				found = true
+				if con.Name != "" {
+					t.Errorf("%s: expected an unnamed primary key, got %q", name, con.Name)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: expected a primary key constraint", name)
+		}
+	}
+}
+
+func TestMYColumnToColumnDef_Generated(t *testing.T) {
+	myCol := &MYColumn{
+		Name:                 "full_name",
+		IsNullable:           true,
+		IsGenerated:          true,
+		GenerationExpression: "concat(`first`,' ',`last`)",
+		GenerationKind:       "STORED",
+	}
+
+	colDef := MYColumnToColumnDef(myCol)
+	if colDef.Options["IsGenerated"] != "true" {
+		t.Error("Expected IsGenerated option")
+	}
+	if colDef.Options["GenerationExpression"] != myCol.GenerationExpression {
+		t.Errorf("Unexpected GenerationExpression: %s", colDef.Options["GenerationExpression"])
+	}
+	if colDef.Options["GenerationKind"] != "STORED" {
+		t.Errorf("Expected GenerationKind STORED, got %s", colDef.Options["GenerationKind"])
+	}
+}
+
+func TestMYDatabaseToMetaDatabase_Views(t *testing.T) {
+	meta := MYDatabaseToMetaDatabase(&MYDatabase{
+		Name: "shop",
+		Views: []*MYView{
+			{Name: &ObjectName{Idents: []string{"shop", "active_users"}}, Definition: "select `id` from `users`", CheckOption: "CASCADED", IsUpdatable: true},
+			{Name: &ObjectName{Idents: []string{"shop", "totals"}}, Definition: "select count(0) from `orders`", CheckOption: "NONE"},
+		},
+	})
+
+	if len(meta.Tables) != 0 || len(meta.Views) != 2 {
+		t.Fatalf("Expected two views and no tables, got %v", meta)
+	}
+	active := meta.Views[0]
+	if active.Definition != "select `id` from `users`" || active.Options["CheckOption"] != "CASCADED" || active.Options["IsUpdatable"] != "true" {
+		t.Errorf("Unexpected view %v", active)
+	}
+	if len(meta.Views[1].Options) != 0 {
+		t.Errorf("Expected no options for a read-only view without check option, got %v", meta.Views[1].Options)
+	}
+}
+
+func TestReferentialActionToSQL(t *testing.T) {
+	for _, kw := range []string{"CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT", "NO ACTION"} {
+		if got := ReferentialActionToSQL(mapReferentialAction(kw)); got != kw {
+			t.Errorf("Round trip of %q gave %q", kw, got)
+		}
+	}
+	if got := ReferentialActionToSQL(ReferentialAction_ReferentialAction_Unknown); got != "" {
+		t.Errorf("Expected empty keyword for unknown action, got %q", got)
+	}
+
+	for _, kw := range []string{"FULL", "PARTIAL", "SIMPLE"} {
+		if got := MatchOptionToSQL(mapMatchOption(kw)); got != kw {
+			t.Errorf("Round trip of %q gave %q", kw, got)
+		}
+	}
+	if got := MatchOptionToSQL(MatchOption_MatchOption_Unknown); got != "" {
+		t.Errorf("Expected empty keyword for unknown match option, got %q", got)
+	}
+}
+
+func TestDatabaseToMetaDatabase_Source(t *testing.T) {
+	pg := PGDatabaseToMetaDatabase(&PGDatabase{
+		Name:    "app",
+		Version: "16.2",
+		Schemas: []*PGSchema{
+			{Name: "public", Tables: []*PGTable{{Name: &ObjectName{Idents: []string{"public", "users"}}}}},
+			{Name: "audit", Tables: []*PGTable{{Name: &ObjectName{Idents: []string{"audit", "log"}}}}},
+		},
+	})
+	if len(pg.Tables) != 2 {
+		t.Fatalf("Expected tables from all schemas, got %d", len(pg.Tables))
+	}
+	if SourceDialect(pg) != DialectPostgres || pg.Options["ServerVersion"] != "16.2" {
+		t.Errorf("Unexpected source options %v", pg.Options)
+	}
+
+	my := MYDatabaseToMetaDatabase(&MYDatabase{Name: "shop", Version: "8.0.36"})
+	if SourceDialect(my) != DialectMySQL || my.Options["ServerVersion"] != "8.0.36" {
+		t.Errorf("Unexpected source options %v", my.Options)
+	}
+
+	lite := SQLiteDatabaseToMetaDatabase(&SQLiteDatabase{Name: "main", Version: "3.45.1"})
+	if SourceDialect(lite) != DialectSQLite {
+		t.Errorf("Unexpected source options %v", lite.Options)
+	}
+
+	bq := BQDatasetToMetaDatabase(&BQDataset{Name: &ObjectName{Idents: []string{"proj", "ds"}}, Location: "EU"})
+	if bq.Name != "proj.ds" || SourceDialect(bq) != DialectBigQuery || bq.Options["Location"] != "EU" {
+		t.Errorf("Unexpected BigQuery database %v", bq)
+	}
+
+	proj := BQProjectToMetaDatabase(&BQProject{
+		ProjectId: "proj",
+		Datasets: []*BQDataset{
+			{Tables: []*BQTable{{Name: &ObjectName{Idents: []string{"proj", "sales", "orders"}}}}},
+			{Tables: []*BQTable{{Name: &ObjectName{Idents: []string{"proj", "web", "events"}}}}},
+		},
+	})
+	if proj.Name != "proj" || len(proj.Tables) != 2 || objectNameKey(proj.Tables[1].Name) != "proj.web.events" {
+		t.Errorf("Unexpected BigQuery project database %v", proj)
+	}
+
+	if SourceDialect(&MetaDatabase{}) != DialectUnknown || SourceDialect(nil) != DialectUnknown {
+		t.Error("Expected unknown dialect without a source marker")
+	}
+}
+
+func TestPGDatabaseToMetaDatabase_Partitions(t *testing.T) {
+	column := func(typ string) []*PGColumn {
+		return []*PGColumn{{Name: "created_at", DataType: mapPostgresTypeForProto(typ, "")}}
+	}
+	pgDB := func(childType string) *PGDatabase {
+		return &PGDatabase{Schemas: []*PGSchema{{Name: "public", Tables: []*PGTable{
+			{
+				Name:         &ObjectName{Idents: []string{"public", "events"}},
+				Columns:      column("timestamp"),
+				PartitionKey: "RANGE (created_at)",
+			},
+			{
+				Name:           &ObjectName{Idents: []string{"public", "events_2024"}},
+				Columns:        column(childType),
+				PartitionOf:    &ObjectName{Idents: []string{"public", "events"}},
+				PartitionBound: "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')",
+			},
+		}}}}
+	}
+
+	meta := PGDatabaseToMetaDatabase(pgDB("timestamp"))
+	if len(meta.Tables) != 2 {
+		t.Fatalf("Expected parent and partition, got %d tables", len(meta.Tables))
+	}
+	parent, child := meta.Tables[0], meta.Tables[1]
+	if parent.Options["PartitionStrategy"] != "RANGE" || parent.Options["PartitionKey"] != "created_at" {
+		t.Errorf("Unexpected parent options %v", parent.Options)
+	}
+	if child.Options["PartitionOf"] != "public.events" {
+		t.Errorf("Unexpected partition options %v", child.Options)
+	}
+
+	changes := DiffDatabaseWithOptions(&MetaDatabase{}, meta, DiffOptions{MatchSimpleNames: true})
+	if len(changes) != 2 || changes[1].(AddTable).Table != child {
+		t.Fatalf("Expected the parent to be created before the partition, got %v", changes)
+	}
+	stmts, _ := GenerateSQL(changes[0], DialectPostgres)
+	if len(stmts) != 1 || !strings.HasSuffix(stmts[0], `) PARTITION BY RANGE (created_at)`) {
+		t.Errorf("Unexpected parent SQL %v", stmts)
+	}
+	stmts, _ = GenerateSQL(changes[1], DialectPostgres)
+	want := `CREATE TABLE "public"."events_2024" PARTITION OF "public"."events" FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')`
+	if len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("Unexpected partition SQL %v", stmts)
+	}
+
+	// Inherited columns are diffed on the parent only
+	if changes := DiffDatabase(meta, PGDatabaseToMetaDatabase(pgDB("timestamptz"))); len(changes) != 0 {
+		t.Errorf("Expected partition columns to be ignored, got %v", changes)
+	}
+
+	folded := PGDatabaseToMetaDatabaseWithOptions(pgDB("timestamp"), PGConvertOptions{FoldPartitions: true})
+	if len(folded.Tables) != 1 || folded.Tables[0].Options["Partitions"] != "public.events_2024" {
+		t.Errorf("Expected partitions folded into the parent, got %v", folded.Tables)
+	}
+}
+
+func TestPGDatabaseToMetaDatabase_InheritanceAndTablespace(t *testing.T) {
+	pgDB := func(tablespace string) *PGDatabase {
+		columns := []*PGColumn{{Name: "name", DataType: mapPostgresTypeForProto("text", ""), IsNullable: true}}
+		return &PGDatabase{Schemas: []*PGSchema{{Name: "public", Tables: []*PGTable{
+			{
+				Name:         &ObjectName{Idents: []string{"public", "capitals"}},
+				Columns:      columns,
+				InheritsFrom: []*ObjectName{{Idents: []string{"public", "cities"}}},
+				Tablespace:   tablespace,
+			},
+			{Name: &ObjectName{Idents: []string{"public", "cities"}}, Columns: columns},
+		}}}}
+	}
+
+	meta := PGDatabaseToMetaDatabase(pgDB("fast"))
+	capitals := meta.Tables[0]
+	if capitals.Options["InheritsFrom"] != "public.cities" || capitals.Options["Tablespace"] != "fast" {
+		t.Fatalf("Unexpected options %v", capitals.Options)
+	}
+
+	changes := DiffDatabaseWithOptions(&MetaDatabase{}, meta, DiffOptions{MatchSimpleNames: true})
+	if len(changes) != 2 || changes[1].(AddTable).Table != capitals {
+		t.Fatalf("Expected the parent to be created first, got %v", changes)
+	}
+	stmts, err := GenerateSQL(changes[1], DialectPostgres)
+	if err != nil || len(stmts) != 1 || !strings.HasSuffix(stmts[0], `) INHERITS ("public"."cities") TABLESPACE "fast"`) {
+		t.Errorf("Unexpected SQL %v, %v", stmts, err)
+	}
+	if _, err := GenerateSQL(changes[1], DialectMySQL); err == nil {
+		t.Error("Expected an error for inheritance outside Postgres")
+	}
+
+	changes = DiffDatabase(meta, PGDatabaseToMetaDatabase(pgDB("")))
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v", changes)
+	}
+	stmts, _ = GenerateSQL(changes[0].(AlterTableOptions), DialectPostgres)
+	if len(stmts) != 1 || stmts[0] != `ALTER TABLE "public"."capitals" SET TABLESPACE "pg_default"` {
+		t.Errorf("Unexpected SQL %v", stmts)
+	}
+
+	stmts, _ = GenerateSQL(AlterTableOptions{
+		TableName:  capitals.Name,
+		OldOptions: map[string]string{"InheritsFrom": "public.cities"},
+		NewOptions: map[string]string{"InheritsFrom": "public.places"},
+	}, DialectPostgres)
+	if len(stmts) != 2 || stmts[0] != `ALTER TABLE "public"."capitals" NO INHERIT "public"."cities"` ||
+		stmts[1] != `ALTER TABLE "public"."capitals" INHERIT "public"."places"` {
+		t.Errorf("Unexpected SQL %v", stmts)
+	}
+}
+
+func TestServerVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version      string
+		major, minor int
+		expected     bool
+	}{
+		{"8.0.36-0ubuntu0.22.04.1", 8, 0, true},
+		{"8.0.36", 8, 1, false},
+		{"5.7.44-log", 8, 0, false},
+		{"16.2 (Debian 16.2-1.pgdg120+2)", 10, 0, true},
+		{"17beta1", 17, 0, true},
+		{"", 1, 0, false},
+		{"unknown", 1, 0, false},
+	}
+	for _, tt := range tests {
+		db := &MetaDatabase{Options: map[string]string{"ServerVersion": tt.version}}
+		if got := ServerVersionAtLeast(db, tt.major, tt.minor); got != tt.expected {
+			t.Errorf("ServerVersionAtLeast(%q, %d, %d) = %v; expected %v", tt.version, tt.major, tt.minor, got, tt.expected)
+		}
+	}
+}
+
+func TestMYDatabaseToMetaDatabase_InlineForeignKeys(t *testing.T) {
+	d := &MYDatabase{
+		Name: "shop",
+		Tables: []*MYTable{{
+			Name: &ObjectName{Idents: []string{"shop", "orders"}},
+			Columns: []*MYColumn{
+				{Name: "user_id", IsNullable: true},
+				{Name: "a", IsNullable: true},
+				{Name: "b", IsNullable: true},
+			},
+			ForeignKeys: []*MYForeignKey{
+				{Name: "fk_user", LocalColumns: []string{"user_id"}, ForeignTable: &ObjectName{Idents: []string{"shop", "users"}}, ForeignColumns: []string{"id"}, OnDelete: "CASCADE"},
+				{Name: "fk_pair", LocalColumns: []string{"a", "b"}, ForeignTable: &ObjectName{Idents: []string{"shop", "pairs"}}, ForeignColumns: []string{"a", "b"}},
+			},
+		}},
+	}
+
+	meta := MYDatabaseToMetaDatabaseWithOptions(d, MYConvertOptions{InlineForeignKeys: true})
+	cols := columnsFromElements(meta.Tables[0].Elements)
+	if len(cols["user_id"].Constraints) != 1 {
+		t.Fatalf("Expected an inline foreign key on user_id, got %v", cols["user_id"])
+	}
+	con := cols["user_id"].Constraints[0]
+	ref := con.GetSpec().GetReferenceItem()
+	if con.Name != "fk_user" || objectNameKey(ref.GetTableName()) != "shop.users" || !slices.Equal(ref.Columns, []string{"id"}) {
+		t.Errorf("Unexpected inline foreign key %v", con)
+	}
+	var tableFKs []string
+	for _, elem := range meta.Tables[0].Elements {
+		if tc := elem.GetTableConstraintElement(); tc.GetSpec().GetReferenceItem() != nil {
+			tableFKs = append(tableFKs, tc.Name)
+		}
+	}
+	if !slices.Equal(tableFKs, []string{"fk_pair"}) {
+		t.Errorf("Expected only the two-column key as a table constraint, got %v", tableFKs)
+	}
+
+	// Both forms describe the same schema
+	if changes := DiffDatabase(MYDatabaseToMetaDatabase(d), meta); len(changes) != 0 {
+		t.Errorf("Expected no changes between inline and table foreign keys, got %v", changes)
+	}
+}
+
+func TestViewToMetaView_SecurityOptions(t *testing.T) {
+	pg := PGViewToMetaView(&PGView{
+		Name:            &ObjectName{Idents: []string{"public", "active_users"}},
+		Definition:      "SELECT id FROM users",
+		CheckOption:     "local",
+		SecurityBarrier: true,
+	})
+	if pg.Options["CheckOption"] != "LOCAL" || pg.Options["SecurityBarrier"] != "true" {
+		t.Errorf("Unexpected Postgres view options %v", pg.Options)
+	}
+
+	my := MYViewToMetaView(&MYView{Name: &ObjectName{Idents: []string{"shop", "v"}}, CheckOption: "NONE", SecurityType: "INVOKER"})
+	if len(my.Options) != 1 || my.Options["SqlSecurity"] != "INVOKER" {
+		t.Errorf("Unexpected MySQL view options %v", my.Options)
+	}
+	if definer := MYViewToMetaView(&MYView{SecurityType: "DEFINER"}); len(definer.Options) != 0 {
+		t.Errorf("Expected the default DEFINER to be left out, got %v", definer.Options)
+	}
+}
+
+func TestMYTableToMetaTable_Indexes(t *testing.T) {
+	meta := MYTableToMetaTable(&MYTable{
+		Name:    &ObjectName{Idents: []string{"shop", "articles"}},
+		Columns: []*MYColumn{{Name: "id"}, {Name: "body", IsNullable: true}, {Name: "area", IsNullable: true}},
+		Indexes: []*MYIndex{
+			{Name: "PRIMARY", IsUnique: true, IndexType: "BTREE", Columns: []string{"id"}},
+			{Name: "ft_body", IndexType: "FULLTEXT", Columns: []string{"body"}},
+			{Name: "sp_area", IndexType: "SPATIAL", Columns: []string{"area"}},
+			{Name: "idx_body", IndexType: "BTREE", Columns: []string{"body"}, IndexComment: "lookup"},
+		},
+	})
+
+	if !hasTablePrimaryKey(meta.Elements) {
+		t.Error("Expected the primary key as a constraint")
+	}
+	if len(meta.Indexes) != 3 {
+		t.Fatalf("Expected three plain indexes, got %v", meta.Indexes)
+	}
+	for i, want := range []string{"FULLTEXT", "SPATIAL", "BTREE"} {
+		if idx := meta.Indexes[i]; idx.Method != want || idx.IsUnique {
+			t.Errorf("Index %s: expected non-unique %s, got %v", idx.Name, want, idx)
+		}
+	}
+	if meta.Indexes[2].Comment != "lookup" {
+		t.Errorf("Expected the index comment, got %q", meta.Indexes[2].Comment)
+	}
+}
+
+func TestMYTableToMetaTable_TableOptions(t *testing.T) {
+	meta := MYTableToMetaTable(&MYTable{
+		Name:          &ObjectName{Idents: []string{"shop", "events"}},
+		Engine:        "InnoDB",
+		RowFormat:     "Compressed",
+		KeyBlockSize:  8,
+		CreateOptions: "row_format=COMPRESSED key_block_size=8 partitioned",
+		Columns:       []*MYColumn{{Name: "id", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}}},
+		Partitioning: &MYPartitioning{
+			Method:     "RANGE",
+			Expression: "`id`",
+			Partitions: []*MYPartition{{Name: "p0", Description: "1000"}, {Name: "pmax", Description: "MAXVALUE"}},
+		},
+	})
+	want := map[string]string{
+		"Engine":               "InnoDB",
+		"RowFormat":            "COMPRESSED",
+		"KeyBlockSize":         "8",
+		"CreateOptions":        "row_format=COMPRESSED key_block_size=8 partitioned",
+		"PartitionStrategy":    "RANGE",
+		"PartitionKey":         "`id`",
+		"PartitionDefinitions": "PARTITION p0 VALUES LESS THAN (1000), PARTITION pmax VALUES LESS THAN MAXVALUE",
+	}
+	if !mapsEqual(meta.Options, want) {
+		t.Errorf("Expected options %v, got %v", want, meta.Options)
+	}
+
+	stmts, err := GenerateSQL(AddTable{Table: meta}, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || !strings.HasSuffix(stmts[0], ") ENGINE=InnoDB ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 PARTITION BY RANGE (`id`) "+
+		"(PARTITION p0 VALUES LESS THAN (1000), PARTITION pmax VALUES LESS THAN MAXVALUE)") {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	hashed := myPartitionDefinitions(&MYPartitioning{Method: "HASH", Partitions: []*MYPartition{{Name: "p0"}, {Name: "p1"}}})
+	if hashed != "PARTITION p0, PARTITION p1" {
+		t.Errorf("Unexpected HASH partitions %q", hashed)
+	}
+}
+
+func TestDiffDatabase_MySQLRowFormat(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL("CREATE TABLE logs (id INT) ENGINE=InnoDB ROW_FORMAT=DYNAMIC", DialectMySQL)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL("CREATE TABLE logs (id INT) ENGINE=InnoDB ROW_FORMAT=compressed KEY_BLOCK_SIZE=4", DialectMySQL)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	changes := DiffDatabase(current, desired)
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v", changes)
+	}
+	if _, ok := changes[0].(AlterTableOptions); !ok {
+		t.Fatalf("Expected AlterTableOptions, got %T", changes[0])
+	}
+	stmts, err := GenerateSQL(changes[0], DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `logs` ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=4" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+}
@@ -0,0 +1,1404 @@
+package xmeta
+
+// ddl.go renders SchemaChanges into dialect-specific DDL statements.
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DDLOptions controls GenerateSQLWithOptions.
+type DDLOptions struct {
+	// IfExistsGuards adds IF EXISTS to drops and IF NOT EXISTS to creations
+	// where the dialect supports the guard for that object, so a migration
+	// can be run more than once. Guards the dialect lacks (e.g. on MySQL
+	// columns and indexes) are omitted.
+	IfExistsGuards bool
+	// CommentOutDestructive renders the statements of destructive changes
+	// (DropTable, DropColumn, AlterColumn, ...) as SQL comments
+	// under a header naming the change, so a migration file keeps them for
+	// manual review without running them.
+	CommentOutDestructive bool
+	// QualifyNames renders every table, view and type name schema-qualified,
+	// putting bare names in DefaultSchema. It has no effect without
+	// DefaultSchema.
+	QualifyNames bool
+	// DefaultSchema is the schema the migration runs in, e.g. the first
+	// entry of the Postgres search_path. Unless QualifyNames is set, names
+	// in it are rendered bare and names in other schemas stay qualified, so
+	// a script runs unchanged under that search_path.
+	DefaultSchema string
+}
+
+// guard returns clause, with a leading space, when guards are requested
+// and the dialect supports them for the kind of object.
+func (o DDLOptions) guard(clause string, dialect Dialect, kind string) string {
+	if !o.IfExistsGuards {
+		return ""
+	}
+	supported := false
+	switch kind {
+	case "schema":
+		supported = dialect != DialectSQLite
+	case "table", "view", "trigger":
+		supported = true
+	case "column":
+		supported = dialect == DialectPostgres || dialect == DialectBigQuery
+	case "index":
+		supported = dialect == DialectPostgres || dialect == DialectSQLite
+	case "constraint":
+		supported = dialect == DialectPostgres
+	}
+	if !supported {
+		return ""
+	}
+	return " " + clause
+}
+
+// GenerateSQL renders a single schema change into the SQL statements needed
+// to apply it in the given dialect. A change may produce zero statements when
+// it has no DDL equivalent in the dialect (e.g. a comment-only change).
+func GenerateSQL(change SchemaChange, dialect Dialect) ([]string, error) {
+	return GenerateSQLWithOptions(change, dialect, DDLOptions{})
+}
+
+// GenerateSQLWithOptions is GenerateSQL with explicit options.
+func GenerateSQLWithOptions(change SchemaChange, dialect Dialect, opts DDLOptions) ([]string, error) {
+	stmts, err := generateSQL(opts.qualifyChange(change), dialect, opts)
+	if err != nil || !opts.CommentOutDestructive || !change.IsDestructive() || len(stmts) == 0 {
+		return stmts, err
+	}
+	return []string{commentOut(change, stmts)}, nil
+}
+
+// commentOut turns the statements of a destructive change into a single
+// comment block. The caller's statement terminator ends up inside the
+// last comment line.
+func commentOut(change SchemaChange, stmts []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- DESTRUCTIVE, commented out for manual review: %s\n", DescribeChange(change))
+	for i, stmt := range stmts {
+		if i > 0 {
+			b.WriteString(";\n")
+		}
+		lines := strings.Split(stmt, "\n")
+		for j, line := range lines {
+			b.WriteString("-- " + line)
+			if j < len(lines)-1 {
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func generateSQL(change SchemaChange, dialect Dialect, opts DDLOptions) ([]string, error) {
+	switch c := change.(type) {
+	case AlterDatabase:
+		return alterDatabaseSQL(c, dialect)
+	case AddSchema:
+		if dialect == DialectSQLite {
+			return nil, fmt.Errorf("schemas are not supported by %s", dialect)
+		}
+		return []string{"CREATE SCHEMA" + opts.guard("IF NOT EXISTS", dialect, "schema") + " " + quoteObjectName(c.SchemaName, dialect)}, nil
+	case DropSchema:
+		if dialect == DialectSQLite {
+			return nil, fmt.Errorf("schemas are not supported by %s", dialect)
+		}
+		return []string{"DROP SCHEMA" + opts.guard("IF EXISTS", dialect, "schema") + " " + quoteObjectName(c.SchemaName, dialect)}, nil
+	case AddTable:
+		return addTableSQL(c, dialect, opts)
+	case DropTable:
+		return []string{"DROP TABLE" + opts.guard("IF EXISTS", dialect, "table") + " " + quoteObjectName(c.TableName, dialect)}, nil
+	case RenameTable:
+		return renameTableSQL(c, dialect)
+	case AlterTableOptions:
+		if c.IsView {
+			return alterViewOptionsSQL(c, dialect)
+		}
+		return alterTableOptionsSQL(c, dialect)
+	case AlterTags:
+		return alterTagsSQL(c, dialect), nil
+	case AddColumn:
+		def, err := columnDefSQL(c.Column, dialect, true)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", c.Column.GetName(), err)
+		}
+		stmts := []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN%s %s%s", quoteObjectName(c.TableName, dialect),
+			opts.guard("IF NOT EXISTS", dialect, "column"), def, columnPositionSQL(c.After, c.First, dialect))}
+		if c.Column.Comment != "" && dialect == DialectPostgres {
+			stmts = append(stmts, pgCommentSQL("COLUMN", columnTarget(c.TableName, c.Column.Name), c.Column.Comment))
+		}
+		return stmts, nil
+	case DropColumn:
+		return []string{fmt.Sprintf("ALTER TABLE %s DROP COLUMN%s %s", quoteObjectName(c.TableName, dialect),
+			opts.guard("IF EXISTS", dialect, "column"), quoteIdent(c.ColumnName, dialect))}, nil
+	case AlterColumn:
+		return alterColumnSQL(c, dialect)
+	case SetColumnDefault:
+		seq, err := serialSequenceSQL(c.TableName, c.Column, dialect)
+		if err != nil {
+			return nil, err
+		}
+		stmts, err := columnDefaultSQL(c.TableName, c.Column, "SET DEFAULT "+c.Default, dialect)
+		if seq != "" && err == nil {
+			stmts = append([]string{seq}, stmts...)
+		}
+		return stmts, err
+	case DropColumnDefault:
+		return columnDefaultSQL(c.TableName, c.Column, "DROP DEFAULT", dialect)
+	case SetColumnNullability:
+		return columnNullabilitySQL(c, dialect)
+	case AlterColumnPosition:
+		if dialect != DialectMySQL {
+			return nil, fmt.Errorf("reordering column %s is not supported by %s", c.Column.GetName(), dialect)
+		}
+		def, err := columnDefSQL(c.Column, dialect, false)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", c.Column.GetName(), err)
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s%s", quoteObjectName(c.TableName, dialect), def,
+			columnPositionSQL(c.After, c.First, dialect))}, nil
+	case AddConstraint:
+		if dialect == DialectSQLite {
+			return nil, fmt.Errorf("adding constraints to an existing table is not supported by %s", dialect)
+		}
+		def, err := tableConstraintSQL(c.Constraint, dialect)
+		if err != nil {
+			return nil, fmt.Errorf("constraint %s: %w", c.Constraint.GetName(), err)
+		}
+		if c.NotValid && dialect == DialectPostgres && supportsNotValid(c.Constraint) {
+			def += " NOT VALID"
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s ADD %s", quoteObjectName(c.TableName, dialect), def)}, nil
+	case DropConstraint:
+		return dropConstraintSQL(c, dialect, opts)
+	case AlterConstraint:
+		return alterConstraintSQL(c, dialect)
+	case ValidateConstraint:
+		if dialect != DialectPostgres {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", quoteObjectName(c.TableName, dialect), quoteIdent(c.ConstraintName, dialect))}, nil
+	case AddIndex:
+		stmt, err := createIndexSQL(c.TableName, c.Index, dialect, opts)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", c.Index.GetName(), err)
+		}
+		if c.Index.Comment != "" && dialect == DialectPostgres {
+			return []string{stmt, pgCommentSQL("INDEX", quoteObjectName(indexObjectName(c.TableName, c.Index.Name), dialect), c.Index.Comment)}, nil
+		}
+		return []string{stmt}, nil
+	case DropIndex:
+		return dropIndexSQL(c, dialect, opts)
+	case AddView:
+		stmt, err := createViewSQL(c.View, dialect)
+		if err != nil {
+			return nil, err
+		}
+		return []string{stmt}, nil
+	case DropView:
+		kind := "VIEW"
+		if c.Materialized && dialect == DialectPostgres {
+			kind = "MATERIALIZED VIEW"
+		}
+		return []string{"DROP " + kind + opts.guard("IF EXISTS", dialect, "view") + " " + quoteObjectName(c.ViewName, dialect)}, nil
+	case AddTrigger:
+		if dialect == DialectBigQuery {
+			return nil, fmt.Errorf("triggers are not supported by %s", dialect)
+		}
+		def := strings.TrimSuffix(strings.TrimSpace(c.Trigger.GetDefinition()), ";")
+		if def == "" {
+			return nil, fmt.Errorf("trigger %s has no definition", c.Trigger.GetName())
+		}
+		return []string{def}, nil
+	case DropTrigger:
+		return dropTriggerSQL(c, dialect, opts)
+	}
+	return nil, fmt.Errorf("unsupported schema change %T", change)
+}
+
+// =============================================================================
+// Table Statements
+// =============================================================================
+
+func addTableSQL(c AddTable, dialect Dialect, opts DDLOptions) ([]string, error) {
+	t := c.Table
+	if t == nil {
+		return nil, fmt.Errorf("AddTable without table")
+	}
+	if (t.Options["PartitionOf"] != "" || t.Options["PartitionStrategy"] != "" && dialect != DialectMySQL) && dialect != DialectPostgres {
+		return nil, fmt.Errorf("partitioned table %s is not supported by %s", formatObjectName(t.Name), dialect)
+	}
+	if t.Options["InheritsFrom"] != "" && dialect != DialectPostgres {
+		return nil, fmt.Errorf("table inheritance of %s is not supported by %s", formatObjectName(t.Name), dialect)
+	}
+	// MySQL parses but ignores inline REFERENCES
+	if dialect == DialectMySQL {
+		t = canonicalForeignKeys(t, nil)
+	}
+	var tablespace string
+	if ts := t.Options["Tablespace"]; ts != "" && dialect == DialectPostgres {
+		tablespace = " TABLESPACE " + quoteIdent(ts, dialect)
+	}
+	// A partition takes its columns, constraints and indexes from its parent
+	if parent := t.Options["PartitionOf"]; parent != "" {
+		return []string{fmt.Sprintf("CREATE TABLE%s %s PARTITION OF %s %s%s", opts.guard("IF NOT EXISTS", dialect, "table"),
+			quoteObjectName(t.Name, dialect), quoteObjectName(&ObjectName{Idents: strings.Split(parent, ".")}, dialect),
+			t.Options["PartitionBound"], tablespace)}, nil
+	}
+
+	// A table-level primary key takes precedence over inline column flags;
+	// several inline flags are folded into one table-level primary key.
+	tablePK := hasTablePrimaryKey(t.Elements)
+	inlinePK := primaryKeyColumns(t.Elements)
+	inline := !tablePK && len(inlinePK) == 1
+
+	var defs []string
+	for _, elem := range t.Elements {
+		if col := elem.GetColumnDefElement(); col != nil {
+			def, err := columnDefSQL(col, dialect, inline)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", col.Name, err)
+			}
+			defs = append(defs, def)
+		}
+	}
+	if !tablePK && len(inlinePK) > 1 {
+		defs = append(defs, "PRIMARY KEY ("+quoteIdents(inlinePK, dialect)+")")
+	}
+	for _, elem := range t.Elements {
+		if tc := elem.GetTableConstraintElement(); tc != nil {
+			def, err := tableConstraintSQL(tc, dialect)
+			if err != nil {
+				return nil, fmt.Errorf("constraint %s: %w", tc.Name, err)
+			}
+			defs = append(defs, def)
+		}
+	}
+
+	stmt := fmt.Sprintf("CREATE TABLE%s %s (\n  %s\n)", opts.guard("IF NOT EXISTS", dialect, "table"),
+		quoteObjectName(t.Name, dialect), strings.Join(defs, ",\n  "))
+	switch dialect {
+	case DialectPostgres:
+		if parents := optionList(t.Options["InheritsFrom"]); len(parents) > 0 {
+			stmt += " INHERITS (" + quoteObjectNames(parents, dialect) + ")"
+		}
+		if strategy := t.Options["PartitionStrategy"]; strategy != "" {
+			stmt += fmt.Sprintf(" PARTITION BY %s (%s)", strategy, t.Options["PartitionKey"])
+		}
+		stmt += tablespace
+	case DialectMySQL:
+		if opts := mysqlTableOptionsSQL(t.Options); opts != "" {
+			stmt += " " + opts
+		}
+		if t.Comment != "" {
+			stmt += " COMMENT=" + quoteDialectString(t.Comment, dialect)
+		}
+		if strategy := t.Options["PartitionStrategy"]; strategy != "" {
+			stmt += fmt.Sprintf(" PARTITION BY %s (%s)", strategy, t.Options["PartitionKey"])
+			if defs := t.Options["PartitionDefinitions"]; defs != "" {
+				stmt += " (" + defs + ")"
+			}
+		}
+	case DialectSQLite:
+		if opts := sqliteTableOptionsSQL(t.Options); opts != "" {
+			stmt += " " + opts
+		}
+	case DialectBigQuery:
+		if t.Comment != "" {
+			stmt += " OPTIONS (description = " + quoteDialectString(t.Comment, dialect) + ")"
+		}
+	}
+
+	stmts := []string{stmt}
+	if dialect == DialectPostgres {
+		if t.Comment != "" {
+			stmts = append(stmts, pgCommentSQL("TABLE", quoteObjectName(t.Name, dialect), t.Comment))
+		}
+		for _, col := range orderedColumns(t.Elements) {
+			if col.Comment != "" {
+				stmts = append(stmts, pgCommentSQL("COLUMN", columnTarget(t.Name, col.Name), col.Comment))
+			}
+		}
+	}
+	for _, idx := range t.Indexes {
+		idxStmt, err := createIndexSQL(t.Name, idx, dialect, opts)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", idx.Name, err)
+		}
+		stmts = append(stmts, idxStmt)
+		if idx.Comment != "" && dialect == DialectPostgres {
+			stmts = append(stmts, pgCommentSQL("INDEX", quoteObjectName(indexObjectName(t.Name, idx.Name), dialect), idx.Comment))
+		}
+	}
+	return stmts, nil
+}
+
+// renameTableSQL renames a table within its schema. Only MySQL takes a
+// qualified new name.
+func renameTableSQL(c RenameTable, dialect Dialect) ([]string, error) {
+	newName := simpleNameKey(c.NewName)
+	if newName == "" {
+		return nil, fmt.Errorf("RenameTable without new name")
+	}
+	if dialect == DialectMySQL {
+		return []string{fmt.Sprintf("RENAME TABLE %s TO %s", quoteObjectName(c.OldName, dialect), quoteObjectName(c.NewName, dialect))}, nil
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", quoteObjectName(c.OldName, dialect), quoteIdent(newName, dialect))}, nil
+}
+
+func alterTableOptionsSQL(c AlterTableOptions, dialect Dialect) ([]string, error) {
+	commentChanged := c.OldComment != c.NewComment
+	switch dialect {
+	case DialectPostgres:
+		stmts := pgAlterTableOptionsSQL(c)
+		if commentChanged {
+			stmts = append(stmts, pgCommentSQL("TABLE", quoteObjectName(c.TableName, dialect), c.NewComment))
+		}
+		return stmts, nil
+	case DialectSQLite:
+		if sqliteTableOptionsSQL(c.OldOptions) != sqliteTableOptionsSQL(c.NewOptions) {
+			return nil, fmt.Errorf("changing STRICT or WITHOUT ROWID of an existing table is not supported by %s", dialect)
+		}
+		return nil, nil
+	case DialectBigQuery:
+		if !commentChanged {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s SET OPTIONS (description = %s)",
+			quoteObjectName(c.TableName, dialect), bqDescriptionSQL(c.NewComment))}, nil
+	case DialectMySQL:
+	default:
+		return nil, nil
+	}
+	changed := make(map[string]string)
+	for _, key := range []string{"Engine", "Charset", "Collation", "RowFormat", "KeyBlockSize"} {
+		if v := c.NewOptions[key]; v != "" && v != c.OldOptions[key] {
+			changed[key] = v
+		}
+	}
+	opts := mysqlTableOptionsSQL(changed)
+	if commentChanged {
+		// An empty comment removes it
+		opts = strings.TrimSpace(opts + " COMMENT=" + quoteDialectString(c.NewComment, dialect))
+	}
+	if opts == "" {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s %s", quoteObjectName(c.TableName, dialect), opts)}, nil
+}
+
+// alterDatabaseSQL renames a Postgres database, or changes the default
+// charset and collation of a MySQL one. Other database options have no DDL.
+func alterDatabaseSQL(c AlterDatabase, dialect Dialect) ([]string, error) {
+	var stmts []string
+	if c.OldName != c.NewName {
+		if dialect != DialectPostgres {
+			return nil, fmt.Errorf("renaming database %s is not supported by %s", c.OldName, dialect)
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s RENAME TO %s", quoteIdent(c.OldName, dialect), quoteIdent(c.NewName, dialect)))
+	}
+
+	var clauses []string
+	for _, key := range changedDatabaseOptions(c.OldOptions, c.NewOptions) {
+		if key != "Charset" && key != "Collation" {
+			continue
+		}
+		if dialect != DialectMySQL {
+			return nil, fmt.Errorf("changing the default %s of database %s is not supported by %s", strings.ToLower(key), c.NewName, dialect)
+		}
+		if key == "Charset" {
+			clauses = append(clauses, "CHARACTER SET "+c.NewOptions[key])
+		} else {
+			clauses = append(clauses, "COLLATE "+c.NewOptions[key])
+		}
+	}
+	if len(clauses) > 0 {
+		stmts = append(stmts, fmt.Sprintf("ALTER DATABASE %s %s", quoteIdent(c.NewName, dialect), strings.Join(clauses, " ")))
+	}
+	return stmts, nil
+}
+
+// alterViewOptionsSQL changes the check option and security settings of a
+// view. Postgres sets and resets them as view options; MySQL restates the
+// view with ALTER VIEW, which needs its definition.
+func alterViewOptionsSQL(c AlterTableOptions, dialect Dialect) ([]string, error) {
+	view := quoteObjectName(c.TableName, dialect)
+	switch dialect {
+	case DialectPostgres:
+		var set, reset []string
+		for _, opt := range []struct{ key, name string }{{"CheckOption", "check_option"}, {"SecurityBarrier", "security_barrier"}} {
+			oldV, newV := c.OldOptions[opt.key], c.NewOptions[opt.key]
+			switch {
+			case oldV == newV:
+			case newV == "":
+				reset = append(reset, opt.name)
+			default:
+				set = append(set, opt.name+"="+strings.ToLower(newV))
+			}
+		}
+		var stmts []string
+		if len(set) > 0 {
+			stmts = append(stmts, fmt.Sprintf("ALTER VIEW %s SET (%s)", view, strings.Join(set, ", ")))
+		}
+		if len(reset) > 0 {
+			stmts = append(stmts, fmt.Sprintf("ALTER VIEW %s RESET (%s)", view, strings.Join(reset, ", ")))
+		}
+		if c.OldComment != c.NewComment {
+			kind := "VIEW"
+			if c.NewOptions["Materialized"] == "true" {
+				kind = "MATERIALIZED VIEW"
+			}
+			stmts = append(stmts, pgCommentSQL(kind, view, c.NewComment))
+		}
+		return stmts, nil
+	case DialectMySQL:
+		if c.OldOptions["CheckOption"] == c.NewOptions["CheckOption"] && c.OldOptions["SqlSecurity"] == c.NewOptions["SqlSecurity"] {
+			return nil, nil
+		}
+		if c.ViewDefinition == "" {
+			return nil, fmt.Errorf("altering view %s needs its definition", formatObjectName(c.TableName))
+		}
+		return []string{"ALTER" + viewClausesSQL(view, c.ViewDefinition, c.NewOptions, dialect)}, nil
+	case DialectBigQuery:
+		if c.OldComment == c.NewComment {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("ALTER VIEW %s SET OPTIONS (description = %s)", view, bqDescriptionSQL(c.NewComment))}, nil
+	}
+	return nil, nil
+}
+
+// createViewSQL renders CREATE VIEW for v with its check option and
+// security settings.
+func createViewSQL(v *MetaView, dialect Dialect) (string, error) {
+	if v.GetDefinition() == "" {
+		return "", fmt.Errorf("view %s has no definition", formatObjectName(v.GetName()))
+	}
+	view := quoteObjectName(v.Name, dialect)
+	if dialect == DialectPostgres && v.Options["Materialized"] == "true" {
+		return fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s", view, v.Definition), nil
+	}
+	return "CREATE" + viewClausesSQL(view, v.Definition, v.Options, dialect), nil
+}
+
+// viewClausesSQL renders a view statement after its CREATE or ALTER keyword.
+func viewClausesSQL(view, definition string, options map[string]string, dialect Dialect) string {
+	var b strings.Builder
+	if v := options["SqlSecurity"]; v != "" && dialect == DialectMySQL {
+		b.WriteString(" SQL SECURITY " + strings.ToUpper(v))
+	}
+	b.WriteString(" VIEW " + view)
+	if options["SecurityBarrier"] == "true" && dialect == DialectPostgres {
+		b.WriteString(" WITH (security_barrier)")
+	}
+	b.WriteString(" AS " + definition)
+	if v := options["CheckOption"]; v != "" && dialect != DialectSQLite && dialect != DialectBigQuery {
+		b.WriteString(" WITH " + strings.ToUpper(v) + " CHECK OPTION")
+	}
+	return b.String()
+}
+
+// pgAlterTableOptionsSQL moves a table to its new tablespace, the default
+// one when none is set, and attaches or detaches INHERITS parents.
+func pgAlterTableOptionsSQL(c AlterTableOptions) []string {
+	table := quoteObjectName(c.TableName, DialectPostgres)
+	var stmts []string
+	if oldTS, newTS := c.OldOptions["Tablespace"], c.NewOptions["Tablespace"]; oldTS != newTS {
+		if newTS == "" {
+			newTS = "pg_default"
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SET TABLESPACE %s", table, quoteIdent(newTS, DialectPostgres)))
+	}
+	oldParents, newParents := optionList(c.OldOptions["InheritsFrom"]), optionList(c.NewOptions["InheritsFrom"])
+	for _, parent := range oldParents {
+		if !slices.Contains(newParents, parent) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s NO INHERIT %s", table, quoteObjectNames([]string{parent}, DialectPostgres)))
+		}
+	}
+	for _, parent := range newParents {
+		if !slices.Contains(oldParents, parent) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s INHERIT %s", table, quoteObjectNames([]string{parent}, DialectPostgres)))
+		}
+	}
+	return stmts
+}
+
+// alterTagsSQL sets the labels of a BigQuery table to its new tags. Column
+// tags, and tags in other dialects, exist only in the model.
+func alterTagsSQL(c AlterTags, dialect Dialect) []string {
+	if dialect != DialectBigQuery || c.ColumnName != "" {
+		return nil
+	}
+	labels := "NULL"
+	if len(c.NewTags) > 0 {
+		var pairs []string
+		for _, k := range slices.Sorted(maps.Keys(c.NewTags)) {
+			pairs = append(pairs, fmt.Sprintf("(%s, %s)", quoteString(k), quoteString(c.NewTags[k])))
+		}
+		labels = "[" + strings.Join(pairs, ", ") + "]"
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s SET OPTIONS (labels = %s)", quoteObjectName(c.TableName, dialect), labels)}
+}
+
+// pgCommentSQL renders COMMENT ON for a Postgres object of the given kind,
+// e.g. "TABLE" or "COLUMN"; an empty comment removes it.
+func pgCommentSQL(kind, target, comment string) string {
+	text := "NULL"
+	if comment != "" {
+		text = quoteDialectString(comment, DialectPostgres)
+	}
+	return fmt.Sprintf("COMMENT ON %s %s IS %s", kind, target, text)
+}
+
+// columnTarget renders the qualified name of a column of table.
+func columnTarget(table *ObjectName, column string) string {
+	return quoteObjectName(&ObjectName{Idents: append(slices.Clone(table.GetIdents()), column)}, DialectPostgres)
+}
+
+// bqDescriptionSQL renders a BigQuery description option value; NULL
+// removes the description.
+func bqDescriptionSQL(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return quoteDialectString(comment, DialectBigQuery)
+}
+
+// optionList splits a comma-separated option value such as InheritsFrom.
+func optionList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// quoteObjectNames quotes dotted table names and joins them with commas.
+func quoteObjectNames(names []string, dialect Dialect) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteObjectName(&ObjectName{Idents: strings.Split(name, ".")}, dialect)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sqliteTableOptionsSQL renders the SQLite table options carried in
+// MetaTable.Options, e.g. "WITHOUT ROWID, STRICT".
+func sqliteTableOptionsSQL(options map[string]string) string {
+	var parts []string
+	if options["WithoutRowID"] == "true" {
+		parts = append(parts, "WITHOUT ROWID")
+	}
+	if options["Strict"] == "true" {
+		parts = append(parts, "STRICT")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// mysqlTableOptionsSQL renders the MySQL table options carried in MetaTable.Options.
+func mysqlTableOptionsSQL(options map[string]string) string {
+	var parts []string
+	if v := options["Engine"]; v != "" {
+		parts = append(parts, "ENGINE="+v)
+	}
+	if v := options["Charset"]; v != "" {
+		parts = append(parts, "DEFAULT CHARSET="+v)
+	}
+	if v := options["Collation"]; v != "" {
+		parts = append(parts, "COLLATE="+v)
+	}
+	if v := options["RowFormat"]; v != "" {
+		parts = append(parts, "ROW_FORMAT="+v)
+	}
+	if v := options["KeyBlockSize"]; v != "" {
+		parts = append(parts, "KEY_BLOCK_SIZE="+v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// =============================================================================
+// Column Statements
+// =============================================================================
+
+// columnDefSQL renders a column definition. Inline PRIMARY KEY constraints
+// are only emitted when inlinePK is true.
+func columnDefSQL(col *ColumnDef, dialect Dialect, inlinePK bool) (string, error) {
+	if col == nil {
+		return "", fmt.Errorf("missing column definition")
+	}
+	typ, err := dataTypeSQL(col.DataType, dialect)
+	if err != nil {
+		return "", err
+	}
+
+	if dialect == DialectBigQuery && col.Options["IsGenerated"] == "true" {
+		return "", fmt.Errorf("generated columns are not supported by %s", dialect)
+	}
+
+	// A Postgres serial column is declared as such, which creates the
+	// sequence its nextval default draws from
+	def := anyToString(col.Default)
+	if serial := pgSerialType(col, dialect); serial != "" {
+		typ, def = serial, ""
+	}
+
+	parts := []string{quoteIdent(col.Name, dialect), typ}
+	if clause := collationSQL(col, dialect); clause != "" {
+		parts = append(parts, clause)
+	}
+	if clause := generatedSQL(col, dialect); clause != "" {
+		parts = append(parts, clause)
+	}
+	if isNotNull(col) {
+		parts = append(parts, "NOT NULL")
+	}
+	if def != "" {
+		parts = append(parts, "DEFAULT "+def)
+	}
+	if dialect == DialectMySQL && hasAutoIncrement(col) {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+
+	for _, con := range col.Constraints {
+		spec := con.GetSpec()
+		switch {
+		case spec.GetUniqueItem() != nil:
+			if !spec.GetUniqueItem().IsPrimaryKey {
+				parts = append(parts, "UNIQUE")
+			} else if inlinePK {
+				parts = append(parts, "PRIMARY KEY")
+				if dialect == DialectSQLite && hasAutoIncrement(col) {
+					parts = append(parts, "AUTOINCREMENT")
+				}
+			}
+		case spec.GetCheckItem() != nil:
+			if con.Name != "" {
+				parts = append(parts, "CONSTRAINT "+quoteIdent(con.Name, dialect))
+			}
+			parts = append(parts, checkSQL(anyToString(spec.GetCheckItem())))
+		case spec.GetReferenceItem() != nil:
+			if con.Name != "" {
+				parts = append(parts, "CONSTRAINT "+quoteIdent(con.Name, dialect))
+			}
+			ref := spec.GetReferenceItem()
+			clause := fmt.Sprintf("REFERENCES %s", quoteObjectName(ref.TableName, dialect))
+			if len(ref.Columns) > 0 {
+				clause += " (" + quoteIdents(ref.Columns, dialect) + ")"
+			}
+			clause += referenceActionsSQL(ref.Match, ref.OnDelete, ref.OnUpdate, ref.Deferrable, ref.InitiallyDeferred)
+			parts = append(parts, clause)
+		}
+	}
+
+	// Postgres comments are separate COMMENT ON statements
+	if col.Comment != "" {
+		switch dialect {
+		case DialectMySQL:
+			parts = append(parts, "COMMENT "+quoteDialectString(col.Comment, dialect))
+		case DialectBigQuery:
+			parts = append(parts, "OPTIONS (description = "+quoteDialectString(col.Comment, dialect)+")")
+		}
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// pgSerialType returns the serial pseudo-type col was declared with, in
+// upper case, if it is a Postgres column still defaulting to nextval.
+func pgSerialType(col *ColumnDef, dialect Dialect) string {
+	serial := col.Options["Serial"]
+	if dialect != DialectPostgres || serial == "" || !strings.HasPrefix(strings.ToLower(anyToString(col.Default)), "nextval(") {
+		return ""
+	}
+	return strings.ToUpper(serial)
+}
+
+// serialSequenceSQL renders the CREATE SEQUENCE an existing Postgres column
+// of table needs before it can default to its serial sequence, or "" if
+// col is not serial.
+func serialSequenceSQL(table *ObjectName, col *ColumnDef, dialect Dialect) (string, error) {
+	if pgSerialType(col, dialect) == "" {
+		return "", nil
+	}
+	typ, err := dataTypeSQL(col.DataType, dialect)
+	if err != nil {
+		return "", fmt.Errorf("column %s: %w", col.Name, err)
+	}
+	seq := &ObjectName{Idents: strings.Split(pgSerialSequence(table, col.Name), ".")}
+	return fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s AS %s OWNED BY %s.%s", quoteObjectName(seq, dialect), typ,
+		quoteObjectName(table, dialect), quoteIdent(col.Name, dialect)), nil
+}
+
+// columnPositionSQL renders MySQL's FIRST / AFTER clause. Other dialects
+// always append columns, so it returns "" for them.
+func columnPositionSQL(after string, first bool, dialect Dialect) string {
+	if dialect != DialectMySQL {
+		return ""
+	}
+	if first {
+		return " FIRST"
+	}
+	if after != "" {
+		return " AFTER " + quoteIdent(after, dialect)
+	}
+	return ""
+}
+
+// collationSQL renders a column's character set and collation: both for
+// MySQL, the collation alone for Postgres and SQLite.
+func collationSQL(col *ColumnDef, dialect Dialect) string {
+	charset, collation := col.Options["Charset"], col.Options["Collation"]
+	var parts []string
+	switch dialect {
+	case DialectMySQL:
+		if charset != "" {
+			parts = append(parts, "CHARACTER SET "+charset)
+		}
+		if collation != "" {
+			parts = append(parts, "COLLATE "+collation)
+		}
+	case DialectPostgres, DialectSQLite:
+		if collation != "" {
+			parts = append(parts, "COLLATE "+quoteIdent(collation, dialect))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// generatedSQL renders the identity or generated-column clause recorded in
+// the column options, if any.
+func generatedSQL(col *ColumnDef, dialect Dialect) string {
+	if col.Options["IsIdentity"] == "true" && dialect == DialectPostgres {
+		generation := col.Options["IdentityGeneration"]
+		if generation == "" {
+			generation = "BY DEFAULT"
+		}
+		clause := "GENERATED " + generation + " AS IDENTITY"
+		var options []string
+		if start := col.Options["IdentityStart"]; start != "" {
+			options = append(options, "START WITH "+start)
+		}
+		if increment := col.Options["IdentityIncrement"]; increment != "" {
+			options = append(options, "INCREMENT BY "+increment)
+		}
+		if len(options) > 0 {
+			clause += " (" + strings.Join(options, " ") + ")"
+		}
+		return clause
+	}
+	if col.Options["IsGenerated"] != "true" || col.Options["GenerationExpression"] == "" {
+		return ""
+	}
+	clause := "GENERATED ALWAYS AS (" + col.Options["GenerationExpression"] + ")"
+	switch kind := col.Options["GenerationKind"]; {
+	case dialect == DialectPostgres:
+		clause += " STORED"
+	case kind != "":
+		clause += " " + kind
+	}
+	return clause
+}
+
+func alterColumnSQL(c AlterColumn, dialect Dialect) ([]string, error) {
+	oldCol, newCol := c.OldColumn, c.NewColumn
+	if oldCol == nil || newCol == nil {
+		return nil, fmt.Errorf("AlterColumn without old and new column")
+	}
+	table := quoteObjectName(c.TableName, dialect)
+	name := quoteIdent(newCol.Name, dialect)
+
+	// Postgres comments follow the ALTER TABLE, once the column has its
+	// new name
+	var stmts, clauses, comments []string
+	redefine := false
+	deltas := c.Deltas()
+	typeChanged := slices.ContainsFunc(deltas, func(d ColumnDelta) bool {
+		_, ok := d.(TypeChanged)
+		return ok
+	})
+
+	// A generation expression or kind cannot be changed in place, short
+	// of Postgres 17, so the column is dropped and re-added with its new
+	// definition, which covers the other deltas too. MySQL appends the
+	// re-added column. Postgres can turn a generated column into a plain
+	// one, keeping its values.
+	if i := slices.IndexFunc(deltas, func(d ColumnDelta) bool {
+		_, ok := d.(GenerationChanged)
+		return ok
+	}); i >= 0 && (dialect == DialectPostgres || dialect == DialectMySQL) {
+		if d := deltas[i].(GenerationChanged); dialect != DialectPostgres || d.NewExpression != "" {
+			def, err := columnDefSQL(newCol, dialect, false)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", newCol.Name, err)
+			}
+			return []string{
+				fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, quoteIdent(oldCol.Name, dialect)),
+				fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, def),
+			}, nil
+		}
+	}
+	for _, delta := range deltas {
+		switch d := delta.(type) {
+		case RenamedTo:
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s",
+				table, quoteIdent(oldCol.Name, dialect), name))
+		case TypeChanged:
+			redefine = true
+			typ, err := dataTypeSQL(d.New, dialect)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", newCol.Name, err)
+			}
+			if dialect == DialectBigQuery {
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET DATA TYPE %s", name, typ))
+			} else {
+				clauses = append(clauses, strings.TrimSpace(fmt.Sprintf("ALTER COLUMN %s TYPE %s %s", name, typ, collationSQL(newCol, dialect))))
+			}
+		case EnumLabelsAdded:
+			if dialect != DialectPostgres {
+				redefine = true
+				break
+			}
+			if d.TypeName == nil {
+				return nil, fmt.Errorf("column %s: inline ENUM types are not supported by %s", newCol.Name, dialect)
+			}
+			stmts = append(stmts, addEnumLabelsSQL(d, dialect)...)
+		case GenerationChanged:
+			// Only a Postgres generated column becoming plain, or SQLite,
+			// gets here
+			redefine = true
+			switch dialect {
+			case DialectPostgres:
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP EXPRESSION", name))
+			case DialectBigQuery:
+				return nil, fmt.Errorf("column %s: generated columns are not supported by %s", newCol.Name, dialect)
+			}
+		case OptionChanged:
+			// Only charset, collation and the identity sequence options
+			// have DDL; Postgres changes the collation by restating the type
+			switch {
+			case dialect == DialectPostgres && (d.Key == "IdentityStart" || d.Key == "IdentityIncrement"):
+				if newCol.Options["IsIdentity"] != "true" {
+					break
+				}
+				redefine = true
+				clauses = append(clauses, identityOptionSQL(name, d.Key, d.New, newCol))
+			case d.Key != "Charset" && d.Key != "Collation":
+			case dialect == DialectMySQL:
+				redefine = true
+			case dialect == DialectPostgres && d.Key == "Collation" && !typeChanged:
+				redefine = true
+				typ, err := dataTypeSQL(newCol.DataType, dialect)
+				if err != nil {
+					return nil, fmt.Errorf("column %s: %w", newCol.Name, err)
+				}
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s TYPE %s %s", name, typ, collationSQL(newCol, dialect)))
+			}
+		case CommentChanged:
+			switch dialect {
+			case DialectPostgres:
+				comments = append(comments, pgCommentSQL("COLUMN", columnTarget(c.TableName, newCol.Name), d.New))
+			case DialectMySQL:
+				redefine = true
+			case DialectBigQuery:
+				redefine = true
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET OPTIONS (description = %s)", name, bqDescriptionSQL(d.New)))
+			}
+		case DefaultChanged:
+			redefine = true
+			if seq, err := serialSequenceSQL(c.TableName, newCol, dialect); err != nil {
+				return nil, err
+			} else if seq != "" {
+				stmts = append(stmts, seq)
+			}
+			if d.New == "" {
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", name))
+			} else {
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", name, d.New))
+			}
+		case NullabilityChanged:
+			redefine = true
+			if d.NowNullable {
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", name))
+			} else {
+				if dialect == DialectBigQuery {
+					return nil, fmt.Errorf("adding NOT NULL to column %s is not supported by %s", newCol.Name, dialect)
+				}
+				clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", name))
+			}
+		}
+	}
+	if !redefine {
+		return append(stmts, comments...), nil
+	}
+
+	switch dialect {
+	case DialectMySQL:
+		def, err := columnDefSQL(newCol, dialect, false)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", newCol.Name, err)
+		}
+		return append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", table, def)), nil
+	case DialectSQLite:
+		return nil, fmt.Errorf("altering column %s is not supported by %s", newCol.Name, dialect)
+	case DialectBigQuery:
+		// BigQuery accepts only one action per ALTER TABLE statement
+		for _, clause := range clauses {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s %s", table, clause))
+		}
+		return stmts, nil
+	}
+	return append(append(stmts, fmt.Sprintf("ALTER TABLE %s %s", table, strings.Join(clauses, ", "))), comments...), nil
+}
+
+// identityOptionSQL renders the ALTER COLUMN action setting the identity
+// sequence option key to value, or back to its default when value is empty.
+func identityOptionSQL(name, key, value string, col *ColumnDef) string {
+	if key == "IdentityIncrement" {
+		if value == "" {
+			value = "1"
+		}
+		return fmt.Sprintf("ALTER COLUMN %s SET INCREMENT BY %s", name, value)
+	}
+	if value == "" {
+		increment, _ := strconv.ParseInt(col.Options["IdentityIncrement"], 10, 64)
+		value = strconv.FormatInt(identityDefaultStart(increment), 10)
+	}
+	return fmt.Sprintf("ALTER COLUMN %s SET START WITH %s", name, value)
+}
+
+// columnDefaultSQL renders the SET DEFAULT or DROP DEFAULT action on col.
+// MySQL restates the column to set a default, as its SET DEFAULT takes
+// only literals and parenthesized expressions.
+func columnDefaultSQL(tableName *ObjectName, col *ColumnDef, action string, dialect Dialect) ([]string, error) {
+	if col == nil {
+		return nil, fmt.Errorf("column default change without column")
+	}
+	table := quoteObjectName(tableName, dialect)
+	switch {
+	case dialect == DialectSQLite:
+		return nil, fmt.Errorf("altering column %s is not supported by %s", col.Name, dialect)
+	case dialect == DialectMySQL && action != "DROP DEFAULT":
+		def, err := columnDefSQL(col, dialect, false)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", table, def)}, nil
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", table, quoteIdent(col.Name, dialect), action)}, nil
+}
+
+// columnNullabilitySQL renders SET NOT NULL or DROP NOT NULL. MySQL
+// restates the column instead.
+func columnNullabilitySQL(c SetColumnNullability, dialect Dialect) ([]string, error) {
+	col := c.Column
+	if col == nil {
+		return nil, fmt.Errorf("SetColumnNullability without column")
+	}
+	table := quoteObjectName(c.TableName, dialect)
+	switch dialect {
+	case DialectSQLite:
+		return nil, fmt.Errorf("altering column %s is not supported by %s", col.Name, dialect)
+	case DialectMySQL:
+		def, err := columnDefSQL(columnWithNullability(col, c.Nullable), dialect, false)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", table, def)}, nil
+	case DialectBigQuery:
+		if !c.Nullable {
+			return nil, fmt.Errorf("adding NOT NULL to column %s is not supported by %s", col.Name, dialect)
+		}
+	}
+	action := "SET NOT NULL"
+	if c.Nullable {
+		action = "DROP NOT NULL"
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", table, quoteIdent(col.Name, dialect), action)}, nil
+}
+
+// columnWithDefault returns a copy of col with the default def, none if
+// def is empty.
+func columnWithDefault(col *ColumnDef, def string) *ColumnDef {
+	if col == nil {
+		return nil
+	}
+	col = proto.Clone(col).(*ColumnDef)
+	col.Default = stringToAny(def)
+	return col
+}
+
+// columnWithNullability returns col, or a copy of it with its NOT NULL
+// constraint added or removed so that it is nullable as requested.
+func columnWithNullability(col *ColumnDef, nullable bool) *ColumnDef {
+	if isNotNull(col) != nullable {
+		return col
+	}
+	col = proto.Clone(col).(*ColumnDef)
+	if nullable {
+		col.Constraints = slices.DeleteFunc(col.Constraints, func(con *ColumnConstraint) bool {
+			return con.GetSpec().GetNotNullItem() == NotNullColumnSpec_NotNullColumnSpecConfirm
+		})
+	} else {
+		col.Constraints = append(col.Constraints, &ColumnConstraint{Spec: &ColumnConstraintSpec{
+			ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm},
+		}})
+	}
+	return col
+}
+
+// addEnumLabelsSQL renders the ALTER TYPE statements adding the labels of
+// d to a Postgres enum. A label goes before the next label that already
+// exists, or at the end; IF NOT EXISTS lets several columns of the type
+// report the same addition.
+func addEnumLabelsSQL(d EnumLabelsAdded, dialect Dialect) []string {
+	var stmts []string
+	for i, label := range d.New {
+		if slices.Contains(d.Old, label) {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", quoteObjectName(d.TypeName, dialect), quoteString(label))
+		for _, next := range d.New[i+1:] {
+			if slices.Contains(d.Old, next) {
+				stmt += " BEFORE " + quoteString(next)
+				break
+			}
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// =============================================================================
+// Constraint Statements
+// =============================================================================
+
+// supportsNotValid reports whether Postgres can add tc as NOT VALID, which
+// it allows only for foreign key and check constraints.
+func supportsNotValid(tc *TableConstraint) bool {
+	return tc.GetSpec().GetReferenceItem() != nil || tc.GetSpec().GetCheckItem() != nil
+}
+
+// tableConstraintSQL renders a table constraint as it appears inside
+// CREATE TABLE or after ALTER TABLE ... ADD.
+func tableConstraintSQL(tc *TableConstraint, dialect Dialect) (string, error) {
+	if tc == nil || tc.Spec == nil {
+		return "", fmt.Errorf("missing constraint specification")
+	}
+
+	var body string
+	switch spec := tc.Spec.TableConstraintSpecClause.(type) {
+	case *TableConstraintSpec_UniqueItem:
+		u := spec.UniqueItem
+		if u.IsPrimary {
+			body = "PRIMARY KEY (" + quoteIdents(u.Columns, dialect) + ")"
+		} else if u.NullsNotDistinct && dialect == DialectPostgres {
+			body = "UNIQUE NULLS NOT DISTINCT (" + quoteIdents(u.Columns, dialect) + ")"
+		} else {
+			body = "UNIQUE (" + quoteIdents(u.Columns, dialect) + ")"
+		}
+		if len(u.Include) > 0 && dialect == DialectPostgres {
+			body += " INCLUDE (" + quoteIdents(u.Include, dialect) + ")"
+		}
+	case *TableConstraintSpec_CheckItem:
+		body = checkSQL(anyToString(spec.CheckItem))
+	case *TableConstraintSpec_ReferenceItem:
+		ref := spec.ReferenceItem
+		body = fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s", quoteIdents(ref.Columns, dialect),
+			quoteObjectName(&ObjectName{Idents: strings.Split(ref.GetKeyExpr().GetTableName(), ".")}, dialect))
+		if cols := ref.GetKeyExpr().GetColumns(); len(cols) > 0 {
+			body += " (" + quoteIdents(cols, dialect) + ")"
+		}
+		body += referenceActionsSQL(ref.Match, ref.OnDelete, ref.OnUpdate, ref.Deferrable, ref.InitiallyDeferred)
+	case *TableConstraintSpec_ExcludeItem:
+		if dialect != DialectPostgres {
+			return "", fmt.Errorf("exclusion constraints are not supported by %s", dialect)
+		}
+		ex := spec.ExcludeItem
+		if len(ex.Elements) == 0 && ex.Definition != "" {
+			body = ex.Definition
+			break
+		}
+		var elems []string
+		for _, e := range ex.Elements {
+			elems = append(elems, anyToString(e.Expr)+" WITH "+e.Operator)
+		}
+		body = "EXCLUDE "
+		if ex.Method != "" {
+			body += "USING " + ex.Method + " "
+		}
+		body += "(" + strings.Join(elems, ", ") + ")"
+		if len(ex.Include) > 0 {
+			body += " INCLUDE (" + quoteIdents(ex.Include, dialect) + ")"
+		}
+		if where := anyToString(ex.Where); where != "" {
+			body += " WHERE (" + where + ")"
+		}
+	default:
+		return "", fmt.Errorf("unsupported constraint type %T", tc.Spec.TableConstraintSpecClause)
+	}
+
+	if tc.NotEnforced && (dialect == DialectMySQL || dialect == DialectBigQuery) {
+		body += " NOT ENFORCED"
+	}
+	if tc.Name != "" && dialect != DialectBigQuery {
+		return "CONSTRAINT " + quoteIdent(tc.Name, dialect) + " " + body, nil
+	}
+	return body, nil
+}
+
+// alterConstraintSQL alters a constraint in place when only a property the
+// dialect can change differs: deferrability of a Postgres foreign key, or
+// enforcement of a MySQL CHECK. Anything else is a drop and re-add.
+func alterConstraintSQL(c AlterConstraint, dialect Dialect) ([]string, error) {
+	oldCon, newCon := c.OldConstraint, c.NewConstraint
+	if oldCon == nil || newCon == nil {
+		return nil, fmt.Errorf("AlterConstraint without old and new constraint")
+	}
+	table := quoteObjectName(c.TableName, dialect)
+	name := quoteIdent(newCon.Name, dialect)
+
+	switch dialect {
+	case DialectPostgres:
+		if oldIdx, newIdx := oldCon.Spec.GetUniqueItem().GetIndexName(), newCon.Spec.GetUniqueItem().GetIndexName(); oldIdx != "" && newIdx != "" && oldIdx != newIdx {
+			renamed := proto.Clone(oldCon).(*TableConstraint)
+			renamed.Spec.GetUniqueItem().IndexName = newIdx
+			if proto.Equal(renamed, newCon) {
+				return []string{fmt.Sprintf("ALTER INDEX %s RENAME TO %s",
+					quoteObjectName(indexObjectName(c.TableName, oldIdx), dialect), quoteIdent(newIdx, dialect))}, nil
+			}
+		}
+		oldRef, newRef := oldCon.Spec.GetReferenceItem(), newCon.Spec.GetReferenceItem()
+		if oldRef != nil && newRef != nil && oldCon.NotEnforced == newCon.NotEnforced {
+			a := proto.Clone(oldRef).(*ReferentialTableConstraint)
+			a.Deferrable, a.InitiallyDeferred = newRef.Deferrable, newRef.InitiallyDeferred
+			if proto.Equal(a, newRef) {
+				clause := "NOT DEFERRABLE"
+				if newRef.Deferrable {
+					clause = "DEFERRABLE INITIALLY IMMEDIATE"
+					if newRef.InitiallyDeferred {
+						clause = "DEFERRABLE INITIALLY DEFERRED"
+					}
+				}
+				return []string{fmt.Sprintf("ALTER TABLE %s ALTER CONSTRAINT %s %s", table, name, clause)}, nil
+			}
+		}
+	case DialectMySQL:
+		if oldCon.Spec.GetCheckItem() != nil && proto.Equal(oldCon.Spec, newCon.Spec) {
+			clause := "ENFORCED"
+			if newCon.NotEnforced {
+				clause = "NOT ENFORCED"
+			}
+			return []string{fmt.Sprintf("ALTER TABLE %s ALTER CHECK %s %s", table, name, clause)}, nil
+		}
+	}
+
+	stmts, err := dropConstraintSQL(DropConstraint{
+		TableName:      c.TableName,
+		ConstraintName: oldCon.Name,
+		IsForeignKey:   oldCon.Spec.GetReferenceItem() != nil,
+	}, dialect, DDLOptions{})
+	if err != nil {
+		return nil, err
+	}
+	add, err := GenerateSQL(AddConstraint{TableName: c.TableName, Constraint: newCon}, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return append(stmts, add...), nil
+}
+
+func dropConstraintSQL(c DropConstraint, dialect Dialect, opts DDLOptions) ([]string, error) {
+	table := quoteObjectName(c.TableName, dialect)
+	name := quoteIdent(c.ConstraintName, dialect)
+
+	switch dialect {
+	case DialectSQLite:
+		return nil, fmt.Errorf("dropping constraints from an existing table is not supported by %s", dialect)
+	case DialectMySQL:
+		if c.IsForeignKey {
+			return []string{fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", table, name)}, nil
+		}
+		if strings.ToUpper(c.ConstraintName) == "PRIMARY" {
+			return []string{fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", table)}, nil
+		}
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT%s %s", table, opts.guard("IF EXISTS", dialect, "constraint"), name)}, nil
+}
+
+// =============================================================================
+// Index Statements
+// =============================================================================
+
+func createIndexSQL(tableName *ObjectName, idx *MetaIndex, dialect Dialect, opts DDLOptions) (string, error) {
+	if dialect == DialectBigQuery {
+		return "", fmt.Errorf("secondary indexes are not supported by %s", dialect)
+	}
+	if idx == nil {
+		return "", fmt.Errorf("AddIndex without index")
+	}
+
+	keys := idx.Expression
+	if keys == "" {
+		if len(idx.Columns) == 0 {
+			return "", fmt.Errorf("index has no key columns")
+		}
+		keys = quoteIdents(idx.Columns, dialect)
+	}
+
+	// MySQL FULLTEXT and SPATIAL are kinds of index rather than methods
+	method := idx.Method
+	kind := ""
+	if idx.IsUnique {
+		kind = "UNIQUE "
+	}
+	if upper := strings.ToUpper(method); upper == "FULLTEXT" || upper == "SPATIAL" {
+		if dialect != DialectMySQL {
+			return "", fmt.Errorf("%s indexes are not supported by %s", upper, dialect)
+		}
+		kind, method = upper+" ", ""
+	}
+
+	var b strings.Builder
+	b.WriteString("CREATE " + kind)
+	fmt.Fprintf(&b, "INDEX%s %s ON %s", opts.guard("IF NOT EXISTS", dialect, "index"), quoteIdent(idx.Name, dialect), quoteObjectName(tableName, dialect))
+	if dialect == DialectPostgres && method != "" {
+		b.WriteString(" USING " + method)
+	}
+	b.WriteString(" (" + keys + ")")
+	if dialect == DialectMySQL && method != "" {
+		b.WriteString(" USING " + strings.ToUpper(method))
+	}
+	if include := idx.Options["Include"]; include != "" {
+		if dialect != DialectPostgres {
+			return "", fmt.Errorf("INCLUDE columns are not supported by %s", dialect)
+		}
+		b.WriteString(" INCLUDE (" + quoteIdents(strings.Split(include, ","), dialect) + ")")
+	}
+	if idx.Predicate != "" {
+		if dialect == DialectMySQL {
+			return "", fmt.Errorf("partial indexes are not supported by %s", dialect)
+		}
+		b.WriteString(" WHERE " + idx.Predicate)
+	}
+	return b.String(), nil
+}
+
+func dropIndexSQL(c DropIndex, dialect Dialect, opts DDLOptions) ([]string, error) {
+	switch dialect {
+	case DialectBigQuery:
+		return nil, fmt.Errorf("secondary indexes are not supported by %s", dialect)
+	case DialectMySQL:
+		return []string{fmt.Sprintf("DROP INDEX %s ON %s", quoteIdent(c.IndexName, dialect), quoteObjectName(c.TableName, dialect))}, nil
+	}
+	name := indexObjectName(c.TableName, c.IndexName)
+	return []string{"DROP INDEX" + opts.guard("IF EXISTS", dialect, "index") + " " + quoteObjectName(name, dialect)}, nil
+}
+
+// dropTriggerSQL drops a trigger. Postgres names triggers per table; MySQL
+// and SQLite per schema, like indexes.
+func dropTriggerSQL(c DropTrigger, dialect Dialect, opts DDLOptions) ([]string, error) {
+	guard := opts.guard("IF EXISTS", dialect, "trigger")
+	switch dialect {
+	case DialectBigQuery:
+		return nil, fmt.Errorf("triggers are not supported by %s", dialect)
+	case DialectPostgres:
+		return []string{fmt.Sprintf("DROP TRIGGER%s %s ON %s", guard, quoteIdent(c.TriggerName, dialect), quoteObjectName(c.TableName, dialect))}, nil
+	}
+	return []string{"DROP TRIGGER" + guard + " " + quoteObjectName(indexObjectName(c.TableName, c.TriggerName), dialect)}, nil
+}
+
+// indexObjectName qualifies a Postgres or SQLite index name with the schema
+// of its table, where such indexes live.
+func indexObjectName(tableName *ObjectName, indexName string) *ObjectName {
+	name := &ObjectName{Idents: []string{indexName}}
+	if idents := tableName.GetIdents(); len(idents) > 1 {
+		name.Idents = append(slices.Clone(idents[:len(idents)-1]), indexName)
+	}
+	return name
+}
+
+// checkSQL renders a CHECK clause. Definitions loaded from the catalog
+// (e.g. pg_get_constraintdef) already carry the CHECK keyword.
+func checkSQL(expr string) string {
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(expr)), "CHECK") {
+		return strings.TrimSpace(expr)
+	}
+	return "CHECK (" + expr + ")"
+}
+
+// referenceActionsSQL renders the optional MATCH, ON DELETE, ON UPDATE and
+// deferrability clauses of a foreign key.
+func referenceActionsSQL(match MatchOption, onDelete, onUpdate ReferentialAction, deferrable, deferred bool) string {
+	var s string
+	if m := MatchOptionToSQL(match); m != "" {
+		s += " MATCH " + m
+	}
+	if a := ReferentialActionToSQL(onDelete); a != "" {
+		s += " ON DELETE " + a
+	}
+	if a := ReferentialActionToSQL(onUpdate); a != "" {
+		s += " ON UPDATE " + a
+	}
+	if deferrable {
+		s += " DEFERRABLE"
+		if deferred {
+			s += " INITIALLY DEFERRED"
+		}
+	}
+	return s
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// isNotNull reports whether a column carries a NOT NULL constraint.
+func isNotNull(col *ColumnDef) bool {
+	for _, con := range col.GetConstraints() {
+		if con.GetSpec().GetNotNullItem() == NotNullColumnSpec_NotNullColumnSpecConfirm {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAutoIncrement reports whether a column is marked AUTO_INCREMENT.
+func hasAutoIncrement(col *ColumnDef) bool {
+	for _, deco := range col.GetMyDecos() {
+		if deco == AutoIncrement_AutoIncrementConfirm {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTablePrimaryKey reports whether the elements contain a table-level primary key.
+func hasTablePrimaryKey(elems []*TableElement) bool {
+	for _, elem := range elems {
+		if elem.GetTableConstraintElement().GetSpec().GetUniqueItem().GetIsPrimary() {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryKeyColumns returns the columns carrying an inline PRIMARY KEY constraint.
+func primaryKeyColumns(elems []*TableElement) []string {
+	var cols []string
+	for _, elem := range elems {
+		col := elem.GetColumnDefElement()
+		for _, con := range col.GetConstraints() {
+			if con.GetSpec().GetUniqueItem().GetIsPrimaryKey() {
+				cols = append(cols, col.Name)
+				break
+			}
+		}
+	}
+	return cols
+}
@@ -0,0 +1,168 @@
+package xmeta
+
+// ddl_names.go applies the DefaultSchema and QualifyNames DDLOptions: it
+// rewrites the object names a change refers to before the change is
+// rendered, so every statement names objects the same way.
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// qualify returns name as GenerateSQLWithOptions renders it: a bare name is
+// put in DefaultSchema when QualifyNames is set, and a name in DefaultSchema
+// is made bare when it is not. Other names, and every name when
+// DefaultSchema is empty, are returned unchanged.
+func (o DDLOptions) qualify(name *ObjectName) *ObjectName {
+	if o.DefaultSchema == "" || name == nil {
+		return name
+	}
+	switch {
+	case len(name.Idents) == 1 && o.QualifyNames:
+		return &ObjectName{Idents: []string{o.DefaultSchema, name.Idents[0]}}
+	case len(name.Idents) == 2 && !o.QualifyNames && name.Idents[0] == o.DefaultSchema:
+		return &ObjectName{Idents: []string{name.Idents[1]}}
+	}
+	return name
+}
+
+// qualifyKey is qualify for a dotted name such as a foreign key's
+// ReferenceKeyExpr.TableName.
+func (o DDLOptions) qualifyKey(key string) string {
+	if key == "" {
+		return key
+	}
+	return formatObjectName(o.qualify(&ObjectName{Idents: strings.Split(key, ".")}))
+}
+
+// qualifyChange returns a copy of change with its table, view, type and
+// trigger names, and the names its foreign keys, partition parents,
+// INHERITS parents and column types refer to, passed through qualify; see
+// qualifyType for the column types. Schema names are left
+// alone. The models change refers to are cloned before they are rewritten.
+func (o DDLOptions) qualifyChange(change SchemaChange) SchemaChange {
+	if o.DefaultSchema == "" {
+		return change
+	}
+	switch change.(type) {
+	case AddSchema, DropSchema:
+		return change
+	}
+	v := reflect.New(reflect.TypeOf(change)).Elem()
+	v.Set(reflect.ValueOf(change))
+	if v.Kind() != reflect.Struct {
+		return change
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		field := v.Field(i)
+		switch x := field.Interface().(type) {
+		case *ObjectName:
+			field.Set(reflect.ValueOf(o.qualify(x)))
+		case *MetaTable:
+			if x != nil {
+				field.Set(reflect.ValueOf(o.qualifyTable(proto.Clone(x).(*MetaTable))))
+			}
+		case *MetaView:
+			if x != nil {
+				x = proto.Clone(x).(*MetaView)
+				x.Name = o.qualify(x.Name)
+				field.Set(reflect.ValueOf(x))
+			}
+		case *MetaTrigger:
+			if x != nil {
+				x = proto.Clone(x).(*MetaTrigger)
+				x.TableName = o.qualify(x.TableName)
+				field.Set(reflect.ValueOf(x))
+			}
+		case *TableConstraint:
+			if x != nil {
+				x = proto.Clone(x).(*TableConstraint)
+				o.qualifyReference(x.GetSpec().GetReferenceItem())
+				field.Set(reflect.ValueOf(x))
+			}
+		case *ColumnDef:
+			if x != nil {
+				x = proto.Clone(x).(*ColumnDef)
+				o.qualifyColumnReferences(x)
+				field.Set(reflect.ValueOf(x))
+			}
+		case map[string]string:
+			field.Set(reflect.ValueOf(o.qualifyOptions(x)))
+		}
+	}
+	return v.Interface().(SchemaChange)
+}
+
+// qualifyTable rewrites the names in t, which the caller owns.
+func (o DDLOptions) qualifyTable(t *MetaTable) *MetaTable {
+	t.Name = o.qualify(t.Name)
+	for _, elem := range t.Elements {
+		o.qualifyReference(elem.GetTableConstraintElement().GetSpec().GetReferenceItem())
+		if col := elem.GetColumnDefElement(); col != nil {
+			o.qualifyColumnReferences(col)
+		}
+	}
+	t.Options = o.qualifyOptions(t.Options)
+	return t
+}
+
+func (o DDLOptions) qualifyReference(ref *ReferentialTableConstraint) {
+	if ref != nil && ref.KeyExpr != nil {
+		ref.KeyExpr.TableName = o.qualifyKey(ref.KeyExpr.TableName)
+	}
+}
+
+func (o DDLOptions) qualifyColumnReferences(col *ColumnDef) {
+	o.qualifyType(col.DataType)
+	for _, con := range col.Constraints {
+		if ref := con.GetSpec().GetReferenceItem(); ref != nil {
+			ref.TableName = o.qualify(ref.TableName)
+		}
+	}
+}
+
+// qualifyType rewrites the user-defined type dt, or the element type of an
+// array dt, refers to: a Postgres enum type, or a custom type such as a
+// domain whose name carries its schema. A bare custom type name is left
+// alone, as it may be a built-in type like tsvector.
+func (o DDLOptions) qualifyType(dt *DataType) {
+	if elem := dt.GetArrayData().GetType(); elem != nil {
+		dt = elem
+	}
+	if enum := dt.GetEnumData(); enum != nil {
+		enum.TypeName = o.qualify(enum.TypeName)
+		return
+	}
+	if custom := dt.GetCustomData(); custom != nil {
+		name := &ObjectName{Idents: strings.Split(formatObjectName(custom), ".")}
+		if len(name.Idents) > 1 {
+			dt.TypeClause = &DataType_CustomData{CustomData: o.qualify(name)}
+		}
+	}
+}
+
+// qualifyOptions returns a copy of options with the PartitionOf and
+// InheritsFrom parents qualified, or options itself when it has neither.
+func (o DDLOptions) qualifyOptions(options map[string]string) map[string]string {
+	parent, inherits := options["PartitionOf"], options["InheritsFrom"]
+	if parent == "" && inherits == "" {
+		return options
+	}
+	options = maps.Clone(options)
+	if parent != "" {
+		options["PartitionOf"] = o.qualifyKey(parent)
+	}
+	if parents := optionList(inherits); len(parents) > 0 {
+		for i, p := range parents {
+			parents[i] = o.qualifyKey(p)
+		}
+		options["InheritsFrom"] = strings.Join(parents, ",")
+	}
+	return options
+}
@@ -0,0 +1,624 @@
+package xmeta
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGenerateSQL_AddTable(t *testing.T) {
+	change := AddTable{Table: &MetaTable{
+		Name: &ObjectName{Idents: []string{"public", "users"}},
+		Elements: []*TableElement{
+			{TableElementClause: &TableElement_ColumnDefElement{
+				ColumnDefElement: &ColumnDef{
+					Name:     "id",
+					DataType: &DataType{TypeClause: &DataType_BigIntData{BigIntData: &BigInt{}}},
+					Constraints: []*ColumnConstraint{
+						{Spec: &ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{
+							UniqueItem: &UniqueColumnSpec{IsPrimaryKey: true},
+						}}},
+						{Spec: &ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{
+							NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm,
+						}}},
+					},
+				},
+			}},
+			{TableElementClause: &TableElement_ColumnDefElement{
+				ColumnDefElement: &ColumnDef{
+					Name:     "email",
+					DataType: &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 255}}},
+					Default:  stringToAny("''"),
+				},
+			}},
+		},
+	}}
+
+	stmts, err := GenerateSQL(change, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	expected := "CREATE TABLE \"public\".\"users\" (\n  \"id\" BIGINT NOT NULL PRIMARY KEY,\n  \"email\" VARCHAR(255) DEFAULT ''\n)"
+	if len(stmts) != 1 || stmts[0] != expected {
+		t.Errorf("Unexpected SQL:\n%v", stmts)
+	}
+
+	stmts, err = GenerateSQL(change, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if !strings.HasPrefix(stmts[0], "CREATE TABLE `public`.`users`") {
+		t.Errorf("Expected backtick quoting for MySQL, got %s", stmts[0])
+	}
+}
+
+func TestGenerateSQL_AlterColumn(t *testing.T) {
+	change := AlterColumn{
+		TableName: &ObjectName{Idents: []string{"users"}},
+		OldColumn: &ColumnDef{
+			Name:     "age",
+			DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}},
+		},
+		NewColumn: &ColumnDef{
+			Name:     "age",
+			DataType: &DataType{TypeClause: &DataType_BigIntData{BigIntData: &BigInt{}}},
+			Default:  stringToAny("0"),
+		},
+	}
+
+	stmts, err := GenerateSQL(change, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	expected := `ALTER TABLE "users" ALTER COLUMN "age" TYPE BIGINT, ALTER COLUMN "age" SET DEFAULT 0`
+	if len(stmts) != 1 || stmts[0] != expected {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	if _, err := GenerateSQL(change, DialectSQLite); err == nil {
+		t.Error("Expected error altering a column in SQLite")
+	}
+}
+
+func TestGenerateSQL_EnumLabelsAdded(t *testing.T) {
+	mood := func(labels ...string) *DataType {
+		return &DataType{TypeClause: &DataType_EnumData{EnumData: &EnumType{
+			Values:   labels,
+			TypeName: &ObjectName{Idents: []string{"public", "mood"}},
+		}}}
+	}
+	change := AlterColumn{
+		TableName: &ObjectName{Idents: []string{"people"}},
+		OldColumn: &ColumnDef{Name: "mood", DataType: mood("sad", "happy")},
+		NewColumn: &ColumnDef{Name: "mood", DataType: mood("sad", "ok", "happy", "ecstatic")},
+	}
+	deltas := change.Deltas()
+	if len(deltas) != 1 {
+		t.Fatalf("Expected one delta, got %v", deltas)
+	}
+	if added, ok := deltas[0].(EnumLabelsAdded); !ok || !slices.Equal(added.Added(), []string{"ok", "ecstatic"}) {
+		t.Fatalf("Expected added labels, got %v", deltas[0])
+	}
+
+	stmts, err := GenerateSQL(change, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	expected := []string{
+		`ALTER TYPE "public"."mood" ADD VALUE IF NOT EXISTS 'ok' BEFORE 'happy'`,
+		`ALTER TYPE "public"."mood" ADD VALUE IF NOT EXISTS 'ecstatic'`,
+	}
+	if !slices.Equal(stmts, expected) {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	stmts, err = GenerateSQL(change, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `people` MODIFY COLUMN `mood` ENUM('sad', 'ok', 'happy', 'ecstatic')" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	// Removing or reordering labels is a type change
+	change.NewColumn = &ColumnDef{Name: "mood", DataType: mood("happy", "sad")}
+	if _, ok := change.Deltas()[0].(TypeChanged); !ok {
+		t.Errorf("Expected a type change, got %v", change.Deltas())
+	}
+}
+
+func TestGenerateSQL_GenerationChanged(t *testing.T) {
+	generated := func(expr, kind string) *ColumnDef {
+		col := &ColumnDef{
+			Name:     "total",
+			DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}},
+			Options:  map[string]string{},
+		}
+		if expr != "" {
+			col.Options = map[string]string{"IsGenerated": "true", "GenerationExpression": expr, "GenerationKind": kind}
+		}
+		return col
+	}
+	change := AlterColumn{
+		TableName: &ObjectName{Idents: []string{"orders"}},
+		OldColumn: generated("price * qty", "STORED"),
+		NewColumn: generated("price * qty - discount", "STORED"),
+	}
+	if !change.IsDestructive() {
+		t.Error("Expected a changed generation expression to be destructive")
+	}
+
+	stmts, err := GenerateSQL(change, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	expected := []string{
+		`ALTER TABLE "orders" DROP COLUMN "total"`,
+		`ALTER TABLE "orders" ADD COLUMN "total" INTEGER GENERATED ALWAYS AS (price * qty - discount) STORED`,
+	}
+	if !slices.Equal(stmts, expected) {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	change.NewColumn = generated("price * qty", "VIRTUAL")
+	stmts, err = GenerateSQL(change, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 2 || stmts[1] != "ALTER TABLE `orders` ADD COLUMN `total` INT GENERATED ALWAYS AS (price * qty) VIRTUAL" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	// Postgres keeps the values of a generated column that becomes plain
+	change.NewColumn = generated("", "")
+	stmts, err = GenerateSQL(change, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != `ALTER TABLE "orders" ALTER COLUMN "total" DROP EXPRESSION` {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	if _, err := GenerateSQL(AddColumn{TableName: change.TableName, Column: change.OldColumn}, DialectBigQuery); err == nil {
+		t.Error("Expected an error adding a generated column in BigQuery")
+	}
+}
+
+func TestGenerateSQL_DropForeignKeyMySQL(t *testing.T) {
+	change := DropConstraint{
+		TableName:      &ObjectName{Idents: []string{"orders"}},
+		ConstraintName: "fk_user",
+		IsForeignKey:   true,
+	}
+
+	stmts, err := GenerateSQL(change, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `orders` DROP FOREIGN KEY `fk_user`" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+}
+
+func TestGenerateSQL_NotValidForeignKey(t *testing.T) {
+	table := &ObjectName{Idents: []string{"orders"}}
+	changes := []SchemaChange{
+		ValidateConstraint{TableName: table, ConstraintName: "fk_user"},
+		AddConstraint{
+			TableName: table,
+			NotValid:  true,
+			Constraint: &TableConstraint{
+				Name: "fk_user",
+				Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+					ReferenceItem: &ReferentialTableConstraint{
+						Columns: []string{"user_id"},
+						KeyExpr: &ReferenceKeyExpr{TableName: "users", Columns: []string{"id"}},
+					},
+				}},
+			},
+		},
+	}
+	SortChanges(changes)
+
+	var pg []string
+	for _, c := range changes {
+		stmts, err := GenerateSQL(c, DialectPostgres)
+		if err != nil {
+			t.Fatalf("GenerateSQL failed: %v", err)
+		}
+		pg = append(pg, stmts...)
+	}
+	if len(pg) != 2 || !strings.HasSuffix(pg[0], " NOT VALID") ||
+		pg[1] != `ALTER TABLE "orders" VALIDATE CONSTRAINT "fk_user"` {
+		t.Errorf("Unexpected Postgres SQL: %v", pg)
+	}
+
+	var my []string
+	for _, c := range changes {
+		stmts, err := GenerateSQL(c, DialectMySQL)
+		if err != nil {
+			t.Fatalf("GenerateSQL failed: %v", err)
+		}
+		my = append(my, stmts...)
+	}
+	if len(my) != 1 || strings.Contains(my[0], "NOT VALID") {
+		t.Errorf("Unexpected MySQL SQL: %v", my)
+	}
+}
+
+func TestGenerateSQLWithOptions_IfExistsGuards(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	opts := DDLOptions{IfExistsGuards: true}
+	tests := []struct {
+		change  SchemaChange
+		dialect Dialect
+		want    string
+	}{
+		{DropColumn{TableName: users, ColumnName: "phone"}, DialectPostgres, `ALTER TABLE "users" DROP COLUMN IF EXISTS "phone"`},
+		{DropColumn{TableName: users, ColumnName: "phone"}, DialectMySQL, "ALTER TABLE `users` DROP COLUMN `phone`"},
+		{AddColumn{TableName: users, Column: &ColumnDef{Name: "phone", DataType: &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}}},
+			DialectPostgres, `ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "phone" TEXT`},
+		{DropTable{TableName: users}, DialectSQLite, `DROP TABLE IF EXISTS "users"`},
+		{AddTable{Table: &MetaTable{Name: users, Elements: []*TableElement{{TableElementClause: &TableElement_ColumnDefElement{
+			ColumnDefElement: &ColumnDef{Name: "id", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}},
+		}}}}}, DialectMySQL, "CREATE TABLE IF NOT EXISTS `users` (\n  `id` INT\n)"},
+		{DropIndex{TableName: users, IndexName: "users_name_idx"}, DialectMySQL, "DROP INDEX `users_name_idx` ON `users`"},
+		{DropIndex{TableName: users, IndexName: "users_name_idx"}, DialectSQLite, `DROP INDEX IF EXISTS "users_name_idx"`},
+	}
+	for _, tt := range tests {
+		stmts, err := GenerateSQLWithOptions(tt.change, tt.dialect, opts)
+		if err != nil {
+			t.Fatalf("%T: GenerateSQLWithOptions failed: %v", tt.change, err)
+		}
+		if len(stmts) != 1 || stmts[0] != tt.want {
+			t.Errorf("%T on %s: got %q, want %q", tt.change, tt.dialect, stmts, tt.want)
+		}
+	}
+}
+
+func TestApplyChanges_DryRun(t *testing.T) {
+	changes := []SchemaChange{
+		AddColumn{
+			TableName: &ObjectName{Idents: []string{"users"}},
+			Column: &ColumnDef{
+				Name:     "phone",
+				DataType: &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 20}}},
+			},
+		},
+	}
+
+	var out strings.Builder
+	err := ApplyChanges(context.Background(), nil, DialectPostgres, changes, ApplyOptions{DryRun: true, Output: &out})
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	if out.String() != "ALTER TABLE \"users\" ADD COLUMN \"phone\" VARCHAR(20);\n" {
+		t.Errorf("Unexpected dry-run output: %q", out.String())
+	}
+}
+
+func TestApplyChanges_RefusesDestructive(t *testing.T) {
+	changes := []SchemaChange{DropTable{TableName: &ObjectName{Idents: []string{"users"}}}}
+
+	err := ApplyChanges(context.Background(), nil, DialectPostgres, changes, ApplyOptions{DryRun: true})
+	if err == nil {
+		t.Fatal("Expected destructive change to be refused")
+	}
+
+	var out strings.Builder
+	err = ApplyChanges(context.Background(), nil, DialectPostgres, changes, ApplyOptions{DryRun: true, AllowDestructive: true, Output: &out})
+	if err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	if out.String() != "DROP TABLE \"users\";\n" {
+		t.Errorf("Unexpected dry-run output: %q", out.String())
+	}
+}
+
+func TestGenerateSQL_CommentOutDestructive(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	changes := []SchemaChange{
+		DropTable{TableName: &ObjectName{Idents: []string{"sessions"}}},
+		DropColumn{TableName: users, ColumnName: "legacy"},
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "phone", DataType: &DataType{TypeClause: &DataType_TextData{}}}},
+	}
+
+	var out strings.Builder
+	opts := ApplyOptions{DryRun: true, Output: &out, DDL: DDLOptions{CommentOutDestructive: true}}
+	if err := ApplyChanges(context.Background(), nil, DialectPostgres, changes, opts); err != nil {
+		t.Fatalf("ApplyChanges failed: %v", err)
+	}
+	want := `-- DESTRUCTIVE, commented out for manual review: - column users.legacy
+-- ALTER TABLE "users" DROP COLUMN "legacy";
+-- DESTRUCTIVE, commented out for manual review: - table sessions
+-- DROP TABLE "sessions";
+ALTER TABLE "users" ADD COLUMN "phone" TEXT;
+`
+	if out.String() != want {
+		t.Errorf("Unexpected dry-run output:\n%s", out.String())
+	}
+
+	// Executing never skips the commented-out changes
+	opts.DryRun = false
+	if err := ApplyChanges(context.Background(), nil, DialectPostgres, changes, opts); err == nil || !strings.Contains(err.Error(), "refusing destructive change") {
+		t.Errorf("Expected the destructive changes refused when executing, got %v", err)
+	}
+}
+
+func TestGenerateSQL_ViewOptions(t *testing.T) {
+	current := &MetaDatabase{Views: []*MetaView{{
+		Name:       &ObjectName{Idents: []string{"app", "active_users"}},
+		Definition: "SELECT id FROM users WHERE active",
+		Options:    map[string]string{"CheckOption": "LOCAL", "IsUpdatable": "true"},
+	}}}
+	desired := &MetaDatabase{Views: []*MetaView{{
+		Name:       &ObjectName{Idents: []string{"app", "active_users"}},
+		Definition: "SELECT id FROM users WHERE active",
+		Options:    map[string]string{"SecurityBarrier": "true", "SqlSecurity": "INVOKER"},
+	}}}
+
+	changes := DiffDatabase(current, desired)
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v", changes)
+	}
+	alter, ok := changes[0].(AlterTableOptions)
+	if !ok || !alter.IsView {
+		t.Fatalf("Expected AlterTableOptions for the view, got %#v", changes[0])
+	}
+	if got := DescribeChange(alter); !strings.HasPrefix(got, "~ view app.active_users:") {
+		t.Errorf("Unexpected description %q", got)
+	}
+
+	stmts, err := GenerateSQL(alter, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`ALTER VIEW "app"."active_users" SET (security_barrier=true)`,
+		`ALTER VIEW "app"."active_users" RESET (check_option)`,
+	}
+	if strings.Join(stmts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected Postgres SQL %q", stmts)
+	}
+
+	stmts, err = GenerateSQL(alter, DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER SQL SECURITY INVOKER VIEW `app`.`active_users` AS SELECT id FROM users WHERE active" {
+		t.Errorf("Unexpected MySQL SQL %q", stmts)
+	}
+
+	// Updatability alone is not a change
+	desired.Views[0].Options = map[string]string{"CheckOption": "LOCAL"}
+	if changes := DiffDatabase(current, desired); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestGenerateSQL_Comments(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	column := func(comment string) *ColumnDef {
+		return &ColumnDef{Name: "bio", DataType: &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}, Comment: comment}
+	}
+	table := &MetaTable{
+		Name:     users,
+		Comment:  "People's accounts",
+		Elements: []*TableElement{{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: column(`It's a C:\ path`)}}},
+	}
+
+	tests := []struct {
+		change  SchemaChange
+		dialect Dialect
+		want    []string
+	}{
+		{AddTable{Table: table}, DialectPostgres, []string{
+			"CREATE TABLE \"users\" (\n  \"bio\" TEXT\n)",
+			`COMMENT ON TABLE "users" IS 'People''s accounts'`,
+			`COMMENT ON COLUMN "users"."bio" IS 'It''s a C:\ path'`,
+		}},
+		{AddTable{Table: table}, DialectMySQL, []string{
+			"CREATE TABLE `users` (\n  `bio` TEXT COMMENT 'It''s a C:\\\\ path'\n) COMMENT='People''s accounts'",
+		}},
+		{AlterColumn{TableName: users, OldColumn: column("old"), NewColumn: column("")}, DialectPostgres, []string{
+			`COMMENT ON COLUMN "users"."bio" IS NULL`,
+		}},
+		{AlterColumn{TableName: users, OldColumn: column(""), NewColumn: column("new")}, DialectMySQL, []string{
+			"ALTER TABLE `users` MODIFY COLUMN `bio` TEXT COMMENT 'new'",
+		}},
+		{AlterColumn{TableName: users, OldColumn: column(""), NewColumn: column("it's")}, DialectBigQuery, []string{
+			"ALTER TABLE `users` ALTER COLUMN `bio` SET OPTIONS (description = 'it\\'s')",
+		}},
+		{AlterTableOptions{TableName: users, OldComment: "old", NewComment: "new"}, DialectMySQL, []string{
+			"ALTER TABLE `users` COMMENT='new'",
+		}},
+		{AlterTableOptions{TableName: users, OldComment: "old"}, DialectPostgres, []string{
+			`COMMENT ON TABLE "users" IS NULL`,
+		}},
+		{AddIndex{TableName: &ObjectName{Idents: []string{"public", "users"}}, Index: &MetaIndex{Name: "users_bio_idx", Columns: []string{"bio"}, Comment: "Bio search"}},
+			DialectPostgres, []string{
+				`CREATE INDEX "users_bio_idx" ON "public"."users" ("bio")`,
+				`COMMENT ON INDEX "public"."users_bio_idx" IS 'Bio search'`,
+			}},
+	}
+	for _, tt := range tests {
+		stmts, err := GenerateSQL(tt.change, tt.dialect)
+		if err != nil {
+			t.Errorf("GenerateSQL(%v, %s) failed: %v", tt.change, tt.dialect, err)
+			continue
+		}
+		if !slices.Equal(stmts, tt.want) {
+			t.Errorf("GenerateSQL(%v, %s) = %q, want %q", tt.change, tt.dialect, stmts, tt.want)
+		}
+	}
+}
+
+func TestGenerateSQLWithOptions_DefaultSchema(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE public.users (id INTEGER PRIMARY KEY);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES public.users (id));
+CREATE TABLE audit.log (id INTEGER);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	orders := db.Tables[1]
+
+	tests := []struct {
+		opts   DDLOptions
+		change SchemaChange
+		want   string
+	}{
+		{DDLOptions{DefaultSchema: "public"}, DropTable{TableName: db.Tables[0].Name}, `DROP TABLE "users"`},
+		{DDLOptions{DefaultSchema: "public"}, DropTable{TableName: db.Tables[2].Name}, `DROP TABLE "audit"."log"`},
+		{DDLOptions{DefaultSchema: "public", QualifyNames: true}, DropTable{TableName: orders.Name}, `DROP TABLE "public"."orders"`},
+		{DDLOptions{QualifyNames: true}, DropTable{TableName: orders.Name}, `DROP TABLE "orders"`},
+		{DDLOptions{DefaultSchema: "public"}, AddTable{Table: orders},
+			"CREATE TABLE \"orders\" (\n  \"id\" INTEGER PRIMARY KEY,\n  \"user_id\" INTEGER REFERENCES \"users\" (\"id\")\n)"},
+		{DDLOptions{DefaultSchema: "public", QualifyNames: true}, AddTable{Table: orders},
+			"CREATE TABLE \"public\".\"orders\" (\n  \"id\" INTEGER PRIMARY KEY,\n  \"user_id\" INTEGER REFERENCES \"public\".\"users\" (\"id\")\n)"},
+		{DDLOptions{DefaultSchema: "public", QualifyNames: true}, AddSchema{SchemaName: &ObjectName{Idents: []string{"audit"}}}, `CREATE SCHEMA "audit"`},
+	}
+	for _, tt := range tests {
+		stmts, err := GenerateSQLWithOptions(tt.change, DialectPostgres, tt.opts)
+		if err != nil {
+			t.Fatalf("%T: GenerateSQLWithOptions failed: %v", tt.change, err)
+		}
+		if len(stmts) != 1 || stmts[0] != tt.want {
+			t.Errorf("%T with %+v: got %q, want %q", tt.change, tt.opts, stmts, tt.want)
+		}
+	}
+	if got := objectNameKey(orders.Name); got != "orders" {
+		t.Errorf("Expected the table itself left unqualified, got %s", got)
+	}
+}
+
+func TestGenerateSQLWithOptions_DefaultSchemaTypes(t *testing.T) {
+	mood := &DataType{TypeClause: &DataType_EnumData{EnumData: &EnumType{
+		Values:   []string{"sad", "happy"},
+		TypeName: &ObjectName{Idents: []string{"public", "mood"}},
+	}}}
+	column := func(name string, dt *DataType) *TableElement {
+		return &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: name, DataType: dt}}}
+	}
+	table := &MetaTable{
+		Name: &ObjectName{Idents: []string{"public", "people"}},
+		Elements: []*TableElement{
+			column("mood", mood),
+			column("moods", &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: proto.Clone(mood).(*DataType)}}}),
+			column("email", &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: []string{"public", "email_address"}}}}),
+			column("doc", &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: []string{"tsvector"}}}}),
+		},
+	}
+
+	tests := []struct {
+		opts DDLOptions
+		want string
+	}{
+		{DDLOptions{DefaultSchema: "public"},
+			"CREATE TABLE \"people\" (\n  \"mood\" mood,\n  \"moods\" mood[],\n  \"email\" email_address,\n  \"doc\" tsvector\n)"},
+		{DDLOptions{DefaultSchema: "public", QualifyNames: true},
+			"CREATE TABLE \"public\".\"people\" (\n  \"mood\" public.mood,\n  \"moods\" public.mood[],\n  \"email\" public.email_address,\n  \"doc\" tsvector\n)"},
+	}
+	for _, tt := range tests {
+		stmts, err := GenerateSQLWithOptions(AddTable{Table: table}, DialectPostgres, tt.opts)
+		if err != nil {
+			t.Fatalf("GenerateSQLWithOptions failed: %v", err)
+		}
+		if len(stmts) != 1 || stmts[0] != tt.want {
+			t.Errorf("With %+v: got %q, want %q", tt.opts, stmts, tt.want)
+		}
+	}
+
+	col := &ColumnDef{Name: "mood", DataType: &DataType{TypeClause: &DataType_EnumData{EnumData: &EnumType{
+		Values:   []string{"sad"},
+		TypeName: &ObjectName{Idents: []string{"mood"}},
+	}}}}
+	stmts, err := GenerateSQLWithOptions(AddColumn{TableName: table.Name, Column: col}, DialectPostgres, DDLOptions{DefaultSchema: "public", QualifyNames: true})
+	if err != nil {
+		t.Fatalf("GenerateSQLWithOptions failed: %v", err)
+	}
+	if want := `ALTER TABLE "public"."people" ADD COLUMN "mood" public.mood`; len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("Expected %q, got %q", want, stmts)
+	}
+	if got := formatObjectName(col.DataType.GetEnumData().TypeName); got != "mood" {
+		t.Errorf("Expected the column's enum type left unqualified, got %s", got)
+	}
+}
+
+func TestIdentitySequenceOptions(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER GENERATED ALWAYS AS IDENTITY, name TEXT);
+CREATE TABLE orders (id BIGINT GENERATED BY DEFAULT AS IDENTITY (START WITH 1 INCREMENT BY 1));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER GENERATED ALWAYS AS IDENTITY (START WITH 1000 INCREMENT BY 10), name TEXT);
+CREATE TABLE orders (id BIGINT GENERATED BY DEFAULT AS IDENTITY);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	changes := DiffDatabase(current, desired)
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change for the users identity start, got %v", changes)
+	}
+	stmts, err := GenerateSQL(changes[0], DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	want := `ALTER TABLE "users" ALTER COLUMN "id" SET INCREMENT BY 10, ALTER COLUMN "id" SET START WITH 1000`
+	if len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("Expected %q, got %q", want, stmts)
+	}
+
+	stmts, err = GenerateSQL(AddTable{Table: desired.Tables[0]}, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if !strings.Contains(stmts[0], `"id" INTEGER GENERATED ALWAYS AS IDENTITY (START WITH 1000 INCREMENT BY 10)`) {
+		t.Errorf("Expected the identity options in CREATE TABLE, got %s", stmts[0])
+	}
+}
+
+func TestSerialColumns(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER NOT NULL, name TEXT);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id SERIAL, name TEXT);
+CREATE TABLE orders (id BIGSERIAL PRIMARY KEY);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	// The table is created with the pseudo-type, which creates the sequence
+	stmts, err := GenerateSQL(AddTable{Table: desired.Tables[1]}, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if !strings.Contains(stmts[0], `"id" BIGSERIAL NOT NULL PRIMARY KEY`) || strings.Contains(stmts[0], "nextval") {
+		t.Errorf("Expected a BIGSERIAL column without its default, got %s", stmts[0])
+	}
+
+	// An existing column creates the sequence before defaulting to it
+	changes := DiffDatabase(current, desired)
+	if len(changes) != 2 {
+		t.Fatalf("Expected the users id default and the orders table, got %v", changes)
+	}
+	stmts, err = GenerateSQL(changes[1], DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	want := []string{
+		`CREATE SEQUENCE IF NOT EXISTS "users_id_seq" AS INTEGER OWNED BY "users"."id"`,
+		`ALTER TABLE "users" ALTER COLUMN "id" SET DEFAULT nextval('users_id_seq'::regclass)`,
+	}
+	if !slices.Equal(stmts, want) {
+		t.Errorf("Expected %q, got %q", want, stmts)
+	}
+}
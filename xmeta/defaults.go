@@ -0,0 +1,134 @@
+package xmeta
+
+// defaults.go compares column DEFAULT expressions by meaning rather than
+// by spelling.
+
+import (
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var (
+	// defaultCast matches a Postgres cast such as ::regclass,
+	// ::character varying(20) or ::timestamp without time zone.
+	defaultCast = regexp.MustCompile(`(?i)::\s*"?[a-z_][a-z0-9_."]*(\s+(varying|precision|without|with|time|zone))*(\s*\([0-9,\s]*\))?(\[\])*`)
+	// defaultNumber matches a numeric literal, optionally quoted.
+	defaultNumber = regexp.MustCompile(`^('?)([+-]?)([0-9]*)(?:\.([0-9]*))?('?)$`)
+)
+
+// defaultSynonyms maps default expressions to the spelling they are
+// compared as. Keys are in the lower-cased, cast-free form.
+var defaultSynonyms = map[string]string{
+	"now()":                   "current_timestamp",
+	"current_timestamp()":     "current_timestamp",
+	"transaction_timestamp()": "current_timestamp",
+	"curdate()":               "current_date",
+	"current_date()":          "current_date",
+	"curtime()":               "current_time",
+	"current_time()":          "current_time",
+	"null":                    "",
+}
+
+// defaultsEquivalent reports whether two column defaults have the same
+// meaning in dialect: casts, wrapping parentheses, letter case outside
+// string literals, quoting and trailing zeros of numbers and synonyms such
+// as now() and CURRENT_TIMESTAMP are ignored, and DEFAULT NULL equals no
+// default. DialectUnknown applies the rules common to all dialects.
+func defaultsEquivalent(a, b *anypb.Any, dialect Dialect) bool {
+	if proto.Equal(a, b) {
+		return true
+	}
+	for _, d := range []*anypb.Any{a, b} {
+		if d != nil && !d.MessageIs(&wrapperspb.StringValue{}) {
+			return false
+		}
+	}
+	return normalizeDefault(anyToString(a), dialect) == normalizeDefault(anyToString(b), dialect)
+}
+
+// normalizeDefault returns the canonical form of a default expression.
+func normalizeDefault(expr string, dialect Dialect) string {
+	expr = trimOuterParens(strings.TrimSpace(expr))
+
+	// Casts and case only matter outside string literals
+	var b strings.Builder
+	for i, part := range splitStringLiterals(expr) {
+		if i%2 == 1 {
+			b.WriteString(part)
+			continue
+		}
+		part = defaultCast.ReplaceAllString(part, "")
+		b.WriteString(strings.ToLower(strings.Join(strings.Fields(part), " ")))
+	}
+	expr = trimOuterParens(strings.TrimSpace(b.String()))
+
+	if m := defaultNumber.FindStringSubmatch(expr); m != nil && m[1] == m[5] && m[3]+m[4] != "" {
+		whole, frac := strings.TrimLeft(m[3], "0"), strings.TrimRight(m[4], "0")
+		if whole == "" {
+			whole = "0"
+		}
+		sign := strings.TrimPrefix(m[2], "+")
+		if whole == "0" && frac == "" {
+			sign = ""
+		}
+		expr = sign + whole
+		if frac != "" {
+			expr += "." + frac
+		}
+	}
+
+	if synonym, ok := defaultSynonyms[expr]; ok {
+		expr = synonym
+	}
+
+	switch dialect {
+	case DialectPostgres:
+		// pg_dump qualifies sequences in public, which the catalog shows bare
+		expr = strings.Replace(expr, "nextval('public.", "nextval('", 1)
+		switch expr {
+		case "'t'", "'true'":
+			expr = "true"
+		case "'f'", "'false'":
+			expr = "false"
+		}
+	case DialectMySQL, DialectSQLite:
+		// Booleans are stored as integers
+		switch expr {
+		case "true":
+			expr = "1"
+		case "false":
+			expr = "0"
+		}
+	}
+	return expr
+}
+
+// splitStringLiterals splits expr into alternating code and single-quoted
+// string literals, starting with code. Doubled quotes stay inside their
+// literal.
+func splitStringLiterals(expr string) []string {
+	var parts []string
+	start, inString := 0, false
+	for i := 0; i < len(expr); i++ {
+		if expr[i] != '\'' {
+			continue
+		}
+		if inString && i+1 < len(expr) && expr[i+1] == '\'' {
+			i++
+			continue
+		}
+		if inString {
+			parts = append(parts, expr[start:i+1])
+			start = i + 1
+		} else {
+			parts = append(parts, expr[start:i])
+			start = i
+		}
+		inString = !inString
+	}
+	return append(parts, expr[start:])
+}
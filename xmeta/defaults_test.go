@@ -0,0 +1,71 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestDefaultsEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		dialect Dialect
+		want    bool
+	}{
+		{"nextval('users_id_seq'::regclass)", "nextval('users_id_seq')", DialectPostgres, true},
+		{"0", "'0'", DialectUnknown, true},
+		{"'1.50'::numeric", "1.5", DialectPostgres, true},
+		{"-0.0", "0", DialectUnknown, true},
+		{"007", "7", DialectUnknown, true},
+		{"now()", "CURRENT_TIMESTAMP", DialectUnknown, true},
+		{"(now())", "current_timestamp", DialectSQLite, true},
+		{"'active'::character varying", "'active'", DialectPostgres, true},
+		{"'2024-01-01 00:00:00'::timestamp without time zone", "'2024-01-01 00:00:00'", DialectPostgres, true},
+		{"NULL", "", DialectUnknown, true},
+		{"'t'", "true", DialectPostgres, true},
+		{"TRUE", "1", DialectMySQL, true},
+		{"'Active'", "'active'", DialectUnknown, false},
+		{"'it''s'", "'it''s'::text", DialectPostgres, true},
+		{"1", "2", DialectUnknown, false},
+		{"'t'", "true", DialectMySQL, false},
+		{"now()", "CURRENT_DATE", DialectUnknown, false},
+	}
+	for _, tt := range tests {
+		if got := defaultsEquivalent(stringToAny(tt.a), stringToAny(tt.b), tt.dialect); got != tt.want {
+			t.Errorf("defaultsEquivalent(%q, %q, %s) = %v", tt.a, tt.b, tt.dialect, got)
+		}
+	}
+	if !defaultsEquivalent(nil, stringToAny("NULL"), DialectUnknown) {
+		t.Error("Expected DEFAULT NULL to equal no default")
+	}
+}
+
+func TestDiffDatabase_EquivalentDefaults(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (
+  id INTEGER DEFAULT nextval('users_id_seq'::regclass),
+  active BOOLEAN DEFAULT 'true'::boolean,
+  created_at TIMESTAMP DEFAULT now(),
+  name TEXT DEFAULT 'x');`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (
+  id INTEGER DEFAULT nextval('users_id_seq'),
+  active BOOLEAN DEFAULT TRUE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  name VARCHAR(20) DEFAULT 'x'::text);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := DiffDatabaseWithOptions(current, desired, DiffOptions{Dialect: DialectPostgres})
+	if len(changes) != 1 {
+		t.Fatalf("Expected only the type change of name, got %v", changes)
+	}
+	alter := changes[0].(AlterColumn)
+	deltas := alter.Deltas()
+	if len(deltas) != 1 {
+		t.Fatalf("Expected the type change only, got %v", deltas)
+	}
+	if _, ok := deltas[0].(TypeChanged); !ok {
+		t.Errorf("Expected TypeChanged, got %T", deltas[0])
+	}
+}
@@ -0,0 +1,432 @@
+package xmeta
+
+// dialect.go defines the SQL dialects understood by the DDL generator
+// and the dialect-specific rendering of identifiers and data types.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect identifies a SQL dialect.
+type Dialect int
+
+const (
+	DialectUnknown Dialect = iota
+	DialectPostgres
+	DialectMySQL
+	DialectSQLite
+	DialectBigQuery
+)
+
+// String returns the canonical lower-case name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectPostgres:
+		return "postgres"
+	case DialectMySQL:
+		return "mysql"
+	case DialectSQLite:
+		return "sqlite"
+	case DialectBigQuery:
+		return "bigquery"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDialect returns the dialect named by s, accepting the String() names
+// and common aliases such as "postgresql" and "sqlite3". Unrecognized names
+// give DialectUnknown.
+func ParseDialect(s string) Dialect {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "postgres", "postgresql", "pg":
+		return DialectPostgres
+	case "mysql", "mariadb":
+		return DialectMySQL
+	case "sqlite", "sqlite3":
+		return DialectSQLite
+	case "bigquery", "bq":
+		return DialectBigQuery
+	default:
+		return DialectUnknown
+	}
+}
+
+// quoteIdent quotes a single identifier for the dialect.
+func quoteIdent(name string, dialect Dialect) string {
+	switch dialect {
+	case DialectMySQL, DialectBigQuery:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	default:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+}
+
+// quoteObjectName quotes every identifier of an ObjectName and joins them with dots.
+func quoteObjectName(on *ObjectName, dialect Dialect) string {
+	if on == nil {
+		return ""
+	}
+	parts := make([]string, len(on.Idents))
+	for i, ident := range on.Idents {
+		parts[i] = quoteIdent(ident, dialect)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdents quotes a list of column names and joins them with commas.
+func quoteIdents(names []string, dialect Dialect) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = quoteIdent(name, dialect)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// quoteString renders a SQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteDialectString renders a SQL string literal for the dialect. MySQL
+// and BigQuery treat a backslash as an escape character, so it is escaped
+// too; BigQuery also escapes quotes with a backslash.
+func quoteDialectString(s string, dialect Dialect) string {
+	switch dialect {
+	case DialectMySQL:
+		return quoteString(strings.ReplaceAll(s, `\`, `\\`))
+	case DialectBigQuery:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+	}
+	return quoteString(s)
+}
+
+// ParseDataType parses a type name as written in DDL, such as
+// "numeric(10,2)", "character varying(255)" or "int[]", into a DataType.
+// Names of any supported dialect are accepted; unknown types are kept as
+// CustomData.
+func ParseDataType(s string) (*DataType, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("empty data type")
+	}
+	if strings.Count(s, "(") != strings.Count(s, ")") {
+		return nil, fmt.Errorf("unbalanced parentheses in data type %q", s)
+	}
+	return parseSQLDataType(s), nil
+}
+
+// FormatDataType renders a DataType in a dialect-neutral spelling that
+// ParseDataType reads back, e.g. "NUMERIC(10,2)" or "VARCHAR(255)". It
+// returns "" for a nil or empty DataType.
+func FormatDataType(dt *DataType) string {
+	s, _ := dataTypeSQL(dt, DialectUnknown)
+	return s
+}
+
+// dataTypeSQL renders a DataType as a column type for the dialect.
+// DialectUnknown gives the neutral spelling used by FormatDataType.
+func dataTypeSQL(dt *DataType, dialect Dialect) (string, error) {
+	if dt == nil || dt.TypeClause == nil {
+		return "", fmt.Errorf("missing data type")
+	}
+
+	switch t := dt.TypeClause.(type) {
+	case *DataType_IntData:
+		return intTypeSQL("INT", "INTEGER", t.IntData.IsUnsigned, dialect), nil
+	case *DataType_SmallIntData:
+		return intTypeSQL("SMALLINT", "SMALLINT", t.SmallIntData.IsUnsigned, dialect), nil
+	case *DataType_BigIntData:
+		return intTypeSQL("BIGINT", "BIGINT", t.BigIntData.IsUnsigned, dialect), nil
+	case *DataType_TinyIntData:
+		return intTypeSQL("TINYINT", "SMALLINT", t.TinyIntData.IsUnsigned, dialect), nil
+	case *DataType_MediumIntData:
+		return intTypeSQL("MEDIUMINT", "INTEGER", t.MediumIntData.IsUnsigned, dialect), nil
+	case *DataType_DecimalData:
+		d := t.DecimalData
+		name := "NUMERIC"
+		if dialect == DialectMySQL {
+			name = "DECIMAL"
+		}
+		if d.Precision > 0 {
+			if d.Scale > 0 {
+				name = fmt.Sprintf("%s(%d,%d)", name, d.Precision, d.Scale)
+			} else {
+				name = fmt.Sprintf("%s(%d)", name, d.Precision)
+			}
+		}
+		if d.IsUnsigned && (dialect == DialectMySQL || dialect == DialectUnknown) {
+			name += " UNSIGNED"
+		}
+		return name, nil
+	case *DataType_CharData:
+		if dialect == DialectBigQuery {
+			return sizedTypeSQL("STRING", t.CharData.Size), nil
+		}
+		return sizedTypeSQL("CHAR", t.CharData.Size), nil
+	case *DataType_VarcharData:
+		switch dialect {
+		case DialectBigQuery:
+			return sizedTypeSQL("STRING", t.VarcharData.Size), nil
+		case DialectMySQL:
+			if t.VarcharData.Size == 0 {
+				// MySQL requires a length for VARCHAR
+				return "VARCHAR(255)", nil
+			}
+		}
+		return sizedTypeSQL("VARCHAR", t.VarcharData.Size), nil
+	case *DataType_TextData:
+		if dialect == DialectBigQuery {
+			return "STRING", nil
+		}
+		return "TEXT", nil
+	case *DataType_CustomData:
+		return formatObjectName(t.CustomData), nil
+	case *DataType_ArrayData:
+		elem, err := dataTypeSQL(t.ArrayData.Type, dialect)
+		if err != nil {
+			return "", err
+		}
+		switch dialect {
+		case DialectPostgres, DialectUnknown:
+			return elem + "[]", nil
+		case DialectBigQuery:
+			return "ARRAY<" + elem + ">", nil
+		}
+		return "", fmt.Errorf("array types are not supported by %s", dialect)
+	case *DataType_StructData:
+		if dialect != DialectBigQuery && dialect != DialectUnknown {
+			return "", fmt.Errorf("struct types are not supported by %s", dialect)
+		}
+		var fields []string
+		for _, f := range t.StructData.Fields {
+			ft, err := dataTypeSQL(f.DataType, dialect)
+			if err != nil {
+				return "", fmt.Errorf("struct field %s: %w", f.Name, err)
+			}
+			fields = append(fields, quoteIdent(f.Name, dialect)+" "+ft)
+		}
+		return "STRUCT<" + strings.Join(fields, ", ") + ">", nil
+	case *DataType_UUIDData:
+		switch dialect {
+		case DialectMySQL:
+			return "CHAR(36)", nil
+		case DialectBigQuery:
+			return "STRING", nil
+		}
+		return "UUID", nil
+	case *DataType_TimestampData:
+		ts := t.TimestampData
+		switch dialect {
+		case DialectPostgres, DialectUnknown:
+			if ts.WithTimeZone {
+				return precisionTypeSQL("TIMESTAMP", ts.Precision) + " WITH TIME ZONE", nil
+			}
+			return precisionTypeSQL("TIMESTAMP", ts.Precision), nil
+		case DialectMySQL:
+			if ts.WithTimeZone {
+				return precisionTypeSQL("TIMESTAMP", ts.Precision), nil
+			}
+			return precisionTypeSQL("DATETIME", ts.Precision), nil
+		case DialectBigQuery:
+			if ts.WithTimeZone {
+				return "TIMESTAMP", nil
+			}
+			return "DATETIME", nil
+		}
+		return "TIMESTAMP", nil
+	case *DataType_BooleanData:
+		if dialect == DialectBigQuery {
+			return "BOOL", nil
+		}
+		return "BOOLEAN", nil
+	case *DataType_DateData:
+		return "DATE", nil
+	case *DataType_TimeData:
+		return "TIME", nil
+	case *DataType_TimeTypeData:
+		tt := t.TimeTypeData
+		switch dialect {
+		case DialectPostgres, DialectUnknown:
+			if tt.WithTimeZone {
+				return precisionTypeSQL("TIME", tt.Precision) + " WITH TIME ZONE", nil
+			}
+			return precisionTypeSQL("TIME", tt.Precision), nil
+		case DialectMySQL:
+			// MySQL has no TIME WITH TIME ZONE
+			return precisionTypeSQL("TIME", tt.Precision), nil
+		}
+		return "TIME", nil
+	case *DataType_IntervalData:
+		iv := t.IntervalData
+		switch dialect {
+		case DialectPostgres, DialectUnknown:
+			name := "INTERVAL"
+			if iv.Fields != "" {
+				name += " " + iv.Fields
+			}
+			return precisionTypeSQL(name, iv.Precision), nil
+		case DialectBigQuery:
+			return "INTERVAL", nil
+		}
+		return "", fmt.Errorf("interval types are not supported by %s", dialect)
+	case *DataType_DoubleData:
+		switch dialect {
+		case DialectPostgres:
+			return "DOUBLE PRECISION", nil
+		case DialectUnknown:
+			if t.DoubleData.IsDoublePrecision {
+				return "DOUBLE PRECISION", nil
+			}
+		case DialectSQLite:
+			return "REAL", nil
+		case DialectBigQuery:
+			return "FLOAT64", nil
+		}
+		return "DOUBLE", nil
+	case *DataType_FloatData:
+		switch dialect {
+		case DialectBigQuery:
+			return "FLOAT64", nil
+		case DialectSQLite:
+			return "REAL", nil
+		}
+		return sizedTypeSQL("FLOAT", t.FloatData.Size), nil
+	case *DataType_RealData:
+		if dialect == DialectBigQuery {
+			return "FLOAT64", nil
+		}
+		return "REAL", nil
+	case *DataType_BitData:
+		switch dialect {
+		case DialectPostgres, DialectUnknown:
+			if t.BitData.Varying {
+				return sizedTypeSQL("BIT VARYING", t.BitData.Size), nil
+			}
+			return sizedTypeSQL("BIT", t.BitData.Size), nil
+		case DialectMySQL:
+			return sizedTypeSQL("BIT", t.BitData.Size), nil
+		}
+		return "", fmt.Errorf("bit types are not supported by %s", dialect)
+	case *DataType_RegclassData:
+		if dialect != DialectPostgres && dialect != DialectUnknown {
+			return "", fmt.Errorf("regclass is not supported by %s", dialect)
+		}
+		return "REGCLASS", nil
+	case *DataType_ByteaData:
+		switch dialect {
+		case DialectPostgres:
+			return "BYTEA", nil
+		case DialectBigQuery:
+			return "BYTES", nil
+		}
+		return "BLOB", nil
+	case *DataType_CollateData:
+		inner, err := dataTypeSQL(t.CollateData.Type, dialect)
+		if err != nil {
+			return "", err
+		}
+		if t.CollateData.CollationName == "" {
+			return inner, nil
+		}
+		if dialect == DialectPostgres {
+			return inner + " COLLATE " + quoteIdent(t.CollateData.CollationName, dialect), nil
+		}
+		return inner + " COLLATE " + t.CollateData.CollationName, nil
+	case *DataType_EnumData:
+		// A Postgres enum type is referenced by name; CREATE TYPE defines it
+		if dialect == DialectPostgres && t.EnumData.TypeName != nil {
+			return formatObjectName(t.EnumData.TypeName), nil
+		}
+		if dialect != DialectMySQL && dialect != DialectUnknown {
+			return "", fmt.Errorf("inline ENUM types are not supported by %s", dialect)
+		}
+		return "ENUM(" + quoteStrings(t.EnumData.Values) + ")", nil
+	case *DataType_SetData:
+		if dialect != DialectMySQL && dialect != DialectUnknown {
+			return "", fmt.Errorf("SET types are not supported by %s", dialect)
+		}
+		return "SET(" + quoteStrings(t.SetData.Values) + ")", nil
+	case *DataType_YearData:
+		if dialect == DialectMySQL || dialect == DialectUnknown {
+			return "YEAR", nil
+		}
+		return intTypeSQL("SMALLINT", "SMALLINT", false, dialect), nil
+	case *DataType_JSONData:
+		switch dialect {
+		case DialectSQLite:
+			return "TEXT", nil
+		case DialectPostgres, DialectUnknown:
+			if t.JSONData == DataTypeSingle_JSONB {
+				return "JSONB", nil
+			}
+		}
+		return "JSON", nil
+	case *DataType_NetworkData:
+		kind := strings.ToUpper(strings.TrimPrefix(t.NetworkData.Kind.String(), "NetworkKind_"))
+		switch dialect {
+		case DialectPostgres, DialectUnknown:
+			return kind, nil
+		case DialectMySQL:
+			// Long enough for an IPv6 address with a prefix length
+			return "VARCHAR(43)", nil
+		case DialectBigQuery:
+			return "STRING", nil
+		}
+		return "TEXT", nil
+	case *DataType_XMLData:
+		if dialect == DialectPostgres || dialect == DialectUnknown {
+			return "XML", nil
+		}
+		if dialect == DialectBigQuery {
+			return "STRING", nil
+		}
+		return "TEXT", nil
+	}
+
+	return "", fmt.Errorf("unsupported data type %T", dt.TypeClause)
+}
+
+// intTypeSQL renders an integer type. myName is the MySQL (and neutral)
+// spelling and stdName the spelling used by Postgres and SQLite; BigQuery
+// only has INT64.
+func intTypeSQL(myName, stdName string, unsigned bool, dialect Dialect) string {
+	switch dialect {
+	case DialectMySQL, DialectUnknown:
+		if unsigned {
+			return myName + " UNSIGNED"
+		}
+		return myName
+	case DialectBigQuery:
+		return "INT64"
+	case DialectSQLite:
+		return "INTEGER"
+	}
+	return stdName
+}
+
+// sizedTypeSQL appends an optional length to a type name.
+func sizedTypeSQL(name string, size uint32) string {
+	if size == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s(%d)", name, size)
+}
+
+// precisionTypeSQL appends a fractional seconds precision to a temporal
+// type name when one is set, as in "TIMESTAMP(3)".
+func precisionTypeSQL(name string, precision *uint32) string {
+	if precision == nil {
+		return name
+	}
+	return fmt.Sprintf("%s(%d)", name, *precision)
+}
+
+// quoteStrings renders a list of SQL string literals separated by commas.
+func quoteStrings(values []string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = quoteString(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,75 @@
+package xmeta
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseDataType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *DataType
+	}{
+		{"numeric(10,2)", &DataType{TypeClause: &DataType_DecimalData{DecimalData: &Decimal{Precision: 10, Scale: 2}}}},
+		{"varchar(255)", &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 255}}}},
+		{"INT UNSIGNED", &DataType{TypeClause: &DataType_IntData{IntData: &Int{IsUnsigned: true}}}},
+		{"text[]", &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}}}}},
+		{"geometry", &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: []string{"geometry"}}}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseDataType(tt.in)
+		if err != nil {
+			t.Errorf("ParseDataType(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("ParseDataType(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "  ", "numeric(10,2"} {
+		if _, err := ParseDataType(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestFormatDataType_RoundTrip(t *testing.T) {
+	for _, in := range []string{
+		"NUMERIC(10,2)", "VARCHAR(255)", "CHAR(2)", "TEXT", "INT UNSIGNED", "TINYINT", "BIGINT",
+		"TIMESTAMP WITH TIME ZONE", "DOUBLE PRECISION", "BIT VARYING(8)", "UUID", "JSON", "XML",
+		"INT[]", "ENUM('a', 'b,c')", "YEAR", "TIMESTAMP(0)", "TIMESTAMP(3) WITH TIME ZONE", "TIME",
+		"TIME(6) WITH TIME ZONE", "INTERVAL", "INTERVAL DAY TO SECOND(3)", "JSONB", "INET", "CIDR", "MACADDR8",
+	} {
+		dt, err := ParseDataType(in)
+		if err != nil {
+			t.Fatalf("ParseDataType(%q) failed: %v", in, err)
+		}
+		if got := FormatDataType(dt); got != in {
+			t.Errorf("FormatDataType(ParseDataType(%q)) = %q", in, got)
+		}
+	}
+
+	if FormatDataType(nil) != "" {
+		t.Error("Expected empty string for nil DataType")
+	}
+}
+
+func TestTimestampPrecision(t *testing.T) {
+	p0, _ := ParseDataType("timestamp(0)")
+	p6, _ := ParseDataType("timestamp(6)")
+	if proto.Equal(p0, p6) {
+		t.Error("Expected timestamp(0) and timestamp(6) to differ")
+	}
+	if got, _ := dataTypeSQL(p6, DialectMySQL); got != "DATETIME(6)" {
+		t.Errorf("Unexpected MySQL type %q", got)
+	}
+	if got, _ := dataTypeSQL(p6, DialectBigQuery); got != "DATETIME" {
+		t.Errorf("Unexpected BigQuery type %q", got)
+	}
+	interval, _ := ParseDataType("interval")
+	if _, err := dataTypeSQL(interval, DialectMySQL); err == nil {
+		t.Error("Expected an error for an interval in MySQL")
+	}
+}
@@ -3,26 +3,123 @@ package xmeta
 // diff.go implements the schema comparison logic.
 
 import (
+	"maps"
+	"slices"
+	"sort"
 	"strings"
+	"unicode"
 
 	"google.golang.org/protobuf/proto"
 )
 
+// DiffOptions controls how DiffDatabaseWithOptions matches and compares objects.
+type DiffOptions struct {
+	// MatchSimpleNames matches tables by their last identifier only, ignoring
+	// the schema qualifier. Useful for single-schema databases whose table
+	// names are qualified differently on each side. No schema changes are
+	// reported in this mode.
+	MatchSimpleNames bool
+	// DetectColumnOrder reports existing columns whose position changed as
+	// AlterColumnPosition changes.
+	DetectColumnOrder bool
+	// Normalize, when set, normalizes copies of both databases with
+	// NormalizeMetaDatabase before comparing them.
+	Normalize *NormalizeOptions
+	// AlterConstraints reports a constraint that exists on both sides but
+	// differs as a single AlterConstraint instead of a drop and re-add.
+	AlterConstraints bool
+	// Dialect is the dialect both databases are written in. Column defaults
+	// are compared by meaning, so that e.g. nextval('seq'::regclass) matches
+	// nextval('seq') and now() matches CURRENT_TIMESTAMP; the dialect adds
+	// its own equivalences, such as 't' for true in Postgres.
+	Dialect Dialect
+	// DetectRenames reports a dropped table and an added table in the same
+	// schema as a RenameTable when the added table has every column of the
+	// dropped one unchanged and the same constraints up to their names. The
+	// remaining differences are diffed against the renamed table. Tables
+	// with more than one such counterpart are left as drop and add.
+	DetectRenames bool
+	// IgnoreImplicit leaves constraints and indexes that the database
+	// creates on its own out of the diff, on both sides. See IsImplicit and
+	// IsImplicitIndex for the heuristics.
+	IgnoreImplicit bool
+	// TypeEquivalence, when set, compares column types with
+	// DataTypesEquivalent under the policy instead of requiring them to be
+	// identical. An equivalent type keeps its current spelling.
+	TypeEquivalence *EquivalencePolicy
+}
+
 // DiffDatabase compares two MetaDatabase states and returns the changes needed
-// to transform 'current' into 'desired'.
+// to transform 'current' into 'desired'. A nil database is treated as empty.
 func DiffDatabase(current, desired *MetaDatabase) []SchemaChange {
+	return DiffDatabaseWithOptions(current, desired, DiffOptions{})
+}
+
+// DiffDatabaseWithOptions is DiffDatabase with explicit options.
+func DiffDatabaseWithOptions(current, desired *MetaDatabase, opts DiffOptions) []SchemaChange {
 	var changes []SchemaChange
+	diffDatabase(current, desired, opts, func(c ...SchemaChange) bool {
+		changes = append(changes, c...)
+		return true
+	})
+	SortChanges(changes)
+	return changes
+}
+
+// diffDatabase compares current and desired table by table, handing the
+// unsorted changes of each table, and then of the views and triggers, to
+// add as soon as they are computed. It stops when add returns false.
+func diffDatabase(current, desired *MetaDatabase, opts DiffOptions, add func(...SchemaChange) bool) {
+	if current == nil {
+		current = &MetaDatabase{}
+	}
+	if desired == nil {
+		desired = &MetaDatabase{}
+	}
+	if opts.Normalize != nil {
+		current = CloneMetaDatabase(current)
+		desired = CloneMetaDatabase(desired)
+		NormalizeMetaDatabase(current, *opts.Normalize)
+		NormalizeMetaDatabase(desired, *opts.Normalize)
+	}
+
+	if alter, ok := diffDatabaseAttributes(current, desired); ok && !add(alter) {
+		return
+	}
 
 	// Build maps for efficient lookup
-	currentTables := tablesByName(current.GetTables())
-	desiredTables := tablesByName(desired.GetTables())
+	keyFunc := objectNameKey
+	if opts.MatchSimpleNames {
+		keyFunc = simpleNameKey
+	}
+	currentTables := tablesByName(current.GetTables(), keyFunc)
+	desiredTables := tablesByName(desired.GetTables(), keyFunc)
+
+	if !opts.MatchSimpleNames && !add(diffSchemas(current.GetTables(), desired.GetTables())...) {
+		return
+	}
+
+	var renamed, renamedFrom map[string]string
+	if opts.DetectRenames {
+		renamed, renamedFrom = detectTableRenames(currentTables, desiredTables)
+	}
+	for currName, desName := range renamed {
+		currTable, desTable := currentTables[currName], desiredTables[desName]
+		if !add(append([]SchemaChange{RenameTable{OldName: currTable.Name, NewName: desTable.Name}}, diffTable(currTable, desTable, opts)...)...) {
+			return
+		}
+	}
 
 	// Find tables to drop (in current but not in desired)
 	for name, currTable := range currentTables {
+		if _, ok := renamed[name]; ok {
+			continue
+		}
 		if _, exists := desiredTables[name]; !exists {
 			// Drop all constraints first (will be ordered by SortChanges)
+			var changes []SchemaChange
 			for _, elem := range currTable.Elements {
-				if tc := elem.GetTableConstraintElement(); tc != nil {
+				if tc := elem.GetTableConstraintElement(); tc != nil && !(opts.IgnoreImplicit && IsImplicit(tc)) {
 					changes = append(changes, DropConstraint{
 						TableName:      currTable.Name,
 						ConstraintName: tc.Name,
@@ -30,105 +127,417 @@ func DiffDatabase(current, desired *MetaDatabase) []SchemaChange {
 					})
 				}
 			}
-			changes = append(changes, DropTable{TableName: currTable.Name})
+			if !add(append(changes, DropTable{TableName: currTable.Name})...) {
+				return
+			}
 		}
 	}
 
 	// Find tables to add (in desired but not in current)
 	for name, desTable := range desiredTables {
-		if _, exists := currentTables[name]; !exists {
-			changes = append(changes, AddTable{Table: desTable})
+		if _, ok := renamedFrom[name]; ok {
+			continue
+		}
+		if _, exists := currentTables[name]; !exists && !add(AddTable{Table: desTable}) {
+			return
 		}
 	}
 
 	// Find tables that exist in both and diff them
 	for name, desTable := range desiredTables {
-		if currTable, exists := currentTables[name]; exists {
-			tableChanges := diffTable(currTable, desTable)
-			changes = append(changes, tableChanges...)
+		if currTable, exists := currentTables[name]; exists && !add(diffTable(currTable, desTable, opts)...) {
+			return
+		}
+	}
+	if add(diffViews(current.GetViews(), desired.GetViews(), keyFunc)...) {
+		add(diffTriggers(current.GetTriggers(), desired.GetTriggers(), keyFunc)...)
+	}
+}
+
+// diffViews reports added and dropped views and compares the options and
+// comments of views present in both databases. IsUpdatable is derived from
+// the definition and ignored. Redefining a view is not diffed, as catalogs
+// rewrite the definition text.
+func diffViews(current, desired []*MetaView, keyFunc func(*ObjectName) string) []SchemaChange {
+	currentViews := make(map[string]*MetaView, len(current))
+	for _, v := range current {
+		currentViews[keyFunc(v.Name)] = v
+	}
+	desiredViews := make(map[string]*MetaView, len(desired))
+	for _, v := range desired {
+		desiredViews[keyFunc(v.Name)] = v
+	}
+	viewOptions := func(v *MetaView) map[string]string {
+		opts, _ := splitTags(v.GetOptions())
+		delete(opts, "IsUpdatable")
+		return opts
+	}
+
+	var changes []SchemaChange
+	for _, curr := range current {
+		if _, ok := desiredViews[keyFunc(curr.Name)]; !ok {
+			changes = append(changes, DropView{ViewName: curr.Name, Materialized: curr.Options["Materialized"] == "true"})
+		}
+	}
+	for _, des := range desired {
+		curr, ok := currentViews[keyFunc(des.Name)]
+		if !ok {
+			changes = append(changes, AddView{View: des})
+			continue
+		}
+		oldOpts, newOpts := viewOptions(curr), viewOptions(des)
+		if curr.Comment != des.Comment || !mapsEqual(oldOpts, newOpts) {
+			changes = append(changes, AlterTableOptions{
+				TableName:      des.Name,
+				OldOptions:     oldOpts,
+				NewOptions:     newOpts,
+				OldComment:     curr.Comment,
+				NewComment:     des.Comment,
+				IsView:         true,
+				ViewDefinition: des.Definition,
+			})
+		}
+	}
+	return changes
+}
+
+// diffTriggers reports added and dropped triggers. A trigger whose
+// definition changed, up to whitespace, is dropped and created again.
+func diffTriggers(current, desired []*MetaTrigger, keyFunc func(*ObjectName) string) []SchemaChange {
+	key := func(t *MetaTrigger) string { return keyFunc(t.TableName) + "." + t.Name }
+	definition := func(t *MetaTrigger) string { return strings.Join(strings.Fields(t.Definition), " ") }
+	desiredTriggers := make(map[string]*MetaTrigger, len(desired))
+	for _, t := range desired {
+		desiredTriggers[key(t)] = t
+	}
+	currentTriggers := make(map[string]*MetaTrigger, len(current))
+	var changes []SchemaChange
+	for _, curr := range current {
+		currentTriggers[key(curr)] = curr
+		if des, ok := desiredTriggers[key(curr)]; !ok || definition(des) != definition(curr) {
+			changes = append(changes, DropTrigger{TableName: curr.TableName, TriggerName: curr.Name})
+		}
+	}
+	for _, des := range desired {
+		if curr, ok := currentTriggers[key(des)]; !ok || definition(des) != definition(curr) {
+			changes = append(changes, AddTrigger{Trigger: des})
+		}
+	}
+	return changes
+}
+
+// detectTableRenames pairs tables that exist only in current with tables
+// that exist only in desired when each is the other's sole rename candidate.
+// It returns the pairs keyed by current name and by desired name.
+func detectTableRenames(current, desired map[string]*MetaTable) (map[string]string, map[string]string) {
+	candidates := make(map[string][]string)
+	targets := make(map[string]int)
+	for currName, currTable := range current {
+		if _, exists := desired[currName]; exists {
+			continue
+		}
+		for desName, desTable := range desired {
+			if _, exists := current[desName]; exists {
+				continue
+			}
+			if isTableRename(currTable, desTable) {
+				candidates[currName] = append(candidates[currName], desName)
+				targets[desName]++
+			}
+		}
+	}
+
+	renamed := make(map[string]string)
+	renamedFrom := make(map[string]string)
+	for currName, desNames := range candidates {
+		if len(desNames) == 1 && targets[desNames[0]] == 1 {
+			renamed[currName] = desNames[0]
+			renamedFrom[desNames[0]] = currName
+		}
+	}
+	return renamed, renamedFrom
+}
+
+// isTableRename reports whether desired could be current under a new name:
+// both are in the same schema, desired has every column of current
+// unchanged, and their constraints agree up to their names.
+func isTableRename(current, desired *MetaTable) bool {
+	currIdents, desIdents := current.GetName().GetIdents(), desired.GetName().GetIdents()
+	if len(currIdents) == 0 || len(currIdents) != len(desIdents) || !slices.Equal(currIdents[:len(currIdents)-1], desIdents[:len(desIdents)-1]) {
+		return false
+	}
+
+	desiredCols := columnsFromElements(desired.Elements)
+	var currCons, desCons []*TableConstraint
+	for _, elem := range current.Elements {
+		if col := elem.GetColumnDefElement(); col != nil {
+			if des, ok := desiredCols[col.Name]; !ok || !proto.Equal(col, des) {
+				return false
+			}
+		} else if tc := elem.GetTableConstraintElement(); tc != nil {
+			currCons = append(currCons, tc)
+		}
+	}
+	for _, elem := range desired.Elements {
+		if tc := elem.GetTableConstraintElement(); tc != nil {
+			desCons = append(desCons, tc)
+		}
+	}
+	if len(currCons) != len(desCons) {
+		return false
+	}
+	for _, tc := range currCons {
+		if !slices.ContainsFunc(desCons, func(d *TableConstraint) bool { return proto.Equal(tc.Spec, d.Spec) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// diffSchemas reports schemas that appear or disappear between the two table
+// sets. A table's schema is its ObjectName without the last identifier.
+func diffSchemas(current, desired []*MetaTable) []SchemaChange {
+	var changes []SchemaChange
+
+	currentSchemas := schemasOf(current)
+	desiredSchemas := schemasOf(desired)
+
+	for key, name := range currentSchemas {
+		if _, exists := desiredSchemas[key]; !exists {
+			changes = append(changes, DropSchema{SchemaName: name})
+		}
+	}
+	for key, name := range desiredSchemas {
+		if _, exists := currentSchemas[key]; !exists {
+			changes = append(changes, AddSchema{SchemaName: name})
 		}
 	}
 
-	SortChanges(changes)
 	return changes
 }
 
 // diffTable compares two tables and returns the changes.
-func diffTable(current, desired *MetaTable) []SchemaChange {
+func diffTable(current, desired *MetaTable, opts DiffOptions) []SchemaChange {
 	var changes []SchemaChange
 
-	// Compare table-level options and comments
-	if current.Comment != desired.Comment || !mapsEqual(current.Options, desired.Options) {
+	if opts.IgnoreImplicit {
+		current, desired = withoutImplicit(current), withoutImplicit(desired)
+	}
+
+	// Compare primary keys in one form whether they were declared inline or
+	// as a table constraint. A key folded from inline flags takes the name
+	// of the other side's key so the two match.
+	current = canonicalPrimaryKey(current, primaryKeyName(desired))
+	desired = canonicalPrimaryKey(desired, primaryKeyName(current))
+
+	// Likewise compare single-column foreign keys as table constraints,
+	// whether written inline with REFERENCES or not.
+	current = canonicalForeignKeys(current, desired)
+	desired = canonicalForeignKeys(desired, current)
+
+	// And CHECK constraints, which the Postgres loader attaches to their
+	// column when they reference only one
+	current = canonicalChecks(current, desired)
+	desired = canonicalChecks(desired, current)
+
+	// And single-column UNIQUE constraints written on the column
+	current = canonicalUniques(current, desired)
+	desired = canonicalUniques(desired, current)
+
+	// Compare table-level options and comments, and tags apart from them
+	currentOpts, currentTags := splitTags(current.Options)
+	desiredOpts, desiredTags := splitTags(desired.Options)
+	if current.Comment != desired.Comment || !mapsEqual(currentOpts, desiredOpts) {
 		changes = append(changes, AlterTableOptions{
 			TableName:  desired.Name,
 			OldComment: current.Comment,
 			NewComment: desired.Comment,
-			OldOptions: current.Options,
-			NewOptions: desired.Options,
+			OldOptions: currentOpts,
+			NewOptions: desiredOpts,
 		})
 	}
+	if !mapsEqual(currentTags, desiredTags) {
+		changes = append(changes, AlterTags{TableName: desired.Name, OldTags: currentTags, NewTags: desiredTags})
+	}
+
+	// A partition inherits its columns, constraints and indexes from the
+	// parent, whose own diff covers them
+	if current.Options["PartitionOf"] != "" && desired.Options["PartitionOf"] != "" {
+		return changes
+	}
 
 	// Extract columns and constraints from elements
-	currentCols := columnsFromElements(current.Elements)
-	desiredCols := columnsFromElements(desired.Elements)
+	currentCols := orderedColumns(current.Elements)
+	desiredCols := orderedColumns(desired.Elements)
 	currentConstraints := constraintsFromElements(current.Elements)
 	desiredConstraints := constraintsFromElements(desired.Elements)
 
 	// Diff columns
-	colChanges := diffColumns(desired.Name, currentCols, desiredCols)
+	colChanges := diffColumns(desired.Name, currentCols, desiredCols, opts)
 	changes = append(changes, colChanges...)
 
 	// Diff constraints
-	constraintChanges := diffConstraints(desired.Name, currentConstraints, desiredConstraints)
+	constraintChanges := diffConstraints(desired.Name, currentConstraints, desiredConstraints, opts)
 	changes = append(changes, constraintChanges...)
 
+	// Diff indexes
+	changes = append(changes, diffIndexes(desired.Name, current.Indexes, desired.Indexes)...)
+
 	return changes
 }
 
-// diffColumns compares column lists and returns changes.
-func diffColumns(tableName *ObjectName, current, desired map[string]*ColumnDef) []SchemaChange {
+// diffColumns compares column lists, given in declaration order, and returns changes.
+func diffColumns(tableName *ObjectName, current, desired []*ColumnDef, opts DiffOptions) []SchemaChange {
 	var changes []SchemaChange
+	currentByName := columnsByName(current)
+	desiredByName := columnsByName(desired)
 
 	// Find columns to drop
-	for name := range current {
-		if _, exists := desired[name]; !exists {
+	for _, currCol := range current {
+		if _, exists := desiredByName[currCol.Name]; !exists {
 			changes = append(changes, DropColumn{
 				TableName:  tableName,
-				ColumnName: name,
+				ColumnName: currCol.Name,
 			})
 		}
 	}
 
-	// Find columns to add
-	for name, desCol := range desired {
-		if _, exists := current[name]; !exists {
-			changes = append(changes, AddColumn{
+	// Find columns to add, positioned after their predecessor in desired
+	for i, desCol := range desired {
+		if _, exists := currentByName[desCol.Name]; !exists {
+			add := AddColumn{
 				TableName: tableName,
 				Column:    desCol,
-			})
+				First:     i == 0,
+			}
+			if i > 0 {
+				add.After = desired[i-1].Name
+			}
+			changes = append(changes, add)
 		}
 	}
 
-	// Find columns to alter
-	for name, desCol := range desired {
-		if currCol, exists := current[name]; exists {
-			if !columnsEqual(currCol, desCol) {
-				changes = append(changes, AlterColumn{
+	// Find columns to alter. An equivalent default or type keeps its
+	// current spelling so it is not reported as changed.
+	for _, desCol := range desired {
+		if currCol, exists := currentByName[desCol.Name]; exists {
+			if !columnsEqual(currCol, desCol, opts) {
+				if !proto.Equal(currCol.Default, desCol.Default) && defaultsEquivalent(currCol.Default, desCol.Default, opts.Dialect) {
+					desCol = proto.Clone(desCol).(*ColumnDef)
+					desCol.Default = currCol.Default
+				}
+				if !proto.Equal(currCol.DataType, desCol.DataType) && opts.typesEquivalent(currCol.DataType, desCol.DataType) {
+					desCol = proto.Clone(desCol).(*ColumnDef)
+					desCol.DataType = currCol.DataType
+				}
+				alter := AlterColumn{
 					TableName: tableName,
 					OldColumn: currCol,
 					NewColumn: desCol,
+				}
+				// A change of default or nullability alone has its own
+				// minimal, non-destructive statements
+				if fast, ok := alter.FastPath(); ok {
+					changes = append(changes, fast...)
+				} else {
+					changes = append(changes, alter)
+				}
+			}
+			if oldTags, newTags := Tags(currCol), Tags(desCol); !mapsEqual(oldTags, newTags) {
+				changes = append(changes, AlterTags{
+					TableName:  tableName,
+					ColumnName: desCol.Name,
+					OldTags:    oldTags,
+					NewTags:    newTags,
 				})
 			}
 		}
 	}
 
+	if opts.DetectColumnOrder {
+		changes = append(changes, diffColumnOrder(tableName, current, desired)...)
+	}
+
 	return changes
 }
 
+// diffColumnOrder reports the columns present on both sides that must move to
+// reach the desired order. Columns on the longest common subsequence of the
+// two orders stay put; every other column is moved after its desired
+// predecessor, in desired order, so each move targets a column already in place.
+func diffColumnOrder(tableName *ObjectName, current, desired []*ColumnDef) []SchemaChange {
+	currentByName := columnsByName(current)
+	desiredByName := columnsByName(desired)
+
+	var currSeq, desSeq []string
+	for _, col := range current {
+		if _, ok := desiredByName[col.Name]; ok {
+			currSeq = append(currSeq, col.Name)
+		}
+	}
+	for _, col := range desired {
+		if _, ok := currentByName[col.Name]; ok {
+			desSeq = append(desSeq, col.Name)
+		}
+	}
+	stable := longestCommonSubsequence(currSeq, desSeq)
+
+	var changes []SchemaChange
+	for i, col := range desired {
+		if _, ok := currentByName[col.Name]; !ok || stable[col.Name] {
+			continue
+		}
+		move := AlterColumnPosition{TableName: tableName, Column: col, First: i == 0}
+		if i > 0 {
+			move.After = desired[i-1].Name
+		}
+		changes = append(changes, move)
+	}
+	return changes
+}
+
+// longestCommonSubsequence returns the members of one longest common
+// subsequence of a and b.
+func longestCommonSubsequence(a, b []string) map[string]bool {
+	// lengths[i][j] is the LCS length of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	members := make(map[string]bool)
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			members[a[i]] = true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return members
+}
+
 // diffConstraints compares constraint lists and returns changes.
-func diffConstraints(tableName *ObjectName, current, desired map[string]*TableConstraint) []SchemaChange {
+func diffConstraints(tableName *ObjectName, current, desired map[string]*TableConstraint, opts DiffOptions) []SchemaChange {
 	var changes []SchemaChange
 
+	// CHECK constraints are matched by their normalized expression, so a
+	// check named differently on each side is not dropped and re-added
+	desired = matchChecksByExpression(current, desired)
+
 	// Find constraints to drop
 	for name, currCon := range current {
 		if _, exists := desired[name]; !exists {
@@ -150,22 +559,302 @@ func diffConstraints(tableName *ObjectName, current, desired map[string]*TableCo
 		}
 	}
 
-	// Note: Constraint modifications are complex. For v1, we drop and re-add.
-	// A future version could detect in-place modifications.
+	// Find constraints to modify: either in place, or dropped and re-added.
+	// A renamed backing index is always altered, as re-adding the
+	// constraint cannot choose the index name.
+	for name, desCon := range desired {
+		currCon, exists := current[name]
+		if !exists || constraintsEqual(currCon, desCon) {
+			continue
+		}
+		if opts.AlterConstraints || onlyIndexNameDiffers(currCon, desCon) {
+			changes = append(changes, AlterConstraint{
+				TableName:     tableName,
+				OldConstraint: currCon,
+				NewConstraint: desCon,
+			})
+			continue
+		}
+		changes = append(changes,
+			DropConstraint{
+				TableName:      tableName,
+				ConstraintName: name,
+				IsForeignKey:   currCon.Spec.GetReferenceItem() != nil,
+			},
+			AddConstraint{
+				TableName:  tableName,
+				Constraint: desCon,
+			})
+	}
+
+	return changes
+}
+
+// matchChecksByExpression returns desired with each CHECK constraint that
+// has no namesake in current, but has the same normalized expression as a
+// current check without a namesake in desired, renamed to that check.
+// desired is not modified.
+func matchChecksByExpression(current, desired map[string]*TableConstraint) map[string]*TableConstraint {
+	unmatched := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		check := current[name].GetSpec().GetCheckItem()
+		if _, exists := desired[name]; check == nil || exists {
+			continue
+		}
+		if expr := normalizeCheck(anyToString(check)); unmatched[expr] == "" {
+			unmatched[expr] = name
+		}
+	}
+	if len(unmatched) == 0 {
+		return desired
+	}
+
+	matched := maps.Clone(desired)
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		check := desired[name].GetSpec().GetCheckItem()
+		if _, exists := current[name]; check == nil || exists {
+			continue
+		}
+		expr := normalizeCheck(anyToString(check))
+		currName := unmatched[expr]
+		if currName == "" {
+			continue
+		}
+		delete(unmatched, expr)
+		con := proto.Clone(desired[name]).(*TableConstraint)
+		con.Name = currName
+		delete(matched, name)
+		matched[currName] = con
+	}
+	return matched
+}
+
+// constraintsEqual is proto.Equal, except that a unique constraint without
+// IndexName matches one with any backing index name, as only some sources
+// know the index, and CHECK expressions are compared by normalizeCheck.
+func constraintsEqual(a, b *TableConstraint) bool {
+	if ca, cb := a.GetSpec().GetCheckItem(), b.GetSpec().GetCheckItem(); ca != nil && cb != nil && !proto.Equal(ca, cb) {
+		if normalizeCheck(anyToString(ca)) != normalizeCheck(anyToString(cb)) {
+			return false
+		}
+		b = proto.Clone(b).(*TableConstraint)
+		b.Spec.TableConstraintSpecClause = &TableConstraintSpec_CheckItem{CheckItem: ca}
+		return proto.Equal(a, b)
+	}
+	ua, ub := a.GetSpec().GetUniqueItem(), b.GetSpec().GetUniqueItem()
+	if ua == nil || ub == nil || (ua.IndexName != "" && ub.IndexName != "") {
+		return proto.Equal(a, b)
+	}
+	b = proto.Clone(b).(*TableConstraint)
+	b.Spec.GetUniqueItem().IndexName = ua.IndexName
+	return proto.Equal(a, b)
+}
+
+// castTypeWords are the words a Postgres cast type name may continue with,
+// as in ::character varying or ::timestamp without time zone.
+var castTypeWords = map[string]bool{"varying": true, "precision": true, "without": true, "with": true, "time": true, "zone": true}
+
+// normalizeCheck returns the form CHECK expressions are compared in. The
+// CHECK keyword and a NOT VALID suffix as catalogs print them, whitespace,
+// letter case and quoting of names, Postgres casts and parentheses around
+// the whole expression or a single operand are ignored, so that
+// "CHECK ((price > (0)::numeric))" and "price>0" compare equal. An
+// expression that cannot be tokenized is only trimmed.
+func normalizeCheck(expr string) string {
+	toks, err := tokenizeSQL(expr, DialectPostgres)
+	if err != nil {
+		return strings.TrimSpace(expr)
+	}
+	var words []string
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		if t.is(":") && i+2 < len(toks) && toks[i+1].is(":") && toks[i+2].kind != sqlPunct {
+			i += 2
+			for i+1 < len(toks) && toks[i+1].kind == sqlWord && castTypeWords[strings.ToLower(toks[i+1].text)] {
+				i++
+			}
+			if i+1 < len(toks) && toks[i+1].is("(") {
+				for i++; i < len(toks) && !toks[i].is(")"); i++ {
+				}
+			}
+			for i+2 < len(toks) && toks[i+1].is("[") && toks[i+2].is("]") {
+				i += 2
+			}
+			continue
+		}
+		switch t.kind {
+		case sqlString:
+			words = append(words, "'"+strings.ReplaceAll(t.text, "'", "''")+"'")
+		case sqlWord, sqlQuotedIdent:
+			words = append(words, strings.ToLower(t.text))
+		default:
+			words = append(words, t.text)
+		}
+	}
+	if len(words) > 0 && words[0] == "check" {
+		words = words[1:]
+	}
+	if n := len(words); n >= 2 && words[n-2] == "not" && words[n-1] == "valid" {
+		words = words[:n-2]
+	}
+
+	// Parentheses around an operand, but not a function's arguments, and
+	// around an operand of AND, OR or NOT that has no AND or OR of its own
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(words) && !changed; i++ {
+			if words[i] != "(" {
+				continue
+			}
+			j := matchingParen(words, i)
+			if j < 0 {
+				break
+			}
+			prev, next := "", ""
+			if i > 0 {
+				prev = words[i-1]
+			}
+			if j+1 < len(words) {
+				next = words[j+1]
+			}
+			operand := j == i+2 && (prev == "" || !isWordToken(prev) || booleanOperators[prev])
+			clause := (prev == "" || prev == "(" || booleanOperators[prev]) && (next == "" || next == ")" || next == "and" || next == "or") &&
+				!hasTopLevelWord(words[i+1:j], "and", "or")
+			if operand || clause {
+				words = slices.Delete(words, j, j+1)
+				words = slices.Delete(words, i, i+1)
+				changed = true
+			}
+		}
+	}
+	return trimOuterParens(strings.Join(words, " "))
+}
+
+var booleanOperators = map[string]bool{"and": true, "or": true, "not": true}
+
+// isWordToken reports whether a normalized token is a name or keyword.
+func isWordToken(s string) bool {
+	r := []rune(s)[0]
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// matchingParen returns the index of the parenthesis closing words[open],
+// or -1.
+func matchingParen(words []string, open int) int {
+	depth := 0
+	for i := open; i < len(words); i++ {
+		switch words[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// hasTopLevelWord reports whether any of targets occurs in words outside
+// parentheses.
+func hasTopLevelWord(words []string, targets ...string) bool {
+	depth := 0
+	for _, w := range words {
+		switch {
+		case w == "(":
+			depth++
+		case w == ")":
+			depth--
+		case depth == 0 && slices.Contains(targets, w):
+			return true
+		}
+	}
+	return false
+}
+
+// onlyIndexNameDiffers reports whether unique constraints a and b differ
+// in nothing but the name of their backing index.
+func onlyIndexNameDiffers(a, b *TableConstraint) bool {
+	ua, ub := a.GetSpec().GetUniqueItem(), b.GetSpec().GetUniqueItem()
+	if ua == nil || ub == nil || ua.IndexName == ub.IndexName {
+		return false
+	}
+	b = proto.Clone(b).(*TableConstraint)
+	b.Spec.GetUniqueItem().IndexName = ua.IndexName
+	return proto.Equal(a, b)
+}
+
+// diffIndexes compares index lists by name. An index whose definition
+// changed is dropped and recreated, as indexes cannot be altered in place.
+func diffIndexes(tableName *ObjectName, current, desired []*MetaIndex) []SchemaChange {
+	var changes []SchemaChange
+
+	currentByName := make(map[string]*MetaIndex, len(current))
+	for _, idx := range current {
+		currentByName[idx.Name] = idx
+	}
+	desiredByName := make(map[string]*MetaIndex, len(desired))
+	for _, idx := range desired {
+		desiredByName[idx.Name] = idx
+	}
 
+	for _, idx := range current {
+		if des, exists := desiredByName[idx.Name]; !exists || !indexesEqual(idx, des) {
+			changes = append(changes, DropIndex{TableName: tableName, IndexName: idx.Name})
+		}
+	}
+	for _, idx := range desired {
+		if curr, exists := currentByName[idx.Name]; !exists || !indexesEqual(curr, idx) {
+			changes = append(changes, AddIndex{TableName: tableName, Index: idx})
+		}
+	}
 	return changes
 }
 
+// indexesEqual compares the parts of two indexes that need a rebuild to
+// change. An empty method stands for the default, btree.
+func indexesEqual(a, b *MetaIndex) bool {
+	method := func(m string) string {
+		if m == "" {
+			return "btree"
+		}
+		return strings.ToLower(m)
+	}
+	return a.IsUnique == b.IsUnique &&
+		method(a.Method) == method(b.Method) &&
+		slices.Equal(a.Columns, b.Columns) &&
+		a.Expression == b.Expression &&
+		a.Predicate == b.Predicate &&
+		mapsEqual(a.Options, b.Options)
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
 
-// tablesByName creates a map of tables keyed by their qualified name.
-func tablesByName(tables []*MetaTable) map[string]*MetaTable {
+// tablesByName creates a map of tables keyed by their name, as rendered by
+// key (objectNameKey for the qualified name, simpleNameKey for the last ident).
+func tablesByName(tables []*MetaTable, key func(*ObjectName) string) map[string]*MetaTable {
 	m := make(map[string]*MetaTable, len(tables))
 	for _, t := range tables {
-		key := objectNameKey(t.Name)
-		m[key] = t
+		if t != nil {
+			m[key(t.Name)] = t
+		}
+	}
+	return m
+}
+
+// schemasOf collects the distinct schema qualifiers of the tables.
+func schemasOf(tables []*MetaTable) map[string]*ObjectName {
+	m := make(map[string]*ObjectName)
+	for _, t := range tables {
+		idents := t.GetName().GetIdents()
+		if len(idents) < 2 {
+			continue
+		}
+		schema := &ObjectName{Idents: idents[:len(idents)-1]}
+		m[objectNameKey(schema)] = schema
 	}
 	return m
 }
@@ -178,6 +867,14 @@ func objectNameKey(on *ObjectName) string {
 	return strings.Join(on.Idents, ".")
 }
 
+// simpleNameKey returns the last identifier of an ObjectName.
+func simpleNameKey(on *ObjectName) string {
+	if on == nil || len(on.Idents) == 0 {
+		return ""
+	}
+	return on.Idents[len(on.Idents)-1]
+}
+
 // columnsFromElements extracts columns from TableElements into a map.
 func columnsFromElements(elems []*TableElement) map[string]*ColumnDef {
 	m := make(map[string]*ColumnDef)
@@ -189,6 +886,26 @@ func columnsFromElements(elems []*TableElement) map[string]*ColumnDef {
 	return m
 }
 
+// orderedColumns extracts ColumnDefs from TableElements in declaration order.
+func orderedColumns(elems []*TableElement) []*ColumnDef {
+	var cols []*ColumnDef
+	for _, elem := range elems {
+		if col := elem.GetColumnDefElement(); col != nil {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// columnsByName indexes columns by name.
+func columnsByName(cols []*ColumnDef) map[string]*ColumnDef {
+	m := make(map[string]*ColumnDef, len(cols))
+	for _, col := range cols {
+		m[col.Name] = col
+	}
+	return m
+}
+
 // constraintsFromElements extracts named constraints from TableElements.
 func constraintsFromElements(elems []*TableElement) map[string]*TableConstraint {
 	m := make(map[string]*TableConstraint)
@@ -200,34 +917,145 @@ func constraintsFromElements(elems []*TableElement) map[string]*TableConstraint
 	return m
 }
 
-// columnsEqual compares two ColumnDefs for equality.
-func columnsEqual(a, b *ColumnDef) bool {
+// typesEquivalent compares column types under opts.TypeEquivalence, or
+// exactly when it is nil.
+func (opts DiffOptions) typesEquivalent(a, b *DataType) bool {
+	if opts.TypeEquivalence == nil {
+		return proto.Equal(a, b)
+	}
+	return DataTypesEquivalent(a, b, *opts.TypeEquivalence)
+}
+
+// columnsEqual compares two ColumnDefs for equality, with defaults
+// compared by defaultsEquivalent and types by opts.TypeEquivalence.
+func columnsEqual(a, b *ColumnDef, opts DiffOptions) bool {
 	if a.Name != b.Name {
 		return false
 	}
 	if a.Comment != b.Comment {
 		return false
 	}
-	// Compare DataType using proto.Equal for deep comparison
-	if !proto.Equal(a.DataType, b.DataType) {
+	if !opts.typesEquivalent(a.DataType, b.DataType) {
 		return false
 	}
-	// Compare Default (both are Any, use proto.Equal)
-	if !proto.Equal(a.Default, b.Default) {
+	if !defaultsEquivalent(a.Default, b.Default, opts.Dialect) {
 		return false
 	}
-	// For v1, skip detailed constraint comparison within column
-	// Future: compare Constraints slice
+	if isNotNull(a) != isNotNull(b) {
+		return false
+	}
+	// Options cover generation, charset, collation and the like
+	if len(changedColumnOptions(a, b)) > 0 {
+		return false
+	}
+	// Primary key, foreign key, CHECK and UNIQUE column constraints are
+	// compared as table constraints, see diffTable
 	return true
 }
 
-// mapsEqual compares two string maps.
-func mapsEqual(a, b map[string]string) bool {
-	if len(a) != len(b) {
-		return false
+// diffDatabaseAttributes compares the name and options of two databases.
+// A name or option set on one side only is left alone, as a schema file
+// seldom names its database or states the server defaults.
+func diffDatabaseAttributes(current, desired *MetaDatabase) (AlterDatabase, bool) {
+	alter := AlterDatabase{
+		OldName:    current.GetName(),
+		NewName:    desired.GetName(),
+		OldOptions: current.GetOptions(),
+		NewOptions: desired.GetOptions(),
 	}
+	if alter.OldName == "" {
+		alter.OldName = alter.NewName
+	} else if alter.NewName == "" {
+		alter.NewName = alter.OldName
+	}
+	if alter.OldName == alter.NewName && len(changedDatabaseOptions(alter.OldOptions, alter.NewOptions)) == 0 {
+		return AlterDatabase{}, false
+	}
+	return alter, true
+}
+
+// informationalDatabaseOptions record where a MetaDatabase was loaded from,
+// and are ignored when diffing.
+var informationalDatabaseOptions = map[string]bool{
+	"SourceDialect": true,
+	"ServerVersion": true,
+}
+
+// changedDatabaseOptions returns the sorted keys of the database options
+// set on both sides to different values. Charset and Collation compare
+// case-insensitively.
+func changedDatabaseOptions(a, b map[string]string) []string {
+	var changed []string
+	for _, key := range slices.Sorted(maps.Keys(a)) {
+		va, vb := a[key], b[key]
+		switch {
+		case informationalDatabaseOptions[key], va == "" || vb == "":
+		case inheritedColumnOptions[key]:
+			if !strings.EqualFold(va, vb) {
+				changed = append(changed, key)
+			}
+		case va != vb:
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// informationalColumnOptions describe a column without being part of its
+// definition, and are ignored when diffing.
+var informationalColumnOptions = map[string]bool{
+	"IdentitySequence": true, // derived from the table and column names
+	"Serial":           true, // the pseudo-type a file declared; the default is compared
+}
+
+// inheritedColumnOptions default to the table's setting when unset, so they
+// only differ when both sides set them. Their values are case-insensitive.
+var inheritedColumnOptions = map[string]bool{
+	"Charset":   true,
+	"Collation": true,
+}
+
+// changedColumnOptions returns the sorted keys of the options that differ
+// between two columns.
+func changedColumnOptions(a, b *ColumnDef) []string {
+	var keys []string
+	for key := range a.Options {
+		keys = append(keys, key)
+	}
+	for key := range b.Options {
+		if _, ok := a.Options[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changed []string
+	for _, key := range keys {
+		va, vb := a.Options[key], b.Options[key]
+		switch {
+		case informationalColumnOptions[key], strings.HasPrefix(key, tagPrefix):
+		case inheritedColumnOptions[key]:
+			if va != "" && vb != "" && !strings.EqualFold(va, vb) {
+				changed = append(changed, key)
+			}
+		case va != vb:
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// mapsEqual compares two string maps. A nil map equals an empty one, and a
+// key set to "" equals a missing key, so loaders that always allocate their
+// options map compare equal to files that omit it.
+func mapsEqual(a, b map[string]string) bool {
 	for k, v := range a {
-		if bv, ok := b[k]; !ok || bv != v {
+		if b[k] != v {
+			return false
+		}
+	}
+	for k, v := range b {
+		if a[k] != v {
 			return false
 		}
 	}
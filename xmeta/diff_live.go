@@ -0,0 +1,64 @@
+package xmeta
+
+// diff_live.go compares two live databases, possibly of different dialects.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DiffLive loads the source and destination databases with LoadMetaDatabase
+// and returns the changes that make the destination match the source. Tables
+// are matched by their bare name, and the changes name tables unqualified so
+// they apply to the destination's default schema or database. Column
+// defaults are compared by meaning, see DiffOptions.Dialect. When the
+// dialects differ, column types are compared under
+// CrossDialectEquivalencePolicy and dialect-specific table and column
+// options are ignored.
+func DiffLive(ctx context.Context, srcDB *sql.DB, srcDialect Dialect, srcName string, dstDB *sql.DB, dstDialect Dialect, dstName string) ([]SchemaChange, error) {
+	src, err := LoadMetaDatabase(ctx, srcDB, srcDialect, srcName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source: %w", err)
+	}
+	dst, err := LoadMetaDatabase(ctx, dstDB, dstDialect, dstName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load destination: %w", err)
+	}
+
+	crossDialect := srcDialect != dstDialect
+	opts := DiffOptions{Dialect: dstDialect}
+	if crossDialect {
+		opts.Dialect = DialectUnknown
+		opts.TypeEquivalence = &CrossDialectEquivalencePolicy
+	}
+	return DiffDatabaseWithOptions(prepareLiveDiff(dst, crossDialect), prepareLiveDiff(src, crossDialect), opts), nil
+}
+
+// prepareLiveDiff returns a copy of db without its name, so that the two
+// databases are not reported as a rename, with unqualified table names and,
+// across dialects, no options.
+func prepareLiveDiff(db *MetaDatabase, crossDialect bool) *MetaDatabase {
+	db = CloneMetaDatabase(db)
+	db.Name = ""
+	if crossDialect {
+		db.Options = nil
+	}
+	for _, t := range db.Tables {
+		t.Name = &ObjectName{Idents: []string{simpleNameKey(t.Name)}}
+		for _, elem := range t.Elements {
+			if ref := elem.GetTableConstraintElement().GetSpec().GetReferenceItem(); ref.GetKeyExpr() != nil {
+				ref.KeyExpr.TableName = simpleNameKey(&ObjectName{Idents: strings.Split(ref.KeyExpr.TableName, ".")})
+			}
+		}
+		if !crossDialect {
+			continue
+		}
+		t.Options = nil
+		for _, col := range orderedColumns(t.Elements) {
+			col.Options = nil
+		}
+	}
+	return db
+}
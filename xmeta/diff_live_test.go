@@ -0,0 +1,52 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestPrepareLiveDiff_CrossDialect(t *testing.T) {
+	my := MYDatabaseToMetaDatabase(&MYDatabase{
+		Name: "shop",
+		Tables: []*MYTable{{
+			Name:   &ObjectName{Idents: []string{"shop", "users"}},
+			Engine: "InnoDB",
+			Columns: []*MYColumn{
+				{Name: "id", DataType: mapMySQLTypeForProto("int", "int", 0, 0, 0), IsPrimaryKey: true},
+				{Name: "active", DataType: mapMySQLTypeForProto("tinyint", "tinyint(1)", 0, 0, 0)},
+				{Name: "score", DataType: mapMySQLTypeForProto("double", "double", 0, 0, 0), IsNullable: true},
+				{Name: "created_at", DataType: mapMySQLTypeForProto("datetime", "datetime", 0, 0, 0), Charset: "utf8mb4"},
+			},
+		}},
+	})
+	pg := PGDatabaseToMetaDatabase(&PGDatabase{
+		Name: "app",
+		Schemas: []*PGSchema{{Name: "public", Tables: []*PGTable{{
+			Name: &ObjectName{Idents: []string{"public", "users"}},
+			Columns: []*PGColumn{
+				{Name: "id", DataType: mapPostgresTypeForProto("integer", "int4"), IsPrimaryKey: true},
+				{Name: "active", DataType: mapPostgresTypeForProto("boolean", "bool")},
+				{Name: "score", DataType: mapPostgresTypeForProto("double precision", "float8"), IsNullable: true},
+				{Name: "created_at", DataType: mapPostgresTypeForProto("timestamp without time zone", "timestamp")},
+			},
+		}}}},
+	})
+
+	if changes := DiffDatabase(pg, my); len(changes) == 0 {
+		t.Fatal("Expected raw models to differ")
+	}
+	opts := DiffOptions{TypeEquivalence: &CrossDialectEquivalencePolicy}
+	changes := DiffDatabaseWithOptions(prepareLiveDiff(pg, true), prepareLiveDiff(my, true), opts)
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	// A real type change still shows
+	my.Tables[0].Elements[2].GetColumnDefElement().DataType = mapMySQLTypeForProto("varchar", "varchar(20)", 0, 0, 20)
+	changes = DiffDatabaseWithOptions(prepareLiveDiff(pg, true), prepareLiveDiff(my, true), opts)
+	if len(changes) != 1 {
+		t.Fatalf("Expected one AlterColumn, got %v", changes)
+	}
+	if alter, ok := changes[0].(AlterColumn); !ok || objectNameKey(alter.TableName) != "users" {
+		t.Errorf("Expected AlterColumn on unqualified users, got %v", changes[0])
+	}
+}
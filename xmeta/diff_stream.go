@@ -0,0 +1,77 @@
+package xmeta
+
+// diff_stream.go emits the changes of a schema comparison through a
+// callback instead of collecting them in one sorted slice.
+
+import "sort"
+
+// DiffDatabaseStream is DiffDatabase for very large schemas: it passes the
+// changes to emit in the order DiffDatabase returns them, as they are
+// computed, so the whole list is never held. The databases are compared
+// once to find the priority phases that have changes and then once per
+// phase, emitting the changes of that phase table by table and dropping the
+// rest. Only AddTable changes are collected, as they are ordered by their
+// foreign keys within the phase. This trades time, one pass per phase, for
+// memory. It stops at, and returns, the first error from emit.
+func DiffDatabaseStream(current, desired *MetaDatabase, emit func(SchemaChange) error) error {
+	return DiffDatabaseStreamWithOptions(current, desired, DiffOptions{}, emit)
+}
+
+// DiffDatabaseStreamWithOptions is DiffDatabaseStream with explicit options.
+func DiffDatabaseStreamWithOptions(current, desired *MetaDatabase, opts DiffOptions, emit func(SchemaChange) error) error {
+	// Normalize once rather than in every pass
+	if opts.Normalize != nil {
+		current = CloneMetaDatabase(current)
+		desired = CloneMetaDatabase(desired)
+		NormalizeMetaDatabase(current, *opts.Normalize)
+		NormalizeMetaDatabase(desired, *opts.Normalize)
+		opts.Normalize = nil
+	}
+
+	seen := make(map[int]bool)
+	diffDatabase(current, desired, opts, func(changes ...SchemaChange) bool {
+		for _, c := range changes {
+			seen[c.Priority()] = true
+		}
+		return true
+	})
+	priorities := make([]int, 0, len(seen))
+	for p := range seen {
+		priorities = append(priorities, p)
+	}
+	sort.Ints(priorities)
+
+	var err error
+	for _, p := range priorities {
+		var adds []SchemaChange
+		diffDatabase(current, desired, opts, func(changes ...SchemaChange) bool {
+			for _, c := range changes {
+				switch {
+				case c.Priority() != p:
+				case isAddTable(c):
+					adds = append(adds, c)
+				default:
+					if err = emit(c); err != nil {
+						return false
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		orderAddTables(adds, nil)
+		for _, c := range adds {
+			if err := emit(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isAddTable(c SchemaChange) bool {
+	_, ok := c.(AddTable)
+	return ok
+}
@@ -0,0 +1,164 @@
+package xmeta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDiffDatabaseStream(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE legacy (id INTEGER PRIMARY KEY);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(100) NOT NULL, email TEXT);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER);
+CREATE TABLE items (id INTEGER PRIMARY KEY, order_id INTEGER REFERENCES orders (id));
+CREATE INDEX users_email ON users (email);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	var streamed []SchemaChange
+	if err := DiffDatabaseStream(current, desired, func(c SchemaChange) error {
+		streamed = append(streamed, c)
+		return nil
+	}); err != nil {
+		t.Fatalf("DiffDatabaseStream failed: %v", err)
+	}
+	for i := 1; i < len(streamed); i++ {
+		if streamed[i-1].Priority() > streamed[i].Priority() {
+			t.Errorf("Expected changes in priority order, got %T before %T", streamed[i-1], streamed[i])
+		}
+	}
+
+	describe := func(changes []SchemaChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, fmt.Sprintf("%d %s %s", c.Priority(), reflect.TypeOf(c).Name(), objectNameKey(changeTableName(c))))
+		}
+		sort.Strings(out)
+		return out
+	}
+	want := describe(DiffDatabase(current, desired))
+	if got := describe(streamed); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the changes of DiffDatabase\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = DiffDatabaseStream(current, desired, func(SchemaChange) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("Expected the first emit error after one change, got %v after %d", err, count)
+	}
+}
+
+// syntheticSchema builds n tables of ten columns, each referencing the one
+// before, with every column typed as typ.
+func syntheticSchema(n int, typ *DataType) *MetaDatabase {
+	db := &MetaDatabase{Name: "bench"}
+	for i := 0; i < n; i++ {
+		table := &MetaTable{Name: &ObjectName{Idents: []string{"public", fmt.Sprintf("t%04d", i)}}}
+		for j := 0; j < 10; j++ {
+			table.Elements = append(table.Elements, &TableElement{TableElementClause: &TableElement_ColumnDefElement{
+				ColumnDefElement: &ColumnDef{Name: fmt.Sprintf("c%d", j), DataType: typ},
+			}})
+		}
+		if i > 0 {
+			table.Elements = append(table.Elements, &TableElement{TableElementClause: &TableElement_TableConstraintElement{
+				TableConstraintElement: &TableConstraint{
+					Name: fmt.Sprintf("fk_t%04d", i),
+					Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+						ReferenceItem: &ReferentialTableConstraint{
+							Columns: []string{"c0"},
+							KeyExpr: &ReferenceKeyExpr{TableName: fmt.Sprintf("public.t%04d", i-1), Columns: []string{"c0"}},
+						},
+					}},
+				},
+			}})
+		}
+		db.Tables = append(db.Tables, table)
+	}
+	return db
+}
+
+func benchmarkSchemas() (*MetaDatabase, *MetaDatabase) {
+	current := syntheticSchema(2000, &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}})
+	desired := syntheticSchema(2000, &DataType{TypeClause: &DataType_BigIntData{BigIntData: &BigInt{}}})
+	return current, desired
+}
+
+// heapInUse returns the live heap after a collection.
+func heapInUse() int64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
+
+// peakDiffMemory returns the most heap, above what was live before, that
+// DiffDatabase and DiffDatabaseStream hold while their changes are out:
+// the result of DiffDatabase, and the stream sampled every 1000 changes.
+func peakDiffMemory(current, desired *MetaDatabase) (full, stream int64) {
+	base := heapInUse()
+	changes := DiffDatabase(current, desired)
+	full = heapInUse() - base
+	runtime.KeepAlive(changes)
+	changes = nil
+
+	base = heapInUse()
+	n := 0
+	_ = DiffDatabaseStream(current, desired, func(SchemaChange) error {
+		if n++; n%1000 == 0 {
+			stream = max(stream, heapInUse()-base)
+		}
+		return nil
+	})
+	return full, stream
+}
+
+func TestDiffDatabaseStream_PeakMemory(t *testing.T) {
+	current := syntheticSchema(1000, &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}})
+	desired := syntheticSchema(1000, &DataType{TypeClause: &DataType_BigIntData{BigIntData: &BigInt{}}})
+	// The stream holds the table lookups of its pass but no changes
+	full, stream := peakDiffMemory(current, desired)
+	if stream*2 > full {
+		t.Errorf("Expected the stream to hold under half the %d bytes of DiffDatabase, got %d", full, stream)
+	}
+}
+
+func BenchmarkDiffDatabase(b *testing.B) {
+	current, desired := benchmarkSchemas()
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, c := range DiffDatabase(current, desired) {
+			_ = c
+		}
+	}
+	full, _ := peakDiffMemory(current, desired)
+	b.ReportMetric(float64(full), "peak-B")
+}
+
+func BenchmarkDiffDatabaseStream(b *testing.B) {
+	current, desired := benchmarkSchemas()
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := DiffDatabaseStream(current, desired, func(SchemaChange) error { return nil }); err != nil {
+			b.Fatal(err)
+		}
+	}
+	_, stream := peakDiffMemory(current, desired)
+	b.ReportMetric(float64(stream), "peak-B")
+}
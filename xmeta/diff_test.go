@@ -1,6 +1,10 @@
 package xmeta
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -242,3 +246,881 @@ func TestDiffDatabase_ChangeOrdering(t *testing.T) {
 		t.Errorf("Second change should be DropTable, got %T", changes[1])
 	}
 }
+
+func TestSortChangesWithPolicy(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	changes := []SchemaChange{
+		AddIndex{TableName: users, Index: &MetaIndex{Name: "users_name_idx"}},
+		AlterTableOptions{TableName: users},
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "name"}},
+	}
+
+	SortChanges(changes)
+	if _, ok := changes[0].(AddColumn); !ok {
+		t.Errorf("Expected AddColumn first by default, got %T", changes[0])
+	}
+
+	SortChangesWithPolicy(changes, PriorityPolicy{"AlterTableOptions": 45, "AddIndex": 80})
+	if _, ok := changes[0].(AlterTableOptions); !ok {
+		t.Errorf("Expected AlterTableOptions first, got %T", changes[0])
+	}
+	if _, ok := changes[2].(AddIndex); !ok {
+		t.Errorf("Expected AddIndex last, got %T", changes[2])
+	}
+}
+
+func TestDiffDatabase_GeneratedExpressionChange(t *testing.T) {
+	table := func(expr string) *MetaDatabase {
+		return &MetaDatabase{
+			Name: "testdb",
+			Tables: []*MetaTable{
+				{
+					Name: &ObjectName{Idents: []string{"users"}},
+					Elements: []*TableElement{
+						{TableElementClause: &TableElement_ColumnDefElement{
+							ColumnDefElement: &ColumnDef{
+								Name: "full_name",
+								Options: map[string]string{
+									"IsGenerated":          "true",
+									"GenerationExpression": expr,
+								},
+							},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	changes := DiffDatabase(table("first || last"), table("first || ' ' || last"))
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+	if _, ok := changes[0].(AlterColumn); !ok {
+		t.Errorf("Expected AlterColumn, got %T", changes[0])
+	}
+}
+
+func TestDiffDatabase_MultiSchema(t *testing.T) {
+	orders := func(schema string) *MetaTable {
+		return &MetaTable{Name: &ObjectName{Idents: []string{schema, "orders"}}}
+	}
+	current := &MetaDatabase{Tables: []*MetaTable{orders("sales"), orders("audit")}}
+	desired := &MetaDatabase{Tables: []*MetaTable{orders("sales"), orders("archive")}}
+
+	changes := DiffDatabase(current, desired)
+
+	var addSchema, dropSchema, addTable, dropTable int
+	for _, c := range changes {
+		switch c := c.(type) {
+		case AddSchema:
+			addSchema++
+			if objectNameKey(c.SchemaName) != "archive" {
+				t.Errorf("Unexpected AddSchema %v", c.SchemaName.Idents)
+			}
+		case DropSchema:
+			dropSchema++
+			if objectNameKey(c.SchemaName) != "audit" {
+				t.Errorf("Unexpected DropSchema %v", c.SchemaName.Idents)
+			}
+		case AddTable:
+			addTable++
+		case DropTable:
+			dropTable++
+		}
+	}
+	if addSchema != 1 || dropSchema != 1 || addTable != 1 || dropTable != 1 {
+		t.Errorf("Expected one of each schema/table change, got %d/%d/%d/%d", addSchema, dropSchema, addTable, dropTable)
+	}
+
+	// Simple-name matching treats all three "orders" tables as the same table
+	changes = DiffDatabaseWithOptions(current, desired, DiffOptions{MatchSimpleNames: true})
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes with MatchSimpleNames, got %d", len(changes))
+	}
+}
+
+func TestAlterColumn_Deltas(t *testing.T) {
+	change := AlterColumn{
+		TableName: &ObjectName{Idents: []string{"users"}},
+		OldColumn: &ColumnDef{
+			Name:     "age",
+			DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}},
+			Default:  stringToAny("0"),
+			Constraints: []*ColumnConstraint{{
+				Spec: &ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{
+					NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm,
+				}},
+			}},
+		},
+		NewColumn: &ColumnDef{
+			Name:     "years",
+			DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}},
+			Comment:  "Age in years",
+		},
+	}
+
+	deltas := change.Deltas()
+	if len(deltas) != 4 {
+		t.Fatalf("Expected 4 deltas, got %d: %v", len(deltas), deltas)
+	}
+	if d, ok := deltas[0].(RenamedTo); !ok || d.Name != "years" {
+		t.Errorf("Expected RenamedTo first, got %#v", deltas[0])
+	}
+	if d, ok := deltas[1].(DefaultChanged); !ok || d.Old != "0" || d.New != "" {
+		t.Errorf("Expected DefaultChanged, got %#v", deltas[1])
+	}
+	if d, ok := deltas[2].(NullabilityChanged); !ok || !d.NowNullable {
+		t.Errorf("Expected NullabilityChanged, got %#v", deltas[2])
+	}
+	if d, ok := deltas[3].(CommentChanged); !ok || d.New != "Age in years" {
+		t.Errorf("Expected CommentChanged, got %#v", deltas[3])
+	}
+
+	stmts, err := GenerateSQL(change, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	expected := []string{
+		`ALTER TABLE "users" RENAME COLUMN "age" TO "years"`,
+		`ALTER TABLE "users" ALTER COLUMN "years" DROP DEFAULT, ALTER COLUMN "years" DROP NOT NULL`,
+		`COMMENT ON COLUMN "users"."years" IS 'Age in years'`,
+	}
+	if strings.Join(stmts, ";") != strings.Join(expected, ";") {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+}
+
+func TestDiffDatabase_Nil(t *testing.T) {
+	populated := &MetaDatabase{
+		Tables: []*MetaTable{{Name: &ObjectName{Idents: []string{"users"}}}},
+	}
+
+	changes := DiffDatabase(nil, populated)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+	if _, ok := changes[0].(AddTable); !ok {
+		t.Errorf("Expected AddTable, got %T", changes[0])
+	}
+
+	changes = DiffDatabase(populated, nil)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+	if _, ok := changes[0].(DropTable); !ok {
+		t.Errorf("Expected DropTable, got %T", changes[0])
+	}
+
+	if changes := DiffDatabase(nil, nil); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestDiffDatabase_EmptyVsNilOptions(t *testing.T) {
+	current := &MetaDatabase{
+		Tables: []*MetaTable{{Name: &ObjectName{Idents: []string{"users"}}, Options: map[string]string{}}},
+	}
+	desired := &MetaDatabase{
+		Tables: []*MetaTable{{Name: &ObjectName{Idents: []string{"users"}}}},
+	}
+
+	if changes := DiffDatabase(current, desired); len(changes) != 0 {
+		t.Errorf("Expected no changes between empty and nil options, got %v", changes)
+	}
+}
+
+func TestDiffDatabase_LoaderVsFileOptions(t *testing.T) {
+	// A loader always allocates Options, a file without options leaves it nil
+	loaded := MYTableToMetaTable(&MYTable{
+		Name:   &ObjectName{Idents: []string{"shop", "items"}},
+		Engine: "InnoDB",
+	})
+	loaded.Options["RowFormat"] = "" // empty values count as unset
+
+	fromFile, err := LoadMetaDatabaseFromReader(strings.NewReader(`
+Tables { Name { Idents: "shop" Idents: "items" } Options { key: "Engine" value: "InnoDB" } }`), FormatTextProto)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	current := &MetaDatabase{Tables: []*MetaTable{loaded}}
+	if changes := DiffDatabase(current, fromFile); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestDiffDatabase_ColumnPositions(t *testing.T) {
+	table := func(names ...string) *MetaDatabase {
+		var elems []*TableElement
+		for _, name := range names {
+			elems = append(elems, &TableElement{TableElementClause: &TableElement_ColumnDefElement{
+				ColumnDefElement: &ColumnDef{Name: name, DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}},
+			}})
+		}
+		return &MetaDatabase{Tables: []*MetaTable{{Name: &ObjectName{Idents: []string{"t"}}, Elements: elems}}}
+	}
+
+	current := table("id", "a", "b", "c")
+	desired := table("new", "id", "c", "a", "b", "tail")
+
+	changes := DiffDatabase(current, desired)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes without order detection, got %v", changes)
+	}
+	if add, ok := changes[0].(AddColumn); !ok || add.Column.Name != "new" || !add.First {
+		t.Errorf("Expected first AddColumn for new, got %#v", changes[0])
+	}
+	if add, ok := changes[1].(AddColumn); !ok || add.Column.Name != "tail" || add.After != "b" || add.First {
+		t.Errorf("Expected AddColumn tail after b, got %#v", changes[1])
+	}
+
+	changes = DiffDatabaseWithOptions(current, desired, DiffOptions{DetectColumnOrder: true})
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes with order detection, got %v", changes)
+	}
+	move, ok := changes[2].(AlterColumnPosition)
+	if !ok || move.Column.Name != "c" || move.After != "id" {
+		t.Errorf("Expected c to move after id, got %#v", changes[2])
+	}
+
+	stmts, err := GenerateSQL(move, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `t` MODIFY COLUMN `c` INT AFTER `id`" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+	stmts, err = GenerateSQL(changes[0], DialectMySQL)
+	if err != nil || len(stmts) != 1 || stmts[0] != "ALTER TABLE `t` ADD COLUMN `new` INT FIRST" {
+		t.Errorf("Unexpected SQL: %v, %v", stmts, err)
+	}
+	if _, err := GenerateSQL(move, DialectPostgres); err == nil {
+		t.Error("Expected error reordering a column in Postgres")
+	}
+}
+
+func TestDescribeChange(t *testing.T) {
+	users := &ObjectName{Idents: []string{"public", "users"}}
+	text := &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}
+	varchar := &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 20}}}
+	tests := []struct {
+		change SchemaChange
+		want   string
+	}{
+		{AddTable{Table: &MetaTable{Name: users}}, "+ table public.users"},
+		{DropColumn{TableName: users, ColumnName: "legacy"}, "- column public.users.legacy"},
+		{AlterColumn{TableName: users, OldColumn: &ColumnDef{Name: "email", DataType: text}, NewColumn: &ColumnDef{Name: "email", DataType: varchar}},
+			"~ column public.users.email: type TEXT -> VARCHAR(20)"},
+		{AlterTableOptions{TableName: users, OldOptions: map[string]string{"Tablespace": "fast"}},
+			`~ table public.users: Tablespace "fast" -> ""`},
+		{RenameTable{OldName: &ObjectName{Idents: []string{"public", "people"}}, NewName: users}, "~ table public.people: renamed to public.users"},
+	}
+	for _, tt := range tests {
+		if got := DescribeChange(tt.change); got != tt.want {
+			t.Errorf("DescribeChange(%T) = %q, expected %q", tt.change, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	users := &ObjectName{Idents: []string{"public", "users"}}
+	orders := &ObjectName{Idents: []string{"public", "orders"}}
+	changes := []SchemaChange{
+		AddSchema{SchemaName: &ObjectName{Idents: []string{"audit"}}},
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "phone"}},
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "fax"}},
+		DropColumn{TableName: orders, ColumnName: "legacy"},
+	}
+
+	summary := Summarize(changes)
+	if summary.Counts["AddColumn"] != 2 || summary.Counts["DropColumn"] != 1 || summary.Counts["AddSchema"] != 1 {
+		t.Errorf("Unexpected counts %v", summary.Counts)
+	}
+	if summary.Destructive != 1 {
+		t.Errorf("Expected 1 destructive change, got %d", summary.Destructive)
+	}
+	if strings.Join(summary.Tables, ",") != "public.orders,public.users" {
+		t.Errorf("Unexpected tables %v", summary.Tables)
+	}
+
+	if !HasDestructive(changes) {
+		t.Error("Expected destructive changes")
+	}
+	if HasDestructive(changes[:3]) {
+		t.Error("Expected no destructive changes")
+	}
+}
+
+func TestFilterChanges(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	changes := []SchemaChange{
+		DropConstraint{TableName: users, ConstraintName: "fk_org", IsForeignKey: true},
+		DropColumn{TableName: users, ColumnName: "legacy"},
+		DropTable{TableName: &ObjectName{Idents: []string{"old"}}},
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "phone"}},
+		AlterColumn{TableName: users, OldColumn: &ColumnDef{Name: "age"}, NewColumn: &ColumnDef{Name: "age"}},
+	}
+	SortChanges(changes)
+
+	additive := FilterChanges(changes, AdditiveOnly)
+	if len(additive) != 2 {
+		t.Fatalf("Expected 2 additive changes, got %v", additive)
+	}
+	if _, ok := additive[0].(DropConstraint); !ok {
+		t.Errorf("Expected sorted order to be kept, got %v", additive)
+	}
+
+	destructive := FilterChanges(changes, DestructiveOnly)
+	if len(destructive) != 3 || HasDestructive(additive) {
+		t.Errorf("Unexpected destructive changes %v", destructive)
+	}
+}
+
+func TestDiffDatabase_ModifiedConstraint(t *testing.T) {
+	fkTable := func(onDelete ReferentialAction, deferrable bool) *MetaDatabase {
+		return &MetaDatabase{Tables: []*MetaTable{{
+			Name: &ObjectName{Idents: []string{"orders"}},
+			Elements: []*TableElement{{TableElementClause: &TableElement_TableConstraintElement{
+				TableConstraintElement: &TableConstraint{
+					Name: "fk_user",
+					Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+						ReferenceItem: &ReferentialTableConstraint{
+							Columns:    []string{"user_id"},
+							KeyExpr:    &ReferenceKeyExpr{TableName: "users", Columns: []string{"id"}},
+							OnDelete:   onDelete,
+							Deferrable: deferrable,
+						},
+					}},
+				},
+			}}},
+		}}}
+	}
+	current := fkTable(ReferentialAction_ReferentialAction_NoAction, false)
+
+	// Default: drop and re-add
+	changes := DiffDatabase(current, fkTable(ReferentialAction_ReferentialAction_Cascade, false))
+	if len(changes) != 2 {
+		t.Fatalf("Expected drop and add, got %v", changes)
+	}
+	if _, ok := changes[0].(DropConstraint); !ok {
+		t.Errorf("Expected DropConstraint first, got %T", changes[0])
+	}
+	if _, ok := changes[1].(AddConstraint); !ok {
+		t.Errorf("Expected AddConstraint second, got %T", changes[1])
+	}
+
+	opts := DiffOptions{AlterConstraints: true}
+	changes = DiffDatabaseWithOptions(current, fkTable(ReferentialAction_ReferentialAction_Cascade, false), opts)
+	if len(changes) != 1 {
+		t.Fatalf("Expected a single AlterConstraint, got %v", changes)
+	}
+	stmts, err := GenerateSQL(changes[0], DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 2 || stmts[0] != `ALTER TABLE "orders" DROP CONSTRAINT "fk_user"` ||
+		stmts[1] != `ALTER TABLE "orders" ADD CONSTRAINT "fk_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE` {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	// Deferrability alone can be altered in place by Postgres
+	changes = DiffDatabaseWithOptions(current, fkTable(ReferentialAction_ReferentialAction_NoAction, true), opts)
+	stmts, err = GenerateSQL(changes[0], DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != `ALTER TABLE "orders" ALTER CONSTRAINT "fk_user" DEFERRABLE INITIALLY IMMEDIATE` {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+}
+
+func TestDiffDatabase_Indexes(t *testing.T) {
+	indexed := func(predicate string) *MetaDatabase {
+		return &MetaDatabase{Tables: []*MetaTable{{
+			Name: &ObjectName{Idents: []string{"public", "users"}},
+			Indexes: []*MetaIndex{
+				{Name: "users_email_idx", Expression: "lower(email)", IsUnique: true, Method: "btree", Predicate: predicate},
+				{Name: "users_name_idx", Columns: []string{"name"}},
+			},
+		}}}
+	}
+
+	if changes := DiffDatabase(indexed("deleted_at IS NULL"), indexed("deleted_at IS NULL")); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	changes := DiffDatabase(indexed(""), indexed("deleted_at IS NULL"))
+	SortChanges(changes)
+	if len(changes) != 2 {
+		t.Fatalf("Expected drop and recreate, got %v", changes)
+	}
+	if drop, ok := changes[0].(DropIndex); !ok || drop.IndexName != "users_email_idx" {
+		t.Errorf("Expected DropIndex first, got %v", changes[0])
+	}
+	add, ok := changes[1].(AddIndex)
+	if !ok {
+		t.Fatalf("Expected AddIndex second, got %T", changes[1])
+	}
+	stmts, err := GenerateSQL(add, DialectPostgres)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	want := `CREATE UNIQUE INDEX "users_email_idx" ON "public"."users" USING btree (lower(email)) WHERE deleted_at IS NULL`
+	if len(stmts) != 1 || stmts[0] != want {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+	stmts, _ = GenerateSQL(changes[0], DialectPostgres)
+	if len(stmts) != 1 || stmts[0] != `DROP INDEX "public"."users_email_idx"` {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+}
+
+func TestDiffDatabase_CollationChange(t *testing.T) {
+	table := func(collation string) *MetaDatabase {
+		col := MYColumnToColumnDef(&MYColumn{
+			Name:       "name",
+			DataType:   &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 64}}},
+			Charset:    "utf8mb4",
+			Collation:  collation,
+			IsNullable: true,
+		})
+		col.Options["IdentitySequence"] = collation // informational, ignored
+		return &MetaDatabase{Tables: []*MetaTable{{
+			Name:     &ObjectName{Idents: []string{"items"}},
+			Elements: []*TableElement{{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}}},
+		}}}
+	}
+
+	if changes := DiffDatabase(table("utf8mb4_bin"), table("UTF8MB4_BIN")); len(changes) != 0 {
+		t.Errorf("Expected collation case to be ignored, got %v", changes)
+	}
+
+	changes := DiffDatabase(table("utf8mb4_general_ci"), table("utf8mb4_bin"))
+	if len(changes) != 1 {
+		t.Fatalf("Expected one AlterColumn, got %v", changes)
+	}
+	alter, ok := changes[0].(AlterColumn)
+	if !ok {
+		t.Fatalf("Expected AlterColumn, got %T", changes[0])
+	}
+	deltas := alter.Deltas()
+	if len(deltas) != 1 || deltas[0] != (OptionChanged{Key: "Collation", Old: "utf8mb4_general_ci", New: "utf8mb4_bin"}) {
+		t.Errorf("Unexpected deltas %v", deltas)
+	}
+	stmts, err := GenerateSQL(alter, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `items` MODIFY COLUMN `name` VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	// A column without an explicit collation inherits the table's
+	if changes := DiffDatabase(table("utf8mb4_bin"), table("")); len(changes) != 0 {
+		t.Errorf("Expected unset collation to be ignored, got %v", changes)
+	}
+}
+
+func TestDiffDatabase_InlineVsTablePrimaryKey(t *testing.T) {
+	intType := &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}
+	column := func(name string, inlinePK bool) *TableElement {
+		col := &ColumnDef{Name: name, DataType: intType}
+		if inlinePK {
+			col.Constraints = []*ColumnConstraint{{Spec: &ColumnConstraintSpec{
+				ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{UniqueItem: &UniqueColumnSpec{IsPrimaryKey: true}},
+			}}}
+		}
+		return &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}}
+	}
+	tablePK := func(name string, columns ...string) *TableElement {
+		return &TableElement{TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+			Name: name,
+			Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+				UniqueItem: &UniqueTableConstraint{IsPrimary: true, Columns: columns},
+			}},
+		}}}
+	}
+	db := func(elems ...*TableElement) *MetaDatabase {
+		return &MetaDatabase{Tables: []*MetaTable{{Name: &ObjectName{Idents: []string{"users"}}, Elements: elems}}}
+	}
+
+	// Postgres loads both forms; a file declares the key inline
+	loaded := db(column("id", true), column("org_id", false), tablePK("users_pkey", "id"))
+	file := db(column("id", true), column("org_id", false))
+	if changes := DiffDatabase(loaded, file); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+	if changes := DiffDatabase(file, loaded); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	// Several inline flags against a composite table-level key
+	inline := db(column("id", true), column("org_id", true))
+	table := db(column("id", false), column("org_id", false), tablePK("pk_users", "id", "org_id"))
+	if changes := DiffDatabase(inline, table); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	// A live table reports its key columns NOT NULL, which the key implies
+	live := db(column("id", false), column("org_id", false), tablePK("users_pkey", "id"))
+	live.Tables[0].Elements[0].GetColumnDefElement().Constraints = []*ColumnConstraint{{Spec: &ColumnConstraintSpec{
+		ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm},
+	}}}
+	for _, other := range []*MetaDatabase{file, db(column("id", false), column("org_id", false), tablePK("users_pkey", "id"))} {
+		if changes := DiffDatabase(live, other); len(changes) != 0 {
+			t.Errorf("Expected no changes, got %v", changes)
+		}
+		if changes := DiffDatabase(other, live); len(changes) != 0 {
+			t.Errorf("Expected no changes, got %v", changes)
+		}
+	}
+
+	// A key that really moved is still reported, along with the nullability
+	// it implied
+	changes := DiffDatabase(file, db(column("id", false), column("org_id", false), tablePK("users_pkey", "org_id")))
+	if len(changes) != 4 {
+		t.Fatalf("Expected drop and re-add of the key and two nullability changes, got %v", changes)
+	}
+	if add, ok := changes[1].(AddConstraint); !ok || add.Constraint.GetSpec().GetUniqueItem().Columns[0] != "org_id" {
+		t.Errorf("Unexpected change %v", changes[1])
+	}
+	for _, change := range changes[2:] {
+		if _, ok := change.(SetColumnNullability); !ok {
+			t.Errorf("Expected a nullability change, got %v", change)
+		}
+	}
+}
+
+func TestDiffDatabase_RenameTable(t *testing.T) {
+	intType := &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}
+	table := func(name string, columns ...string) *MetaTable {
+		tbl := &MetaTable{Name: &ObjectName{Idents: []string{"public", name}}}
+		for _, c := range columns {
+			tbl.Elements = append(tbl.Elements, &TableElement{TableElementClause: &TableElement_ColumnDefElement{
+				ColumnDefElement: &ColumnDef{Name: c, DataType: intType},
+			}})
+		}
+		return tbl
+	}
+	current := &MetaDatabase{Tables: []*MetaTable{table("orders", "id", "total")}}
+	desired := &MetaDatabase{Tables: []*MetaTable{table("customer_orders", "id", "total", "customer_id")}}
+
+	if changes := DiffDatabase(current, desired); !HasDestructive(changes) {
+		t.Errorf("Expected drop and add without DetectRenames, got %v", changes)
+	}
+
+	changes := DiffDatabaseWithOptions(current, desired, DiffOptions{DetectRenames: true})
+	if len(changes) != 2 {
+		t.Fatalf("Expected RenameTable and AddColumn, got %v", changes)
+	}
+	rename, ok := changes[0].(RenameTable)
+	if !ok || objectNameKey(rename.OldName) != "public.orders" || objectNameKey(rename.NewName) != "public.customer_orders" {
+		t.Errorf("Expected RenameTable first, got %v", changes[0])
+	}
+	add, ok := changes[1].(AddColumn)
+	if !ok || add.Column.Name != "customer_id" || objectNameKey(add.TableName) != "public.customer_orders" {
+		t.Errorf("Expected AddColumn on the renamed table, got %v", changes[1])
+	}
+
+	stmts, err := GenerateSQL(rename, DialectPostgres)
+	if err != nil || len(stmts) != 1 || stmts[0] != `ALTER TABLE "public"."orders" RENAME TO "customer_orders"` {
+		t.Errorf("Unexpected SQL: %v, %v", stmts, err)
+	}
+	stmts, _ = GenerateSQL(rename, DialectMySQL)
+	if len(stmts) != 1 || stmts[0] != "RENAME TABLE `public`.`orders` TO `public`.`customer_orders`" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	// Two equally good targets are ambiguous
+	desired.Tables = append(desired.Tables, table("archived_orders", "id", "total"))
+	changes = DiffDatabaseWithOptions(current, desired, DiffOptions{DetectRenames: true})
+	if !HasDestructive(changes) {
+		t.Errorf("Expected no rename for an ambiguous match, got %v", changes)
+	}
+}
+
+func TestNormalizeCheck(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"(x > 0)", "x > 0", true},
+		{"CHECK ((price > (0)::numeric))", "price>0", true},
+		{`CHECK ((("Qty" > 0) AND (qty < 100))) NOT VALID`, "qty > 0 AND qty < 100", true},
+		{"length(name) > 0", "length name > 0", false},
+		{"(a OR b) AND c", "a OR b AND c", false},
+		{"status IN ('a', 'b')", "STATUS in ('a','b')", true},
+		{"status = 'A'", "status = 'a'", false},
+		{"x > 0", "x > 10", false},
+	}
+	for _, tt := range tests {
+		if got := normalizeCheck(tt.a) == normalizeCheck(tt.b); got != tt.want {
+			t.Errorf("normalizeCheck(%q) = %q, normalizeCheck(%q) = %q", tt.a, normalizeCheck(tt.a), tt.b, normalizeCheck(tt.b))
+		}
+	}
+}
+
+func TestDiffDatabase_CheckExpressions(t *testing.T) {
+	load := func(check string) *MetaDatabase {
+		db, err := LoadMetaDatabaseFromSQL("CREATE TABLE products (price NUMERIC, CONSTRAINT price_positive CHECK "+check+");", DialectPostgres)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+	current := load("(price > 0)")
+	// As pg_get_constraintdef prints it
+	current.Tables[0].Elements[1].GetTableConstraintElement().Spec = &TableConstraintSpec{
+		TableConstraintSpecClause: &TableConstraintSpec_CheckItem{CheckItem: stringToAny("CHECK ((price > (0)::numeric))")},
+	}
+
+	if changes := DiffDatabase(current, load("( price>0 )")); len(changes) != 0 {
+		t.Errorf("Expected no changes for a reformatted check, got %v", changes)
+	}
+
+	changes := DiffDatabase(current, load("(price > 10)"))
+	if len(changes) != 2 {
+		t.Fatalf("Expected the check to be dropped and added, got %v", changes)
+	}
+	changes = DiffDatabaseWithOptions(current, load("(price > 10)"), DiffOptions{AlterConstraints: true})
+	if len(changes) != 1 {
+		t.Fatalf("Expected one AlterConstraint, got %v", changes)
+	}
+	if _, ok := changes[0].(AlterConstraint); !ok {
+		t.Errorf("Unexpected change %v", changes[0])
+	}
+}
+
+func TestDiffDatabase_ColumnChecks(t *testing.T) {
+	// The Postgres loader attaches single-column checks to their column
+	loaded := func(checks ...string) *MetaDatabase {
+		pg := &PGTable{
+			Name:    &ObjectName{Idents: []string{"public", "t"}},
+			Columns: []*PGColumn{{Name: "price", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}, IsNullable: true}},
+		}
+		for _, check := range checks {
+			pg.Constraints = append(pg.Constraints, &PGConstraint{Name: "t_price_check", Type: "c", Columns: []string{"price"}, Definition: "CHECK ((" + check + "))"})
+		}
+		return &MetaDatabase{Tables: []*MetaTable{PGTableToMetaTable(pg)}}
+	}
+
+	tests := []struct {
+		name    string
+		current *MetaDatabase
+		desired *MetaDatabase
+		want    []string
+	}{
+		{"changed", loaded("price > 0"), loaded("price > 100"), []string{"DropConstraint", "AddConstraint"}},
+		{"added", loaded(), loaded("price > 0"), []string{"AddConstraint"}},
+		{"removed", loaded("price > 0"), loaded(), []string{"DropConstraint"}},
+		{"unchanged", loaded("price > 0"), loaded("price > 0"), nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, change := range DiffDatabase(tt.current, tt.desired) {
+			got = append(got, fmt.Sprintf("%T", change)[len("xmeta."):])
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// A table-level check in a schema file matches the loaded inline one,
+	// and so does an unnamed inline one
+	for _, sql := range []string{
+		"CREATE TABLE public.t (price INTEGER, CONSTRAINT t_price_check CHECK (price > 0));",
+		"CREATE TABLE public.t (price INTEGER CHECK (price > 0));",
+	} {
+		file, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres)
+		if err != nil {
+			t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+		}
+		if changes := DiffDatabase(loaded("price > 0"), file); len(changes) != 0 {
+			t.Errorf("Expected no changes against %s, got %v", sql, changes)
+		}
+	}
+}
+
+func TestDiffDatabase_EquivalentChecks(t *testing.T) {
+	tests := []struct {
+		name             string
+		current, desired string
+		want             []string
+	}{
+		{"whitespace", "CHECK (v > 0)", "CHECK (v>0)", nil},
+		{"redundant parentheses", "CHECK (v > 0)", "CHECK ((v > (0)))", nil},
+		{"named and unnamed", "CONSTRAINT positive CHECK (v > 0)", "CHECK (v>0)", nil},
+		{"named both ways", "CONSTRAINT positive CHECK (v > 0)", "CONSTRAINT v_positive CHECK ((v > 0))", nil},
+		{"two columns", "CHECK (v > w)", "CONSTRAINT ordered CHECK (v>w)", nil},
+		{"changed", "CONSTRAINT positive CHECK (v > 0)", "CHECK (v > 1)", []string{"DropConstraint", "AddConstraint"}},
+	}
+	for _, tt := range tests {
+		load := func(check string) *MetaDatabase {
+			db, err := LoadMetaDatabaseFromSQL("CREATE TABLE t (v int, w int, "+check+");", DialectPostgres)
+			if err != nil {
+				t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+			}
+			return db
+		}
+		var got []string
+		for _, change := range DiffDatabase(load(tt.current), load(tt.desired)) {
+			got = append(got, fmt.Sprintf("%T", change)[len("xmeta."):])
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDiffDatabase_ColumnUniques(t *testing.T) {
+	load := func(sql string) *MetaDatabase {
+		db, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres)
+		if err != nil {
+			t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+		}
+		return db
+	}
+	plain := load("CREATE TABLE t (id INTEGER, v INTEGER);")
+	inline := load("CREATE TABLE t (id INTEGER, v INTEGER UNIQUE);")
+	table := load("CREATE TABLE t (id INTEGER, v INTEGER, CONSTRAINT t_v_key UNIQUE (v));")
+	named := load("CREATE TABLE t (id INTEGER, v INTEGER CONSTRAINT v_uq UNIQUE);")
+
+	tests := []struct {
+		name             string
+		current, desired *MetaDatabase
+		want             []string
+	}{
+		{"inline added", plain, inline, []string{"AddConstraint"}},
+		{"inline removed", inline, plain, []string{"DropConstraint"}},
+		{"inline to table", table, inline, nil},
+		{"table to inline", inline, table, nil},
+		{"named inline to table", named, table, []string{"DropConstraint", "AddConstraint"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, change := range DiffDatabase(tt.current, tt.desired) {
+			got = append(got, fmt.Sprintf("%T", change)[len("xmeta."):])
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	changes := DiffDatabase(plain, inline)
+	if add, ok := changes[0].(AddConstraint); !ok || add.Constraint.Name != "t_v_key" || !slices.Equal(add.Constraint.GetSpec().GetUniqueItem().GetColumns(), []string{"v"}) {
+		t.Errorf("Expected ADD CONSTRAINT t_v_key UNIQUE (v), got %v", changes[0])
+	}
+}
+
+func TestDiffDatabase_ColumnFastPath(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER NOT NULL, status TEXT DEFAULT 'new', score INTEGER DEFAULT 0, name TEXT, age INTEGER);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER, status TEXT DEFAULT 'active' NOT NULL, score INTEGER, name TEXT, age BIGINT DEFAULT 18);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	var stmts []string
+	for _, c := range DiffDatabase(current, desired) {
+		if _, ok := c.(AlterColumn); !ok && c.IsDestructive() {
+			t.Errorf("Expected %v to be non-destructive", c)
+		}
+		sql, err := GenerateSQL(c, DialectPostgres)
+		if err != nil {
+			t.Fatalf("GenerateSQL(%v) failed: %v", c, err)
+		}
+		stmts = append(stmts, sql...)
+	}
+	slices.Sort(stmts)
+	want := []string{
+		`ALTER TABLE "users" ALTER COLUMN "age" TYPE BIGINT, ALTER COLUMN "age" SET DEFAULT 18`,
+		`ALTER TABLE "users" ALTER COLUMN "id" DROP NOT NULL`,
+		`ALTER TABLE "users" ALTER COLUMN "score" DROP DEFAULT`,
+		`ALTER TABLE "users" ALTER COLUMN "status" SET DEFAULT 'active'`,
+		`ALTER TABLE "users" ALTER COLUMN "status" SET NOT NULL`,
+	}
+	if !slices.Equal(stmts, want) {
+		t.Errorf("Expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(stmts, "\n"))
+	}
+}
+
+func TestColumnFastPath_MySQLAndInverse(t *testing.T) {
+	table := &ObjectName{Idents: []string{"users"}}
+	col := &ColumnDef{Name: "status", DataType: &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}}
+	set := SetColumnNullability{TableName: table, Column: col, Nullable: false}
+	stmts, err := GenerateSQL(set, DialectMySQL)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `users` MODIFY COLUMN `status` TEXT NOT NULL" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+
+	inverse, ok := InvertChange(SetColumnDefault{TableName: table, Column: columnWithDefault(col, "'x'"), Default: "'x'"})
+	if drop, isDrop := inverse.(DropColumnDefault); !ok || !isDrop || drop.OldDefault != "'x'" || drop.Column.Default != nil {
+		t.Errorf("Expected a DropColumnDefault of 'x', got %v", inverse)
+	}
+	if _, ok := InvertChange(DropColumnDefault{TableName: table, Column: col}); ok {
+		t.Error("Expected a drop without its old default not to be reversible")
+	}
+
+	data, err := json.Marshal([]SchemaChange{set})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	parsed, err := ParseChangesJSON(data)
+	if err != nil {
+		t.Fatalf("ParseChangesJSON failed: %v", err)
+	}
+	if got, ok := parsed[0].(SetColumnNullability); !ok || got.Column.GetName() != "status" || got.Nullable {
+		t.Errorf("Expected the change to round-trip, got %v", parsed[0])
+	}
+}
+
+func TestDiffDatabase_AlterDatabase(t *testing.T) {
+	current := &MetaDatabase{Name: "shop", Options: map[string]string{
+		"SourceDialect": "mysql", "ServerVersion": "8.0.36", "Charset": "utf8mb3", "Collation": "utf8mb3_general_ci",
+	}}
+	desired := &MetaDatabase{Name: "shop", Options: map[string]string{"Charset": "utf8mb4", "Collation": "UTF8MB3_GENERAL_CI"}}
+
+	changes := DiffDatabase(current, desired)
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v", changes)
+	}
+	alter, ok := changes[0].(AlterDatabase)
+	if !ok || alter.Priority() >= (RenameTable{}).Priority() {
+		t.Fatalf("Expected an AlterDatabase running first, got %v", changes[0])
+	}
+	if got := DescribeChange(alter); got != `~ database shop: Charset "utf8mb3" -> "utf8mb4"` {
+		t.Errorf("Unexpected description: %s", got)
+	}
+	stmts, err := GenerateSQL(alter, DialectMySQL)
+	if err != nil || len(stmts) != 1 || stmts[0] != "ALTER DATABASE `shop` CHARACTER SET utf8mb4" {
+		t.Errorf("Unexpected MySQL DDL: %q (%v)", stmts, err)
+	}
+	if _, err := GenerateSQL(alter, DialectPostgres); err == nil {
+		t.Error("Expected an error changing the Postgres encoding")
+	}
+
+	// A name or option given on one side only is not a change
+	if changes := DiffDatabase(current, &MetaDatabase{}); len(changes) != 0 {
+		t.Errorf("Expected no changes against an unnamed database, got %v", changes)
+	}
+
+	renamed := DiffDatabase(&MetaDatabase{Name: "shop"}, &MetaDatabase{Name: "store"})
+	if len(renamed) != 1 {
+		t.Fatalf("Expected a rename, got %v", renamed)
+	}
+	stmts, err = GenerateSQL(renamed[0], DialectPostgres)
+	if err != nil || len(stmts) != 1 || stmts[0] != `ALTER DATABASE "shop" RENAME TO "store"` {
+		t.Errorf("Unexpected Postgres DDL: %q (%v)", stmts, err)
+	}
+	if inverse, ok := InvertChange(renamed[0]); !ok || inverse.(AlterDatabase).NewName != "shop" {
+		t.Errorf("Expected the inverse to rename back to shop, got %v", inverse)
+	}
+}
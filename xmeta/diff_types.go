@@ -3,6 +3,17 @@ package xmeta
 // diff_types.go defines the types representing schema changes.
 // These are used as the output of the Diff engine.
 
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
 // SchemaChange is the common interface for all schema change types.
 type SchemaChange interface {
 	// IsDestructive returns true if the change can cause data loss.
@@ -11,6 +22,42 @@ type SchemaChange interface {
 	Priority() int
 }
 
+// =============================================================================
+// Database-level Changes
+// =============================================================================
+
+// AlterDatabase represents a change of the database name or of its
+// database-level options, such as the default Charset and Collation.
+type AlterDatabase struct {
+	OldName    string
+	NewName    string
+	OldOptions map[string]string
+	NewOptions map[string]string
+}
+
+func (c AlterDatabase) IsDestructive() bool { return false }
+func (c AlterDatabase) Priority() int       { return 1 } // First, before anything inside it
+
+// =============================================================================
+// Schema-level Changes
+// =============================================================================
+
+// AddSchema represents creating a new schema (namespace) for tables.
+type AddSchema struct {
+	SchemaName *ObjectName
+}
+
+func (c AddSchema) IsDestructive() bool { return false }
+func (c AddSchema) Priority() int       { return 38 } // After drops, before add table
+
+// DropSchema represents dropping a schema that no longer holds any table.
+type DropSchema struct {
+	SchemaName *ObjectName
+}
+
+func (c DropSchema) IsDestructive() bool { return true }
+func (c DropSchema) Priority() int       { return 35 } // After drop table
+
 // =============================================================================
 // Table-level Changes
 // =============================================================================
@@ -21,7 +68,12 @@ type AddTable struct {
 }
 
 func (c AddTable) IsDestructive() bool { return false }
-func (c AddTable) Priority() int       { return 40 } // After drops, before columns
+func (c AddTable) Priority() int {
+	if c.Table.GetOptions()["PartitionOf"] != "" {
+		return 41 // After the partitioned parent
+	}
+	return 40 // After drops, before columns
+}
 
 // DropTable represents dropping an existing table.
 type DropTable struct {
@@ -31,18 +83,46 @@ type DropTable struct {
 func (c DropTable) IsDestructive() bool { return true }
 func (c DropTable) Priority() int       { return 30 } // After drop columns
 
+// RenameTable represents renaming a table within its schema, reported when
+// DiffOptions.DetectRenames is set. Changes to the renamed table refer to
+// it by NewName.
+type RenameTable struct {
+	OldName *ObjectName
+	NewName *ObjectName
+}
+
+func (c RenameTable) IsDestructive() bool { return false }
+func (c RenameTable) Priority() int       { return 3 } // Before everything that targets the new name
+
 // AlterTableOptions represents changing table-level options (comment, engine, etc).
+// For a view (IsView) the options are its check option and security
+// settings; ViewDefinition is its SELECT, which MySQL must restate.
 type AlterTableOptions struct {
-	TableName  *ObjectName
-	OldOptions map[string]string
-	NewOptions map[string]string
-	OldComment string
-	NewComment string
+	TableName      *ObjectName
+	OldOptions     map[string]string
+	NewOptions     map[string]string
+	OldComment     string
+	NewComment     string
+	IsView         bool
+	ViewDefinition string
 }
 
 func (c AlterTableOptions) IsDestructive() bool { return false }
 func (c AlterTableOptions) Priority() int       { return 70 } // Last
 
+// AlterTags represents a change of the tags of a table, or of one of its
+// columns when ColumnName is set. Tags are kept apart from the options
+// compared by AlterTableOptions and AlterColumn; see SetTag.
+type AlterTags struct {
+	TableName  *ObjectName
+	ColumnName string
+	OldTags    map[string]string
+	NewTags    map[string]string
+}
+
+func (c AlterTags) IsDestructive() bool { return false }
+func (c AlterTags) Priority() int       { return 70 }
+
 // =============================================================================
 // Column-level Changes
 // =============================================================================
@@ -51,6 +131,10 @@ func (c AlterTableOptions) Priority() int       { return 70 } // Last
 type AddColumn struct {
 	TableName *ObjectName
 	Column    *ColumnDef
+	// After names the preceding column in the desired schema; First is set
+	// when the column comes first. With neither, the column is appended.
+	After string
+	First bool
 }
 
 func (c AddColumn) IsDestructive() bool { return false }
@@ -81,6 +165,233 @@ func (c AlterColumn) IsDestructive() bool {
 }
 func (c AlterColumn) Priority() int { return 70 }
 
+// AlterColumnPosition represents moving an existing column, reported when
+// DiffOptions.DetectColumnOrder is set. After and First are as in AddColumn.
+type AlterColumnPosition struct {
+	TableName *ObjectName
+	Column    *ColumnDef
+	After     string
+	First     bool
+}
+
+func (c AlterColumnPosition) IsDestructive() bool { return false }
+func (c AlterColumnPosition) Priority() int       { return 70 }
+
+// SetColumnDefault represents setting or changing the default of a column
+// whose definition differs in nothing else. Column is the column as it is
+// after the change; OldDefault is the previous default, if any.
+type SetColumnDefault struct {
+	TableName  *ObjectName
+	Column     *ColumnDef
+	Default    string
+	OldDefault string
+}
+
+func (c SetColumnDefault) IsDestructive() bool { return false }
+func (c SetColumnDefault) Priority() int       { return 70 }
+
+// DropColumnDefault represents removing the default of a column whose
+// definition differs in nothing else. Column is as in SetColumnDefault.
+type DropColumnDefault struct {
+	TableName  *ObjectName
+	Column     *ColumnDef
+	OldDefault string
+}
+
+func (c DropColumnDefault) IsDestructive() bool { return false }
+func (c DropColumnDefault) Priority() int       { return 70 }
+
+// SetColumnNullability represents adding or removing NOT NULL on a column
+// whose definition differs in nothing else. Column is as in
+// SetColumnDefault.
+type SetColumnNullability struct {
+	TableName *ObjectName
+	Column    *ColumnDef
+	Nullable  bool
+}
+
+func (c SetColumnNullability) IsDestructive() bool { return false }
+func (c SetColumnNullability) Priority() int       { return 70 }
+
+// Deltas reports the individual differences between OldColumn and NewColumn,
+// in the order a generator should apply them: rename first, so later
+// alterations can target the new name.
+func (c AlterColumn) Deltas() []ColumnDelta {
+	oldCol, newCol := c.OldColumn, c.NewColumn
+	if oldCol == nil || newCol == nil {
+		return nil
+	}
+
+	var deltas []ColumnDelta
+	if oldCol.Name != newCol.Name {
+		deltas = append(deltas, RenamedTo{Name: newCol.Name})
+	}
+	if !proto.Equal(oldCol.DataType, newCol.DataType) {
+		if added, ok := enumLabelsAdded(oldCol.DataType, newCol.DataType); ok {
+			deltas = append(deltas, added)
+		} else {
+			deltas = append(deltas, TypeChanged{Old: oldCol.DataType, New: newCol.DataType})
+		}
+	}
+	if oldDefault, newDefault := anyToString(oldCol.Default), anyToString(newCol.Default); oldDefault != newDefault {
+		deltas = append(deltas, DefaultChanged{Old: oldDefault, New: newDefault})
+	}
+	if isNotNull(oldCol) != isNotNull(newCol) {
+		deltas = append(deltas, NullabilityChanged{NowNullable: !isNotNull(newCol)})
+	}
+	generationChanged := false
+	for _, key := range changedColumnOptions(oldCol, newCol) {
+		switch key {
+		case "IsGenerated", "GenerationExpression", "GenerationKind":
+			if !generationChanged {
+				generationChanged = true
+				deltas = append(deltas, GenerationChanged{
+					OldExpression: oldCol.Options["GenerationExpression"],
+					NewExpression: newCol.Options["GenerationExpression"],
+					OldKind:       oldCol.Options["GenerationKind"],
+					NewKind:       newCol.Options["GenerationKind"],
+				})
+			}
+		default:
+			deltas = append(deltas, OptionChanged{Key: key, Old: oldCol.Options[key], New: newCol.Options[key]})
+		}
+	}
+	if oldCol.Comment != newCol.Comment {
+		deltas = append(deltas, CommentChanged{Old: oldCol.Comment, New: newCol.Comment})
+	}
+	return deltas
+}
+
+// FastPath returns the SetColumnDefault, DropColumnDefault and
+// SetColumnNullability changes equivalent to c when its deltas are limited
+// to the default and the nullability, or false otherwise.
+func (c AlterColumn) FastPath() ([]SchemaChange, bool) {
+	deltas := c.Deltas()
+	if len(deltas) == 0 {
+		return nil, false
+	}
+	var changes []SchemaChange
+	for _, delta := range deltas {
+		switch d := delta.(type) {
+		case DefaultChanged:
+			if d.New == "" {
+				changes = append(changes, DropColumnDefault{TableName: c.TableName, Column: c.NewColumn, OldDefault: d.Old})
+			} else {
+				changes = append(changes, SetColumnDefault{TableName: c.TableName, Column: c.NewColumn, Default: d.New, OldDefault: d.Old})
+			}
+		case NullabilityChanged:
+			changes = append(changes, SetColumnNullability{TableName: c.TableName, Column: c.NewColumn, Nullable: d.NowNullable})
+		default:
+			return nil, false
+		}
+	}
+	return changes, true
+}
+
+// =============================================================================
+// Column Deltas
+// =============================================================================
+
+// ColumnDelta is one specific difference within an AlterColumn.
+type ColumnDelta interface {
+	isColumnDelta()
+}
+
+// RenamedTo reports that the column is renamed to Name.
+type RenamedTo struct {
+	Name string
+}
+
+// TypeChanged reports a change of the column data type.
+type TypeChanged struct {
+	Old *DataType
+	New *DataType
+}
+
+// EnumLabelsAdded reports that labels were added to the enum type of the
+// column, which keeps its other labels in order. TypeName is the Postgres
+// enum type, nil for a MySQL inline ENUM; Old and New are the labels.
+type EnumLabelsAdded struct {
+	TypeName *ObjectName
+	Old      []string
+	New      []string
+}
+
+// enumLabelsAdded reports whether new is old with labels added, and the
+// delta if so.
+func enumLabelsAdded(old, new *DataType) (EnumLabelsAdded, bool) {
+	o, n := old.GetEnumData(), new.GetEnumData()
+	if o == nil || n == nil || !proto.Equal(o.TypeName, n.TypeName) || len(n.Values) <= len(o.Values) {
+		return EnumLabelsAdded{}, false
+	}
+	i := 0
+	for _, label := range n.Values {
+		if i < len(o.Values) && label == o.Values[i] {
+			i++
+		}
+	}
+	if i < len(o.Values) {
+		return EnumLabelsAdded{}, false
+	}
+	return EnumLabelsAdded{TypeName: n.TypeName, Old: o.Values, New: n.Values}, true
+}
+
+// Added returns the labels in New that are not in Old.
+func (d EnumLabelsAdded) Added() []string {
+	var added []string
+	for _, label := range d.New {
+		if !slices.Contains(d.Old, label) {
+			added = append(added, label)
+		}
+	}
+	return added
+}
+
+// DefaultChanged reports a change of the default expression. An empty
+// string means no default.
+type DefaultChanged struct {
+	Old string
+	New string
+}
+
+// NullabilityChanged reports that a NOT NULL constraint was dropped
+// (NowNullable) or added.
+type NullabilityChanged struct {
+	NowNullable bool
+}
+
+// GenerationChanged reports a change of a generated column expression or
+// storage kind. An empty expression means the column is not generated.
+type GenerationChanged struct {
+	OldExpression string
+	NewExpression string
+	OldKind       string
+	NewKind       string
+}
+
+// OptionChanged reports a change of a column option other than the
+// generation options, e.g. Charset or Collation.
+type OptionChanged struct {
+	Key string
+	Old string
+	New string
+}
+
+// CommentChanged reports a change of the column comment.
+type CommentChanged struct {
+	Old string
+	New string
+}
+
+func (RenamedTo) isColumnDelta()          {}
+func (TypeChanged) isColumnDelta()        {}
+func (EnumLabelsAdded) isColumnDelta()    {}
+func (DefaultChanged) isColumnDelta()     {}
+func (NullabilityChanged) isColumnDelta() {}
+func (GenerationChanged) isColumnDelta()  {}
+func (OptionChanged) isColumnDelta()      {}
+func (CommentChanged) isColumnDelta()     {}
+
 // =============================================================================
 // Constraint-level Changes
 // =============================================================================
@@ -89,6 +400,10 @@ func (c AlterColumn) Priority() int { return 70 }
 type AddConstraint struct {
 	TableName  *ObjectName
 	Constraint *TableConstraint
+	// NotValid adds a Postgres foreign key or check constraint without
+	// checking existing rows, avoiding a long lock on large tables. Pair it
+	// with a ValidateConstraint. Other dialects add the constraint normally.
+	NotValid bool
 }
 
 func (c AddConstraint) IsDestructive() bool { return false }
@@ -109,18 +424,380 @@ func (c DropConstraint) Priority() int {
 	return 10
 }
 
+// AlterConstraint represents changing a constraint that keeps its name,
+// reported when DiffOptions.AlterConstraints is set or when only the name of
+// a unique constraint's backing index changed. The generator alters it in
+// place where the dialect allows, and drops and re-adds it otherwise.
+type AlterConstraint struct {
+	TableName     *ObjectName
+	OldConstraint *TableConstraint
+	NewConstraint *TableConstraint
+}
+
+func (c AlterConstraint) IsDestructive() bool { return false }
+func (c AlterConstraint) Priority() int       { return 60 } // With add constraints
+
+// ValidateConstraint represents validating a constraint added with
+// AddConstraint.NotValid. It is a no-op outside Postgres.
+type ValidateConstraint struct {
+	TableName      *ObjectName
+	ConstraintName string
+}
+
+func (c ValidateConstraint) IsDestructive() bool { return false }
+func (c ValidateConstraint) Priority() int       { return 90 } // After everything else
+
+// =============================================================================
+// Index-level Changes
+// =============================================================================
+
+// AddIndex represents creating a secondary index.
+type AddIndex struct {
+	TableName *ObjectName
+	Index     *MetaIndex
+}
+
+func (c AddIndex) IsDestructive() bool { return false }
+func (c AddIndex) Priority() int       { return 65 } // After add constraints
+
+// DropIndex represents dropping a secondary index.
+type DropIndex struct {
+	TableName *ObjectName
+	IndexName string
+}
+
+func (c DropIndex) IsDestructive() bool { return false } // Dropping an index doesn't lose data
+func (c DropIndex) Priority() int       { return 15 }    // Before drop columns
+
+// =============================================================================
+// View and Trigger Changes
+// =============================================================================
+
+// AddView represents creating a view.
+type AddView struct {
+	View *MetaView
+}
+
+func (c AddView) IsDestructive() bool { return false }
+func (c AddView) Priority() int       { return 75 } // After the tables and columns it selects from
+
+// DropView represents dropping a view. Materialized is set for a Postgres
+// materialized view, which needs its own DROP statement.
+type DropView struct {
+	ViewName     *ObjectName
+	Materialized bool
+}
+
+func (c DropView) IsDestructive() bool { return false } // A view holds no data
+func (c DropView) Priority() int       { return 12 }    // Before the columns it selects are dropped
+
+// AddTrigger represents creating a trigger.
+type AddTrigger struct {
+	Trigger *MetaTrigger
+}
+
+func (c AddTrigger) IsDestructive() bool { return false }
+func (c AddTrigger) Priority() int       { return 80 } // After the table and the views it may use
+
+// DropTrigger represents dropping a trigger of a table.
+type DropTrigger struct {
+	TableName   *ObjectName
+	TriggerName string
+}
+
+func (c DropTrigger) IsDestructive() bool { return false }
+func (c DropTrigger) Priority() int       { return 12 } // Before its table is altered
+
 // =============================================================================
 // Utility: Sort Changes
 // =============================================================================
 
 // SortChanges sorts schema changes by priority for safe execution order.
+// Changes of equal priority keep their relative order, so column additions
+// and moves stay in the order their AFTER references need.
 func SortChanges(changes []SchemaChange) {
-	// Simple bubble sort for clarity; could use sort.Slice
-	for i := 0; i < len(changes); i++ {
-		for j := i + 1; j < len(changes); j++ {
-			if changes[i].Priority() > changes[j].Priority() {
-				changes[i], changes[j] = changes[j], changes[i]
+	SortChangesWithPolicy(changes, nil)
+}
+
+// PriorityPolicy overrides change priorities by change type name, e.g.
+// {"AddIndex": 80} to create indexes after the column alterations.
+type PriorityPolicy map[string]int
+
+// priority returns the policy's priority for c, or c.Priority() when the
+// policy has no entry for its type.
+func (p PriorityPolicy) priority(c SchemaChange) int {
+	if v, ok := p[reflect.TypeOf(c).Name()]; ok {
+		return v
+	}
+	return c.Priority()
+}
+
+// SortChangesWithPolicy is SortChanges with the priorities in policy taking
+// precedence over the defaults. A nil policy keeps the defaults.
+func SortChangesWithPolicy(changes []SchemaChange, policy PriorityPolicy) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return policy.priority(changes[i]) < policy.priority(changes[j])
+	})
+	orderAddTables(changes, policy)
+}
+
+// =============================================================================
+// Utility: Filter Changes
+// =============================================================================
+
+// FilterChanges returns the changes for which pred is true, keeping their
+// order, so a sorted list stays sorted.
+func FilterChanges(changes []SchemaChange, pred func(SchemaChange) bool) []SchemaChange {
+	var result []SchemaChange
+	for _, c := range changes {
+		if pred(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// AdditiveOnly selects the changes that cannot lose data: new schemas,
+// tables, columns and constraints, option changes and constraint drops.
+// DropTable, DropColumn, DropSchema and AlterColumn are left out.
+func AdditiveOnly(c SchemaChange) bool { return !c.IsDestructive() }
+
+// DestructiveOnly selects the changes AdditiveOnly leaves out.
+func DestructiveOnly(c SchemaChange) bool { return c.IsDestructive() }
+
+// =============================================================================
+// Utility: Summaries
+// =============================================================================
+
+// DiffSummary is an overview of a list of schema changes.
+type DiffSummary struct {
+	// Counts maps change type names (e.g. "AddColumn") to their number.
+	Counts map[string]int
+	// Destructive counts the changes that can lose data.
+	Destructive int
+	// Tables lists the affected tables by qualified name, sorted.
+	Tables []string
+}
+
+// Summarize counts changes per type and collects the affected tables.
+func Summarize(changes []SchemaChange) DiffSummary {
+	summary := DiffSummary{Counts: make(map[string]int)}
+	seen := make(map[string]bool)
+	for _, c := range changes {
+		summary.Counts[reflect.TypeOf(c).Name()]++
+		if c.IsDestructive() {
+			summary.Destructive++
+		}
+		if name := objectNameKey(changeTableName(c)); name != "" && !seen[name] {
+			seen[name] = true
+			summary.Tables = append(summary.Tables, name)
+		}
+	}
+	sort.Strings(summary.Tables)
+	return summary
+}
+
+// HasDestructive reports whether any change can lose data.
+func HasDestructive(changes []SchemaChange) bool {
+	for _, c := range changes {
+		if c.IsDestructive() {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Utility: Descriptions
+// =============================================================================
+
+// DescribeChange returns a one-line, human-readable description of a
+// change, prefixed with "+" for additions, "-" for removals and "~" for
+// modifications, e.g. "~ column public.users.email: type TEXT -> VARCHAR(20)".
+func DescribeChange(c SchemaChange) string {
+	table := objectNameKey(changeTableName(c))
+	switch c := c.(type) {
+	case AlterDatabase:
+		var parts []string
+		if c.OldName != c.NewName {
+			parts = append(parts, "renamed to "+c.NewName)
+		}
+		for _, k := range changedDatabaseOptions(c.OldOptions, c.NewOptions) {
+			parts = append(parts, fmt.Sprintf("%s %q -> %q", k, c.OldOptions[k], c.NewOptions[k]))
+		}
+		return fmt.Sprintf("~ database %s: %s", c.OldName, strings.Join(parts, ", "))
+	case AddSchema:
+		return "+ schema " + objectNameKey(c.SchemaName)
+	case DropSchema:
+		return "- schema " + objectNameKey(c.SchemaName)
+	case AddTable:
+		return "+ table " + table
+	case DropTable:
+		return "- table " + table
+	case RenameTable:
+		return fmt.Sprintf("~ table %s: renamed to %s", objectNameKey(c.OldName), table)
+	case AlterTableOptions:
+		var parts []string
+		if c.OldComment != c.NewComment {
+			parts = append(parts, fmt.Sprintf("comment %q -> %q", c.OldComment, c.NewComment))
+		}
+		keys := make(map[string]bool)
+		for k := range c.OldOptions {
+			keys[k] = true
+		}
+		for k := range c.NewOptions {
+			keys[k] = true
+		}
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			// Tag changes are described by AlterTags
+			if c.OldOptions[k] != c.NewOptions[k] && !strings.HasPrefix(k, tagPrefix) {
+				parts = append(parts, fmt.Sprintf("%s %q -> %q", k, c.OldOptions[k], c.NewOptions[k]))
+			}
+		}
+		kind := "table"
+		if c.IsView {
+			kind = "view"
+		}
+		return fmt.Sprintf("~ %s %s: %s", kind, table, strings.Join(parts, ", "))
+	case AlterTags:
+		var parts []string
+		keys := maps.Clone(c.OldTags)
+		if keys == nil {
+			keys = make(map[string]string)
+		}
+		maps.Copy(keys, c.NewTags)
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			if c.OldTags[k] != c.NewTags[k] {
+				parts = append(parts, fmt.Sprintf("%s %q -> %q", k, c.OldTags[k], c.NewTags[k]))
 			}
 		}
+		target := "table " + table
+		if c.ColumnName != "" {
+			target = "column " + table + "." + c.ColumnName
+		}
+		return fmt.Sprintf("~ tags of %s: %s", target, strings.Join(parts, ", "))
+	case AddColumn:
+		return fmt.Sprintf("+ column %s.%s %s", table, c.Column.GetName(), FormatDataType(c.Column.GetDataType()))
+	case DropColumn:
+		return fmt.Sprintf("- column %s.%s", table, c.ColumnName)
+	case AlterColumn:
+		var parts []string
+		for _, delta := range c.Deltas() {
+			parts = append(parts, describeDelta(delta))
+		}
+		return fmt.Sprintf("~ column %s.%s: %s", table, c.OldColumn.GetName(), strings.Join(parts, ", "))
+	case SetColumnDefault:
+		return fmt.Sprintf("~ column %s.%s: default %q -> %q", table, c.Column.GetName(), c.OldDefault, c.Default)
+	case DropColumnDefault:
+		return fmt.Sprintf("~ column %s.%s: default %q dropped", table, c.Column.GetName(), c.OldDefault)
+	case SetColumnNullability:
+		if c.Nullable {
+			return fmt.Sprintf("~ column %s.%s: nullable", table, c.Column.GetName())
+		}
+		return fmt.Sprintf("~ column %s.%s: not null", table, c.Column.GetName())
+	case AlterColumnPosition:
+		position := "first"
+		if !c.First {
+			position = "after " + c.After
+		}
+		return fmt.Sprintf("~ column %s.%s: moved %s", table, c.Column.GetName(), position)
+	case AddConstraint:
+		return fmt.Sprintf("+ constraint %s on %s", c.Constraint.GetName(), table)
+	case DropConstraint:
+		return fmt.Sprintf("- constraint %s on %s", c.ConstraintName, table)
+	case AlterConstraint:
+		return fmt.Sprintf("~ constraint %s on %s", c.NewConstraint.GetName(), table)
+	case ValidateConstraint:
+		return fmt.Sprintf("~ constraint %s on %s: validated", c.ConstraintName, table)
+	case AddIndex:
+		return fmt.Sprintf("+ index %s on %s", c.Index.GetName(), table)
+	case DropIndex:
+		return fmt.Sprintf("- index %s on %s", c.IndexName, table)
+	case AddView:
+		return "+ view " + table
+	case DropView:
+		return "- view " + table
+	case AddTrigger:
+		return fmt.Sprintf("+ trigger %s on %s", c.Trigger.GetName(), table)
+	case DropTrigger:
+		return fmt.Sprintf("- trigger %s on %s", c.TriggerName, table)
+	}
+	return fmt.Sprintf("? %T", c)
+}
+
+func describeDelta(d ColumnDelta) string {
+	switch d := d.(type) {
+	case RenamedTo:
+		return "renamed to " + d.Name
+	case TypeChanged:
+		return fmt.Sprintf("type %s -> %s", FormatDataType(d.Old), FormatDataType(d.New))
+	case EnumLabelsAdded:
+		return fmt.Sprintf("enum labels added: %s", strings.Join(d.Added(), ", "))
+	case DefaultChanged:
+		return fmt.Sprintf("default %q -> %q", d.Old, d.New)
+	case NullabilityChanged:
+		if d.NowNullable {
+			return "nullable"
+		}
+		return "not null"
+	case GenerationChanged:
+		return fmt.Sprintf("generated %q -> %q", d.OldExpression, d.NewExpression)
+	case OptionChanged:
+		return fmt.Sprintf("%s %q -> %q", d.Key, d.Old, d.New)
+	case CommentChanged:
+		return fmt.Sprintf("comment %q -> %q", d.Old, d.New)
+	}
+	return fmt.Sprintf("%T", d)
+}
+
+// changeTableName returns the table a change applies to, or nil for
+// changes that are not table-scoped.
+func changeTableName(c SchemaChange) *ObjectName {
+	switch c := c.(type) {
+	case AddTable:
+		return c.Table.GetName()
+	case DropTable:
+		return c.TableName
+	case RenameTable:
+		return c.NewName
+	case AlterTableOptions:
+		return c.TableName
+	case AlterTags:
+		return c.TableName
+	case AddColumn:
+		return c.TableName
+	case DropColumn:
+		return c.TableName
+	case AlterColumn:
+		return c.TableName
+	case AlterColumnPosition:
+		return c.TableName
+	case SetColumnDefault:
+		return c.TableName
+	case DropColumnDefault:
+		return c.TableName
+	case SetColumnNullability:
+		return c.TableName
+	case AddConstraint:
+		return c.TableName
+	case DropConstraint:
+		return c.TableName
+	case AlterConstraint:
+		return c.TableName
+	case ValidateConstraint:
+		return c.TableName
+	case AddIndex:
+		return c.TableName
+	case DropIndex:
+		return c.TableName
+	case AddView:
+		return c.View.GetName()
+	case DropView:
+		return c.ViewName
+	case AddTrigger:
+		return c.Trigger.GetTableName()
+	case DropTrigger:
+		return c.TableName
 	}
+	return nil
 }
@@ -0,0 +1,163 @@
+package xmeta
+
+// equivalence.go compares data types loosely, for diffing schemas whose
+// types went through different dialect mappings.
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// EquivalencePolicy selects which data types DataTypesEquivalent treats as
+// equal beyond exact equality.
+type EquivalencePolicy struct {
+	// BooleanAsInteger treats BOOLEAN as TINYINT, as MySQL stores it.
+	BooleanAsInteger bool
+	// IntegerWidths treats TINYINT, SMALLINT, MEDIUMINT, INT and BIGINT as
+	// equal.
+	IntegerWidths bool
+	// TextLengths treats TEXT and VARCHAR of any size as equal.
+	TextLengths bool
+	// FloatWidths treats FLOAT, REAL and DOUBLE as equal.
+	FloatWidths bool
+	// IgnoreUnsigned treats UNSIGNED numeric types as their signed type.
+	IgnoreUnsigned bool
+	// Spellings maps a type spelling to the spelling it is compared as,
+	// before the rules above apply. Keys are matched case-insensitively
+	// against FormatDataType and against the declared name of a custom
+	// type; values must parse with ParseDataType. When it is set, a custom
+	// type whose name ParseDataType understands is compared as the parsed
+	// type.
+	Spellings map[string]string
+	// Equivalent, when set, decides the pairs that the rules above leave
+	// different.
+	Equivalent func(a, b *DataType) bool
+}
+
+// DefaultEquivalencePolicy only equates types that are the same storage in
+// some dialect: BOOLEAN and MySQL's TINYINT.
+var DefaultEquivalencePolicy = EquivalencePolicy{BooleanAsInteger: true}
+
+// CrossDialectEquivalencePolicy also folds the type spellings that differ
+// between dialects without differing in meaning. DiffLive uses it when the
+// two databases are of different dialects.
+var CrossDialectEquivalencePolicy = EquivalencePolicy{
+	BooleanAsInteger: true,
+	Spellings: map[string]string{
+		"TINYINT(1)": "BOOLEAN",
+		"BIT(1)":     "BOOLEAN",
+		"JSONB":      "JSON",
+		"DOUBLE":     "DOUBLE PRECISION",
+		"FLOAT8":     "DOUBLE PRECISION",
+		"FLOAT":      "REAL",
+		"DATETIME":   "TIMESTAMP",
+		"LONGTEXT":   "TEXT",
+	},
+}
+
+// DataTypesEquivalent reports whether a and b are the same type or equal
+// under policy. Array element types are compared by the same rules.
+func DataTypesEquivalent(a, b *DataType, policy EquivalencePolicy) bool {
+	if proto.Equal(a, b) {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	if proto.Equal(policy.canonical(a), policy.canonical(b)) {
+		return true
+	}
+	return policy.Equivalent != nil && policy.Equivalent(a, b)
+}
+
+// canonical returns the representative of dt's equivalence class.
+func (p EquivalencePolicy) canonical(dt *DataType) *DataType {
+	if dt == nil {
+		return nil
+	}
+	dt = p.respell(dt)
+	if arr := dt.GetArrayData(); arr != nil {
+		return &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: p.canonical(arr.Type)}}}
+	}
+
+	dt = proto.Clone(dt).(*DataType)
+	if p.IgnoreUnsigned {
+		m := dt.ProtoReflect()
+		if oneof := m.WhichOneof(m.Descriptor().Oneofs().ByName("TypeClause")); oneof != nil && oneof.Message() != nil {
+			clause := m.Get(oneof).Message()
+			if fd := clause.Descriptor().Fields().ByName("IsUnsigned"); fd != nil {
+				clause.Clear(fd)
+			}
+		}
+	}
+
+	unsigned := false
+	switch t := dt.TypeClause.(type) {
+	case *DataType_BooleanData:
+		if !p.BooleanAsInteger {
+			return dt
+		}
+		dt = &DataType{TypeClause: &DataType_TinyIntData{TinyIntData: &TinyInt{}}}
+	case *DataType_TinyIntData:
+		unsigned = t.TinyIntData.GetIsUnsigned()
+	case *DataType_SmallIntData:
+		unsigned = t.SmallIntData.GetIsUnsigned()
+	case *DataType_MediumIntData:
+		unsigned = t.MediumIntData.GetIsUnsigned()
+	case *DataType_IntData:
+		unsigned = t.IntData.GetIsUnsigned()
+	case *DataType_BigIntData:
+		unsigned = t.BigIntData.GetIsUnsigned()
+	case *DataType_VarcharData, *DataType_TextData:
+		if p.TextLengths {
+			return &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}
+		}
+		return dt
+	case *DataType_FloatData, *DataType_RealData, *DataType_DoubleData:
+		if p.FloatWidths {
+			return &DataType{TypeClause: &DataType_DoubleData{DoubleData: &DoubleType{}}}
+		}
+		return dt
+	default:
+		return dt
+	}
+	if p.IntegerWidths {
+		return &DataType{TypeClause: &DataType_IntData{IntData: &Int{IsUnsigned: unsigned}}}
+	}
+	return dt
+}
+
+// respell returns the type dt is compared as under p.Spellings. Array
+// element types are left to canonical.
+func (p EquivalencePolicy) respell(dt *DataType) *DataType {
+	if len(p.Spellings) == 0 {
+		return dt
+	}
+	if custom := dt.GetCustomData(); custom != nil {
+		name := formatObjectName(custom)
+		if c, ok := p.spelling(name); ok {
+			return c
+		}
+		if parsed, err := ParseDataType(name); err == nil {
+			dt = parsed
+		}
+	}
+	if dt.GetArrayData() != nil {
+		return dt
+	}
+	if c, ok := p.spelling(FormatDataType(dt)); ok {
+		return c
+	}
+	return dt
+}
+
+func (p EquivalencePolicy) spelling(name string) (*DataType, bool) {
+	for key, value := range p.Spellings {
+		if strings.EqualFold(key, name) {
+			dt, err := ParseDataType(value)
+			return dt, err == nil
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,92 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestDataTypesEquivalent(t *testing.T) {
+	parse := func(s string) *DataType {
+		dt, err := ParseDataType(s)
+		if err != nil {
+			t.Fatalf("ParseDataType(%q): %v", s, err)
+		}
+		return dt
+	}
+	loose := EquivalencePolicy{IntegerWidths: true, TextLengths: true, FloatWidths: true, BooleanAsInteger: true}
+	tests := []struct {
+		a, b   string
+		policy EquivalencePolicy
+		want   bool
+	}{
+		{"INT", "INT", EquivalencePolicy{}, true},
+		{"BOOLEAN", "TINYINT", DefaultEquivalencePolicy, true},
+		{"BOOLEAN", "INT", DefaultEquivalencePolicy, false},
+		{"BOOLEAN", "INT", loose, true},
+		{"SMALLINT", "BIGINT", loose, true},
+		{"INT", "INT UNSIGNED", loose, false},
+		{"INT", "INT UNSIGNED", EquivalencePolicy{IgnoreUnsigned: true}, true},
+		{"TEXT", "VARCHAR(255)", DefaultEquivalencePolicy, false},
+		{"TEXT", "VARCHAR(255)", loose, true},
+		{"REAL", "DOUBLE PRECISION", loose, true},
+		{"INTEGER[]", "BIGINT[]", loose, true},
+		{"INTEGER[]", "TEXT[]", loose, false},
+		{"TEXT", "INT", loose, false},
+		{"DATETIME", "TIMESTAMP", CrossDialectEquivalencePolicy, true},
+		{"DOUBLE", "DOUBLE PRECISION", CrossDialectEquivalencePolicy, true},
+		{"JSONB[]", "JSON[]", CrossDialectEquivalencePolicy, true},
+		{"JSONB", "TEXT", CrossDialectEquivalencePolicy, false},
+		{"JSONB", "JSON", DefaultEquivalencePolicy, false},
+	}
+	for _, tt := range tests {
+		if got := DataTypesEquivalent(parse(tt.a), parse(tt.b), tt.policy); got != tt.want {
+			t.Errorf("DataTypesEquivalent(%s, %s, %+v) = %v", tt.a, tt.b, tt.policy, got)
+		}
+	}
+
+	custom := EquivalencePolicy{Equivalent: func(a, b *DataType) bool {
+		return a.GetUUIDData() != 0 && b.GetCharData().GetSize() == 36
+	}}
+	if !DataTypesEquivalent(parse("UUID"), parse("CHAR(36)"), custom) {
+		t.Error("Expected the custom rule to equate UUID and CHAR(36)")
+	}
+
+	// Custom types are respelled by name, or parsed
+	named := func(name string) *DataType {
+		return &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: []string{name}}}}
+	}
+	spelled := EquivalencePolicy{Spellings: map[string]string{"tinyint(1)": "boolean"}}
+	if !DataTypesEquivalent(named("TINYINT(1)"), parse("BOOLEAN"), spelled) {
+		t.Error("Expected TINYINT(1) to compare as BOOLEAN")
+	}
+	if !DataTypesEquivalent(named("bigint"), parse("BIGINT"), spelled) {
+		t.Error("Expected a known custom type to be parsed")
+	}
+}
+
+func TestDiffDatabase_TypeEquivalence(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL("CREATE TABLE users (id BIGINT, active TINYINT, name TEXT);", DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL("CREATE TABLE users (id INT, active BOOLEAN NOT NULL, name VARCHAR(100));", DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changes := DiffDatabase(current, desired); len(changes) != 3 {
+		t.Errorf("Expected three column changes without a policy, got %v", changes)
+	}
+
+	changes := DiffDatabaseWithOptions(current, desired, DiffOptions{TypeEquivalence: &EquivalencePolicy{IntegerWidths: true, BooleanAsInteger: true}})
+	if len(changes) != 2 {
+		t.Fatalf("Expected changes to active and name only, got %v", changes)
+	}
+	// The nullability changed but the equivalent type is kept
+	set, ok := changes[0].(SetColumnNullability)
+	if !ok {
+		set, ok = changes[1].(SetColumnNullability)
+	}
+	if !ok || set.Column.Name != "active" || set.Nullable {
+		t.Errorf("Expected only the nullability change of active, got %v", changes)
+	}
+}
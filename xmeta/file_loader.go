@@ -3,50 +3,177 @@ package xmeta
 // file_loader.go provides functions to load MetaDatabase from various file formats.
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
 )
 
-// LoadMetaDatabaseFromFile loads a MetaDatabase from a file.
-// The format is detected from the file extension:
+// Format identifies a serialization format for metadata files and streams.
+type Format int
+
+const (
+	FormatUnknown Format = iota
+	FormatTextProto
+	FormatJSON
+	FormatBinaryProto
+	FormatYAML
+	FormatSQL
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatTextProto:
+		return "textproto"
+	case FormatJSON:
+		return "json"
+	case FormatBinaryProto:
+		return "binaryproto"
+	case FormatYAML:
+		return "yaml"
+	case FormatSQL:
+		return "sql"
+	default:
+		return "unknown"
+	}
+}
+
+// FormatFromPath detects the format from the file extension:
 //   - .textpb, .txtpb, .pbtxt → Text proto format
 //   - .json → JSON format
 //   - .pb, .bin → Binary proto format
-func LoadMetaDatabaseFromFile(path string) (*MetaDatabase, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("reading file: %w", err)
-	}
-
-	db := &MetaDatabase{}
+//   - .yaml, .yml → YAML format (protojson field names)
+//   - .sql → Postgres-flavored CREATE TABLE statements
+func FormatFromPath(path string) (Format, error) {
 	ext := strings.ToLower(filepath.Ext(path))
-
 	switch ext {
 	case ".textpb", ".txtpb", ".pbtxt":
-		if err := prototext.Unmarshal(data, db); err != nil {
-			return nil, fmt.Errorf("parsing text proto: %w", err)
-		}
+		return FormatTextProto, nil
 	case ".json":
-		if err := protojson.Unmarshal(data, db); err != nil {
-			return nil, fmt.Errorf("parsing JSON: %w", err)
-		}
+		return FormatJSON, nil
 	case ".pb", ".bin":
-		if err := proto.Unmarshal(data, db); err != nil {
-			return nil, fmt.Errorf("parsing binary proto: %w", err)
+		return FormatBinaryProto, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".sql":
+		return FormatSQL, nil
+	}
+	return FormatUnknown, fmt.Errorf("unknown file extension: %s (supported: .textpb, .json, .pb, .yaml, .sql)", ext)
+}
+
+// DetectFormat sniffs the format of a serialized MetaDatabase: content
+// starting with "{" is JSON, CREATE or ALTER statements are SQL, UTF-8 text
+// is tried as text proto and then YAML, and anything else as binary proto.
+// It returns FormatUnknown when nothing parses.
+func DetectFormat(data []byte) Format {
+	return detectFormat(data, &MetaDatabase{})
+}
+
+// detectFormat is DetectFormat for the message type of m, which is left
+// unchanged.
+func detectFormat(data []byte, m proto.Message) Format {
+	text := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(text) == 0 {
+		return FormatUnknown
+	}
+	if text[0] == '{' {
+		return FormatJSON
+	}
+	if utf8.Valid(text) {
+		for _, line := range strings.Split(string(text), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "--") {
+				continue
+			}
+			if keyword, _, _ := strings.Cut(strings.ToUpper(line), " "); keyword == "CREATE" || keyword == "ALTER" {
+				return FormatSQL
+			}
+			break
+		}
+		for _, format := range []Format{FormatTextProto, FormatYAML} {
+			if unmarshalFormat(data, format, m.ProtoReflect().New().Interface()) == nil {
+				return format
+			}
 		}
-	default:
-		return nil, fmt.Errorf("unknown file extension: %s (supported: .textpb, .json, .pb)", ext)
 	}
+	if unmarshalFormat(data, FormatBinaryProto, m.ProtoReflect().New().Interface()) == nil {
+		return FormatBinaryProto
+	}
+	return FormatUnknown
+}
 
+// LoadMetaDatabaseFromReader loads a MetaDatabase from a stream in the given format.
+func LoadMetaDatabaseFromReader(r io.Reader, format Format) (*MetaDatabase, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+
+	db := &MetaDatabase{}
+	if err := unmarshalFormat(data, format, db); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
 
+// SaveOptions controls how SaveMetaDatabaseToWriter and
+// SaveMetaDatabaseToFile encode JSON, YAML and text proto.
+type SaveOptions struct {
+	// Compact writes JSON and text proto on a single line instead of indented.
+	Compact bool
+	// EmitDefaults writes JSON and YAML fields that hold their default value.
+	EmitDefaults bool
+	// UseProtoNames writes JSON and YAML keys with the proto field names
+	// instead of their JSON names.
+	UseProtoNames bool
+}
+
+// saveOptions returns the first of opts, or the zero SaveOptions.
+func saveOptions(opts []SaveOptions) SaveOptions {
+	if len(opts) == 0 {
+		return SaveOptions{}
+	}
+	return opts[0]
+}
+
+// SaveMetaDatabaseToWriter writes a MetaDatabase to a stream in the given
+// format. An optional SaveOptions controls the encoding.
+func SaveMetaDatabaseToWriter(db *MetaDatabase, w io.Writer, format Format, opts ...SaveOptions) error {
+	data, err := marshalFormat(db, format, saveOptions(opts))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// LoadMetaDatabaseFromFile loads a MetaDatabase from a file.
+// The format is detected from the file extension, see FormatFromPath, or
+// from the content with DetectFormat when the extension is not recognized.
+func LoadMetaDatabaseFromFile(path string) (*MetaDatabase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	format, err := formatOf(path, data, &MetaDatabase{})
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadMetaDatabaseFromReader(bytes.NewReader(data), format)
+}
+
 // LoadMetaTableFromFile loads a single MetaTable from a file.
 // Useful when defining individual tables in separate files.
 func LoadMetaTableFromFile(path string) (*MetaTable, error) {
@@ -54,79 +181,338 @@ func LoadMetaTableFromFile(path string) (*MetaTable, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
+	format, err := formatOf(path, data, &MetaTable{})
+	if err != nil {
+		return nil, err
+	}
 
 	table := &MetaTable{}
-	ext := strings.ToLower(filepath.Ext(path))
+	if err := unmarshalFormat(data, format, table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
 
-	switch ext {
-	case ".textpb", ".txtpb", ".pbtxt":
-		if err := prototext.Unmarshal(data, table); err != nil {
-			return nil, fmt.Errorf("parsing text proto: %w", err)
+// formatOf returns the format of a file from its extension, falling back
+// to sniffing its content as a message like m.
+func formatOf(path string, data []byte, m proto.Message) (Format, error) {
+	format, err := FormatFromPath(path)
+	if err == nil {
+		return format, nil
+	}
+	if detected := detectFormat(data, m); detected != FormatUnknown {
+		return detected, nil
+	}
+	return FormatUnknown, fmt.Errorf("%w, and the content is not recognized", err)
+}
+
+// SaveMetaDatabaseToFile saves a MetaDatabase to a file.
+// Format is determined by file extension. An optional SaveOptions controls
+// the encoding.
+func SaveMetaDatabaseToFile(db *MetaDatabase, path string, opts ...SaveOptions) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := SaveMetaDatabaseToWriter(db, &buf, format, opts...); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// unmarshalFormat decodes data in the given format into m.
+func unmarshalFormat(data []byte, format Format, m proto.Message) error {
+	switch format {
+	case FormatTextProto:
+		if err := prototext.Unmarshal(data, m); err != nil {
+			return fmt.Errorf("parsing text proto: %w", err)
 		}
-	case ".json":
-		if err := protojson.Unmarshal(data, table); err != nil {
-			return nil, fmt.Errorf("parsing JSON: %w", err)
+	case FormatJSON:
+		if err := protojson.Unmarshal(data, m); err != nil {
+			return fmt.Errorf("parsing JSON: %w", err)
 		}
-	case ".pb", ".bin":
-		if err := proto.Unmarshal(data, table); err != nil {
-			return nil, fmt.Errorf("parsing binary proto: %w", err)
+	case FormatBinaryProto:
+		if err := proto.Unmarshal(data, m); err != nil {
+			return fmt.Errorf("parsing binary proto: %w", err)
+		}
+	case FormatYAML:
+		jsonData, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return fmt.Errorf("parsing YAML: %w", err)
+		}
+		if err := protojson.Unmarshal(jsonData, m); err != nil {
+			return fmt.Errorf("parsing YAML: %w", err)
+		}
+	case FormatSQL:
+		parsed, err := LoadMetaDatabaseFromSQL(string(data), DialectPostgres)
+		if err != nil {
+			return fmt.Errorf("parsing SQL: %w", err)
+		}
+		switch v := m.(type) {
+		case *MetaDatabase:
+			proto.Merge(v, parsed)
+		case *MetaTable:
+			if len(parsed.Tables) != 1 {
+				return fmt.Errorf("parsing SQL: expected 1 table, found %d", len(parsed.Tables))
+			}
+			proto.Merge(v, parsed.Tables[0])
+		default:
+			return fmt.Errorf("parsing SQL: unsupported message %T", m)
 		}
 	default:
-		return nil, fmt.Errorf("unknown file extension: %s", ext)
+		return fmt.Errorf("unknown format: %s", format)
 	}
-
-	return table, nil
+	return nil
 }
 
-// SaveMetaDatabaseToFile saves a MetaDatabase to a file.
-// Format is determined by file extension.
-func SaveMetaDatabaseToFile(db *MetaDatabase, path string) error {
-	ext := strings.ToLower(filepath.Ext(path))
+// marshalFormat encodes m in the given format.
+func marshalFormat(m proto.Message, format Format, opts SaveOptions) ([]byte, error) {
 	var data []byte
 	var err error
 
-	switch ext {
-	case ".textpb", ".txtpb", ".pbtxt":
-		data, err = prototext.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(db)
-	case ".json":
-		data, err = protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(db)
-	case ".pb", ".bin":
-		data, err = proto.Marshal(db)
+	jsonOpts := protojson.MarshalOptions{EmitUnpopulated: opts.EmitDefaults, UseProtoNames: opts.UseProtoNames}
+	switch format {
+	case FormatTextProto:
+		textOpts := prototext.MarshalOptions{Multiline: true, Indent: "  "}
+		if opts.Compact {
+			textOpts = prototext.MarshalOptions{}
+		}
+		data, err = textOpts.Marshal(m)
+	case FormatJSON:
+		if !opts.Compact {
+			jsonOpts.Multiline, jsonOpts.Indent = true, "  "
+		}
+		data, err = jsonOpts.Marshal(m)
+	case FormatBinaryProto:
+		data, err = proto.Marshal(m)
+	case FormatYAML:
+		data, err = jsonOpts.Marshal(m)
+		if err == nil {
+			data, err = yaml.JSONToYAML(data)
+		}
+	case FormatSQL:
+		data, err = marshalSQL(m)
 	default:
-		return fmt.Errorf("unknown file extension: %s", ext)
+		return nil, fmt.Errorf("unknown format: %s", format)
 	}
 
 	if err != nil {
-		return fmt.Errorf("marshaling: %w", err)
+		return nil, fmt.Errorf("marshaling: %w", err)
+	}
+	return data, nil
+}
+
+// marshalSQL renders the tables of a MetaDatabase or a single MetaTable as
+// Postgres CREATE TABLE statements, referenced tables first.
+func marshalSQL(m proto.Message) ([]byte, error) {
+	var tables []*MetaTable
+	var views []*MetaView
+	switch v := m.(type) {
+	case *MetaDatabase:
+		tables, _ = topoSortTables(v.Tables)
+		views = v.Views
+	case *MetaTable:
+		tables = []*MetaTable{v}
+	default:
+		return nil, fmt.Errorf("unsupported message %T", m)
+	}
+
+	var buf bytes.Buffer
+	for _, t := range tables {
+		stmts, err := GenerateSQL(AddTable{Table: t}, DialectPostgres)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", formatObjectName(t.Name), err)
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintf(&buf, "%s;\n\n", stmt)
+		}
+	}
+	for _, v := range views {
+		stmt, err := createViewSQL(v, DialectPostgres)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%s;\n\n", stmt)
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadMetaDatabaseFromDirOptions controls how LoadMetaDatabaseFromDirWithOptions
+// scans a directory.
+type LoadMetaDatabaseFromDirOptions struct {
+	// Recursive descends into subdirectories.
+	Recursive bool
+	// DatabaseFilePattern is a filepath.Match pattern, such as "database.*",
+	// for files holding a whole MetaDatabase whose tables are merged in.
+	// Their views, sequences and triggers are appended and their options
+	// added; their name is used when dbName is empty. Empty means only
+	// *.table.* files are loaded.
+	DatabaseFilePattern string
+	// FailOnConflict returns an error when two files define the same table
+	// differently, instead of letting the later file override.
+	FailOnConflict bool
+}
+
+// SaveMetaDatabaseToDir writes db to dir as one <name>.table.<ext> file per
+// table, named after its qualified name with characters other than
+// letters, digits, '.', '-' and '_' replaced by '_'. Views, sequences,
+// triggers and the database name and options go to database.<ext>, which
+// is only written when db has any. dir is created if needed; other files in
+// it are left alone. LoadMetaDatabaseFromDirWithOptions with
+// DatabaseFilePattern "database.*" reads the directory back, with the
+// tables in file name order.
+func SaveMetaDatabaseToDir(db *MetaDatabase, dir string, format Format, opts ...SaveOptions) error {
+	ext := formatExtension(format)
+	if ext == "" {
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	written := make(map[string]string) // file name -> table
+	for _, table := range db.GetTables() {
+		key := objectNameKey(table.Name)
+		name := sanitizeFileName(key) + ".table." + ext
+		if other, ok := written[name]; ok {
+			return fmt.Errorf("tables %s and %s map to the same file %s", other, key, name)
+		}
+		written[name] = key
+		data, err := marshalFormat(table, format, saveOptions(opts))
+		if err != nil {
+			return fmt.Errorf("table %s: %w", key, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
 	}
 
-	return os.WriteFile(path, data, 0644)
+	rest := CloneMetaDatabase(db)
+	rest.Tables = nil
+	if proto.Size(rest) == 0 {
+		return nil
+	}
+	return SaveMetaDatabaseToFile(rest, filepath.Join(dir, "database."+ext), opts...)
+}
+
+// formatExtension returns the file extension written for format, or "".
+func formatExtension(format Format) string {
+	switch format {
+	case FormatTextProto:
+		return "textpb"
+	case FormatJSON:
+		return "json"
+	case FormatBinaryProto:
+		return "pb"
+	case FormatYAML:
+		return "yaml"
+	case FormatSQL:
+		return "sql"
+	}
+	return ""
+}
+
+// sanitizeFileName replaces the characters of name that are unsafe in a
+// file name with '_'.
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
 }
 
 // LoadMetaDatabaseFromDir loads a MetaDatabase by scanning a directory for table files.
-// Each file named *.table.textpb (or .json) is loaded as a MetaTable.
+// Each file named *.table.textpb (or .json, .yaml, .sql) is loaded as a MetaTable.
 func LoadMetaDatabaseFromDir(dir string, dbName string) (*MetaDatabase, error) {
+	return LoadMetaDatabaseFromDirWithOptions(dir, dbName, LoadMetaDatabaseFromDirOptions{})
+}
+
+// LoadMetaDatabaseFromDirWithOptions loads a MetaDatabase from table files and,
+// optionally, whole-database files in a directory tree. Files are read in
+// lexical path order and tables are deduplicated by qualified name: a later
+// file overrides an earlier definition, keeping the original position.
+func LoadMetaDatabaseFromDirWithOptions(dir string, dbName string, opts LoadMetaDatabaseFromDirOptions) (*MetaDatabase, error) {
 	db := &MetaDatabase{Name: dbName}
+	index := make(map[string]int)      // table key -> position in db.Tables
+	sources := make(map[string]string) // table key -> file that defined it
 
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return nil, fmt.Errorf("reading directory: %w", err)
+	merge := func(path string, table *MetaTable) error {
+		key := objectNameKey(table.Name)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(db.Tables)
+			sources[key] = path
+			db.Tables = append(db.Tables, table)
+			return nil
+		}
+		if opts.FailOnConflict && !proto.Equal(db.Tables[i], table) {
+			return fmt.Errorf("table %s in %s conflicts with %s", key, path, sources[key])
+		}
+		db.Tables[i] = table
+		sources[key] = path
+		return nil
 	}
 
-	for _, entry := range entries {
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 		if entry.IsDir() {
-			continue
+			if path != dir && !opts.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
 		}
+
 		name := entry.Name()
+		if opts.DatabaseFilePattern != "" {
+			matched, err := filepath.Match(opts.DatabaseFilePattern, name)
+			if err != nil {
+				return fmt.Errorf("invalid database file pattern: %w", err)
+			}
+			if matched {
+				fileDB, err := LoadMetaDatabaseFromFile(path)
+				if err != nil {
+					return fmt.Errorf("loading database %s: %w", path, err)
+				}
+				for _, table := range fileDB.Tables {
+					if err := merge(path, table); err != nil {
+						return err
+					}
+				}
+				if db.Name == "" {
+					db.Name = fileDB.Name
+				}
+				for k, v := range fileDB.Options {
+					if db.Options == nil {
+						db.Options = make(map[string]string)
+					}
+					db.Options[k] = v
+				}
+				db.Views = append(db.Views, fileDB.Views...)
+				db.Sequences = append(db.Sequences, fileDB.Sequences...)
+				db.Triggers = append(db.Triggers, fileDB.Triggers...)
+				return nil
+			}
+		}
+
 		// Match patterns like users.table.textpb or orders.table.json
 		if strings.Contains(name, ".table.") {
-			path := filepath.Join(dir, name)
 			table, err := LoadMetaTableFromFile(path)
 			if err != nil {
-				return nil, fmt.Errorf("loading table %s: %w", name, err)
+				return fmt.Errorf("loading table %s: %w", name, err)
 			}
-			db.Tables = append(db.Tables, table)
+			return merge(path, table)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading directory: %w", err)
 	}
 
 	return db, nil
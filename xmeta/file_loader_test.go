@@ -0,0 +1,253 @@
+package xmeta
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetaDatabaseReaderWriterRoundTrip(t *testing.T) {
+	db := &MetaDatabase{
+		Name: "testdb",
+		Tables: []*MetaTable{
+			{
+				Name:    &ObjectName{Idents: []string{"public", "users"}},
+				Comment: "User accounts",
+				Elements: []*TableElement{
+					{TableElementClause: &TableElement_ColumnDefElement{
+						ColumnDefElement: &ColumnDef{
+							Name:     "email",
+							DataType: &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 255}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, format := range []Format{FormatTextProto, FormatJSON, FormatBinaryProto, FormatYAML} {
+		var buf bytes.Buffer
+		if err := SaveMetaDatabaseToWriter(db, &buf, format); err != nil {
+			t.Fatalf("%s: save failed: %v", format, err)
+		}
+		loaded, err := LoadMetaDatabaseFromReader(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: load failed: %v", format, err)
+		}
+		if !proto.Equal(db, loaded) {
+			t.Errorf("%s: round trip mismatch: %v", format, loaded)
+		}
+	}
+}
+
+func TestSaveMetaDatabaseToWriter_Options(t *testing.T) {
+	db := &MetaDatabase{
+		Name: "testdb",
+		Tables: []*MetaTable{{
+			Name: &ObjectName{Idents: []string{"readings"}},
+			Elements: []*TableElement{{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{
+				Name:     "value",
+				DataType: &DataType{TypeClause: &DataType_DoubleData{DoubleData: &DoubleType{IsDoublePrecision: true}}},
+			}}}},
+		}},
+	}
+	save := func(format Format, opts ...SaveOptions) string {
+		var buf bytes.Buffer
+		if err := SaveMetaDatabaseToWriter(db, &buf, format, opts...); err != nil {
+			t.Fatalf("%s: save failed: %v", format, err)
+		}
+		loaded, err := LoadMetaDatabaseFromReader(bytes.NewReader(buf.Bytes()), format)
+		if err != nil || !proto.Equal(db, loaded) {
+			t.Errorf("%s: round trip mismatch: %v, %v", format, loaded, err)
+		}
+		return buf.String()
+	}
+
+	if out := save(FormatJSON); !strings.Contains(out, "\n") || !strings.Contains(out, `"isDoublePrecision"`) {
+		t.Errorf("Expected indented JSON with JSON names, got %s", out)
+	}
+	if out := save(FormatJSON, SaveOptions{Compact: true, UseProtoNames: true}); strings.Contains(strings.TrimSpace(out), "\n") ||
+		!strings.Contains(out, `"is_double_precision"`) {
+		t.Errorf("Expected compact JSON with proto names, got %s", out)
+	}
+	if out := save(FormatYAML, SaveOptions{EmitDefaults: true}); !strings.Contains(out, "Comment: \"\"") {
+		t.Errorf("Expected default-valued fields in YAML, got %s", out)
+	}
+	if out := save(FormatTextProto, SaveOptions{Compact: true}); strings.Contains(strings.TrimSpace(out), "\n") {
+		t.Errorf("Expected single-line text proto, got %s", out)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"schema.textpb":     FormatTextProto,
+		"schema.JSON":       FormatJSON,
+		"schema.pb":         FormatBinaryProto,
+		"users.table.yml":   FormatYAML,
+		"users.table.yaml":  FormatYAML,
+		"users.table.pbtxt": FormatTextProto,
+		"schema.sql":        FormatSQL,
+	}
+	for path, expected := range tests {
+		got, err := FormatFromPath(path)
+		if err != nil || got != expected {
+			t.Errorf("FormatFromPath(%q) = %s, %v; expected %s", path, got, err, expected)
+		}
+	}
+
+	if _, err := FormatFromPath("schema.csv"); err == nil {
+		t.Error("Expected error for unknown extension")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	db := &MetaDatabase{
+		Name: "testdb",
+		Tables: []*MetaTable{
+			{Name: &ObjectName{Idents: []string{"users"}}},
+		},
+	}
+	for _, format := range []Format{FormatTextProto, FormatJSON, FormatBinaryProto, FormatYAML} {
+		var buf bytes.Buffer
+		if err := SaveMetaDatabaseToWriter(db, &buf, format); err != nil {
+			t.Fatalf("%s: save failed: %v", format, err)
+		}
+		if got := DetectFormat(buf.Bytes()); got != format {
+			t.Errorf("DetectFormat(%s) = %s", format, got)
+		}
+	}
+
+	sql := "-- users\ncreate table users (id int primary key);\n"
+	if got := DetectFormat([]byte(sql)); got != FormatSQL {
+		t.Errorf("DetectFormat(sql) = %s", got)
+	}
+	if got := DetectFormat([]byte("  \n")); got != FormatUnknown {
+		t.Errorf("DetectFormat(blank) = %s", got)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.schema")
+	if err := os.WriteFile(path, []byte(sql), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadMetaDatabaseFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromFile failed: %v", err)
+	}
+	if len(loaded.Tables) != 1 {
+		t.Errorf("Expected 1 table, got %d", len(loaded.Tables))
+	}
+
+	if err := os.WriteFile(path, []byte{0xff, 0xfe, 0xfd}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMetaDatabaseFromFile(path); err == nil {
+		t.Error("Expected error for unrecognized content")
+	}
+}
+
+func TestLoadMetaDatabaseFromDirWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("database.textpb", `
+Tables { Name { Idents: "users" } Comment: "from database" }
+Tables { Name { Idents: "orders" } }`)
+	write("users.table.textpb", `Name { Idents: "users" } Comment: "from table file"`)
+	write("sub/items.table.json", `{"Name": {"Idents": ["items"]}}`)
+
+	// Defaults: only top-level *.table.* files
+	db, err := LoadMetaDatabaseFromDir(dir, "shop")
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromDir failed: %v", err)
+	}
+	if len(db.Tables) != 1 || db.Tables[0].Comment != "from table file" {
+		t.Errorf("Unexpected tables %v", db.Tables)
+	}
+
+	opts := LoadMetaDatabaseFromDirOptions{Recursive: true, DatabaseFilePattern: "database.*"}
+	db, err = LoadMetaDatabaseFromDirWithOptions(dir, "shop", opts)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromDirWithOptions failed: %v", err)
+	}
+	var names []string
+	for _, table := range db.Tables {
+		names = append(names, objectNameKey(table.Name))
+	}
+	if strings.Join(names, ",") != "users,orders,items" {
+		t.Errorf("Unexpected tables %v", names)
+	}
+	// users.table.textpb sorts after database.textpb and overrides it
+	if db.Tables[0].Comment != "from table file" {
+		t.Errorf("Expected later file to override, got %q", db.Tables[0].Comment)
+	}
+
+	opts.FailOnConflict = true
+	if _, err := LoadMetaDatabaseFromDirWithOptions(dir, "shop", opts); err == nil {
+		t.Error("Expected conflict error for users")
+	}
+}
+
+func TestSaveMetaDatabaseToDir(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`CREATE TABLE public.users (id INTEGER PRIMARY KEY, email TEXT);
+CREATE TABLE "sales"."order items" (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES public.users (id));
+CREATE VIEW public.emails AS SELECT email FROM public.users;`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Name = "shop"
+	db.Options = map[string]string{"SourceDialect": "postgres"}
+
+	for _, format := range []Format{FormatTextProto, FormatJSON, FormatYAML} {
+		dir := filepath.Join(t.TempDir(), "schema")
+		if err := SaveMetaDatabaseToDir(db, dir, format); err != nil {
+			t.Fatalf("%s: save failed: %v", format, err)
+		}
+		ext := formatExtension(format)
+		for _, name := range []string{"public.users.table.", "sales.order_items.table.", "database."} {
+			if _, err := os.Stat(filepath.Join(dir, name+ext)); err != nil {
+				t.Errorf("%s: expected file %s: %v", format, name+ext, err)
+			}
+		}
+
+		loaded, err := LoadMetaDatabaseFromDirWithOptions(dir, "", LoadMetaDatabaseFromDirOptions{DatabaseFilePattern: "database.*"})
+		if err != nil {
+			t.Fatalf("%s: load failed: %v", format, err)
+		}
+		if loaded.Name != "shop" || loaded.Options["SourceDialect"] != "postgres" || len(loaded.Views) != 1 {
+			t.Errorf("%s: database metadata not restored: %v", format, loaded)
+		}
+		if changes := DiffDatabase(db, loaded); len(changes) != 0 {
+			t.Errorf("%s: expected no changes after round trip, got %v", format, changes)
+		}
+	}
+
+	// Without database-level metadata only table files are written
+	dir := t.TempDir()
+	if err := SaveMetaDatabaseToDir(&MetaDatabase{Tables: db.Tables}, dir, FormatTextProto); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "database.textpb")); !os.IsNotExist(err) {
+		t.Errorf("Expected no database file, got %v", err)
+	}
+
+	clash := &MetaDatabase{Tables: []*MetaTable{
+		{Name: &ObjectName{Idents: []string{"a b"}}},
+		{Name: &ObjectName{Idents: []string{"a/b"}}},
+	}}
+	if err := SaveMetaDatabaseToDir(clash, t.TempDir(), FormatJSON); err == nil {
+		t.Error("Expected an error for tables mapping to the same file")
+	}
+}
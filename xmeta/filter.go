@@ -0,0 +1,110 @@
+package xmeta
+
+// filter.go restricts the loaders to a subset of schemas and tables.
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// LoadFilter selects the tables a loader introspects. Patterns are globs in
+// which "*" matches any run of characters and "?" a single character. Table
+// patterns match the bare table name, schema patterns the Postgres schema,
+// MySQL database or BigQuery dataset. Empty include lists select everything;
+// exclusions win over inclusions.
+type LoadFilter struct {
+	IncludeTables  []string
+	ExcludeTables  []string
+	IncludeSchemas []string
+}
+
+// matchTable reports whether a table name passes the filter.
+func (f LoadFilter) matchTable(name string) bool {
+	for _, p := range f.ExcludeTables {
+		if globMatch(p, name) {
+			return false
+		}
+	}
+	return matchAny(f.IncludeTables, name)
+}
+
+// matchSchema reports whether a schema or dataset name passes the filter.
+func (f LoadFilter) matchSchema(name string) bool {
+	return matchAny(f.IncludeSchemas, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if globMatch(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func globMatch(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
+
+// sqlConditions renders the filter as LIKE predicates on the given table and
+// schema columns, each prefixed with " AND ", and appends their arguments to
+// args. placeholder renders the n-th (1-based) bind parameter. An empty
+// schemaCol skips the schema patterns.
+func (f LoadFilter) sqlConditions(tableCol, schemaCol string, args []any, placeholder func(n int) string) (string, []any) {
+	var b strings.Builder
+	like := func(col string, pattern string, not bool) string {
+		args = append(args, likePattern(pattern))
+		op := "LIKE"
+		if not {
+			op = "NOT LIKE"
+		}
+		return fmt.Sprintf("%s %s %s ESCAPE '!'", col, op, placeholder(len(args)))
+	}
+	anyOf := func(col string, patterns []string) {
+		if len(patterns) == 0 {
+			return
+		}
+		var parts []string
+		for _, p := range patterns {
+			parts = append(parts, like(col, p, false))
+		}
+		b.WriteString(" AND (" + strings.Join(parts, " OR ") + ")")
+	}
+
+	anyOf(tableCol, f.IncludeTables)
+	for _, p := range f.ExcludeTables {
+		b.WriteString(" AND " + like(tableCol, p, true))
+	}
+	if schemaCol != "" {
+		anyOf(schemaCol, f.IncludeSchemas)
+	}
+	return b.String(), args
+}
+
+// likePattern converts a glob to a LIKE pattern escaped with "!".
+func likePattern(glob string) string {
+	var b strings.Builder
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		case '%', '_', '!':
+			b.WriteByte('!')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func pgPlaceholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func questionPlaceholder(int) string { return "?" }
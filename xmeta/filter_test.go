@@ -0,0 +1,44 @@
+package xmeta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadFilter_SQLConditions(t *testing.T) {
+	f := LoadFilter{
+		IncludeTables:  []string{"user*", "order_?"},
+		ExcludeTables:  []string{"*_bak"},
+		IncludeSchemas: []string{"public"},
+	}
+
+	conds, args := f.sqlConditions("tablename", "nspname", []any{"app"}, pgPlaceholder)
+	want := " AND (tablename LIKE $2 ESCAPE '!' OR tablename LIKE $3 ESCAPE '!')" +
+		" AND tablename NOT LIKE $4 ESCAPE '!'" +
+		" AND (nspname LIKE $5 ESCAPE '!')"
+	if conds != want {
+		t.Errorf("Unexpected conditions:\n%s", conds)
+	}
+	if !reflect.DeepEqual(args, []any{"app", "user%", "order!__", "%!_bak", "public"}) {
+		t.Errorf("Unexpected args %v", args)
+	}
+
+	if conds, args := (LoadFilter{}).sqlConditions("name", "", nil, questionPlaceholder); conds != "" || len(args) != 0 {
+		t.Errorf("Expected no conditions for an empty filter, got %q %v", conds, args)
+	}
+}
+
+func TestLoadFilter_Match(t *testing.T) {
+	f := LoadFilter{IncludeTables: []string{"user*"}, ExcludeTables: []string{"*_bak"}, IncludeSchemas: []string{"prod_*"}}
+	for name, want := range map[string]bool{"users": true, "users_bak": false, "orders": false} {
+		if got := f.matchTable(name); got != want {
+			t.Errorf("matchTable(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if !f.matchSchema("prod_eu") || f.matchSchema("staging") {
+		t.Error("Unexpected schema matching")
+	}
+	if !(LoadFilter{}).matchTable("anything") {
+		t.Error("Expected an empty filter to match everything")
+	}
+}
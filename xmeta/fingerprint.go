@@ -0,0 +1,74 @@
+package xmeta
+
+// fingerprint.go computes a content hash of a MetaDatabase for cheap change
+// detection.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Fingerprint returns a hex SHA-256 of a canonical form of db. Databases
+// that differ only in the order of tables, columns, constraints, indexes,
+// views or sequences, or in nil versus empty option maps, have the same
+// fingerprint. db is not modified.
+func Fingerprint(db *MetaDatabase) (string, error) {
+	canon := &MetaDatabase{}
+	if db != nil {
+		canon = CloneMetaDatabase(db)
+	}
+	NormalizeMetaDatabase(canon, NormalizeOptions{})
+
+	canon.Options = canonicalOptions(canon.Options)
+	for _, t := range canon.Tables {
+		t.Options = canonicalOptions(t.Options)
+		sort.SliceStable(t.Elements, func(i, j int) bool {
+			return elementKey(t.Elements[i]) < elementKey(t.Elements[j])
+		})
+		for _, elem := range t.Elements {
+			if col := elem.GetColumnDefElement(); col != nil {
+				col.Options = canonicalOptions(col.Options)
+			}
+		}
+		for _, idx := range t.Indexes {
+			idx.Options = canonicalOptions(idx.Options)
+		}
+	}
+	sort.SliceStable(canon.Views, func(i, j int) bool {
+		return objectNameKey(canon.Views[i].GetName()) < objectNameKey(canon.Views[j].GetName())
+	})
+	for _, v := range canon.Views {
+		v.Options = canonicalOptions(v.Options)
+	}
+	sort.SliceStable(canon.Sequences, func(i, j int) bool {
+		return objectNameKey(canon.Sequences[i].GetName()) < objectNameKey(canon.Sequences[j].GetName())
+	})
+	for _, s := range canon.Sequences {
+		s.Options = canonicalOptions(s.Options)
+	}
+	sort.SliceStable(canon.Triggers, func(i, j int) bool {
+		ti, tj := canon.Triggers[i], canon.Triggers[j]
+		return objectNameKey(ti.TableName)+"."+ti.Name < objectNameKey(tj.TableName)+"."+tj.Name
+	})
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(canon)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize database: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalOptions drops empty values, which mapsEqual treats as unset.
+func canonicalOptions(options map[string]string) map[string]string {
+	for k, v := range options {
+		if v == "" {
+			delete(options, k)
+		}
+	}
+	return options
+}
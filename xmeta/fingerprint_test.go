@@ -0,0 +1,48 @@
+package xmeta
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	column := func(name string) *TableElement {
+		return &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{
+			Name:     name,
+			DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}},
+		}}}
+	}
+	table := func(name string, elems ...*TableElement) *MetaTable {
+		return &MetaTable{Name: &ObjectName{Idents: []string{name}}, Elements: elems}
+	}
+
+	a := &MetaDatabase{Tables: []*MetaTable{
+		table("users", column("id"), column("email")),
+		table("orders", column("id")),
+	}}
+	b := &MetaDatabase{
+		Tables: []*MetaTable{
+			table("orders", column("id")),
+			table("users", column("email"), column("id")),
+		},
+		Options: map[string]string{"Note": ""},
+	}
+	b.Tables[1].Options = map[string]string{}
+
+	fa, err := Fingerprint(a)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	fb, err := Fingerprint(b)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if fa != fb || len(fa) != 64 {
+		t.Errorf("Expected equal fingerprints, got %s and %s", fa, fb)
+	}
+	if b.Tables[0].Name.Idents[0] != "orders" || b.Options == nil {
+		t.Error("Fingerprint must not modify its input")
+	}
+
+	b.Tables[0].Elements = append(b.Tables[0].Elements, column("total"))
+	if fc, _ := Fingerprint(b); fc == fa {
+		t.Error("Expected a different fingerprint after adding a column")
+	}
+}
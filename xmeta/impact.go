@@ -0,0 +1,277 @@
+package xmeta
+
+// impact.go estimates the locking and rewrite cost of schema changes, for
+// planning migrations on live databases.
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ImpactReport is the estimated effect of running one change.
+type ImpactReport struct {
+	Change SchemaChange
+	// Lock is the strongest lock the statement takes, in the dialect's own
+	// terms: a Postgres table lock mode such as "ACCESS EXCLUSIVE", a MySQL
+	// online DDL level ("NONE", "SHARED" or "EXCLUSIVE"), "EXCLUSIVE" for
+	// the SQLite database write lock and "NONE" for BigQuery.
+	Lock string
+	// RewritesTable is set when every row is copied, which holds the lock
+	// for a time proportional to the table size.
+	RewritesTable bool
+	// Suggestion describes a safer way to reach the same schema, if any.
+	Suggestion string
+}
+
+// volatileDefault matches defaults that Postgres evaluates per row, so that
+// adding a column with them rewrites the table.
+var volatileDefault = regexp.MustCompile(`(?i)\b(random|gen_random_uuid|uuid_generate_v[14]|clock_timestamp|timeofday|nextval)\s*\(`)
+
+// AnalyzeImpact estimates the lock level and table rewrites of each change
+// when run on dialect, with suggestions for the changes that block writes
+// on a busy table. The estimates follow the documented behavior of
+// Postgres 12+ and MySQL 8.0 with InnoDB; they do not account for table
+// size or concurrent load.
+func AnalyzeImpact(changes []SchemaChange, dialect Dialect) []ImpactReport {
+	reports := make([]ImpactReport, 0, len(changes))
+	for _, c := range changes {
+		var r ImpactReport
+		switch dialect {
+		case DialectPostgres:
+			r = pgImpact(c)
+		case DialectMySQL:
+			r = myImpact(c)
+		case DialectSQLite:
+			r = sqliteImpact(c)
+		default:
+			r = ImpactReport{Lock: "NONE"}
+		}
+		r.Change = c
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// =============================================================================
+// Postgres
+// =============================================================================
+
+func pgImpact(c SchemaChange) ImpactReport {
+	switch c := c.(type) {
+	case AlterDatabase:
+		if c.OldName != c.NewName {
+			return ImpactReport{Lock: "NONE",
+				Suggestion: "disconnect every session from the database first; Postgres refuses to rename a database in use"}
+		}
+		return ImpactReport{Lock: "NONE"}
+	case AddSchema, AddTable, AddView, DropView, AlterTags:
+		return ImpactReport{Lock: "NONE"}
+	case DropSchema, DropTable, RenameTable, DropColumn, DropConstraint, AlterConstraint:
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+	case AlterTableOptions:
+		if c.OldOptions["Tablespace"] != c.NewOptions["Tablespace"] {
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE", RewritesTable: true}
+		}
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+	case AddColumn:
+		col := c.Column
+		if col.GetOptions()["IsGenerated"] == "true" {
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE", RewritesTable: true,
+				Suggestion: "add a plain column, backfill it in batches and keep it current with a trigger"}
+		}
+		if def := anyToString(col.GetDefault()); volatileDefault.MatchString(def) {
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE", RewritesTable: true,
+				Suggestion: "add the column without default, then SET DEFAULT and backfill existing rows in batches"}
+		}
+		if isNotNull(col) && col.GetDefault() == nil {
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE",
+				Suggestion: "add the column as nullable, backfill it, then SET NOT NULL; it fails on a table with rows"}
+		}
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+	case AlterColumn:
+		deltas := c.Deltas()
+		// ALTER TYPE ... ADD VALUE locks only the type
+		if len(deltas) == 1 {
+			if _, ok := deltas[0].(EnumLabelsAdded); ok {
+				return ImpactReport{Lock: "NONE"}
+			}
+		}
+		r := ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+		for _, delta := range deltas {
+			switch d := delta.(type) {
+			case TypeChanged:
+				if pgTypeChangeRewrites(d.Old, d.New) {
+					r.RewritesTable = true
+					r.Suggestion = "add a column of the new type, backfill it in batches and swap the columns"
+				}
+			case NullabilityChanged:
+				if !d.NowNullable && r.Suggestion == "" {
+					r.Suggestion = "add CHECK (col IS NOT NULL) NOT VALID, VALIDATE it, then SET NOT NULL without a full scan"
+				}
+			case GenerationChanged:
+				r.RewritesTable = true
+			}
+		}
+		return r
+	case SetColumnDefault, DropColumnDefault:
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+	case SetColumnNullability:
+		if c.Nullable {
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+		}
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE",
+			Suggestion: "add CHECK (col IS NOT NULL) NOT VALID, VALIDATE it, then SET NOT NULL without a full scan"}
+	case AddConstraint:
+		spec := c.Constraint.GetSpec()
+		switch {
+		case spec.GetReferenceItem() != nil:
+			if c.NotValid {
+				return ImpactReport{Lock: "SHARE ROW EXCLUSIVE"}
+			}
+			return ImpactReport{Lock: "SHARE ROW EXCLUSIVE",
+				Suggestion: "add the foreign key NOT VALID, then VALIDATE CONSTRAINT, which allows writes"}
+		case spec.GetCheckItem() != nil:
+			if c.NotValid {
+				return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+			}
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE",
+				Suggestion: "add the check NOT VALID, then VALIDATE CONSTRAINT, which allows writes"}
+		case spec.GetUniqueItem() != nil:
+			return ImpactReport{Lock: "ACCESS EXCLUSIVE",
+				Suggestion: "CREATE UNIQUE INDEX CONCURRENTLY, then ADD CONSTRAINT ... USING INDEX"}
+		}
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+	case ValidateConstraint:
+		return ImpactReport{Lock: "SHARE UPDATE EXCLUSIVE"}
+	case AddIndex:
+		return ImpactReport{Lock: "SHARE", Suggestion: "CREATE INDEX CONCURRENTLY, outside a transaction"}
+	case DropIndex:
+		return ImpactReport{Lock: "ACCESS EXCLUSIVE", Suggestion: "DROP INDEX CONCURRENTLY, outside a transaction"}
+	case AddTrigger, DropTrigger:
+		return ImpactReport{Lock: "SHARE ROW EXCLUSIVE"}
+	}
+	return ImpactReport{Lock: "ACCESS EXCLUSIVE"}
+}
+
+// pgTypeChangeRewrites reports whether changing a column from old to new
+// rewrites the table. Widening a VARCHAR, VARCHAR to TEXT and raising the
+// precision of a NUMERIC at the same scale are binary compatible.
+func pgTypeChangeRewrites(old, new *DataType) bool {
+	_, toText := new.GetTypeClause().(*DataType_TextData)
+	switch {
+	case old.GetVarcharData() != nil && toText:
+		return false
+	case old.GetVarcharData() != nil && new.GetVarcharData() != nil:
+		size := new.GetVarcharData().GetSize()
+		return size != 0 && size < old.GetVarcharData().GetSize()
+	case old.GetDecimalData() != nil && new.GetDecimalData() != nil:
+		o, n := old.GetDecimalData(), new.GetDecimalData()
+		return n.Precision != 0 && (n.Scale != o.Scale || n.Precision < o.Precision)
+	}
+	return true
+}
+
+// =============================================================================
+// MySQL
+// =============================================================================
+
+func myImpact(c SchemaChange) ImpactReport {
+	switch c := c.(type) {
+	case AlterDatabase, AddSchema, AddTable, AddView, DropView, ValidateConstraint, AlterTags:
+		// A new default charset or collation applies to new tables only
+		return ImpactReport{Lock: "NONE"}
+	case DropSchema, DropTable, RenameTable, AddTrigger, DropTrigger:
+		return ImpactReport{Lock: "EXCLUSIVE"}
+	case AlterTableOptions:
+		for _, key := range []string{"Engine", "Charset", "Collation"} {
+			if c.OldOptions[key] != c.NewOptions[key] {
+				return ImpactReport{Lock: "SHARED", RewritesTable: true,
+					Suggestion: "rebuild the table with an online schema change tool such as gh-ost or pt-online-schema-change"}
+			}
+		}
+		return ImpactReport{Lock: "NONE"}
+	case AddColumn:
+		if c.Column.GetOptions()["GenerationKind"] == "STORED" {
+			return ImpactReport{Lock: "SHARED", RewritesTable: true}
+		}
+		// ALGORITHM=INSTANT, at any position since 8.0.29
+		return ImpactReport{Lock: "NONE"}
+	case DropColumn, AlterColumnPosition:
+		// INSTANT since 8.0.29, an in-place rebuild before
+		return ImpactReport{Lock: "NONE", RewritesTable: true}
+	case AlterColumn:
+		r := ImpactReport{Lock: "NONE"}
+		for _, delta := range c.Deltas() {
+			switch d := delta.(type) {
+			case TypeChanged:
+				if myTypeChangeCopies(d.Old, d.New) {
+					return ImpactReport{Lock: "SHARED", RewritesTable: true,
+						Suggestion: "change the type with an online schema change tool such as gh-ost or pt-online-schema-change"}
+				}
+			case EnumLabelsAdded:
+				// INSTANT only when appending, within the same storage size
+				appended := slices.Equal(d.Old, d.New[:len(d.Old)])
+				if !appended || (len(d.Old) <= 255 && len(d.New) > 255) {
+					return ImpactReport{Lock: "SHARED", RewritesTable: true}
+				}
+			case NullabilityChanged, GenerationChanged:
+				r.RewritesTable = true
+			}
+		}
+		return r
+	case SetColumnDefault, DropColumnDefault:
+		// Defaults are metadata only
+		return ImpactReport{Lock: "NONE"}
+	case SetColumnNullability:
+		return ImpactReport{Lock: "NONE", RewritesTable: true}
+	case AddConstraint:
+		spec := c.Constraint.GetSpec()
+		switch {
+		case spec.GetReferenceItem() != nil:
+			return ImpactReport{Lock: "SHARED", RewritesTable: true,
+				Suggestion: "SET foreign_key_checks = 0 to add the foreign key in place without copying the table"}
+		case spec.GetCheckItem() != nil:
+			return ImpactReport{Lock: "SHARED", RewritesTable: true}
+		}
+		return ImpactReport{Lock: "NONE"}
+	case DropConstraint, AlterConstraint, DropIndex:
+		return ImpactReport{Lock: "NONE"}
+	case AddIndex:
+		if method := strings.ToUpper(c.Index.GetMethod()); method == "FULLTEXT" || method == "SPATIAL" {
+			return ImpactReport{Lock: "SHARED"}
+		}
+		return ImpactReport{Lock: "NONE"}
+	}
+	return ImpactReport{Lock: "SHARED"}
+}
+
+// myTypeChangeCopies reports whether changing a column from old to new
+// needs ALGORITHM=COPY. Widening a VARCHAR is in place as long as its
+// length prefix stays one byte (up to 255) or was already two.
+func myTypeChangeCopies(old, new *DataType) bool {
+	o, n := old.GetVarcharData(), new.GetVarcharData()
+	if o == nil || n == nil || n.Size < o.Size {
+		return true
+	}
+	return o.Size <= 255 && n.Size > 255
+}
+
+// =============================================================================
+// SQLite
+// =============================================================================
+
+// sqliteImpact reports the database write lock that every SQLite DDL
+// statement takes. Changes that GenerateSQL can only make by recreating
+// the table copy it.
+func sqliteImpact(c SchemaChange) ImpactReport {
+	switch c.(type) {
+	case AlterColumn, AlterColumnPosition, SetColumnDefault, DropColumnDefault, SetColumnNullability,
+		AddConstraint, DropConstraint, AlterConstraint:
+		return ImpactReport{Lock: "EXCLUSIVE", RewritesTable: true}
+	case AlterTags:
+		// Tags have no DDL in SQLite
+		return ImpactReport{Lock: "NONE"}
+	}
+	return ImpactReport{Lock: "EXCLUSIVE"}
+}
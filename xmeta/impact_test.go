@@ -0,0 +1,56 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestAnalyzeImpact(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	varchar := func(size uint32) *DataType {
+		return &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: size}}}
+	}
+	fk := &TableConstraint{Name: "fk_users_org", Spec: &TableConstraintSpec{
+		TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{ReferenceItem: &ReferentialTableConstraint{
+			Columns: []string{"org_id"}, KeyExpr: &ReferenceKeyExpr{TableName: "orgs", Columns: []string{"id"}},
+		}},
+	}}
+	changes := []SchemaChange{
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "token", Default: stringToAny("gen_random_uuid()")}},
+		AddColumn{TableName: users, Column: &ColumnDef{Name: "created_at", Default: stringToAny("now()")}},
+		AlterColumn{TableName: users, OldColumn: &ColumnDef{Name: "name", DataType: varchar(50)}, NewColumn: &ColumnDef{Name: "name", DataType: varchar(100)}},
+		AlterColumn{TableName: users, OldColumn: &ColumnDef{Name: "name", DataType: varchar(100)}, NewColumn: &ColumnDef{Name: "name", DataType: varchar(300)}},
+		AddConstraint{TableName: users, Constraint: fk},
+		AddConstraint{TableName: users, Constraint: fk, NotValid: true},
+		AddIndex{TableName: users, Index: &MetaIndex{Name: "idx_users_name", Columns: []string{"name"}}},
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		locks    []string
+		rewrites []bool
+	}{
+		{DialectPostgres,
+			[]string{"ACCESS EXCLUSIVE", "ACCESS EXCLUSIVE", "ACCESS EXCLUSIVE", "ACCESS EXCLUSIVE", "SHARE ROW EXCLUSIVE", "SHARE ROW EXCLUSIVE", "SHARE"},
+			[]bool{true, false, false, false, false, false, false}},
+		{DialectMySQL,
+			[]string{"NONE", "NONE", "NONE", "SHARED", "SHARED", "SHARED", "NONE"},
+			[]bool{false, false, false, true, true, true, false}},
+	}
+	for _, tt := range tests {
+		reports := AnalyzeImpact(changes, tt.dialect)
+		if len(reports) != len(changes) {
+			t.Fatalf("%s: expected a report per change, got %d", tt.dialect, len(reports))
+		}
+		for i, r := range reports {
+			if r.Lock != tt.locks[i] || r.RewritesTable != tt.rewrites[i] {
+				t.Errorf("%s: %v: got lock %q rewrite %v", tt.dialect, r.Change, r.Lock, r.RewritesTable)
+			}
+		}
+	}
+
+	pg := AnalyzeImpact(changes, DialectPostgres)
+	if pg[0].Suggestion == "" || pg[4].Suggestion == "" || pg[5].Suggestion != "" {
+		t.Errorf("Expected suggestions for the volatile default and the validated foreign key only, got %q %q %q",
+			pg[0].Suggestion, pg[4].Suggestion, pg[5].Suggestion)
+	}
+}
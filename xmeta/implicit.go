@@ -0,0 +1,87 @@
+package xmeta
+
+// implicit.go recognizes constraints and indexes that a database creates on
+// its own, which hand-written schemas do not list.
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// notNullCheck matches a CHECK expression that only restates NOT NULL.
+var notNullCheck = regexp.MustCompile(`(?i)^\(*\s*("[^"]+"|` + "`[^`]+`" + `|[a-z_][a-z0-9_$]*)\s+is\s+not\s+null\s*\)*$`)
+
+// IsImplicit reports whether tc is a constraint a database creates
+// implicitly rather than one declared by the schema author:
+//
+//   - a CHECK that only says "col IS NOT NULL", as Postgres 18 catalogs
+//     NOT NULL columns, which ColumnDef nullability already covers;
+//   - a constraint named by SQLite for an inline key (sqlite_autoindex_*).
+func IsImplicit(tc *TableConstraint) bool {
+	if tc == nil {
+		return false
+	}
+	if strings.HasPrefix(tc.Name, "sqlite_autoindex_") {
+		return true
+	}
+	if check := tc.GetSpec().GetCheckItem(); check != nil {
+		return notNullCheck.MatchString(strings.TrimSpace(anyToString(check)))
+	}
+	return false
+}
+
+// IsImplicitIndex reports whether idx, an index of table t, is one a
+// database creates implicitly:
+//
+//   - an index SQLite creates for a UNIQUE or PRIMARY KEY (sqlite_autoindex_*);
+//   - an index named like a unique or primary key constraint of t, which
+//     backs that constraint;
+//   - a plain, non-unique index on exactly the columns of a foreign key of
+//     t, named after the foreign key or its first column, as MySQL creates
+//     for every foreign key that has no usable index.
+//
+// An index with an expression or predicate is never implicit.
+func IsImplicitIndex(t *MetaTable, idx *MetaIndex) bool {
+	if idx == nil {
+		return false
+	}
+	if strings.HasPrefix(idx.Name, "sqlite_autoindex_") {
+		return true
+	}
+	if idx.Expression != "" || idx.Predicate != "" {
+		return false
+	}
+	for _, elem := range t.GetElements() {
+		tc := elem.GetTableConstraintElement()
+		switch spec := tc.GetSpec(); {
+		case spec.GetUniqueItem() != nil:
+			if tc.Name != "" && strings.EqualFold(tc.Name, idx.Name) {
+				return true
+			}
+		case spec.GetReferenceItem() != nil:
+			cols := spec.GetReferenceItem().Columns
+			if idx.IsUnique || !slices.Equal(cols, idx.Columns) {
+				continue
+			}
+			if strings.EqualFold(idx.Name, tc.Name) || strings.EqualFold(idx.Name, cols[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withoutImplicit returns t without its implicit constraints and indexes.
+// t is not modified; a copy is returned when anything is removed.
+func withoutImplicit(t *MetaTable) *MetaTable {
+	isImplicitElem := func(elem *TableElement) bool { return IsImplicit(elem.GetTableConstraintElement()) }
+	isImplicitIndex := func(idx *MetaIndex) bool { return IsImplicitIndex(t, idx) }
+	if !slices.ContainsFunc(t.GetElements(), isImplicitElem) && !slices.ContainsFunc(t.GetIndexes(), isImplicitIndex) {
+		return t
+	}
+	clone := CloneMetaTable(t)
+	clone.Indexes = slices.DeleteFunc(clone.Indexes, func(idx *MetaIndex) bool { return IsImplicitIndex(clone, idx) })
+	clone.Elements = slices.DeleteFunc(clone.Elements, isImplicitElem)
+	return clone
+}
@@ -0,0 +1,70 @@
+package xmeta
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestIsImplicit(t *testing.T) {
+	check := func(name, expr string) *TableConstraint {
+		return &TableConstraint{Name: name, Spec: &TableConstraintSpec{
+			TableConstraintSpecClause: &TableConstraintSpec_CheckItem{CheckItem: stringToAny(expr)},
+		}}
+	}
+	tests := []struct {
+		tc   *TableConstraint
+		want bool
+	}{
+		{check("users_email_not_null", "email IS NOT NULL"), true},
+		{check("users_email_not_null", `("email" is not null)`), true},
+		{check("sqlite_autoindex_users_1", "id > 0"), true},
+		{check("users_age_check", "age IS NOT NULL AND age > 0"), false},
+		{check("users_age_check", "age > 0"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsImplicit(tt.tc); got != tt.want {
+			t.Errorf("IsImplicit(%v) = %v", tt.tc, got)
+		}
+	}
+}
+
+func TestDiffDatabase_IgnoreImplicit(t *testing.T) {
+	desired, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id),
+  CONSTRAINT uq_orders_id UNIQUE (id));`, DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The live database adds the foreign key's index, an index named after
+	// the unique key and a NOT NULL check
+	current := proto.Clone(desired).(*MetaDatabase)
+	orders := current.Tables[1]
+	orders.Indexes = []*MetaIndex{
+		{Name: "fk_orders_user", Columns: []string{"user_id"}},
+		{Name: "uq_orders_id", Columns: []string{"id"}, IsUnique: true},
+		{Name: "idx_orders_user_id_id", Columns: []string{"user_id", "id"}},
+	}
+	orders.Elements = append(orders.Elements, &TableElement{TableElementClause: &TableElement_TableConstraintElement{
+		TableConstraintElement: &TableConstraint{Name: "orders_user_id_not_null", Spec: &TableConstraintSpec{
+			TableConstraintSpecClause: &TableConstraintSpec_CheckItem{CheckItem: stringToAny("user_id IS NOT NULL")},
+		}},
+	}})
+
+	if changes := DiffDatabase(current, desired); len(changes) != 4 {
+		t.Errorf("Expected the implicit objects and the real index as changes, got %v", changes)
+	}
+
+	changes := DiffDatabaseWithOptions(current, desired, DiffOptions{IgnoreImplicit: true})
+	if len(changes) != 1 {
+		t.Fatalf("Expected only the real index to be dropped, got %v", changes)
+	}
+	if drop, ok := changes[0].(DropIndex); !ok || drop.IndexName != "idx_orders_user_id_id" {
+		t.Errorf("Unexpected change %v", changes[0])
+	}
+}
@@ -0,0 +1,150 @@
+package xmeta
+
+// lint.go checks a MetaDatabase against schema conventions.
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Severity grades a LintFinding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// LintFinding is a convention violation reported by a Rule.
+type LintFinding struct {
+	Rule     string
+	Severity Severity
+	// Path locates the offending object: "schema.table" or
+	// "schema.table.column".
+	Path    string
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Path, f.Message, f.Rule)
+}
+
+// Rule is a schema convention checked by Lint.
+type Rule interface {
+	Check(db *MetaDatabase) []LintFinding
+}
+
+// RuleFunc adapts a function to a Rule.
+type RuleFunc func(db *MetaDatabase) []LintFinding
+
+func (f RuleFunc) Check(db *MetaDatabase) []LintFinding { return f(db) }
+
+// DefaultRules are the built-in rules with their default severities.
+var DefaultRules = []Rule{RequirePrimaryKey{}, RequireNotNullOnPK{}, ForbidUnboundedVarchar{}}
+
+// Lint runs rules against db and returns their findings in rule order.
+func Lint(db *MetaDatabase, rules []Rule) []LintFinding {
+	var findings []LintFinding
+	for _, rule := range rules {
+		findings = append(findings, rule.Check(db)...)
+	}
+	return findings
+}
+
+// HasErrors reports whether any finding has SeverityError, e.g. to fail a
+// CI step.
+func HasErrors(findings []LintFinding) bool {
+	return slices.ContainsFunc(findings, func(f LintFinding) bool { return f.Severity == SeverityError })
+}
+
+// RequirePrimaryKey reports tables without a primary key. Partitions are
+// skipped, as their key is declared on the parent.
+type RequirePrimaryKey struct {
+	Severity Severity // SeverityError when empty
+}
+
+func (r RequirePrimaryKey) Check(db *MetaDatabase) []LintFinding {
+	var findings []LintFinding
+	for _, t := range db.GetTables() {
+		if t.Options["PartitionOf"] != "" || len(tablePrimaryKey(t)) > 0 {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			Rule:     "RequirePrimaryKey",
+			Severity: severityOr(r.Severity, SeverityError),
+			Path:     objectNameKey(t.Name),
+			Message:  "table has no primary key",
+		})
+	}
+	return findings
+}
+
+// RequireNotNullOnPK reports primary key columns not declared NOT NULL.
+// Most dialects imply it, but SQLite allows NULL in a primary key.
+type RequireNotNullOnPK struct {
+	Severity Severity // SeverityWarning when empty
+}
+
+func (r RequireNotNullOnPK) Check(db *MetaDatabase) []LintFinding {
+	var findings []LintFinding
+	for _, t := range db.GetTables() {
+		cols := columnsFromElements(t.Elements)
+		for _, name := range tablePrimaryKey(t) {
+			if col, ok := cols[name]; ok && !isNotNull(col) {
+				findings = append(findings, LintFinding{
+					Rule:     "RequireNotNullOnPK",
+					Severity: severityOr(r.Severity, SeverityWarning),
+					Path:     objectNameKey(t.Name) + "." + name,
+					Message:  "primary key column is not declared NOT NULL",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// ForbidUnboundedVarchar reports VARCHAR columns without a length,
+// including VARCHAR elements of arrays and structs.
+type ForbidUnboundedVarchar struct {
+	Severity Severity // SeverityWarning when empty
+}
+
+func (r ForbidUnboundedVarchar) Check(db *MetaDatabase) []LintFinding {
+	var findings []LintFinding
+	for _, t := range db.GetTables() {
+		for _, col := range orderedColumns(t.Elements) {
+			if unboundedVarchar(col.DataType) {
+				findings = append(findings, LintFinding{
+					Rule:     "ForbidUnboundedVarchar",
+					Severity: severityOr(r.Severity, SeverityWarning),
+					Path:     objectNameKey(t.Name) + "." + col.Name,
+					Message:  "VARCHAR has no length",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func unboundedVarchar(dt *DataType) bool {
+	if v := dt.GetVarcharData(); v != nil {
+		return v.Size == 0
+	}
+	if arr := dt.GetArrayData(); arr != nil {
+		return unboundedVarchar(arr.Type)
+	}
+	for _, field := range dt.GetStructData().GetFields() {
+		if unboundedVarchar(field.DataType) {
+			return true
+		}
+	}
+	return false
+}
+
+func severityOr(s, fallback Severity) Severity {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,44 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(100), bio VARCHAR);
+CREATE TABLE tags (id INTEGER NOT NULL, label VARCHAR(20), PRIMARY KEY (id));
+CREATE TABLE audit_log (message TEXT);`, DialectSQLite)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	findings := Lint(db, DefaultRules)
+	expected := []LintFinding{
+		{Rule: "RequirePrimaryKey", Severity: SeverityError, Path: "audit_log"},
+		{Rule: "RequireNotNullOnPK", Severity: SeverityWarning, Path: "users.id"},
+		{Rule: "ForbidUnboundedVarchar", Severity: SeverityWarning, Path: "users.bio"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, f := range findings {
+		if f.Rule != expected[i].Rule || f.Severity != expected[i].Severity || f.Path != expected[i].Path {
+			t.Errorf("Finding %d: expected %v, got %v", i, expected[i], f)
+		}
+	}
+	if !HasErrors(findings) {
+		t.Error("Expected HasErrors")
+	}
+
+	custom := RuleFunc(func(db *MetaDatabase) []LintFinding {
+		return []LintFinding{{Rule: "Custom", Severity: SeverityInfo, Path: db.Name}}
+	})
+	findings = Lint(db, []Rule{RequirePrimaryKey{Severity: SeverityWarning}, custom})
+	if len(findings) != 2 || findings[0].Severity != SeverityWarning || findings[1].Rule != "Custom" {
+		t.Errorf("Unexpected findings %v", findings)
+	}
+	if HasErrors(findings) {
+		t.Error("Expected no errors after lowering the severity")
+	}
+}
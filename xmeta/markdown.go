@@ -0,0 +1,95 @@
+package xmeta
+
+// markdown.go renders a list of schema changes as a GitHub-flavored
+// Markdown report, e.g. for a bot commenting on a pull request.
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// markdownGroups are the report sections, in order, with the prefixes of
+// the change type names they hold. Alters takes the rest.
+var markdownGroups = []struct {
+	title  string
+	prefix string
+}{
+	{"Adds", "Add"},
+	{"Drops", "Drop"},
+	{"Alters", ""},
+}
+
+// RenderMarkdownReport returns a Markdown report of changes: a summary line
+// with the number of changes per type, of affected tables and of
+// destructive changes, then one table per operation (Adds, Drops, Alters)
+// listing each change with its String and DescribeChange forms.
+// Destructive changes are flagged with ⚠️. Rows are sorted, so the report
+// does not depend on the order of changes.
+func RenderMarkdownReport(changes []SchemaChange) string {
+	var b strings.Builder
+	b.WriteString("## Schema changes\n\n")
+	if len(changes) == 0 {
+		b.WriteString("No schema changes.\n")
+		return b.String()
+	}
+
+	summary := Summarize(changes)
+	var counts []string
+	for _, name := range slices.Sorted(maps.Keys(summary.Counts)) {
+		counts = append(counts, fmt.Sprintf("%d %s", summary.Counts[name], name))
+	}
+	fmt.Fprintf(&b, "**%d %s** to %d %s: %s", len(changes), plural(len(changes), "change"),
+		len(summary.Tables), plural(len(summary.Tables), "table"), strings.Join(counts, ", "))
+	if summary.Destructive > 0 {
+		fmt.Fprintf(&b, " (⚠️ %d destructive)", summary.Destructive)
+	}
+	b.WriteString("\n")
+
+	groups := make([][]SchemaChange, len(markdownGroups))
+	for _, c := range changes {
+		name := reflect.TypeOf(c).Name()
+		for i, g := range markdownGroups {
+			if strings.HasPrefix(name, g.prefix) {
+				groups[i] = append(groups[i], c)
+				break
+			}
+		}
+	}
+	for i, g := range markdownGroups {
+		if len(groups[i]) == 0 {
+			continue
+		}
+		rows := make([]string, 0, len(groups[i]))
+		for _, c := range groups[i] {
+			flag := ""
+			if c.IsDestructive() {
+				flag = "⚠️"
+			}
+			rows = append(rows, fmt.Sprintf("| %s | `%s` | %s |", flag,
+				markdownCell(fmt.Sprint(c)), markdownCell(DescribeChange(c))))
+		}
+		slices.Sort(rows)
+		fmt.Fprintf(&b, "\n### %s\n\n|  | Change | Details |\n|---|---|---|\n", g.title)
+		b.WriteString(strings.Join(rows, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// markdownCell escapes text for a table cell: pipes would end the cell and
+// newlines the row.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// plural returns word, with an "s" unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
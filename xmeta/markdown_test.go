@@ -0,0 +1,66 @@
+package xmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownReport(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, legacy TEXT, name TEXT);
+CREATE TABLE audit (id INTEGER);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT);
+CREATE TABLE orders (id INTEGER PRIMARY KEY);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	changes := DiffDatabase(current, desired)
+
+	want := `## Schema changes
+
+**5 changes** to 3 tables: 1 AddColumn, 1 AddTable, 1 DropColumn, 1 DropTable, 1 SetColumnNullability (⚠️ 2 destructive)
+
+### Adds
+
+|  | Change | Details |
+|---|---|---|
+|  | ` + "`ADD COLUMN users.email`" + ` | + column users.email TEXT |
+|  | ` + "`ADD TABLE orders`" + ` | + table orders |
+
+### Drops
+
+|  | Change | Details |
+|---|---|---|
+| ⚠️ | ` + "`DROP COLUMN users.legacy (destructive)`" + ` | - column users.legacy |
+| ⚠️ | ` + "`DROP TABLE audit (destructive)`" + ` | - table audit |
+
+### Alters
+
+|  | Change | Details |
+|---|---|---|
+|  | ` + "`SET COLUMN NULLABILITY users.name`" + ` | ~ column users.name: not null |
+`
+	if got := RenderMarkdownReport(changes); got != want {
+		t.Errorf("Unexpected report:\n%s", got)
+	}
+
+	// The order of the changes does not matter
+	reversed := make([]SchemaChange, len(changes))
+	for i, c := range changes {
+		reversed[len(changes)-1-i] = c
+	}
+	if got := RenderMarkdownReport(reversed); got != want {
+		t.Errorf("Expected the same report for reversed changes, got:\n%s", got)
+	}
+
+	if got := RenderMarkdownReport(nil); !strings.Contains(got, "No schema changes.") {
+		t.Errorf("Unexpected empty report %q", got)
+	}
+	if got := markdownCell("a|b\nc"); got != `a\|b c` {
+		t.Errorf("Unexpected cell %q", got)
+	}
+}
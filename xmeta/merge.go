@@ -0,0 +1,211 @@
+package xmeta
+
+// merge.go overlays one MetaDatabase onto another, e.g. to layer
+// environment-specific changes over a base schema.
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MergeOptions controls MergeMetaDatabaseWithOptions.
+type MergeOptions struct {
+	// DeleteMarker, when non-empty, is a name prefix that turns an overlay
+	// entry into a removal: an overlay column named DeleteMarker+"phone"
+	// removes column "phone" from the base table. The same applies to named
+	// constraints, and to tables through the last identifier of their name.
+	DeleteMarker string
+}
+
+// MergeMetaDatabase overlays overlay onto base and returns the result; see
+// MergeMetaDatabaseWithOptions.
+func MergeMetaDatabase(base, overlay *MetaDatabase) *MetaDatabase {
+	return MergeMetaDatabaseWithOptions(base, overlay, MergeOptions{})
+}
+
+// MergeMetaDatabaseWithOptions overlays overlay onto base. Tables are matched
+// by qualified name: new tables are appended, matched tables are merged
+// element by element, with columns matched by name, constraints by
+// constraint name and indexes by index name. Overlay values win, and base
+// order is preserved. Neither input is modified.
+func MergeMetaDatabaseWithOptions(base, overlay *MetaDatabase, opts MergeOptions) *MetaDatabase {
+	if base == nil {
+		base = &MetaDatabase{}
+	}
+	result := CloneMetaDatabase(base)
+	if overlay == nil {
+		return result
+	}
+
+	if overlay.Name != "" {
+		result.Name = overlay.Name
+	}
+	result.Options = mergeOptions(result.Options, overlay.Options)
+
+	for _, table := range overlay.Tables {
+		key, deleted := overlayTableKey(table.Name, opts)
+		i := indexByName(result.Tables, key)
+		switch {
+		case deleted:
+			if i >= 0 {
+				result.Tables = append(result.Tables[:i], result.Tables[i+1:]...)
+			}
+		case i >= 0:
+			result.Tables[i] = mergeMetaTable(result.Tables[i], table, opts)
+		default:
+			result.Tables = append(result.Tables, CloneMetaTable(table))
+		}
+	}
+
+	// Views, sequences and triggers are replaced or added as a whole
+	for _, view := range overlay.Views {
+		if i := indexByName(result.Views, objectNameKey(view.Name)); i >= 0 {
+			result.Views[i] = proto.Clone(view).(*MetaView)
+		} else {
+			result.Views = append(result.Views, proto.Clone(view).(*MetaView))
+		}
+	}
+	for _, seq := range overlay.Sequences {
+		if i := indexByName(result.Sequences, objectNameKey(seq.Name)); i >= 0 {
+			result.Sequences[i] = proto.Clone(seq).(*MetaSequence)
+		} else {
+			result.Sequences = append(result.Sequences, proto.Clone(seq).(*MetaSequence))
+		}
+	}
+	for _, trigger := range overlay.Triggers {
+		i := slices.IndexFunc(result.Triggers, func(t *MetaTrigger) bool {
+			return t.Name == trigger.Name && objectNameKey(t.TableName) == objectNameKey(trigger.TableName)
+		})
+		if i >= 0 {
+			result.Triggers[i] = proto.Clone(trigger).(*MetaTrigger)
+		} else {
+			result.Triggers = append(result.Triggers, proto.Clone(trigger).(*MetaTrigger))
+		}
+	}
+
+	return result
+}
+
+// mergeMetaTable merges overlay into base, which must already be a copy.
+func mergeMetaTable(base, overlay *MetaTable, opts MergeOptions) *MetaTable {
+	if overlay.Type != "" {
+		base.Type = overlay.Type
+	}
+	if overlay.Comment != "" {
+		base.Comment = overlay.Comment
+	}
+	base.Options = mergeOptions(base.Options, overlay.Options)
+
+	for _, elem := range overlay.Elements {
+		key, deleted := overlayElementKey(elem, opts)
+		i := -1
+		if key != "" {
+			for j, e := range base.Elements {
+				if elementKey(e) == key {
+					i = j
+					break
+				}
+			}
+		}
+		switch {
+		case deleted:
+			if i >= 0 {
+				base.Elements = append(base.Elements[:i], base.Elements[i+1:]...)
+			}
+		case i >= 0:
+			base.Elements[i] = proto.Clone(elem).(*TableElement)
+		default:
+			base.Elements = append(base.Elements, proto.Clone(elem).(*TableElement))
+		}
+	}
+
+	for _, idx := range overlay.Indexes {
+		name, deleted := mergeKey(idx.Name, opts)
+		i := slices.IndexFunc(base.Indexes, func(b *MetaIndex) bool { return b.Name == name })
+		switch {
+		case deleted:
+			if i >= 0 {
+				base.Indexes = slices.Delete(base.Indexes, i, i+1)
+			}
+		case i >= 0:
+			base.Indexes[i] = proto.Clone(idx).(*MetaIndex)
+		default:
+			base.Indexes = append(base.Indexes, proto.Clone(idx).(*MetaIndex))
+		}
+	}
+	return base
+}
+
+// mergeKey strips the delete marker from a name, reporting whether it was present.
+func mergeKey(name string, opts MergeOptions) (string, bool) {
+	if opts.DeleteMarker == "" || !strings.HasPrefix(name, opts.DeleteMarker) {
+		return name, false
+	}
+	return strings.TrimPrefix(name, opts.DeleteMarker), true
+}
+
+// overlayTableKey returns the qualified key of an overlay table, with the
+// delete marker stripped from its last identifier.
+func overlayTableKey(name *ObjectName, opts MergeOptions) (string, bool) {
+	idents := name.GetIdents()
+	if len(idents) == 0 {
+		return "", false
+	}
+	n := len(idents) - 1
+	last, deleted := mergeKey(idents[n], opts)
+	if n == 0 {
+		return last, deleted
+	}
+	return strings.Join(idents[:n], ".") + "." + last, deleted
+}
+
+// elementKey identifies a table element for merging. Unnamed constraints
+// have no key and are always appended.
+func elementKey(elem *TableElement) string {
+	if col := elem.GetColumnDefElement(); col != nil {
+		return "column:" + col.Name
+	}
+	if tc := elem.GetTableConstraintElement(); tc != nil && tc.Name != "" {
+		return "constraint:" + tc.Name
+	}
+	return ""
+}
+
+// overlayElementKey is elementKey for an overlay element, with the delete
+// marker stripped from its name.
+func overlayElementKey(elem *TableElement, opts MergeOptions) (string, bool) {
+	if col := elem.GetColumnDefElement(); col != nil {
+		name, deleted := mergeKey(col.Name, opts)
+		return "column:" + name, deleted
+	}
+	if tc := elem.GetTableConstraintElement(); tc != nil && tc.Name != "" {
+		name, deleted := mergeKey(tc.Name, opts)
+		return "constraint:" + name, deleted
+	}
+	return "", false
+}
+
+func indexByName[T interface{ GetName() *ObjectName }](items []T, key string) int {
+	for i, item := range items {
+		if objectNameKey(item.GetName()) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeOptions returns base with the overlay entries set; overlay wins.
+func mergeOptions(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = make(map[string]string, len(overlay))
+	}
+	for k, v := range overlay {
+		base[k] = v
+	}
+	return base
+}
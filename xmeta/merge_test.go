@@ -0,0 +1,68 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestMergeMetaDatabase(t *testing.T) {
+	intType := &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}
+	textType := &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}
+	column := func(name string, dt *DataType, comment string) *TableElement {
+		return &TableElement{TableElementClause: &TableElement_ColumnDefElement{
+			ColumnDefElement: &ColumnDef{Name: name, DataType: dt, Comment: comment},
+		}}
+	}
+
+	base := &MetaDatabase{
+		Name:    "app",
+		Options: map[string]string{"Charset": "utf8"},
+		Tables: []*MetaTable{
+			{
+				Name:     &ObjectName{Idents: []string{"public", "users"}},
+				Elements: []*TableElement{column("id", intType, ""), column("name", textType, ""), column("debug", textType, "")},
+			},
+			{Name: &ObjectName{Idents: []string{"public", "scratch"}}},
+		},
+	}
+	overlay := &MetaDatabase{
+		Options: map[string]string{"Collation": "utf8_bin"},
+		Tables: []*MetaTable{
+			{
+				Name:     &ObjectName{Idents: []string{"public", "users"}},
+				Comment:  "prod users",
+				Elements: []*TableElement{column("name", textType, "display name"), column("~debug", nil, ""), column("email", textType, "")},
+			},
+			{Name: &ObjectName{Idents: []string{"public", "~scratch"}}},
+			{Name: &ObjectName{Idents: []string{"public", "audit"}}},
+		},
+	}
+
+	merged := MergeMetaDatabaseWithOptions(base, overlay, MergeOptions{DeleteMarker: "~"})
+
+	if merged.Name != "app" || merged.Options["Charset"] != "utf8" || merged.Options["Collation"] != "utf8_bin" {
+		t.Errorf("Unexpected database attributes %q %v", merged.Name, merged.Options)
+	}
+	if len(merged.Tables) != 2 || objectNameKey(merged.Tables[1].Name) != "public.audit" {
+		t.Fatalf("Expected users and audit, got %v", merged.Tables)
+	}
+
+	users := merged.Tables[0]
+	if users.Comment != "prod users" {
+		t.Errorf("Expected overlay comment, got %q", users.Comment)
+	}
+	var names []string
+	for _, elem := range users.Elements {
+		names = append(names, elem.GetColumnDefElement().Name)
+	}
+	if len(names) != 3 || names[0] != "id" || names[1] != "name" || names[2] != "email" {
+		t.Errorf("Unexpected columns %v", names)
+	}
+	if users.Elements[1].GetColumnDefElement().Comment != "display name" {
+		t.Error("Expected overlay column to replace base column")
+	}
+
+	// Inputs are left untouched
+	if len(base.Tables) != 2 || len(base.Tables[0].Elements) != 3 || base.Options["Collation"] != "" {
+		t.Error("Base database was modified")
+	}
+}
@@ -0,0 +1,173 @@
+package xmeta
+
+// mermaid.go renders a MetaDatabase as a Mermaid entity-relationship diagram.
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	mermaidNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+	mermaidTypeUnsafe = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+)
+
+// mermaidRelation is a foreign key drawn as a relationship line.
+type mermaidRelation struct {
+	name     string
+	child    *MetaTable
+	parent   string
+	columns  []string
+	nullable bool
+}
+
+// MetaDatabaseToMermaidER returns a Mermaid erDiagram of db. Each table
+// lists its columns with their types, PK, FK and UK markers, and a
+// "nullable" comment on nullable columns. Every foreign key becomes a
+// relationship line labelled with the constraint name: one-to-one when the
+// key columns are unique in the referencing table, one-to-many otherwise,
+// and optional on the referenced side when a key column is nullable.
+func MetaDatabaseToMermaidER(db *MetaDatabase) string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	tables := make(map[string]*MetaTable)
+	for _, t := range db.GetTables() {
+		tables[objectNameKey(t.Name)] = t
+		tables[simpleNameKey(t.Name)] = t
+	}
+
+	var relations []mermaidRelation
+	for _, t := range db.GetTables() {
+		cols := orderedColumns(t.Elements)
+		pk := tablePrimaryKey(t)
+		fks := make(map[string]bool)
+		uks := make(map[string]bool)
+		for _, elem := range t.Elements {
+			tc := elem.GetTableConstraintElement()
+			if u := tc.GetSpec().GetUniqueItem(); u != nil && !u.IsPrimary && len(u.Columns) == 1 {
+				uks[u.Columns[0]] = true
+			}
+			if ref := tc.GetSpec().GetReferenceItem(); ref != nil {
+				relations = append(relations, mermaidRelation{
+					name:    tc.Name,
+					child:   t,
+					parent:  ref.GetKeyExpr().GetTableName(),
+					columns: ref.Columns,
+				})
+			}
+		}
+		for _, col := range cols {
+			for _, con := range col.Constraints {
+				if ref := con.GetSpec().GetReferenceItem(); ref != nil {
+					relations = append(relations, mermaidRelation{
+						name:    con.Name,
+						child:   t,
+						parent:  formatObjectName(ref.TableName),
+						columns: []string{col.Name},
+					})
+				}
+				if u := con.GetSpec().GetUniqueItem(); u != nil && !u.IsPrimaryKey {
+					uks[col.Name] = true
+				}
+			}
+		}
+		for _, r := range relations {
+			if r.child == t {
+				for _, c := range r.columns {
+					fks[c] = true
+				}
+			}
+		}
+
+		fmt.Fprintf(&b, "    %s {\n", mermaidName(t.Name))
+		for _, col := range cols {
+			var keys []string
+			if slices.Contains(pk, col.Name) {
+				keys = append(keys, "PK")
+			}
+			if fks[col.Name] {
+				keys = append(keys, "FK")
+			}
+			if uks[col.Name] {
+				keys = append(keys, "UK")
+			}
+			fmt.Fprintf(&b, "        %s %s", mermaidType(col.DataType), mermaidNameUnsafe.ReplaceAllString(col.Name, "_"))
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ", "))
+			}
+			if !isNotNull(col) && !slices.Contains(pk, col.Name) {
+				b.WriteString(` "nullable"`)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, r := range relations {
+		parent := mermaidNameUnsafe.ReplaceAllString(r.parent, "_")
+		if t, ok := tables[r.parent]; ok {
+			parent = mermaidName(t.Name)
+		}
+		cols := columnsFromElements(r.child.Elements)
+		parentSide := "||"
+		for _, c := range r.columns {
+			if col, ok := cols[c]; ok && !isNotNull(col) {
+				parentSide = "|o"
+			}
+		}
+		childSide := "o{"
+		if isUniqueKey(r.child, r.columns) {
+			childSide = "o|"
+		}
+		fmt.Fprintf(&b, "    %s %s--%s %s : %q\n", parent, parentSide, childSide, mermaidName(r.child.Name), r.name)
+	}
+	return b.String()
+}
+
+// tablePrimaryKey returns the primary key columns of t, declared at table
+// level or inline.
+func tablePrimaryKey(t *MetaTable) []string {
+	for _, elem := range t.Elements {
+		if u := elem.GetTableConstraintElement().GetSpec().GetUniqueItem(); u.GetIsPrimary() {
+			return u.Columns
+		}
+	}
+	return primaryKeyColumns(t.Elements)
+}
+
+// isUniqueKey reports whether columns are exactly the primary key or a
+// unique constraint of t.
+func isUniqueKey(t *MetaTable, columns []string) bool {
+	if slices.Equal(tablePrimaryKey(t), columns) {
+		return true
+	}
+	for _, elem := range t.Elements {
+		if u := elem.GetTableConstraintElement().GetSpec().GetUniqueItem(); u != nil && slices.Equal(u.Columns, columns) {
+			return true
+		}
+		if col := elem.GetColumnDefElement(); col != nil && len(columns) == 1 && col.Name == columns[0] {
+			for _, con := range col.Constraints {
+				if con.GetSpec().GetUniqueItem() != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// mermaidName turns a table name into a Mermaid entity name.
+func mermaidName(name *ObjectName) string {
+	return mermaidNameUnsafe.ReplaceAllString(objectNameKey(name), "_")
+}
+
+// mermaidType spells a data type as a single Mermaid attribute type word.
+func mermaidType(dt *DataType) string {
+	if dt == nil {
+		return "unknown"
+	}
+	return mermaidTypeUnsafe.ReplaceAllString(FormatDataType(dt), "_")
+}
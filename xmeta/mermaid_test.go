@@ -0,0 +1,45 @@
+package xmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMetaDatabaseToMermaidER(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY,
+  email VARCHAR(255) NOT NULL UNIQUE,
+  nickname TEXT
+);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER NOT NULL,
+  total NUMERIC(10,2),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE TABLE profiles (
+  user_id INTEGER,
+  CONSTRAINT profiles_user_key UNIQUE (user_id),
+  CONSTRAINT fk_profile_user FOREIGN KEY (user_id) REFERENCES users (id)
+);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	er := MetaDatabaseToMermaidER(db)
+	for _, want := range []string{
+		"erDiagram\n",
+		"    users {\n        INT id PK\n",
+		"        VARCHAR(255) email UK\n",
+		`        TEXT nickname "nullable"`,
+		"        INT user_id FK\n",
+		`        NUMERIC(10_2) total "nullable"`,
+		`    users ||--o{ orders : "fk_user"`,
+		`    users |o--o| profiles : "fk_profile_user"`,
+	} {
+		if !strings.Contains(er, want) {
+			t.Errorf("Expected %q in diagram:\n%s", want, er)
+		}
+	}
+}
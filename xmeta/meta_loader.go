@@ -0,0 +1,153 @@
+package xmeta
+
+// meta_loader.go loads a live database straight into the unified model.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// LoadMetaDatabase loads the database behind db with the loader for dialect
+// and converts the result to a MetaDatabase. dbName is the MySQL database
+// to load; Postgres loads the connected database and SQLite the main one,
+// ignoring it; an empty dbName is the current MySQL database. Use
+// LoadMetaDatabaseBigQuery for BigQuery. Identifiers are folded with the
+// dialect's DefaultCaseFolding, except that MySQL names are lower-cased
+// when the server's lower_case_table_names is 1 or 2.
+func LoadMetaDatabase(ctx context.Context, db *sql.DB, dialect Dialect, dbName string) (*MetaDatabase, error) {
+	return LoadMetaDatabaseWithOptions(ctx, db, dialect, dbName, MetaLoadOptions{})
+}
+
+// MetaLoadOptions controls LoadMetaDatabaseWithOptions.
+type MetaLoadOptions struct {
+	// Filter restricts the tables loaded, as in LoadPostgresWithFilter.
+	Filter LoadFilter
+	// CaseFolding folds table, column, constraint and index identifiers
+	// as they are read. The zero value is the dialect's default, see
+	// LoadMetaDatabase. Both sides of a diff must be folded alike, or
+	// names that differ only in case show up as changes.
+	CaseFolding CaseFolding
+	// TypeMapper, when set, maps column types before the loader's
+	// built-in mapping, which still applies where it returns nil. See
+	// TypeMap for overrides by type name.
+	TypeMapper TypeMapper
+}
+
+// LoadMetaDatabaseWithOptions is LoadMetaDatabase with explicit options.
+func LoadMetaDatabaseWithOptions(ctx context.Context, db *sql.DB, dialect Dialect, dbName string, opts MetaLoadOptions) (*MetaDatabase, error) {
+	var meta *MetaDatabase
+	switch dialect {
+	case DialectPostgres:
+		pg, err := loadPostgres(ctx, db, opts.Filter, opts.TypeMapper)
+		if err != nil {
+			return nil, err
+		}
+		meta = PGDatabaseToMetaDatabase(pg)
+	case DialectMySQL:
+		my, err := loadMySQL(ctx, db, dbName, opts.Filter, opts.TypeMapper)
+		if err != nil {
+			return nil, err
+		}
+		meta = MYDatabaseToMetaDatabase(my)
+	case DialectSQLite:
+		lite, err := loadSQLite(ctx, db, opts.Filter, opts.TypeMapper)
+		if err != nil {
+			return nil, err
+		}
+		meta = SQLiteDatabaseToMetaDatabase(lite)
+	case DialectBigQuery:
+		return nil, fmt.Errorf("%s is loaded with LoadMetaDatabaseBigQuery", dialect)
+	default:
+		return nil, fmt.Errorf("no loader for dialect %s", dialect)
+	}
+	folding, err := liveCaseFolding(ctx, db, dialect, opts.CaseFolding)
+	if err != nil {
+		return nil, err
+	}
+	FoldIdentifiers(meta, folding)
+	return meta, nil
+}
+
+// liveCaseFolding resolves CaseFoldingDefault to the dialect's folding,
+// reading lower_case_table_names from a MySQL server.
+func liveCaseFolding(ctx context.Context, db *sql.DB, dialect Dialect, folding CaseFolding) (CaseFolding, error) {
+	switch {
+	case folding != CaseFoldingDefault:
+		return folding, nil
+	case dialect == DialectMySQL:
+		return myCaseFolding(ctx, db)
+	}
+	return DefaultCaseFolding(dialect), nil
+}
+
+// LoadTable loads a single table of the live database behind db, with its
+// columns, constraints, indexes and foreign keys. qualifiedName is
+// "schema.table" for Postgres, where a bare name is looked up in public;
+// "table" or "database.table" for MySQL, where a bare name is looked up in
+// the current database; and the table name for SQLite. Only the catalog
+// rows of that table are queried. Identifiers are folded as by
+// LoadMetaDatabase.
+func LoadTable(ctx context.Context, db *sql.DB, dialect Dialect, qualifiedName string) (*MetaTable, error) {
+	idents := strings.Split(qualifiedName, ".")
+	table := idents[len(idents)-1]
+	if table == "" || len(idents) > 2 {
+		return nil, fmt.Errorf("invalid table name %q", qualifiedName)
+	}
+	filter := LoadFilter{IncludeTables: []string{table}}
+
+	var meta *MetaDatabase
+	switch dialect {
+	case DialectPostgres:
+		if len(idents) == 1 {
+			idents = []string{"public", table}
+		}
+		filter.IncludeSchemas = idents[:1]
+		pg, err := LoadPostgresWithFilter(ctx, db, filter)
+		if err != nil {
+			return nil, err
+		}
+		meta = PGDatabaseToMetaDatabase(pg)
+	case DialectMySQL:
+		if len(idents) == 1 {
+			current, err := myCurrentDatabase(ctx, db)
+			if err != nil {
+				return nil, fmt.Errorf("table %s: %w", table, err)
+			}
+			idents = []string{current, table}
+		}
+		my, err := LoadMySQLWithFilter(ctx, db, idents[0], filter)
+		if err != nil {
+			return nil, err
+		}
+		meta = MYDatabaseToMetaDatabase(my)
+	case DialectSQLite:
+		if len(idents) > 1 {
+			return nil, fmt.Errorf("attached databases are not supported: %s", qualifiedName)
+		}
+		lite, err := LoadSQLiteWithFilter(ctx, db, filter)
+		if err != nil {
+			return nil, err
+		}
+		meta = SQLiteDatabaseToMetaDatabase(lite)
+	case DialectBigQuery:
+		return nil, fmt.Errorf("%s is loaded with LoadMetaDatabaseBigQuery", dialect)
+	default:
+		return nil, fmt.Errorf("no loader for dialect %s", dialect)
+	}
+
+	// Glob characters in the name may have matched other tables too
+	folding, err := liveCaseFolding(ctx, db, dialect, CaseFoldingDefault)
+	if err != nil {
+		return nil, err
+	}
+	FoldIdentifiers(meta, folding)
+	key := folding.fold(strings.Join(idents, "."))
+	for _, t := range meta.GetTables() {
+		if objectNameKey(t.Name) == key {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("table %s not found", key)
+}
@@ -0,0 +1,31 @@
+package xmeta
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLoadMetaDatabase_Dialects(t *testing.T) {
+	for _, dialect := range []Dialect{DialectBigQuery, DialectUnknown} {
+		if _, err := LoadMetaDatabase(context.Background(), nil, dialect, "app"); err == nil {
+			t.Errorf("Expected an error for %s", dialect)
+		}
+	}
+}
+
+func TestLoadTable_Arguments(t *testing.T) {
+	ctx := context.Background()
+	for _, name := range []string{"", "a.b.c", "app."} {
+		if _, err := LoadTable(ctx, nil, DialectPostgres, name); err == nil {
+			t.Errorf("Expected an error for table name %q", name)
+		}
+	}
+	if _, err := LoadTable(ctx, nil, DialectSQLite, "aux.users"); err == nil {
+		t.Error("Expected an error for a qualified SQLite table")
+	}
+	for _, dialect := range []Dialect{DialectBigQuery, DialectUnknown} {
+		if _, err := LoadTable(ctx, nil, dialect, "users"); err == nil {
+			t.Errorf("Expected an error for %s", dialect)
+		}
+	}
+}
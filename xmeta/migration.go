@@ -0,0 +1,175 @@
+package xmeta
+
+// migration.go writes schema changes as up/down migration scripts in the
+// layout used by golang-migrate.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+	migrationNameUnsafe  = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+)
+
+// WriteMigration writes the changes as a pair of scripts, NNNN_name.up.sql
+// and NNNN_name.down.sql, into dir. NNNN is one more than the highest
+// version already in dir. The up script applies the changes in priority
+// order; the down script undoes them in reverse. Changes that cannot be
+// undone from the information they carry, such as a dropped table whose
+// definition is gone, appear in the down script as a "-- NOT REVERSIBLE"
+// comment block.
+func WriteMigration(changes []SchemaChange, dialect Dialect, dir string, name string) error {
+	up, down, err := migrationScripts(changes, dialect)
+	if err != nil {
+		return err
+	}
+
+	version, err := nextMigrationVersion(dir)
+	if err != nil {
+		return err
+	}
+	base := fmt.Sprintf("%04d_%s", version, strings.Trim(migrationNameUnsafe.ReplaceAllString(name, "_"), "_"))
+
+	if err := os.WriteFile(filepath.Join(dir, base+".up.sql"), []byte(up), 0o644); err != nil {
+		return fmt.Errorf("failed to write up migration: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, base+".down.sql"), []byte(down), 0o644); err != nil {
+		return fmt.Errorf("failed to write down migration: %w", err)
+	}
+	return nil
+}
+
+// migrationScripts renders the up and down scripts for the changes.
+func migrationScripts(changes []SchemaChange, dialect Dialect) (string, string, error) {
+	ordered := slices.Clone(changes)
+	SortChanges(ordered)
+
+	var up, down strings.Builder
+	for _, change := range ordered {
+		stmts, err := GenerateSQL(change, dialect)
+		if err != nil {
+			return "", "", fmt.Errorf("generating SQL for %T: %w", change, err)
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintf(&up, "%s;\n", stmt)
+		}
+	}
+
+	for i := len(ordered) - 1; i >= 0; i-- {
+		change := ordered[i]
+		if _, ok := change.(ValidateConstraint); ok {
+			// Dropping the constraint undoes its validation too.
+			continue
+		}
+		inverse, ok := InvertChange(change)
+		if !ok {
+			writeNotReversible(&down, change, "the change does not record what it removes")
+			continue
+		}
+		stmts, err := GenerateSQL(inverse, dialect)
+		if err != nil {
+			writeNotReversible(&down, change, err.Error())
+			continue
+		}
+		for _, stmt := range stmts {
+			fmt.Fprintf(&down, "%s;\n", stmt)
+		}
+	}
+	return up.String(), down.String(), nil
+}
+
+func writeNotReversible(b *strings.Builder, change SchemaChange, reason string) {
+	target := formatObjectName(changeTableName(change))
+	if s, ok := change.(DropSchema); ok {
+		target = formatObjectName(s.SchemaName)
+	}
+	fmt.Fprintf(b, "-- NOT REVERSIBLE: %T %s\n-- %s\n", change, target, reason)
+}
+
+// InvertChange returns the change that undoes c, or false when c does not
+// carry enough information to be undone (e.g. DropTable only knows the
+// table's name).
+func InvertChange(c SchemaChange) (SchemaChange, bool) {
+	switch c := c.(type) {
+	case AlterDatabase:
+		return AlterDatabase{OldName: c.NewName, NewName: c.OldName, OldOptions: c.NewOptions, NewOptions: c.OldOptions}, true
+	case AddSchema:
+		return DropSchema{SchemaName: c.SchemaName}, true
+	case AddTable:
+		return DropTable{TableName: c.Table.GetName()}, true
+	case RenameTable:
+		return RenameTable{OldName: c.NewName, NewName: c.OldName}, true
+	case AlterTableOptions:
+		return AlterTableOptions{
+			TableName:      c.TableName,
+			OldOptions:     c.NewOptions,
+			NewOptions:     c.OldOptions,
+			OldComment:     c.NewComment,
+			NewComment:     c.OldComment,
+			IsView:         c.IsView,
+			ViewDefinition: c.ViewDefinition,
+		}, true
+	case AlterTags:
+		return AlterTags{TableName: c.TableName, ColumnName: c.ColumnName, OldTags: c.NewTags, NewTags: c.OldTags}, true
+	case AddColumn:
+		return DropColumn{TableName: c.TableName, ColumnName: c.Column.GetName()}, true
+	case AlterColumn:
+		return AlterColumn{TableName: c.TableName, OldColumn: c.NewColumn, NewColumn: c.OldColumn}, true
+	case SetColumnDefault:
+		col := columnWithDefault(c.Column, c.OldDefault)
+		if c.OldDefault == "" {
+			return DropColumnDefault{TableName: c.TableName, Column: col, OldDefault: c.Default}, true
+		}
+		return SetColumnDefault{TableName: c.TableName, Column: col, Default: c.OldDefault, OldDefault: c.Default}, true
+	case DropColumnDefault:
+		if c.OldDefault == "" {
+			return nil, false
+		}
+		col := columnWithDefault(c.Column, c.OldDefault)
+		return SetColumnDefault{TableName: c.TableName, Column: col, Default: c.OldDefault}, true
+	case SetColumnNullability:
+		return SetColumnNullability{TableName: c.TableName, Column: columnWithNullability(c.Column, !c.Nullable), Nullable: !c.Nullable}, true
+	case AddConstraint:
+		return DropConstraint{
+			TableName:      c.TableName,
+			ConstraintName: c.Constraint.GetName(),
+			IsForeignKey:   c.Constraint.GetSpec().GetReferenceItem() != nil,
+		}, true
+	case AlterConstraint:
+		return AlterConstraint{TableName: c.TableName, OldConstraint: c.NewConstraint, NewConstraint: c.OldConstraint}, true
+	case AddIndex:
+		return DropIndex{TableName: c.TableName, IndexName: c.Index.GetName()}, true
+	case AddView:
+		return DropView{ViewName: c.View.GetName(), Materialized: c.View.GetOptions()["Materialized"] == "true"}, true
+	case AddTrigger:
+		return DropTrigger{TableName: c.Trigger.GetTableName(), TriggerName: c.Trigger.GetName()}, true
+	}
+	return nil, false
+}
+
+// nextMigrationVersion returns one more than the highest migration version
+// in dir, or 1 if there is none.
+func nextMigrationVersion(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migration directory: %w", err)
+	}
+	highest := 0
+	for _, entry := range entries {
+		m := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		if v, err := strconv.Atoi(m[1]); err == nil && v > highest {
+			highest = v
+		}
+	}
+	return highest + 1, nil
+}
@@ -0,0 +1,55 @@
+package xmeta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteMigration(t *testing.T) {
+	users := &ObjectName{Idents: []string{"users"}}
+	changes := []SchemaChange{
+		DropTable{TableName: &ObjectName{Idents: []string{"legacy"}}},
+		AddColumn{
+			TableName: users,
+			Column: &ColumnDef{
+				Name:     "phone",
+				DataType: &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: 20}}},
+			},
+		},
+		AddIndex{TableName: users, Index: &MetaIndex{Name: "users_phone_idx", Columns: []string{"phone"}}},
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0007_init.up.sql"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteMigration(changes, DialectPostgres, dir, "add phone"); err != nil {
+		t.Fatalf("WriteMigration failed: %v", err)
+	}
+
+	up, err := os.ReadFile(filepath.Join(dir, "0008_add_phone.up.sql"))
+	if err != nil {
+		t.Fatalf("Missing up script: %v", err)
+	}
+	wantUp := `DROP TABLE "legacy";
+ALTER TABLE "users" ADD COLUMN "phone" VARCHAR(20);
+CREATE INDEX "users_phone_idx" ON "users" ("phone");
+`
+	if string(up) != wantUp {
+		t.Errorf("Unexpected up script:\n%s", up)
+	}
+
+	down, err := os.ReadFile(filepath.Join(dir, "0008_add_phone.down.sql"))
+	if err != nil {
+		t.Fatalf("Missing down script: %v", err)
+	}
+	wantDown := `DROP INDEX "users_phone_idx";
+ALTER TABLE "users" DROP COLUMN "phone";
+-- NOT REVERSIBLE: xmeta.DropTable legacy
+-- the change does not record what it removes
+`
+	if string(down) != wantDown {
+		t.Errorf("Unexpected down script:\n%s", down)
+	}
+}
@@ -1,40 +1,150 @@
 package xmeta
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
-// LoadMySQL loads metadata into a MYDatabase structure.
+// LoadMySQL loads metadata into a MYDatabase structure. An empty dbName
+// loads the connection's current database.
 func LoadMySQL(db *sql.DB, dbName string) (*MYDatabase, error) {
+	return LoadMySQLContext(context.Background(), db, dbName)
+}
+
+// LoadMySQLContext is LoadMySQL with a context that cancels the catalog queries.
+func LoadMySQLContext(ctx context.Context, db *sql.DB, dbName string) (*MYDatabase, error) {
+	return LoadMySQLWithFilter(ctx, db, dbName, LoadFilter{})
+}
+
+// LoadMySQLWithFilter is LoadMySQLContext restricted to the tables selected
+// by filter. The filter is applied in the catalog queries; schema patterns
+// match dbName. It fails if dbName, or the current database when dbName is
+// empty, does not exist.
+func LoadMySQLWithFilter(ctx context.Context, db *sql.DB, dbName string, filter LoadFilter) (*MYDatabase, error) {
+	return loadMySQL(ctx, db, dbName, filter, nil)
+}
+
+// loadMySQL is LoadMySQLWithFilter with the column types mapped by mapper
+// first.
+func loadMySQL(ctx context.Context, db *sql.DB, dbName string, filter LoadFilter, mapper TypeMapper) (*MYDatabase, error) {
 	// Get version
 	var version string
-	if err := db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
 		return nil, fmt.Errorf("failed to get mysql version: %w", err)
 	}
 
+	if dbName == "" {
+		current, err := myCurrentDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		dbName = current
+	}
+	var charset, collation sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME
+		FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?`, dbName).Scan(&charset, &collation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("database %s does not exist", dbName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check database %s: %w", dbName, err)
+	}
+
 	myDB := &MYDatabase{
-		Name: dbName,
+		Name:      dbName,
+		Version:   version,
+		Charset:   charset.String,
+		Collation: collation.String,
 	}
 
 	// Load tables
-	tables, err := loadMYTables(db, dbName)
+	tables, err := loadMYTables(ctx, db, dbName, filter, mapper)
 	if err != nil {
 		return nil, err
 	}
 	myDB.Tables = tables
 
+	// Load views
+	views, err := loadMYViews(ctx, db, dbName, filter)
+	if err != nil {
+		return nil, err
+	}
+	myDB.Views = views
+
 	return myDB, nil
 }
 
-func loadMYTables(db *sql.DB, dbName string) ([]*MYTable, error) {
+// myCurrentDatabase returns the connection's current database, as
+// LoadPostgres uses current_database(). It fails if none is selected.
+func myCurrentDatabase(ctx context.Context, db *sql.DB) (string, error) {
+	var current sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&current); err != nil {
+		return "", fmt.Errorf("failed to get current database: %w", err)
+	}
+	if !current.Valid || current.String == "" {
+		return "", fmt.Errorf("no database name given and no database selected on the connection")
+	}
+	return current.String, nil
+}
+
+// myCaseFolding returns the folding matching the server's
+// lower_case_table_names: names are kept when it is 0 and lower-cased when
+// it is 1, or 2, where MySQL stores them as given but compares them
+// lower-cased.
+func myCaseFolding(ctx context.Context, db *sql.DB) (CaseFolding, error) {
+	var lower int
+	if err := db.QueryRowContext(ctx, "SELECT @@lower_case_table_names").Scan(&lower); err != nil {
+		return CaseFoldingDefault, fmt.Errorf("failed to get lower_case_table_names: %w", err)
+	}
+	if lower == 0 {
+		return CaseFoldingAsIs, nil
+	}
+	return CaseFoldingLower, nil
+}
+
+func loadMYViews(ctx context.Context, db *sql.DB, dbName string, filter LoadFilter) ([]*MYView, error) {
+	query := `
+		SELECT TABLE_NAME, VIEW_DEFINITION, CHECK_OPTION, IS_UPDATABLE, SECURITY_TYPE
+		FROM information_schema.VIEWS
+		WHERE TABLE_SCHEMA = ?
+	`
+	conds, args := filter.sqlConditions("TABLE_NAME", "TABLE_SCHEMA", []any{dbName}, questionPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds+" ORDER BY TABLE_NAME", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*MYView
+	for rows.Next() {
+		var name, definition, checkOption, updatable, security sql.NullString
+		if err := rows.Scan(&name, &definition, &checkOption, &updatable, &security); err != nil {
+			return nil, err
+		}
+		views = append(views, &MYView{
+			Name:         &ObjectName{Idents: []string{dbName, name.String}},
+			Definition:   definition.String,
+			CheckOption:  checkOption.String,
+			IsUpdatable:  strings.ToUpper(updatable.String) == "YES",
+			SecurityType: security.String,
+		})
+	}
+	return views, rows.Err()
+}
+
+func loadMYTables(ctx context.Context, db *sql.DB, dbName string, filter LoadFilter, mapper TypeMapper) ([]*MYTable, error) {
 	query := `
-		SELECT TABLE_NAME, ENGINE, TABLE_COLLATION, TABLE_COMMENT, AUTO_INCREMENT
+		SELECT TABLE_NAME, ENGINE, TABLE_COLLATION, TABLE_COMMENT, AUTO_INCREMENT, ROW_FORMAT, CREATE_OPTIONS
 		FROM information_schema.TABLES
 		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'
 	`
-	rows, err := db.Query(query, dbName)
+	conds, args := filter.sqlConditions("TABLE_NAME", "TABLE_SCHEMA", []any{dbName}, questionPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -42,10 +152,10 @@ func loadMYTables(db *sql.DB, dbName string) ([]*MYTable, error) {
 
 	var tables []*MYTable
 	for rows.Next() {
-		var name, engine, collation, comment sql.NullString
+		var name, engine, collation, comment, rowFormat, createOptions sql.NullString
 		var autoInc sql.NullInt64
 
-		if err := rows.Scan(&name, &engine, &collation, &comment, &autoInc); err != nil {
+		if err := rows.Scan(&name, &engine, &collation, &comment, &autoInc, &rowFormat, &createOptions); err != nil {
 			return nil, err
 		}
 
@@ -57,43 +167,54 @@ func loadMYTables(db *sql.DB, dbName string) ([]*MYTable, error) {
 			Collation:     collation.String,
 			Comment:       comment.String,
 			AutoIncrement: autoInc.Int64,
+			RowFormat:     rowFormat.String,
+			CreateOptions: createOptions.String,
+			KeyBlockSize:  myKeyBlockSize(createOptions.String),
 		}
 
 		// Load columns
-		cols, err := loadMYColumns(db, dbName, name.String)
+		cols, err := loadMYColumns(ctx, db, dbName, name.String, mapper)
 		if err != nil {
 			return nil, err
 		}
 		table.Columns = cols
 
 		// Load indexes
-		indexes, err := loadMYIndexes(db, dbName, name.String)
+		indexes, err := loadMYIndexes(ctx, db, dbName, name.String)
 		if err != nil {
 			return nil, err
 		}
 		table.Indexes = indexes
 
 		// Load foreign keys
-		fks, err := loadMYForeignKeys(db, dbName, name.String)
+		fks, err := loadMYForeignKeys(ctx, db, dbName, name.String)
 		if err != nil {
 			return nil, err
 		}
 		table.ForeignKeys = fks
 
+		// Load partitioning
+		partitioning, err := loadMYPartitioning(ctx, db, dbName, name.String)
+		if err != nil {
+			return nil, err
+		}
+		table.Partitioning = partitioning
+
 		tables = append(tables, table)
 	}
 	return tables, nil
 }
 
-func loadMYColumns(db *sql.DB, dbName, tableName string) ([]*MYColumn, error) {
+func loadMYColumns(ctx context.Context, db *sql.DB, dbName, tableName string, mapper TypeMapper) ([]*MYColumn, error) {
 	query := `
 		SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT, COLUMN_KEY, EXTRA, COLUMN_COMMENT, 
-		       CHARACTER_SET_NAME, COLLATION_NAME, NUMERIC_PRECISION, NUMERIC_SCALE, CHARACTER_MAXIMUM_LENGTH
+		       CHARACTER_SET_NAME, COLLATION_NAME, NUMERIC_PRECISION, NUMERIC_SCALE, CHARACTER_MAXIMUM_LENGTH,
+		       GENERATION_EXPRESSION, COLUMN_TYPE
 		FROM information_schema.COLUMNS
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY ORDINAL_POSITION
 	`
-	rows, err := db.Query(query, dbName, tableName)
+	rows, err := db.QueryContext(ctx, query, dbName, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query columns: %w", err)
 	}
@@ -101,17 +222,21 @@ func loadMYColumns(db *sql.DB, dbName, tableName string) ([]*MYColumn, error) {
 
 	var cols []*MYColumn
 	for rows.Next() {
-		var name, dataType, isNullable, defaultVal, colKey, extra, comment, charset, collation sql.NullString
+		var name, dataType, isNullable, defaultVal, colKey, extra, comment, charset, collation, genExpr, columnType sql.NullString
 		var precision, scale, length sql.NullInt64
 
 		if err := rows.Scan(&name, &dataType, &isNullable, &defaultVal, &colKey, &extra, &comment,
-			&charset, &collation, &precision, &scale, &length); err != nil {
+			&charset, &collation, &precision, &scale, &length, &genExpr, &columnType); err != nil {
 			return nil, err
 		}
 
+		dt := mapType(mapper, columnType.String, precision.Int64, scale.Int64, length.Int64)
+		if dt == nil {
+			dt = mapMySQLTypeForProto(dataType.String, columnType.String, precision.Int64, scale.Int64, length.Int64)
+		}
 		col := &MYColumn{
 			Name:          name.String,
-			DataType:      mapMySQLTypeForProto(dataType.String, precision.Int64, scale.Int64, length.Int64),
+			DataType:      dt,
 			IsNullable:    strings.ToUpper(isNullable.String) == "YES",
 			DefaultValue:  defaultVal.String,
 			IsPrimaryKey:  colKey.String == "PRI",
@@ -119,14 +244,31 @@ func loadMYColumns(db *sql.DB, dbName, tableName string) ([]*MYColumn, error) {
 			Charset:       charset.String,
 			Collation:     collation.String,
 			Comment:       comment.String,
+			IsUnsigned:    strings.Contains(strings.ToLower(columnType.String), "unsigned"),
+		}
+
+		// EXTRA is "STORED GENERATED" or "VIRTUAL GENERATED" for generated columns
+		// (not to be confused with "DEFAULT_GENERATED" for expression defaults)
+		lowerExtra := strings.ToLower(extra.String)
+		if strings.Contains(lowerExtra, "stored generated") {
+			col.IsGenerated = true
+			col.GenerationKind = "STORED"
+		} else if strings.Contains(lowerExtra, "virtual generated") {
+			col.IsGenerated = true
+			col.GenerationKind = "VIRTUAL"
+		}
+		if col.IsGenerated {
+			col.GenerationExpression = genExpr.String
 		}
+
 		cols = append(cols, col)
 	}
 	return cols, nil
 }
 
-// Placeholder for type mapping
-func mapMySQLTypeForProto(typ string, precision, scale, length int64) *DataType {
+// Placeholder for type mapping. columnType is the full COLUMN_TYPE, which
+// carries the value lists of ENUM and SET columns.
+func mapMySQLTypeForProto(typ, columnType string, precision, scale, length int64) *DataType {
 	t := &DataType{}
 	typ = strings.ToLower(typ)
 
@@ -144,13 +286,27 @@ func mapMySQLTypeForProto(typ string, precision, scale, length int64) *DataType
 		t.TypeClause = &DataType_DecimalData{DecimalData: &Decimal{Precision: uint32(precision), Scale: uint32(scale)}}
 	case "varchar", "char", "text", "mediumtext", "longtext", "tinytext":
 		t.TypeClause = &DataType_TextData{TextData: DataTypeSingle_Text}
+	case "enum", "set":
+		// COLUMN_TYPE is e.g. enum('small','large'); the values keep their case
+		return parseSQLDataType(columnType)
+	case "date":
+		t.TypeClause = &DataType_DateData{DateData: DataTypeSingle_Date}
+	case "json":
+		t.TypeClause = &DataType_JSONData{JSONData: DataTypeSingle_JSON}
+	case "datetime", "timestamp", "time":
+		// COLUMN_TYPE carries the fractional seconds, e.g. datetime(6);
+		// a MySQL TIMESTAMP is stored in UTC
+		t = parseSQLDataType(columnType)
+		if ts := t.GetTimestampData(); ts != nil && typ == "timestamp" {
+			ts.WithTimeZone = true
+		}
 	default:
 		t.TypeClause = &DataType_CustomData{CustomData: &ObjectName{Idents: []string{typ}}}
 	}
 	return t
 }
 
-func loadMYIndexes(db *sql.DB, dbName, tableName string) ([]*MYIndex, error) {
+func loadMYIndexes(ctx context.Context, db *sql.DB, dbName, tableName string) ([]*MYIndex, error) {
 	// MySQL SHOW INDEX OR information_schema.STATISTICS
 	query := `
 		SELECT INDEX_NAME, NON_UNIQUE, INDEX_TYPE, COLUMN_NAME
@@ -158,13 +314,14 @@ func loadMYIndexes(db *sql.DB, dbName, tableName string) ([]*MYIndex, error) {
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY INDEX_NAME, SEQ_IN_INDEX
 	`
-	rows, err := db.Query(query, dbName, tableName)
+	rows, err := db.QueryContext(ctx, query, dbName, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query indexes: %w", err)
 	}
 	defer rows.Close()
 
 	indexMap := make(map[string]*MYIndex)
+	var indexes []*MYIndex
 	for rows.Next() {
 		var indexName, indexType, colName string
 		var nonUnique int
@@ -184,25 +341,21 @@ func loadMYIndexes(db *sql.DB, dbName, tableName string) ([]*MYIndex, error) {
 				IndexType: indexType,
 			}
 			indexMap[indexName] = idx
+			indexes = append(indexes, idx)
 		}
 		idx.Columns = append(idx.Columns, colName)
 	}
-
-	var indexes []*MYIndex
-	for _, idx := range indexMap {
-		indexes = append(indexes, idx)
-	}
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
-func loadMYForeignKeys(db *sql.DB, dbName, tableName string) ([]*MYForeignKey, error) {
+func loadMYForeignKeys(ctx context.Context, db *sql.DB, dbName, tableName string) ([]*MYForeignKey, error) {
 	query := `
 		SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_SCHEMA
 		FROM information_schema.KEY_COLUMN_USAGE
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL
 		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION
 	`
-	rows, err := db.Query(query, dbName, tableName)
+	rows, err := db.QueryContext(ctx, query, dbName, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
 	}
@@ -239,3 +392,50 @@ func loadMYForeignKeys(db *sql.DB, dbName, tableName string) ([]*MYForeignKey, e
 	}
 	return fks, nil
 }
+
+// myKeyBlockSize reads key_block_size from CREATE_OPTIONS, e.g.
+// "row_format=COMPRESSED key_block_size=8", or returns 0.
+func myKeyBlockSize(createOptions string) uint32 {
+	for _, opt := range strings.Fields(createOptions) {
+		if k, v, ok := strings.Cut(opt, "="); ok && strings.EqualFold(k, "key_block_size") {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err == nil {
+				return uint32(n)
+			}
+		}
+	}
+	return 0
+}
+
+// loadMYPartitioning reads the partitions of a table in order, or returns
+// nil for a table that is not partitioned. Subpartitions are not modelled.
+func loadMYPartitioning(ctx context.Context, db *sql.DB, dbName, tableName string) (*MYPartitioning, error) {
+	query := `
+		SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION, PARTITION_DESCRIPTION
+		FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL
+		  AND (SUBPARTITION_ORDINAL_POSITION IS NULL OR SUBPARTITION_ORDINAL_POSITION = 1)
+		ORDER BY PARTITION_ORDINAL_POSITION
+	`
+	rows, err := db.QueryContext(ctx, query, dbName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitioning *MYPartitioning
+	for rows.Next() {
+		var name, method, expression, description sql.NullString
+		if err := rows.Scan(&name, &method, &expression, &description); err != nil {
+			return nil, err
+		}
+		if partitioning == nil {
+			partitioning = &MYPartitioning{Method: method.String, Expression: expression.String}
+		}
+		partitioning.Partitions = append(partitioning.Partitions, &MYPartition{
+			Name:        name.String,
+			Description: description.String,
+		})
+	}
+	return partitioning, rows.Err()
+}
@@ -0,0 +1,46 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestMapMySQLTypeForProto_EnumSet(t *testing.T) {
+	dt := mapMySQLTypeForProto("enum", "enum('Small','it''s, large')", 0, 0, 0)
+	values := dt.GetEnumData().GetValues()
+	if len(values) != 2 || values[0] != "Small" || values[1] != "it's, large" {
+		t.Errorf("Unexpected enum values %v", values)
+	}
+
+	dt = mapMySQLTypeForProto("set", "set('read','write')", 0, 0, 0)
+	if values := dt.GetSetData().GetValues(); len(values) != 2 || values[1] != "write" {
+		t.Errorf("Unexpected set values %v", values)
+	}
+}
+
+func TestMapMySQLTypeForProto_Temporal(t *testing.T) {
+	if got, _ := dataTypeSQL(mapMySQLTypeForProto("timestamp", "timestamp(3)", 0, 0, 0), DialectMySQL); got != "TIMESTAMP(3)" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+	if got, _ := dataTypeSQL(mapMySQLTypeForProto("datetime", "datetime", 0, 0, 0), DialectMySQL); got != "DATETIME" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+	if got, _ := dataTypeSQL(mapMySQLTypeForProto("time", "time(6)", 0, 0, 0), DialectMySQL); got != "TIME(6)" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+	if mapMySQLTypeForProto("date", "date", 0, 0, 0).GetDateData() != DataTypeSingle_Date {
+		t.Error("Expected a date")
+	}
+}
+
+func TestMyKeyBlockSize(t *testing.T) {
+	for opts, want := range map[string]uint32{
+		"row_format=COMPRESSED key_block_size=8": 8,
+		"KEY_BLOCK_SIZE=16 partitioned":          16,
+		"row_format=DYNAMIC":                     0,
+		"":                                       0,
+	} {
+		if got := myKeyBlockSize(opts); got != want {
+			t.Errorf("%q: expected %d, got %d", opts, want, got)
+		}
+	}
+}
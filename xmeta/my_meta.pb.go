@@ -23,20 +23,23 @@ const (
 
 // Represents a column in a MySQL table
 type MYColumn struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	DataType      *DataType              `protobuf:"bytes,2,opt,name=DataType,proto3" json:"DataType,omitempty"`
-	IsNullable    bool                   `protobuf:"varint,3,opt,name=IsNullable,proto3" json:"IsNullable,omitempty"`
-	DefaultValue  string                 `protobuf:"bytes,4,opt,name=DefaultValue,proto3" json:"DefaultValue,omitempty"`
-	IsPrimaryKey  bool                   `protobuf:"varint,5,opt,name=IsPrimaryKey,proto3" json:"IsPrimaryKey,omitempty"`
-	AutoIncrement bool                   `protobuf:"varint,6,opt,name=AutoIncrement,proto3" json:"AutoIncrement,omitempty"`
-	Charset       string                 `protobuf:"bytes,7,opt,name=Charset,proto3" json:"Charset,omitempty"`
-	Collation     string                 `protobuf:"bytes,8,opt,name=Collation,proto3" json:"Collation,omitempty"`
-	Comment       string                 `protobuf:"bytes,9,opt,name=Comment,proto3" json:"Comment,omitempty"`
-	IsUnsigned    bool                   `protobuf:"varint,10,opt,name=IsUnsigned,proto3" json:"IsUnsigned,omitempty"`
-	DisplayWidth  uint32                 `protobuf:"varint,11,opt,name=DisplayWidth,proto3" json:"DisplayWidth,omitempty"` // e.g., int(11)
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	DataType             *DataType              `protobuf:"bytes,2,opt,name=DataType,proto3" json:"DataType,omitempty"`
+	IsNullable           bool                   `protobuf:"varint,3,opt,name=IsNullable,proto3" json:"IsNullable,omitempty"`
+	DefaultValue         string                 `protobuf:"bytes,4,opt,name=DefaultValue,proto3" json:"DefaultValue,omitempty"`
+	IsPrimaryKey         bool                   `protobuf:"varint,5,opt,name=IsPrimaryKey,proto3" json:"IsPrimaryKey,omitempty"`
+	AutoIncrement        bool                   `protobuf:"varint,6,opt,name=AutoIncrement,proto3" json:"AutoIncrement,omitempty"`
+	Charset              string                 `protobuf:"bytes,7,opt,name=Charset,proto3" json:"Charset,omitempty"`
+	Collation            string                 `protobuf:"bytes,8,opt,name=Collation,proto3" json:"Collation,omitempty"`
+	Comment              string                 `protobuf:"bytes,9,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	IsUnsigned           bool                   `protobuf:"varint,10,opt,name=IsUnsigned,proto3" json:"IsUnsigned,omitempty"`
+	DisplayWidth         uint32                 `protobuf:"varint,11,opt,name=DisplayWidth,proto3" json:"DisplayWidth,omitempty"` // e.g., int(11)
+	IsGenerated          bool                   `protobuf:"varint,12,opt,name=IsGenerated,proto3" json:"IsGenerated,omitempty"`
+	GenerationExpression string                 `protobuf:"bytes,13,opt,name=GenerationExpression,proto3" json:"GenerationExpression,omitempty"`
+	GenerationKind       string                 `protobuf:"bytes,14,opt,name=GenerationKind,proto3" json:"GenerationKind,omitempty"` // "STORED" or "VIRTUAL"
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *MYColumn) Reset() {
@@ -146,6 +149,27 @@ func (x *MYColumn) GetDisplayWidth() uint32 {
 	return 0
 }
 
+func (x *MYColumn) GetIsGenerated() bool {
+	if x != nil {
+		return x.IsGenerated
+	}
+	return false
+}
+
+func (x *MYColumn) GetGenerationExpression() string {
+	if x != nil {
+		return x.GenerationExpression
+	}
+	return ""
+}
+
+func (x *MYColumn) GetGenerationKind() string {
+	if x != nil {
+		return x.GenerationKind
+	}
+	return ""
+}
+
 // Represents an index in a MySQL table
 type MYIndex struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -345,6 +369,9 @@ type MYTable struct {
 	Comment       string                 `protobuf:"bytes,8,opt,name=Comment,proto3" json:"Comment,omitempty"`
 	AutoIncrement int64                  `protobuf:"varint,9,opt,name=AutoIncrement,proto3" json:"AutoIncrement,omitempty"` // Next auto_increment value
 	CreateOptions string                 `protobuf:"bytes,10,opt,name=CreateOptions,proto3" json:"CreateOptions,omitempty"` // row_format=DYNAMIC, etc.
+	RowFormat     string                 `protobuf:"bytes,11,opt,name=RowFormat,proto3" json:"RowFormat,omitempty"`         // Dynamic, Compressed, etc.
+	KeyBlockSize  uint32                 `protobuf:"varint,12,opt,name=KeyBlockSize,proto3" json:"KeyBlockSize,omitempty"`  // From key_block_size in CreateOptions
+	Partitioning  *MYPartitioning        `protobuf:"bytes,13,opt,name=Partitioning,proto3" json:"Partitioning,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -449,18 +476,234 @@ func (x *MYTable) GetCreateOptions() string {
 	return ""
 }
 
+func (x *MYTable) GetRowFormat() string {
+	if x != nil {
+		return x.RowFormat
+	}
+	return ""
+}
+
+func (x *MYTable) GetKeyBlockSize() uint32 {
+	if x != nil {
+		return x.KeyBlockSize
+	}
+	return 0
+}
+
+func (x *MYTable) GetPartitioning() *MYPartitioning {
+	if x != nil {
+		return x.Partitioning
+	}
+	return nil
+}
+
+// Represents one partition of a partitioned MySQL table
+type MYPartition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=Description,proto3" json:"Description,omitempty"` // Bound, e.g. "100" or "MAXVALUE"; empty for HASH and KEY
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MYPartition) Reset() {
+	*x = MYPartition{}
+	mi := &file_my_meta_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MYPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MYPartition) ProtoMessage() {}
+
+func (x *MYPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_my_meta_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MYPartition.ProtoReflect.Descriptor instead.
+func (*MYPartition) Descriptor() ([]byte, []int) {
+	return file_my_meta_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MYPartition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MYPartition) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// Represents how a MySQL table is partitioned
+type MYPartitioning struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=Method,proto3" json:"Method,omitempty"`         // RANGE, LIST, HASH, KEY, RANGE COLUMNS, LINEAR HASH, etc.
+	Expression    string                 `protobuf:"bytes,2,opt,name=Expression,proto3" json:"Expression,omitempty"` // Partitioning expression or column list
+	Partitions    []*MYPartition         `protobuf:"bytes,3,rep,name=Partitions,proto3" json:"Partitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MYPartitioning) Reset() {
+	*x = MYPartitioning{}
+	mi := &file_my_meta_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MYPartitioning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MYPartitioning) ProtoMessage() {}
+
+func (x *MYPartitioning) ProtoReflect() protoreflect.Message {
+	mi := &file_my_meta_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MYPartitioning.ProtoReflect.Descriptor instead.
+func (*MYPartitioning) Descriptor() ([]byte, []int) {
+	return file_my_meta_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MYPartitioning) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MYPartitioning) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *MYPartitioning) GetPartitions() []*MYPartition {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+// Represents a MySQL view
+type MYView struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *ObjectName            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Definition    string                 `protobuf:"bytes,2,opt,name=Definition,proto3" json:"Definition,omitempty"`   // SELECT statement
+	CheckOption   string                 `protobuf:"bytes,3,opt,name=CheckOption,proto3" json:"CheckOption,omitempty"` // NONE, CASCADED, LOCAL
+	IsUpdatable   bool                   `protobuf:"varint,4,opt,name=IsUpdatable,proto3" json:"IsUpdatable,omitempty"`
+	SecurityType  string                 `protobuf:"bytes,5,opt,name=SecurityType,proto3" json:"SecurityType,omitempty"` // DEFINER, INVOKER
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MYView) Reset() {
+	*x = MYView{}
+	mi := &file_my_meta_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MYView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MYView) ProtoMessage() {}
+
+func (x *MYView) ProtoReflect() protoreflect.Message {
+	mi := &file_my_meta_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MYView.ProtoReflect.Descriptor instead.
+func (*MYView) Descriptor() ([]byte, []int) {
+	return file_my_meta_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MYView) GetName() *ObjectName {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *MYView) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
+func (x *MYView) GetCheckOption() string {
+	if x != nil {
+		return x.CheckOption
+	}
+	return ""
+}
+
+func (x *MYView) GetIsUpdatable() bool {
+	if x != nil {
+		return x.IsUpdatable
+	}
+	return false
+}
+
+func (x *MYView) GetSecurityType() string {
+	if x != nil {
+		return x.SecurityType
+	}
+	return ""
+}
+
 // Represents a MySQL database (schema)
 type MYDatabase struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	Tables        []*MYTable             `protobuf:"bytes,2,rep,name=Tables,proto3" json:"Tables,omitempty"` // Views, routines, etc. can be added later
+	Tables        []*MYTable             `protobuf:"bytes,2,rep,name=Tables,proto3" json:"Tables,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=Version,proto3" json:"Version,omitempty"` // SELECT VERSION()
+	Views         []*MYView              `protobuf:"bytes,4,rep,name=Views,proto3" json:"Views,omitempty"`
+	Charset       string                 `protobuf:"bytes,5,opt,name=Charset,proto3" json:"Charset,omitempty"`     // DEFAULT CHARACTER SET of the database
+	Collation     string                 `protobuf:"bytes,6,opt,name=Collation,proto3" json:"Collation,omitempty"` // DEFAULT COLLATE of the database
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MYDatabase) Reset() {
 	*x = MYDatabase{}
-	mi := &file_my_meta_proto_msgTypes[4]
+	mi := &file_my_meta_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -472,7 +715,7 @@ func (x *MYDatabase) String() string {
 func (*MYDatabase) ProtoMessage() {}
 
 func (x *MYDatabase) ProtoReflect() protoreflect.Message {
-	mi := &file_my_meta_proto_msgTypes[4]
+	mi := &file_my_meta_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -485,7 +728,7 @@ func (x *MYDatabase) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MYDatabase.ProtoReflect.Descriptor instead.
 func (*MYDatabase) Descriptor() ([]byte, []int) {
-	return file_my_meta_proto_rawDescGZIP(), []int{4}
+	return file_my_meta_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *MYDatabase) GetName() string {
@@ -502,11 +745,39 @@ func (x *MYDatabase) GetTables() []*MYTable {
 	return nil
 }
 
+func (x *MYDatabase) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *MYDatabase) GetViews() []*MYView {
+	if x != nil {
+		return x.Views
+	}
+	return nil
+}
+
+func (x *MYDatabase) GetCharset() string {
+	if x != nil {
+		return x.Charset
+	}
+	return ""
+}
+
+func (x *MYDatabase) GetCollation() string {
+	if x != nil {
+		return x.Collation
+	}
+	return ""
+}
+
 var File_my_meta_proto protoreflect.FileDescriptor
 
 const file_my_meta_proto_rawDesc = "" +
 	"\n" +
-	"\rmy_meta.proto\x12\x06mymeta\x1a\vtypes.proto\"\xf1\x02\n" +
+	"\rmy_meta.proto\x12\x06mymeta\x1a\vtypes.proto\"\xef\x03\n" +
 	"\bMYColumn\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12-\n" +
 	"\bDataType\x18\x02 \x01(\v2\x11.sqlmeta.DataTypeR\bDataType\x12\x1e\n" +
@@ -523,7 +794,10 @@ const file_my_meta_proto_rawDesc = "" +
 	"IsUnsigned\x18\n" +
 	" \x01(\bR\n" +
 	"IsUnsigned\x12\"\n" +
-	"\fDisplayWidth\x18\v \x01(\rR\fDisplayWidth\"\xee\x01\n" +
+	"\fDisplayWidth\x18\v \x01(\rR\fDisplayWidth\x12 \n" +
+	"\vIsGenerated\x18\f \x01(\bR\vIsGenerated\x122\n" +
+	"\x14GenerationExpression\x18\r \x01(\tR\x14GenerationExpression\x12&\n" +
+	"\x0eGenerationKind\x18\x0e \x01(\tR\x0eGenerationKind\"\xee\x01\n" +
 	"\aMYIndex\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x121\n" +
 	"\tTableName\x18\x02 \x01(\v2\x13.sqlmeta.ObjectNameR\tTableName\x12\x1a\n" +
@@ -539,7 +813,7 @@ const file_my_meta_proto_rawDesc = "" +
 	"\fForeignTable\x18\x04 \x01(\v2\x13.sqlmeta.ObjectNameR\fForeignTable\x12&\n" +
 	"\x0eForeignColumns\x18\x05 \x03(\tR\x0eForeignColumns\x12\x1a\n" +
 	"\bOnUpdate\x18\x06 \x01(\tR\bOnUpdate\x12\x1a\n" +
-	"\bOnDelete\x18\a \x01(\tR\bOnDelete\"\xf7\x02\n" +
+	"\bOnDelete\x18\a \x01(\tR\bOnDelete\"\xf5\x03\n" +
 	"\aMYTable\x12'\n" +
 	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x16\n" +
 	"\x06Engine\x18\x02 \x01(\tR\x06Engine\x12\x18\n" +
@@ -551,11 +825,37 @@ const file_my_meta_proto_rawDesc = "" +
 	"\aComment\x18\b \x01(\tR\aComment\x12$\n" +
 	"\rAutoIncrement\x18\t \x01(\x03R\rAutoIncrement\x12$\n" +
 	"\rCreateOptions\x18\n" +
-	" \x01(\tR\rCreateOptions\"I\n" +
+	" \x01(\tR\rCreateOptions\x12\x1c\n" +
+	"\tRowFormat\x18\v \x01(\tR\tRowFormat\x12\"\n" +
+	"\fKeyBlockSize\x18\f \x01(\rR\fKeyBlockSize\x12:\n" +
+	"\fPartitioning\x18\r \x01(\v2\x16.mymeta.MYPartitioningR\fPartitioning\"C\n" +
+	"\vMYPartition\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12 \n" +
+	"\vDescription\x18\x02 \x01(\tR\vDescription\"}\n" +
+	"\x0eMYPartitioning\x12\x16\n" +
+	"\x06Method\x18\x01 \x01(\tR\x06Method\x12\x1e\n" +
+	"\n" +
+	"Expression\x18\x02 \x01(\tR\n" +
+	"Expression\x123\n" +
+	"\n" +
+	"Partitions\x18\x03 \x03(\v2\x13.mymeta.MYPartitionR\n" +
+	"Partitions\"\xb9\x01\n" +
+	"\x06MYView\x12'\n" +
+	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x1e\n" +
+	"\n" +
+	"Definition\x18\x02 \x01(\tR\n" +
+	"Definition\x12 \n" +
+	"\vCheckOption\x18\x03 \x01(\tR\vCheckOption\x12 \n" +
+	"\vIsUpdatable\x18\x04 \x01(\bR\vIsUpdatable\x12\"\n" +
+	"\fSecurityType\x18\x05 \x01(\tR\fSecurityType\"\xc1\x01\n" +
 	"\n" +
 	"MYDatabase\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12'\n" +
-	"\x06Tables\x18\x02 \x03(\v2\x0f.mymeta.MYTableR\x06TablesB\"Z github.com/genelet/sqlmeta/xmetab\x06proto3"
+	"\x06Tables\x18\x02 \x03(\v2\x0f.mymeta.MYTableR\x06Tables\x12\x18\n" +
+	"\aVersion\x18\x03 \x01(\tR\aVersion\x12$\n" +
+	"\x05Views\x18\x04 \x03(\v2\x0e.mymeta.MYViewR\x05Views\x12\x18\n" +
+	"\aCharset\x18\x05 \x01(\tR\aCharset\x12\x1c\n" +
+	"\tCollation\x18\x06 \x01(\tR\tCollationB\"Z github.com/genelet/sqlmeta/xmetab\x06proto3"
 
 var (
 	file_my_meta_proto_rawDescOnce sync.Once
@@ -569,31 +869,38 @@ func file_my_meta_proto_rawDescGZIP() []byte {
 	return file_my_meta_proto_rawDescData
 }
 
-var file_my_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_my_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_my_meta_proto_goTypes = []any{
-	(*MYColumn)(nil),     // 0: mymeta.MYColumn
-	(*MYIndex)(nil),      // 1: mymeta.MYIndex
-	(*MYForeignKey)(nil), // 2: mymeta.MYForeignKey
-	(*MYTable)(nil),      // 3: mymeta.MYTable
-	(*MYDatabase)(nil),   // 4: mymeta.MYDatabase
-	(*DataType)(nil),     // 5: sqlmeta.DataType
-	(*ObjectName)(nil),   // 6: sqlmeta.ObjectName
+	(*MYColumn)(nil),       // 0: mymeta.MYColumn
+	(*MYIndex)(nil),        // 1: mymeta.MYIndex
+	(*MYForeignKey)(nil),   // 2: mymeta.MYForeignKey
+	(*MYTable)(nil),        // 3: mymeta.MYTable
+	(*MYPartition)(nil),    // 4: mymeta.MYPartition
+	(*MYPartitioning)(nil), // 5: mymeta.MYPartitioning
+	(*MYView)(nil),         // 6: mymeta.MYView
+	(*MYDatabase)(nil),     // 7: mymeta.MYDatabase
+	(*DataType)(nil),       // 8: sqlmeta.DataType
+	(*ObjectName)(nil),     // 9: sqlmeta.ObjectName
 }
 var file_my_meta_proto_depIdxs = []int32{
-	5, // 0: mymeta.MYColumn.DataType:type_name -> sqlmeta.DataType
-	6, // 1: mymeta.MYIndex.TableName:type_name -> sqlmeta.ObjectName
-	6, // 2: mymeta.MYForeignKey.TableName:type_name -> sqlmeta.ObjectName
-	6, // 3: mymeta.MYForeignKey.ForeignTable:type_name -> sqlmeta.ObjectName
-	6, // 4: mymeta.MYTable.Name:type_name -> sqlmeta.ObjectName
-	0, // 5: mymeta.MYTable.Columns:type_name -> mymeta.MYColumn
-	1, // 6: mymeta.MYTable.Indexes:type_name -> mymeta.MYIndex
-	2, // 7: mymeta.MYTable.ForeignKeys:type_name -> mymeta.MYForeignKey
-	3, // 8: mymeta.MYDatabase.Tables:type_name -> mymeta.MYTable
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	8,  // 0: mymeta.MYColumn.DataType:type_name -> sqlmeta.DataType
+	9,  // 1: mymeta.MYIndex.TableName:type_name -> sqlmeta.ObjectName
+	9,  // 2: mymeta.MYForeignKey.TableName:type_name -> sqlmeta.ObjectName
+	9,  // 3: mymeta.MYForeignKey.ForeignTable:type_name -> sqlmeta.ObjectName
+	9,  // 4: mymeta.MYTable.Name:type_name -> sqlmeta.ObjectName
+	0,  // 5: mymeta.MYTable.Columns:type_name -> mymeta.MYColumn
+	1,  // 6: mymeta.MYTable.Indexes:type_name -> mymeta.MYIndex
+	2,  // 7: mymeta.MYTable.ForeignKeys:type_name -> mymeta.MYForeignKey
+	5,  // 8: mymeta.MYTable.Partitioning:type_name -> mymeta.MYPartitioning
+	4,  // 9: mymeta.MYPartitioning.Partitions:type_name -> mymeta.MYPartition
+	9,  // 10: mymeta.MYView.Name:type_name -> sqlmeta.ObjectName
+	3,  // 11: mymeta.MYDatabase.Tables:type_name -> mymeta.MYTable
+	6,  // 12: mymeta.MYDatabase.Views:type_name -> mymeta.MYView
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_my_meta_proto_init() }
@@ -608,7 +915,7 @@ func file_my_meta_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_my_meta_proto_rawDesc), len(file_my_meta_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
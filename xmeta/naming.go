@@ -0,0 +1,133 @@
+package xmeta
+
+// naming.go derives stable names for constraints that have none, so that
+// anonymous constraints can be matched by name when diffing.
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxConstraintNameLen is the Postgres identifier limit, the tightest of the
+// supported dialects.
+const maxConstraintNameLen = 63
+
+// GenerateConstraintName returns a deterministic name for a constraint,
+// following the Postgres conventions: users_pkey, users_email_key,
+// orders_user_id_fkey, and users_balance_check for a check on the one
+// column balance or users_check for a check on several. Exclusion
+// constraints, whose columns are not recorded, get a short hash of their
+// definition instead: users_excl_1a2b3c4d. Names longer than 63 bytes are
+// shortened with a hash suffix.
+func GenerateConstraintName(tableName *ObjectName, tc *TableConstraint) string {
+	table := simpleNameKey(tableName)
+	spec := tc.GetSpec()
+
+	var parts []string
+	switch {
+	case spec.GetUniqueItem() != nil:
+		if spec.GetUniqueItem().IsPrimary {
+			parts = []string{table, "pkey"}
+		} else {
+			parts = append(append([]string{table}, spec.GetUniqueItem().Columns...), "key")
+		}
+	case spec.GetReferenceItem() != nil:
+		parts = append(append([]string{table}, spec.GetReferenceItem().Columns...), "fkey")
+	case spec.GetCheckItem() != nil:
+		parts = []string{table, "check"}
+		if cols := checkColumns(anyToString(spec.GetCheckItem())); len(cols) == 1 {
+			parts = []string{table, cols[0], "check"}
+		}
+	case spec.GetExcludeItem() != nil:
+		ex := spec.GetExcludeItem()
+		def := ex.Definition
+		for _, e := range ex.Elements {
+			def += anyToString(e.Expr) + " " + e.Operator + ","
+		}
+		parts = []string{table, "excl", shortHash(def)}
+	default:
+		parts = []string{table, "constraint"}
+	}
+
+	name := strings.Join(parts, "_")
+	if len(name) > maxConstraintNameLen {
+		suffix := "_" + shortHash(name)
+		name = name[:maxConstraintNameLen-len(suffix)] + suffix
+	}
+	return name
+}
+
+// nameUnnamedConstraints gives every unnamed table constraint of t a
+// generated name, numbered as Postgres does when it is taken: users_check,
+// users_check1.
+func nameUnnamedConstraints(t *MetaTable) {
+	used := constraintNames(t)
+	for _, elem := range t.GetElements() {
+		if tc := elem.GetTableConstraintElement(); tc != nil && tc.Name == "" {
+			tc.Name = freeConstraintName(used, GenerateConstraintName(t.Name, tc))
+		}
+	}
+}
+
+// constraintNames returns the names of the table constraints of t.
+func constraintNames(t *MetaTable) map[string]bool {
+	used := make(map[string]bool)
+	for _, elem := range t.GetElements() {
+		if tc := elem.GetTableConstraintElement(); tc.GetName() != "" {
+			used[tc.Name] = true
+		}
+	}
+	return used
+}
+
+// freeConstraintName returns name, or name with the first number that is
+// not in used appended, and records the result in used.
+func freeConstraintName(used map[string]bool, name string) string {
+	free := name
+	for i := 1; used[free]; i++ {
+		suffix := strconv.Itoa(i)
+		free = name[:min(len(name), maxConstraintNameLen-len(suffix))] + suffix
+	}
+	used[free] = true
+	return free
+}
+
+// checkKeywords are the words of a CHECK expression that are not names.
+var checkKeywords = map[string]bool{
+	"and": true, "or": true, "not": true, "is": true, "null": true, "true": true, "false": true,
+	"in": true, "between": true, "like": true, "ilike": true, "similar": true, "to": true,
+	"escape": true, "distinct": true, "from": true, "case": true, "when": true, "then": true,
+	"else": true, "end": true, "any": true, "all": true, "some": true, "exists": true,
+	"array": true, "collate": true, "unknown": true, "glob": true, "regexp": true,
+	"current_date": true, "current_time": true, "current_timestamp": true,
+}
+
+// checkColumns returns the distinct names a CHECK expression refers to, in
+// order: the words of its normalized form that are neither keywords nor
+// called as functions.
+func checkColumns(expr string) []string {
+	toks, err := tokenizeSQL(normalizeCheck(expr), DialectPostgres)
+	if err != nil {
+		return nil
+	}
+	var cols []string
+	for i, tok := range toks {
+		if tok.kind != sqlWord && tok.kind != sqlQuotedIdent {
+			continue
+		}
+		if checkKeywords[tok.text] || (i+1 < len(toks) && toks[i+1].is("(")) || slices.Contains(cols, tok.text) {
+			continue
+		}
+		cols = append(cols, tok.text)
+	}
+	return cols
+}
+
+// shortHash returns the first 8 hex digits of the SHA-1 of s.
+func shortHash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,94 @@
+package xmeta
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConstraintName(t *testing.T) {
+	users := &ObjectName{Idents: []string{"public", "users"}}
+	checkExpr := stringToAny("balance >= 0")
+
+	tests := []struct {
+		name string
+		tc   *TableConstraint
+		want string
+	}{
+		{"primary", &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+			UniqueItem: &UniqueTableConstraint{IsPrimary: true, Columns: []string{"id"}}}}}, "users_pkey"},
+		{"unique", &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+			UniqueItem: &UniqueTableConstraint{Columns: []string{"email"}}}}}, "users_email_key"},
+		{"foreign", &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+			ReferenceItem: &ReferentialTableConstraint{Columns: []string{"org_id", "team_id"}}}}}, "users_org_id_team_id_fkey"},
+		{"check", &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_CheckItem{
+			CheckItem: checkExpr}}}, "users_balance_check"},
+		{"check on two columns", &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_CheckItem{
+			CheckItem: stringToAny(`"Low" < upper(High) AND low IS NOT NULL`)}}}, "users_check"},
+		{"check with a cast", &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_CheckItem{
+			CheckItem: stringToAny("((price > (0)::numeric))")}}}, "users_price_check"},
+	}
+	for _, tt := range tests {
+		if got := GenerateConstraintName(users, tt.tc); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	long := &TableConstraint{Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+		UniqueItem: &UniqueTableConstraint{Columns: []string{strings.Repeat("a", 40), strings.Repeat("b", 40)}}}}}
+	name := GenerateConstraintName(users, long)
+	if len(name) != maxConstraintNameLen || name != GenerateConstraintName(users, long) {
+		t.Errorf("Expected a stable %d-byte name, got %q", maxConstraintNameLen, name)
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_ConstraintNames(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL("CREATE TABLE t (id int, code text, PRIMARY KEY (id), UNIQUE (code))", DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	constraints := constraintsFromElements(db.Tables[0].Elements)
+	if constraints["t_pkey"] == nil || constraints["t_code_key"] == nil {
+		t.Errorf("Expected generated constraint names, got %v", constraints)
+	}
+
+	db, err = LoadMetaDatabaseFromSQL("CREATE TABLE t (id int, code text, PRIMARY KEY (id), UNIQUE KEY (code))", DialectMySQL)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	constraints = constraintsFromElements(db.Tables[0].Elements)
+	if constraints["PRIMARY"] == nil || constraints["code"] == nil {
+		t.Errorf("Expected MySQL key names, got %v", constraints)
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_CheckNames(t *testing.T) {
+	names := func(sql string) []string {
+		db, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres)
+		if err != nil {
+			t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+		}
+		var names []string
+		for _, elem := range db.Tables[0].Elements {
+			if tc := elem.GetTableConstraintElement(); tc != nil {
+				names = append(names, tc.Name)
+			}
+		}
+		return names
+	}
+
+	// Spellings of one check get one name, as Postgres would give it
+	for _, sql := range []string{
+		"CREATE TABLE t (v int, CHECK (v > 0))",
+		"CREATE TABLE t (v int, CHECK (v>0))",
+		"CREATE TABLE t (v int, CHECK ((v > 0)))",
+	} {
+		if got := names(sql); len(got) != 1 || got[0] != "t_v_check" {
+			t.Errorf("%s: expected [t_v_check], got %v", sql, got)
+		}
+	}
+
+	if got, want := names("CREATE TABLE t (a int, b int, CHECK (a > 0), CHECK (a < 10), CHECK (a < b))"), []string{"t_a_check", "t_a_check1", "t_check"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,483 @@
+package xmeta
+
+// normalize.go rewrites a MetaDatabase into a canonical form so that
+// cosmetic differences (schema qualifiers, identifier case, element order)
+// do not show up as changes when diffing.
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// NormalizeOptions controls NormalizeMetaDatabase.
+type NormalizeOptions struct {
+	// DefaultSchema is stripped from qualified names, so "public.users"
+	// and "users" compare equal. Empty leaves names as they are.
+	DefaultSchema string
+	// FoldCase lower-cases identifiers, for case-insensitive backends such
+	// as MySQL.
+	FoldCase bool
+}
+
+// NormalizeMetaDatabase rewrites db in place: table, column and constraint
+// names (including the names inside constraint definitions) are folded and
+// unqualified as requested, tables are sorted by name, and within each table
+// columns keep their declaration order and are followed by the constraints
+// sorted by name. Indexes are sorted by name as well.
+func NormalizeMetaDatabase(db *MetaDatabase, opts NormalizeOptions) {
+	if db == nil {
+		return
+	}
+	n := normalizer{opts: opts}
+
+	for _, t := range db.Tables {
+		n.normalizeTable(t)
+	}
+	sort.SliceStable(db.Tables, func(i, j int) bool {
+		return objectNameKey(db.Tables[i].GetName()) < objectNameKey(db.Tables[j].GetName())
+	})
+	n.normalizeObjects(db)
+}
+
+// normalizeObjects folds and unqualifies the names of the views, sequences
+// and triggers of db.
+func (n normalizer) normalizeObjects(db *MetaDatabase) {
+	for _, v := range db.Views {
+		v.Name = n.objectName(v.Name)
+	}
+	for _, s := range db.Sequences {
+		s.Name = n.objectName(s.Name)
+	}
+	for _, t := range db.Triggers {
+		t.TableName = n.objectName(t.TableName)
+	}
+}
+
+type normalizer struct {
+	opts    NormalizeOptions
+	folding CaseFolding
+}
+
+func (n normalizer) ident(s string) string {
+	if n.opts.FoldCase {
+		return strings.ToLower(s)
+	}
+	return n.folding.fold(s)
+}
+
+func (n normalizer) idents(list []string) []string {
+	for i, s := range list {
+		list[i] = n.ident(s)
+	}
+	return list
+}
+
+// objectName folds an ObjectName and drops a leading default schema.
+func (n normalizer) objectName(on *ObjectName) *ObjectName {
+	if on == nil {
+		return nil
+	}
+	idents := n.idents(on.Idents)
+	if len(idents) > 1 && n.opts.DefaultSchema != "" && strings.EqualFold(idents[0], n.opts.DefaultSchema) {
+		idents = idents[1:]
+	}
+	on.Idents = idents
+	return on
+}
+
+// tableName normalizes the dotted table name used by ReferenceKeyExpr.
+func (n normalizer) tableName(s string) string {
+	if s == "" {
+		return s
+	}
+	return formatObjectName(n.objectName(&ObjectName{Idents: strings.Split(s, ".")}))
+}
+
+func (n normalizer) normalizeTable(t *MetaTable) {
+	if t == nil {
+		return
+	}
+	n.normalizeNames(t)
+
+	var columns, constraints []*TableElement
+	for _, elem := range t.Elements {
+		if elem.GetColumnDefElement() != nil {
+			columns = append(columns, elem)
+			continue
+		}
+		constraints = append(constraints, elem)
+	}
+	sort.SliceStable(constraints, func(i, j int) bool {
+		return constraints[i].GetTableConstraintElement().GetName() < constraints[j].GetTableConstraintElement().GetName()
+	})
+	t.Elements = append(columns, constraints...)
+	sort.SliceStable(t.Indexes, func(i, j int) bool {
+		return t.Indexes[i].Name < t.Indexes[j].Name
+	})
+}
+
+// normalizeNames folds and unqualifies the names of t, its columns,
+// constraints and indexes, and the names they refer to, in place.
+func (n normalizer) normalizeNames(t *MetaTable) {
+	t.Name = n.objectName(t.Name)
+	for _, elem := range t.Elements {
+		if col := elem.GetColumnDefElement(); col != nil {
+			n.normalizeColumn(col)
+		}
+		if tc := elem.GetTableConstraintElement(); tc != nil {
+			n.normalizeConstraint(tc)
+		}
+	}
+	for _, idx := range t.Indexes {
+		idx.Name = n.ident(idx.Name)
+		n.idents(idx.Columns)
+	}
+}
+
+func (n normalizer) normalizeColumn(col *ColumnDef) {
+	col.Name = n.ident(col.Name)
+	for _, con := range col.Constraints {
+		con.Name = n.ident(con.Name)
+		if ref := con.GetSpec().GetReferenceItem(); ref != nil {
+			ref.TableName = n.objectName(ref.TableName)
+			n.idents(ref.Columns)
+		}
+	}
+}
+
+func (n normalizer) normalizeConstraint(tc *TableConstraint) {
+	tc.Name = n.ident(tc.Name)
+	spec := tc.GetSpec()
+	if u := spec.GetUniqueItem(); u != nil {
+		n.idents(u.Columns)
+		n.idents(u.Include)
+		u.IndexName = n.ident(u.IndexName)
+	}
+	if ref := spec.GetReferenceItem(); ref != nil {
+		n.idents(ref.Columns)
+		if ref.KeyExpr != nil {
+			ref.KeyExpr.TableName = n.tableName(ref.KeyExpr.TableName)
+			n.idents(ref.KeyExpr.Columns)
+		}
+	}
+	if ex := spec.GetExcludeItem(); ex != nil {
+		n.idents(ex.Include)
+	}
+}
+
+// canonicalPrimaryKey returns t with its primary key expressed only as a
+// table-level constraint: inline PRIMARY KEY column constraints are removed
+// and, when no table-level primary key exists, folded into one named name.
+// The key's columns are marked NOT NULL, which the key implies: a live
+// database reports it while a file usually leaves it out. t is not
+// modified; a copy is returned when anything changes.
+func canonicalPrimaryKey(t *MetaTable, name string) *MetaTable {
+	inline := primaryKeyColumns(t.GetElements())
+	key := tablePrimaryKeyColumns(t.GetElements())
+	if key == nil {
+		key = inline
+	}
+	columns := columnsFromElements(t.GetElements())
+	if len(inline) == 0 && !slices.ContainsFunc(key, func(c string) bool {
+		return columns[c] != nil && !isNotNull(columns[c])
+	}) {
+		return t
+	}
+	t = CloneMetaTable(t)
+	for _, elem := range t.Elements {
+		if col := elem.GetColumnDefElement(); col != nil {
+			col.Constraints = slices.DeleteFunc(col.Constraints, func(con *ColumnConstraint) bool {
+				return con.GetSpec().GetUniqueItem().GetIsPrimaryKey()
+			})
+			if slices.Contains(key, col.Name) {
+				setNotNull(col, true)
+			}
+		}
+	}
+	if len(inline) == 0 || hasTablePrimaryKey(t.Elements) {
+		return t
+	}
+	pk := &TableConstraint{
+		Name: name,
+		Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+			UniqueItem: &UniqueTableConstraint{IsPrimary: true, Columns: inline},
+		}},
+	}
+	if pk.Name == "" {
+		pk.Name = GenerateConstraintName(t.Name, pk)
+	}
+	t.Elements = append(t.Elements, &TableElement{
+		TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: pk},
+	})
+	return t
+}
+
+// tablePrimaryKeyColumns returns the columns of the table-level primary
+// key, or nil when there is none.
+func tablePrimaryKeyColumns(elems []*TableElement) []string {
+	for _, elem := range elems {
+		if u := elem.GetTableConstraintElement().GetSpec().GetUniqueItem(); u.GetIsPrimary() {
+			return u.Columns
+		}
+	}
+	return nil
+}
+
+// canonicalForeignKeys returns t with its inline REFERENCES column
+// constraints turned into table-level foreign keys, so the two spellings of
+// a single-column foreign key compare equal. An unnamed inline key takes
+// the name of the matching foreign key of other, or a generated one. t is
+// not modified; a copy is returned when anything changes.
+func canonicalForeignKeys(t, other *MetaTable) *MetaTable {
+	if !slices.ContainsFunc(orderedColumns(t.GetElements()), func(col *ColumnDef) bool {
+		return slices.ContainsFunc(col.Constraints, func(con *ColumnConstraint) bool { return con.GetSpec().GetReferenceItem() != nil })
+	}) {
+		return t
+	}
+	var names map[string]string
+	if other != nil {
+		names = make(map[string]string)
+		for _, elem := range other.Elements {
+			if tc := elem.GetTableConstraintElement(); tc.GetSpec().GetReferenceItem() != nil {
+				names[foreignKeySignature(tc)] = tc.Name
+			}
+		}
+		for _, col := range orderedColumns(other.Elements) {
+			for _, con := range col.Constraints {
+				if con.GetSpec().GetReferenceItem() != nil && con.Name != "" {
+					names[foreignKeySignature(columnForeignKey(col, con))] = con.Name
+				}
+			}
+		}
+	}
+
+	t = CloneMetaTable(t)
+	var fks []*TableConstraint
+	for _, col := range orderedColumns(t.Elements) {
+		col.Constraints = slices.DeleteFunc(col.Constraints, func(con *ColumnConstraint) bool {
+			if con.GetSpec().GetReferenceItem() == nil {
+				return false
+			}
+			fk := columnForeignKey(col, con)
+			if fk.Name == "" {
+				fk.Name = names[foreignKeySignature(fk)]
+			}
+			if fk.Name == "" {
+				fk.Name = GenerateConstraintName(t.Name, fk)
+			}
+			fks = append(fks, fk)
+			return true
+		})
+	}
+	for _, fk := range fks {
+		t.Elements = append(t.Elements, &TableElement{
+			TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: fk},
+		})
+	}
+	return t
+}
+
+// canonicalChecks returns t with its inline CHECK column constraints
+// turned into table-level checks, so a check compares the same whether it
+// was written on the column or the table, or loaded from a catalog that
+// attaches single-column checks to their column. An unnamed inline check
+// takes the name of the check of other with the same normalized
+// expression, or a generated one. t is not modified; a copy is returned
+// when anything changes.
+func canonicalChecks(t, other *MetaTable) *MetaTable {
+	if !slices.ContainsFunc(orderedColumns(t.GetElements()), func(col *ColumnDef) bool {
+		return slices.ContainsFunc(col.Constraints, func(con *ColumnConstraint) bool { return con.GetSpec().GetCheckItem() != nil })
+	}) {
+		return t
+	}
+	var names map[string]string
+	if other != nil {
+		names = make(map[string]string)
+		for _, elem := range other.Elements {
+			if tc := elem.GetTableConstraintElement(); tc.GetSpec().GetCheckItem() != nil {
+				names[normalizeCheck(anyToString(tc.Spec.GetCheckItem()))] = tc.Name
+			}
+		}
+		for _, col := range orderedColumns(other.Elements) {
+			for _, con := range col.Constraints {
+				if con.GetSpec().GetCheckItem() != nil && con.Name != "" {
+					names[normalizeCheck(anyToString(con.Spec.GetCheckItem()))] = con.Name
+				}
+			}
+		}
+	}
+
+	t = CloneMetaTable(t)
+	used := constraintNames(t)
+	var checks []*TableConstraint
+	for _, col := range orderedColumns(t.Elements) {
+		col.Constraints = slices.DeleteFunc(col.Constraints, func(con *ColumnConstraint) bool {
+			if con.GetSpec().GetCheckItem() == nil {
+				return false
+			}
+			check := &TableConstraint{
+				Name:        con.Name,
+				NotEnforced: con.NotEnforced,
+				Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_CheckItem{
+					CheckItem: con.Spec.GetCheckItem(),
+				}},
+			}
+			if check.Name == "" {
+				check.Name = names[normalizeCheck(anyToString(check.Spec.GetCheckItem()))]
+			}
+			if check.Name == "" {
+				check.Name = freeConstraintName(used, GenerateConstraintName(t.Name, check))
+			}
+			checks = append(checks, check)
+			return true
+		})
+	}
+	for _, check := range checks {
+		t.Elements = append(t.Elements, &TableElement{
+			TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: check},
+		})
+	}
+	return t
+}
+
+// canonicalUniques returns t with its inline UNIQUE column constraints
+// turned into table-level unique constraints on that column, so the two
+// spellings compare equal and adding or removing an inline UNIQUE shows as
+// a constraint change. An unnamed inline constraint takes the name of the
+// unique constraint of other on the same column, or a generated one. t is
+// not modified; a copy is returned when anything changes.
+func canonicalUniques(t, other *MetaTable) *MetaTable {
+	if !slices.ContainsFunc(orderedColumns(t.GetElements()), func(col *ColumnDef) bool {
+		return slices.ContainsFunc(col.Constraints, isInlineUnique)
+	}) {
+		return t
+	}
+	var names map[string]string
+	if other != nil {
+		names = make(map[string]string)
+		for _, elem := range other.Elements {
+			if u := elem.GetTableConstraintElement().GetSpec().GetUniqueItem(); u != nil && !u.IsPrimary && len(u.Columns) == 1 {
+				names[u.Columns[0]] = elem.GetTableConstraintElement().Name
+			}
+		}
+		for _, col := range orderedColumns(other.Elements) {
+			for _, con := range col.Constraints {
+				if isInlineUnique(con) && con.Name != "" {
+					names[col.Name] = con.Name
+				}
+			}
+		}
+	}
+
+	t = CloneMetaTable(t)
+	var uniques []*TableConstraint
+	for _, col := range orderedColumns(t.Elements) {
+		col.Constraints = slices.DeleteFunc(col.Constraints, func(con *ColumnConstraint) bool {
+			if !isInlineUnique(con) {
+				return false
+			}
+			unique := &TableConstraint{
+				Name:        con.Name,
+				NotEnforced: con.NotEnforced,
+				Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+					UniqueItem: &UniqueTableConstraint{Columns: []string{col.Name}},
+				}},
+			}
+			if unique.Name == "" {
+				unique.Name = names[col.Name]
+			}
+			if unique.Name == "" {
+				unique.Name = GenerateConstraintName(t.Name, unique)
+			}
+			uniques = append(uniques, unique)
+			return true
+		})
+	}
+	for _, unique := range uniques {
+		t.Elements = append(t.Elements, &TableElement{
+			TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: unique},
+		})
+	}
+	return t
+}
+
+// isInlineUnique reports whether con is an inline UNIQUE, not PRIMARY KEY,
+// column constraint.
+func isInlineUnique(con *ColumnConstraint) bool {
+	u := con.GetSpec().GetUniqueItem()
+	return u != nil && !u.IsPrimaryKey
+}
+
+// foreignKeySignature identifies a foreign key by its definition, ignoring
+// its name.
+func foreignKeySignature(tc *TableConstraint) string {
+	spec, _ := proto.MarshalOptions{Deterministic: true}.Marshal(tc.GetSpec())
+	return string(spec)
+}
+
+// columnForeignKey converts an inline REFERENCES constraint of col to the
+// equivalent table-level foreign key, keeping its name.
+func columnForeignKey(col *ColumnDef, con *ColumnConstraint) *TableConstraint {
+	ref := con.GetSpec().GetReferenceItem()
+	return &TableConstraint{
+		Name:        con.Name,
+		NotEnforced: con.NotEnforced,
+		Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+			ReferenceItem: &ReferentialTableConstraint{
+				Columns:           []string{col.Name},
+				KeyExpr:           &ReferenceKeyExpr{TableName: objectNameKey(ref.TableName), Columns: ref.Columns},
+				OnDelete:          ref.OnDelete,
+				OnUpdate:          ref.OnUpdate,
+				Match:             ref.Match,
+				Deferrable:        ref.Deferrable,
+				InitiallyDeferred: ref.InitiallyDeferred,
+			},
+		}},
+	}
+}
+
+// inlineForeignKeys moves t's single-column table-level foreign keys onto
+// their column as REFERENCES constraints, in place.
+func inlineForeignKeys(t *MetaTable) {
+	cols := columnsFromElements(t.GetElements())
+	t.Elements = slices.DeleteFunc(t.Elements, func(elem *TableElement) bool {
+		tc := elem.GetTableConstraintElement()
+		ref := tc.GetSpec().GetReferenceItem()
+		if ref == nil || len(ref.Columns) != 1 || len(ref.GetKeyExpr().GetColumns()) > 1 {
+			return false
+		}
+		col, ok := cols[ref.Columns[0]]
+		if !ok {
+			return false
+		}
+		col.Constraints = append(col.Constraints, &ColumnConstraint{
+			Name:        tc.Name,
+			NotEnforced: tc.NotEnforced,
+			Spec: &ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_ReferenceItem{
+				ReferenceItem: &ReferencesColumnSpec{
+					TableName:         &ObjectName{Idents: strings.Split(ref.GetKeyExpr().GetTableName(), ".")},
+					Columns:           ref.GetKeyExpr().GetColumns(),
+					OnDelete:          ref.OnDelete,
+					OnUpdate:          ref.OnUpdate,
+					Match:             ref.Match,
+					Deferrable:        ref.Deferrable,
+					InitiallyDeferred: ref.InitiallyDeferred,
+				},
+			}},
+		})
+		return true
+	})
+}
+
+// primaryKeyName returns the name of t's table-level primary key, or "".
+func primaryKeyName(t *MetaTable) string {
+	for _, elem := range t.GetElements() {
+		if tc := elem.GetTableConstraintElement(); tc.GetSpec().GetUniqueItem().GetIsPrimary() {
+			return tc.Name
+		}
+	}
+	return ""
+}
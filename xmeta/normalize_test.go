@@ -0,0 +1,117 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestNormalizeMetaDatabase(t *testing.T) {
+	db := &MetaDatabase{
+		Tables: []*MetaTable{
+			{
+				Name: &ObjectName{Idents: []string{"public", "Orders"}},
+				Elements: []*TableElement{
+					{TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+						Name: "FK_User",
+						Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+							ReferenceItem: &ReferentialTableConstraint{
+								Columns: []string{"User_ID"},
+								KeyExpr: &ReferenceKeyExpr{TableName: "public.Users", Columns: []string{"ID"}},
+							},
+						}},
+					}}},
+					{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: "ID"}}},
+					{TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+						Name: "Orders_PKey",
+						Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+							UniqueItem: &UniqueTableConstraint{IsPrimary: true, Columns: []string{"ID"}},
+						}},
+					}}},
+					{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: "User_ID"}}},
+				},
+			},
+			{Name: &ObjectName{Idents: []string{"audit", "Log"}}},
+		},
+	}
+
+	NormalizeMetaDatabase(db, NormalizeOptions{DefaultSchema: "public", FoldCase: true})
+
+	if objectNameKey(db.Tables[0].Name) != "audit.log" || objectNameKey(db.Tables[1].Name) != "orders" {
+		t.Fatalf("Unexpected table order/names: %v, %v", db.Tables[0].Name, db.Tables[1].Name)
+	}
+
+	var order []string
+	for _, elem := range db.Tables[1].Elements {
+		if col := elem.GetColumnDefElement(); col != nil {
+			order = append(order, col.Name)
+		} else {
+			order = append(order, elem.GetTableConstraintElement().Name)
+		}
+	}
+	if len(order) != 4 || order[0] != "id" || order[1] != "user_id" || order[2] != "fk_user" || order[3] != "orders_pkey" {
+		t.Errorf("Unexpected element order %v", order)
+	}
+
+	fk := constraintsFromElements(db.Tables[1].Elements)["fk_user"].Spec.GetReferenceItem()
+	if fk.Columns[0] != "user_id" || fk.KeyExpr.TableName != "users" || fk.KeyExpr.Columns[0] != "id" {
+		t.Errorf("Unexpected foreign key %v", fk)
+	}
+}
+
+func TestDiffDatabase_Normalize(t *testing.T) {
+	live := &MetaDatabase{Tables: []*MetaTable{{
+		Name: &ObjectName{Idents: []string{"public", "users"}},
+		Elements: []*TableElement{
+			{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: "id"}}},
+		},
+	}}}
+	spec := &MetaDatabase{Tables: []*MetaTable{{
+		Name: &ObjectName{Idents: []string{"Users"}},
+		Elements: []*TableElement{
+			{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: "ID"}}},
+		},
+	}}}
+
+	if changes := DiffDatabase(live, spec); len(changes) == 0 {
+		t.Fatal("Expected changes without normalization")
+	}
+	opts := DiffOptions{Normalize: &NormalizeOptions{DefaultSchema: "public", FoldCase: true}}
+	if changes := DiffDatabaseWithOptions(live, spec, opts); len(changes) != 0 {
+		t.Errorf("Expected no changes after normalization, got %v", changes)
+	}
+	if spec.Tables[0].Name.Idents[0] != "Users" {
+		t.Error("DiffDatabaseWithOptions modified its input")
+	}
+}
+
+func TestDiffDatabase_InlineForeignKeys(t *testing.T) {
+	current, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER,
+  CONSTRAINT orders_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  user_id INTEGER REFERENCES users (id) ON DELETE CASCADE);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changes := DiffDatabase(current, desired); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+
+	// A different target is still a change
+	desired.Tables[1] = canonicalForeignKeys(desired.Tables[1], nil)
+	for _, elem := range desired.Tables[1].Elements {
+		if tc := elem.GetTableConstraintElement(); tc.GetSpec().GetReferenceItem() != nil {
+			tc.GetSpec().GetReferenceItem().OnDelete = ReferentialAction_ReferentialAction_Restrict
+		}
+	}
+	if changes := DiffDatabase(current, desired); len(changes) == 0 {
+		t.Error("Expected a change for a different ON DELETE action")
+	}
+}
@@ -0,0 +1,227 @@
+package xmeta
+
+// openapi.go exports a MetaDatabase as OpenAPI 3 component schemas.
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var openAPINameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// MetaDatabaseToOpenAPISchemas returns an OpenAPI 3.0 components.schemas
+// object for db with one object schema per table, named after the table
+// (schema-qualified names keep their dots). Each column becomes a property
+// typed from its DataType, with format hints such as int64, double, date,
+// date-time and uuid, maxLength for sized text and enum for ENUM values.
+// NOT NULL columns are listed in required, the others are nullable. A
+// column of a foreign key to a table of db is a $ref to the referenced
+// column's property, so both sides share one definition.
+func MetaDatabaseToOpenAPISchemas(db *MetaDatabase) (map[string]any, error) {
+	names := make(map[*MetaTable]string)
+	owners := make(map[string]*MetaTable)
+	for _, t := range db.GetTables() {
+		name := openAPIName(t.Name)
+		if other, ok := owners[name]; ok {
+			return nil, fmt.Errorf("tables %s and %s share the schema name %s", formatObjectName(other.Name), formatObjectName(t.Name), name)
+		}
+		names[t] = name
+		owners[name] = t
+	}
+	lookup := tableLookup(db.GetTables())
+
+	schemas := make(map[string]any)
+	for _, t := range db.GetTables() {
+		refs := openAPIRefs(canonicalForeignKeys(t, nil), lookup, names)
+		properties := make(map[string]any)
+		var required []string
+		pk := tablePrimaryKey(t)
+		for _, col := range orderedColumns(t.Elements) {
+			notNull := isNotNull(col) || slices.Contains(pk, col.Name)
+			prop, err := openAPIProperty(col, refs[col.Name], notNull)
+			if err != nil {
+				return nil, fmt.Errorf("table %s: column %s: %w", formatObjectName(t.Name), col.Name, err)
+			}
+			properties[col.Name] = prop
+			if notNull {
+				required = append(required, col.Name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		if t.Comment != "" {
+			schema["description"] = t.Comment
+		}
+		schemas[names[t]] = schema
+	}
+	return schemas, nil
+}
+
+// openAPIRefs maps the foreign key columns of t to the $ref of the column
+// they reference. Keys to tables outside the database are left out.
+func openAPIRefs(t *MetaTable, lookup func(string) *MetaTable, names map[*MetaTable]string) map[string]string {
+	refs := make(map[string]string)
+	for _, elem := range t.Elements {
+		ref := elem.GetTableConstraintElement().GetSpec().GetReferenceItem()
+		if ref == nil {
+			continue
+		}
+		parent := lookup(ref.GetKeyExpr().GetTableName())
+		if parent == nil {
+			continue
+		}
+		target := ref.GetKeyExpr().GetColumns()
+		if len(target) == 0 {
+			target = tablePrimaryKey(parent)
+		}
+		if len(target) != len(ref.Columns) {
+			continue
+		}
+		for i, col := range ref.Columns {
+			refs[col] = "#/components/schemas/" + names[parent] + "/properties/" + openAPIPointerEscape(target[i])
+		}
+	}
+	return refs
+}
+
+// openAPIProperty returns the schema of col, or a reference to ref when set.
+func openAPIProperty(col *ColumnDef, ref string, notNull bool) (map[string]any, error) {
+	var prop map[string]any
+	if ref != "" {
+		// Siblings of $ref are ignored in OpenAPI 3.0, so extras need allOf
+		prop = map[string]any{"$ref": ref}
+		if notNull && col.Comment == "" {
+			return prop, nil
+		}
+		prop = map[string]any{"allOf": []any{prop}}
+	} else {
+		var err error
+		if prop, err = openAPIType(col.DataType); err != nil {
+			return nil, err
+		}
+	}
+	if !notNull {
+		prop["nullable"] = true
+	}
+	if col.Comment != "" {
+		prop["description"] = col.Comment
+	}
+	return prop, nil
+}
+
+// openAPIType maps a DataType to an OpenAPI schema.
+func openAPIType(dt *DataType) (map[string]any, error) {
+	switch t := dt.GetTypeClause().(type) {
+	case *DataType_TinyIntData, *DataType_SmallIntData, *DataType_MediumIntData, *DataType_YearData:
+		return map[string]any{"type": "integer", "format": "int32"}, nil
+	case *DataType_IntData:
+		if t.IntData.GetIsUnsigned() {
+			return map[string]any{"type": "integer", "format": "int64"}, nil
+		}
+		return map[string]any{"type": "integer", "format": "int32"}, nil
+	case *DataType_BigIntData:
+		return map[string]any{"type": "integer", "format": "int64"}, nil
+	case *DataType_FloatData, *DataType_RealData:
+		return map[string]any{"type": "number", "format": "float"}, nil
+	case *DataType_DoubleData:
+		return map[string]any{"type": "number", "format": "double"}, nil
+	case *DataType_DecimalData:
+		return map[string]any{"type": "number"}, nil
+	case *DataType_BooleanData:
+		return map[string]any{"type": "boolean"}, nil
+	case *DataType_CharData:
+		return openAPIString(t.CharData.GetSize()), nil
+	case *DataType_VarcharData:
+		return openAPIString(t.VarcharData.GetSize()), nil
+	case *DataType_TextData, *DataType_XMLData, *DataType_RegclassData, *DataType_BitData, *DataType_NetworkData:
+		return map[string]any{"type": "string"}, nil
+	case *DataType_ByteaData:
+		return map[string]any{"type": "string", "format": "byte"}, nil
+	case *DataType_UUIDData:
+		return map[string]any{"type": "string", "format": "uuid"}, nil
+	case *DataType_DateData:
+		return map[string]any{"type": "string", "format": "date"}, nil
+	case *DataType_TimeData, *DataType_TimeTypeData:
+		return map[string]any{"type": "string", "format": "time"}, nil
+	case *DataType_IntervalData:
+		return map[string]any{"type": "string"}, nil
+	case *DataType_TimestampData:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case *DataType_JSONData:
+		return map[string]any{}, nil
+	case *DataType_EnumData:
+		return map[string]any{"type": "string", "enum": t.EnumData.GetValues()}, nil
+	case *DataType_SetData:
+		return map[string]any{"type": "array", "uniqueItems": true,
+			"items": map[string]any{"type": "string", "enum": t.SetData.GetValues()}}, nil
+	case *DataType_CollateData:
+		return openAPIType(t.CollateData.GetType())
+	case *DataType_ArrayData:
+		items, err := openAPIType(t.ArrayData.GetType())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case *DataType_StructData:
+		properties := make(map[string]any)
+		var required []string
+		for _, field := range t.StructData.GetFields() {
+			prop, err := openAPIProperty(field, "", isNotNull(field))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			properties[field.Name] = prop
+			if isNotNull(field) {
+				required = append(required, field.Name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema, nil
+	case *DataType_CustomData:
+		return openAPICustomType(formatObjectName(t.CustomData))
+	}
+	return nil, fmt.Errorf("no OpenAPI type for %s", FormatDataType(dt))
+}
+
+// openAPICustomType maps the types loaders keep by name.
+func openAPICustomType(name string) (map[string]any, error) {
+	switch strings.ToUpper(name) {
+	case "NUMERIC", "BIGNUMERIC", "MONEY":
+		return map[string]any{"type": "number"}, nil
+	case "TIMESTAMP", "DATETIME":
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case "DATE":
+		return map[string]any{"type": "string", "format": "date"}, nil
+	case "TIME":
+		return map[string]any{"type": "string", "format": "time"}, nil
+	case "GEOGRAPHY", "INTERVAL", "CITEXT", "INET", "CIDR":
+		return map[string]any{"type": "string"}, nil
+	case "JSON", "JSONB":
+		return map[string]any{}, nil
+	}
+	return nil, fmt.Errorf("no OpenAPI type for %s", name)
+}
+
+func openAPIString(size uint32) map[string]any {
+	if size == 0 {
+		return map[string]any{"type": "string"}
+	}
+	return map[string]any{"type": "string", "maxLength": size}
+}
+
+// openAPIName turns a table name into a valid component name.
+func openAPIName(on *ObjectName) string {
+	return openAPINameUnsafe.ReplaceAllString(objectNameKey(on), "_")
+}
+
+// openAPIPointerEscape escapes s for use in a JSON pointer.
+func openAPIPointerEscape(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
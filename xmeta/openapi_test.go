@@ -0,0 +1,58 @@
+package xmeta
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMetaDatabaseToOpenAPISchemas(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (
+  id BIGINT PRIMARY KEY,
+  email VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP
+);
+CREATE TABLE orders (
+  id BIGINT PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users (id),
+  total DECIMAL(10,2)
+);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, err := MetaDatabaseToOpenAPISchemas(db)
+	if err != nil {
+		t.Fatalf("MetaDatabaseToOpenAPISchemas failed: %v", err)
+	}
+	want := map[string]any{
+		"users": map[string]any{
+			"type":     "object",
+			"required": []any{"id", "email"},
+			"properties": map[string]any{
+				"id":         map[string]any{"type": "integer", "format": "int64"},
+				"email":      map[string]any{"type": "string", "maxLength": 255.0},
+				"created_at": map[string]any{"type": "string", "format": "date-time", "nullable": true},
+			},
+		},
+		"orders": map[string]any{
+			"type":     "object",
+			"required": []any{"id", "user_id"},
+			"properties": map[string]any{
+				"id":      map[string]any{"type": "integer", "format": "int64"},
+				"user_id": map[string]any{"$ref": "#/components/schemas/users/properties/id"},
+				"total":   map[string]any{"type": "number", "nullable": true},
+			},
+		},
+	}
+	gotJSON, _ := json.Marshal(schemas)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Unexpected schemas:\n got %s\nwant %s", gotJSON, wantJSON)
+	}
+
+	db.Tables[0].Elements[0].GetColumnDefElement().DataType = &DataType{TypeClause: &DataType_CustomData{
+		CustomData: &ObjectName{Idents: []string{"tsvector"}}}}
+	if _, err := MetaDatabaseToOpenAPISchemas(db); err == nil {
+		t.Error("Expected an error for a type without OpenAPI mapping")
+	}
+}
@@ -1,17 +1,38 @@
 package xmeta
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
+
+	"google.golang.org/protobuf/proto"
 )
 
 // LoadPostgres metadata into a PGDatabase structure.
 // Requires a connected database.
 func LoadPostgres(db *sql.DB) (*PGDatabase, error) {
+	return LoadPostgresContext(context.Background(), db)
+}
+
+// LoadPostgresContext is LoadPostgres with a context that cancels the catalog queries.
+func LoadPostgresContext(ctx context.Context, db *sql.DB) (*PGDatabase, error) {
+	return LoadPostgresWithFilter(ctx, db, LoadFilter{})
+}
+
+// LoadPostgresWithFilter is LoadPostgresContext restricted to the schemas and
+// tables selected by filter. The filter is applied in the catalog queries.
+func LoadPostgresWithFilter(ctx context.Context, db *sql.DB, filter LoadFilter) (*PGDatabase, error) {
+	return loadPostgres(ctx, db, filter, nil)
+}
+
+// loadPostgres is LoadPostgresWithFilter with the column types mapped by
+// mapper first.
+func loadPostgres(ctx context.Context, db *sql.DB, filter LoadFilter, mapper TypeMapper) (*PGDatabase, error) {
 	// Get Version
 	var version string
-	row := db.QueryRow("SHOW server_version")
+	row := db.QueryRowContext(ctx, "SHOW server_version")
 	if err := row.Scan(&version); err != nil {
 		return nil, fmt.Errorf("failed to get server version: %w", err)
 	}
@@ -23,22 +44,24 @@ func LoadPostgres(db *sql.DB) (*PGDatabase, error) {
 	}
 
 	// Query current database name
-	dbNameRow := db.QueryRow("SELECT current_database()")
+	dbNameRow := db.QueryRowContext(ctx, "SELECT current_database()")
 	if err := dbNameRow.Scan(&pgDB.Name); err != nil {
 		// ignore error, stick to default
 	}
 
 	// Load Schemas
-	schemas, err := loadPGSchemas(db)
+	schemas, err := loadPGSchemas(ctx, db, filter, mapper)
 	if err != nil {
 		return nil, err
 	}
 	pgDB.Schemas = schemas
+	resolvePGEnums(schemas)
+	resolvePGIdentitySequences(schemas)
 
 	return pgDB, nil
 }
 
-func loadPGSchemas(db *sql.DB) ([]*PGSchema, error) {
+func loadPGSchemas(ctx context.Context, db *sql.DB, filter LoadFilter, mapper TypeMapper) ([]*PGSchema, error) {
 	query := `
 		SELECT nspname, 
 		       COALESCE(pg_catalog.pg_get_userbyid(nspowner), '') as owner
@@ -47,7 +70,8 @@ func loadPGSchemas(db *sql.DB) ([]*PGSchema, error) {
 		  AND nspname NOT LIKE 'pg_toast_%'
 		  AND nspname NOT IN ('information_schema')
 	`
-	rows, err := db.Query(query)
+	conds, args := LoadFilter{IncludeSchemas: filter.IncludeSchemas}.sqlConditions("", "nspname", nil, pgPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schemas: %w", err)
 	}
@@ -65,27 +89,249 @@ func loadPGSchemas(db *sql.DB) ([]*PGSchema, error) {
 			Owner: owner,
 		}
 
+		enums, err := loadPGEnums(ctx, db, name)
+		if err != nil {
+			return nil, err
+		}
+		schema.Enums = enums
+
 		// Load Tables for this schema
-		tables, err := loadPGTables(db, name)
+		tables, err := loadPGTables(ctx, db, name, filter, mapper)
 		if err != nil {
 			return nil, err
 		}
 		schema.Tables = tables
 
-		// TODO: Load Views, Sequences
+		views, err := loadPGViews(ctx, db, name, filter)
+		if err != nil {
+			return nil, err
+		}
+		schema.Views = views
+
+		sequences, err := loadPGSequences(ctx, db, name)
+		if err != nil {
+			return nil, err
+		}
+		schema.Sequences = sequences
 
 		schemas = append(schemas, schema)
 	}
 	return schemas, nil
 }
 
-func loadPGTables(db *sql.DB, schemaName string) ([]*PGTable, error) {
+// loadPGViews loads the views and materialized views of a schema. The check
+// option and security_barrier are read from the view's reloptions.
+func loadPGViews(ctx context.Context, db *sql.DB, schemaName string, filter LoadFilter) ([]*PGView, error) {
+	query := `
+		SELECT c.relname,
+		       COALESCE(pg_catalog.pg_get_userbyid(c.relowner), ''),
+		       COALESCE(pg_catalog.pg_get_viewdef(c.oid), ''),
+		       c.relkind = 'm',
+		       COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), ''),
+		       COALESCE((SELECT upper(split_part(o, '=', 2)) FROM unnest(c.reloptions) o
+		                 WHERE o LIKE 'check_option=%'), ''),
+		       COALESCE((SELECT split_part(o, '=', 2) IN ('true', 'on', '1') FROM unnest(c.reloptions) o
+		                 WHERE o LIKE 'security_barrier=%'), false)
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('v', 'm')
+	`
+	conds, args := LoadFilter{IncludeTables: filter.IncludeTables, ExcludeTables: filter.ExcludeTables}.
+		sqlConditions("c.relname", "", []any{schemaName}, pgPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds+" ORDER BY c.relname", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views for schema %s: %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var views []*PGView
+	for rows.Next() {
+		view := &PGView{}
+		var name string
+		if err := rows.Scan(&name, &view.Owner, &view.Definition, &view.IsMaterialized, &view.Comment, &view.CheckOption, &view.SecurityBarrier); err != nil {
+			return nil, err
+		}
+		view.Name = &ObjectName{Idents: []string{schemaName, name}}
+		view.Definition = strings.TrimSuffix(strings.TrimSpace(view.Definition), ";")
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// loadPGEnums loads the enum types of a schema with their labels in sort
+// order.
+func loadPGEnums(ctx context.Context, db *sql.DB, schemaName string) ([]*PGEnum, error) {
+	query := `
+		SELECT t.typname, e.enumlabel,
+		       COALESCE(pg_catalog.obj_description(t.oid, 'pg_type'), '')
+		FROM pg_catalog.pg_type t
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+		JOIN pg_catalog.pg_enum e ON e.enumtypid = t.oid
+		WHERE n.nspname = $1
+		ORDER BY t.typname, e.enumsortorder
+	`
+	rows, err := db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query enum types for schema %s: %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var enums []*PGEnum
+	for rows.Next() {
+		var name, label, comment string
+		if err := rows.Scan(&name, &label, &comment); err != nil {
+			return nil, err
+		}
+		if n := len(enums); n == 0 || enums[n-1].Name.Idents[1] != name {
+			enums = append(enums, &PGEnum{
+				Name:    &ObjectName{Idents: []string{schemaName, name}},
+				Comment: comment,
+			})
+		}
+		last := enums[len(enums)-1]
+		last.Labels = append(last.Labels, label)
+	}
+	return enums, rows.Err()
+}
+
+// resolvePGEnums replaces the user-defined column types that name a loaded
+// enum type, element types of arrays included, with EnumData carrying the
+// type name and labels. Enums of schemas outside the filter stay custom.
+func resolvePGEnums(schemas []*PGSchema) {
+	enums := make(map[string]*PGEnum)
+	for _, schema := range schemas {
+		for _, enum := range schema.Enums {
+			enums[objectNameKey(enum.Name)] = enum
+		}
+	}
+	if len(enums) == 0 {
+		return
+	}
+
+	resolve := func(dt *DataType) {
+		if elem := dt.GetArrayData().GetType(); elem != nil {
+			dt = elem
+		}
+		if enum, ok := enums[objectNameKey(dt.GetCustomData())]; ok {
+			dt.TypeClause = &DataType_EnumData{EnumData: &EnumType{
+				Values:   slices.Clone(enum.Labels),
+				TypeName: proto.Clone(enum.Name).(*ObjectName),
+			}}
+		}
+	}
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			for _, col := range table.Columns {
+				resolve(col.DataType)
+			}
+		}
+		for _, view := range schema.Views {
+			for _, col := range view.Columns {
+				resolve(col.DataType)
+			}
+		}
+	}
+}
+
+// loadPGSequences loads the sequences of a schema with their options and
+// the column owning them, if any: the serial or identity column whose
+// sequence it is, or the column it was made OWNED BY.
+func loadPGSequences(ctx context.Context, db *sql.DB, schemaName string) ([]*PGSequence, error) {
+	query := `
+		SELECT c.relname, pg_catalog.format_type(s.seqtypid, NULL),
+		       s.seqstart, s.seqmin, s.seqmax, s.seqincrement, s.seqcycle, s.seqcache,
+		       COALESCE(tn.nspname, ''), COALESCE(t.relname, ''), COALESCE(a.attname, ''),
+		       COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '')
+		FROM pg_catalog.pg_sequence s
+		JOIN pg_catalog.pg_class c ON c.oid = s.seqrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_depend d ON d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid
+		 AND d.refclassid = 'pg_catalog.pg_class'::regclass AND d.deptype IN ('a', 'i')
+		LEFT JOIN pg_catalog.pg_class t ON t.oid = d.refobjid
+		LEFT JOIN pg_catalog.pg_namespace tn ON tn.oid = t.relnamespace
+		LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+		WHERE n.nspname = $1
+		ORDER BY c.relname
+	`
+	rows, err := db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sequences for schema %s: %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var sequences []*PGSequence
+	for rows.Next() {
+		var name, typ, ownerSchema, ownerTable, ownerColumn, comment string
+		seq := &PGSequence{}
+		if err := rows.Scan(&name, &typ, &seq.StartValue, &seq.MinValue, &seq.MaxValue, &seq.IncrementBy,
+			&seq.Cycle, &seq.CacheSize, &ownerSchema, &ownerTable, &ownerColumn, &comment); err != nil {
+			return nil, err
+		}
+		seq.Name = &ObjectName{Idents: []string{schemaName, name}}
+		seq.DataType = parseSQLDataType(typ)
+		seq.Comment = comment
+		if ownerTable != "" {
+			seq.OwnerTable = &ObjectName{Idents: []string{ownerSchema, ownerTable}}
+			seq.OwnerColumn = ownerColumn
+		}
+		sequences = append(sequences, seq)
+	}
+	return sequences, rows.Err()
+}
+
+// resolvePGIdentitySequences copies the start and increment of each loaded
+// identity sequence to the identity column owning it.
+func resolvePGIdentitySequences(schemas []*PGSchema) {
+	sequences := make(map[string]*PGSequence)
+	for _, schema := range schemas {
+		for _, seq := range schema.Sequences {
+			if seq.OwnerTable != nil {
+				sequences[objectNameKey(seq.OwnerTable)+"."+seq.OwnerColumn] = seq
+			}
+		}
+	}
+	if len(sequences) == 0 {
+		return
+	}
+
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			for _, col := range table.Columns {
+				if seq, ok := sequences[objectNameKey(table.Name)+"."+col.Name]; ok && col.IsIdentity {
+					col.IdentityStart = seq.StartValue
+					col.IdentityIncrement = seq.IncrementBy
+				}
+			}
+		}
+	}
+}
+
+func loadPGTables(ctx context.Context, db *sql.DB, schemaName string, filter LoadFilter, mapper TypeMapper) ([]*PGTable, error) {
+	// Partitioned tables report their key; partitions their parent and bound.
+	// Other pg_inherits rows are INHERITS parents, listed in declaration order.
 	query := `
-		SELECT tablename, tableowner
-	    FROM pg_catalog.pg_tables
-		WHERE schemaname = $1
+		SELECT t.tablename, t.tableowner,
+		       COALESCE(pg_catalog.pg_get_partkeydef(c.oid), ''),
+		       COALESCE(pn.nspname, ''), COALESCE(p.relname, ''),
+		       COALESCE(pg_catalog.pg_get_expr(c.relpartbound, c.oid), ''),
+		       COALESCE((SELECT string_agg(hn.nspname || '.' || h.relname, ',' ORDER BY hi.inhseqno)
+		                 FROM pg_catalog.pg_inherits hi
+		                 JOIN pg_catalog.pg_class h ON h.oid = hi.inhparent
+		                 JOIN pg_catalog.pg_namespace hn ON hn.oid = h.relnamespace
+		                 WHERE hi.inhrelid = c.oid AND NOT c.relispartition), ''),
+		       COALESCE(t.tablespace, ''),
+		       COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '')
+	    FROM pg_catalog.pg_tables t
+		JOIN pg_catalog.pg_namespace n ON n.nspname = t.schemaname
+		JOIN pg_catalog.pg_class c ON c.relnamespace = n.oid AND c.relname = t.tablename
+		LEFT JOIN pg_catalog.pg_inherits i ON c.relispartition AND i.inhrelid = c.oid
+		LEFT JOIN pg_catalog.pg_class p ON p.oid = i.inhparent
+		LEFT JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace
+		WHERE t.schemaname = $1
 	`
-	rows, err := db.Query(query, schemaName)
+	conds, args := LoadFilter{IncludeTables: filter.IncludeTables, ExcludeTables: filter.ExcludeTables}.
+		sqlConditions("t.tablename", "", []any{schemaName}, pgPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables for schema %s: %w", schemaName, err)
 	}
@@ -93,8 +339,8 @@ func loadPGTables(db *sql.DB, schemaName string) ([]*PGTable, error) {
 
 	var tables []*PGTable
 	for rows.Next() {
-		var name, owner string
-		if err := rows.Scan(&name, &owner); err != nil {
+		var name, owner, partKey, parentSchema, parentName, partBound, inherits, tablespace, comment string
+		if err := rows.Scan(&name, &owner, &partKey, &parentSchema, &parentName, &partBound, &inherits, &tablespace, &comment); err != nil {
 			return nil, err
 		}
 
@@ -102,30 +348,76 @@ func loadPGTables(db *sql.DB, schemaName string) ([]*PGTable, error) {
 			Name: &ObjectName{
 				Idents: []string{schemaName, name},
 			},
-			Owner:     owner,
-			TableType: "BASE TABLE", // Approximation for now
+			Owner:          owner,
+			TableType:      "BASE TABLE", // Approximation for now
+			PartitionKey:   partKey,
+			PartitionBound: partBound,
+			Tablespace:     tablespace,
+			Comment:        comment,
+		}
+		if parentName != "" {
+			table.PartitionOf = &ObjectName{Idents: []string{parentSchema, parentName}}
+		}
+		if inherits != "" {
+			for _, parent := range strings.Split(inherits, ",") {
+				table.InheritsFrom = append(table.InheritsFrom, &ObjectName{Idents: strings.SplitN(parent, ".", 2)})
+			}
 		}
 
 		// Load Columns
-		cols, err := loadPGColumns(db, schemaName, name)
+		cols, err := loadPGColumns(ctx, db, schemaName, name, mapper)
 		if err != nil {
 			return nil, err
 		}
 		table.Columns = cols
 
+		// Load Constraints
+		constraints, err := loadPGConstraints(ctx, db, schemaName, name)
+		if err != nil {
+			return nil, err
+		}
+		table.Constraints = constraints
+
+		// Load Foreign Keys
+		fks, err := loadPGForeignKeys(ctx, db, schemaName, name)
+		if err != nil {
+			return nil, err
+		}
+		table.ForeignKeys = fks
+
+		// Load Indexes
+		indexes, err := loadPGIndexes(ctx, db, schemaName, name)
+		if err != nil {
+			return nil, err
+		}
+		table.Indexes = indexes
+
 		tables = append(tables, table)
 	}
 	return tables, nil
 }
 
-func loadPGColumns(db *sql.DB, schemaName, tableName string) ([]*PGColumn, error) {
+func loadPGColumns(ctx context.Context, db *sql.DB, schemaName, tableName string, mapper TypeMapper) ([]*PGColumn, error) {
+	// information_schema carries identity and generation metadata; comments
+	// live in pg_description, keyed by the table oid and attribute number.
 	query := `
-		SELECT column_name, data_type, is_nullable, column_default, ordinal_position
-		FROM information_schema.columns
-		WHERE table_schema = $1 AND table_name = $2
-		ORDER BY ordinal_position
+		SELECT c.column_name, c.data_type, c.is_nullable, c.column_default, c.ordinal_position,
+		       c.is_identity, c.identity_generation,
+		       CASE WHEN c.is_identity = 'YES'
+		            THEN pg_get_serial_sequence(quote_ident(c.table_schema) || '.' || quote_ident(c.table_name), c.column_name)
+		       END,
+		       c.is_generated, c.generation_expression, d.description, c.udt_schema, c.udt_name,
+		       a.atttypmod, c.interval_type, pg_catalog.format_type(a.atttypid, a.atttypmod),
+		       c.numeric_precision, c.numeric_scale, c.character_maximum_length
+		FROM information_schema.columns c
+		JOIN pg_catalog.pg_namespace n ON n.nspname = c.table_schema
+		JOIN pg_catalog.pg_class cl ON cl.relnamespace = n.oid AND cl.relname = c.table_name
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = cl.oid AND a.attname = c.column_name
+		LEFT JOIN pg_catalog.pg_description d ON d.objoid = cl.oid AND d.objsubid = a.attnum
+		WHERE c.table_schema = $1 AND c.table_name = $2
+		ORDER BY c.ordinal_position
 	`
-	rows, err := db.Query(query, schemaName, tableName)
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query columns: %w", err)
 	}
@@ -133,31 +425,300 @@ func loadPGColumns(db *sql.DB, schemaName, tableName string) ([]*PGColumn, error
 
 	var cols []*PGColumn
 	for rows.Next() {
-		var name, dataType, isNullableStr string
-		var defaultVal sql.NullString
-		var pos int32
+		var name, dataType, isNullableStr, isIdentity, isGenerated, udtSchema, udtName string
+		var defaultVal, identityGen, identitySeq, genExpr, comment, intervalType, rawType sql.NullString
+		var pos, typmod int32
+		var precision, scale, length sql.NullInt64
 
-		if err := rows.Scan(&name, &dataType, &isNullableStr, &defaultVal, &pos); err != nil {
+		if err := rows.Scan(&name, &dataType, &isNullableStr, &defaultVal, &pos,
+			&isIdentity, &identityGen, &identitySeq, &isGenerated, &genExpr, &comment, &udtSchema, &udtName,
+			&typmod, &intervalType, &rawType, &precision, &scale, &length); err != nil {
 			return nil, err
 		}
 
+		// User-defined types keep their schema, so enum types can be
+		// resolved; built-in array element types live in pg_catalog
+		dt := mapType(mapper, rawType.String, precision.Int64, scale.Int64, length.Int64)
+		if dt == nil {
+			dt = mapPostgresTypeForProto(dataType, udtName)
+			if strings.EqualFold(dataType, "USER-DEFINED") {
+				dt.TypeClause = &DataType_CustomData{CustomData: &ObjectName{Idents: []string{udtSchema, udtName}}}
+			} else if elem := dt.GetArrayData().GetType().GetCustomData(); elem != nil && udtSchema != "pg_catalog" {
+				elem.Idents = []string{udtSchema, strings.TrimPrefix(udtName, "_")}
+			}
+			applyPGTypmod(dt, typmod, intervalType.String)
+			applyPGSize(dt, length.Int64, precision.Int64, scale.Int64)
+		}
+
 		col := &PGColumn{
 			Name:            name,
-			DataType:        mapPostgresTypeForProto(dataType),
+			DataType:        dt,
 			IsNullable:      (strings.ToUpper(isNullableStr) == "YES"),
 			DefaultValue:    defaultVal.String,
 			OrdinalPosition: pos,
+			Comment:         comment.String,
+		}
+		if strings.ToUpper(isIdentity) == "YES" {
+			col.IsIdentity = true
+			col.IdentityGeneration = identityGen.String
+			col.IdentitySequence = identitySeq.String
+		}
+		// is_generated is "ALWAYS" for generated columns, "NEVER" otherwise
+		if strings.ToUpper(isGenerated) == "ALWAYS" {
+			col.IsGenerated = true
+			col.GenerationExpression = genExpr.String
 		}
 		cols = append(cols, col)
 	}
 	return cols, nil
 }
 
-func mapPostgresTypeForProto(pgType string) *DataType {
+// loadPGConstraints loads primary key, unique, check and exclusion
+// constraints. Foreign keys are loaded by loadPGForeignKeys.
+func loadPGConstraints(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*PGConstraint, error) {
+	query := `
+		SELECT con.conname, con.contype,
+		       COALESCE((SELECT string_agg(a.attname, ',' ORDER BY k.ord)
+		                 FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		                 JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum), ''),
+		       pg_get_constraintdef(con.oid), con.condeferrable, con.condeferred,
+		       obj_description(con.oid, 'pg_constraint'), COALESCE(ic.relname, ''),
+		       COALESCE((to_jsonb(i) ->> 'indnullsnotdistinct')::boolean, false)
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+		LEFT JOIN pg_catalog.pg_class ic ON ic.oid = con.conindid
+		LEFT JOIN pg_catalog.pg_index i ON i.indexrelid = con.conindid
+		WHERE n.nspname = $1 AND cl.relname = $2 AND con.contype IN ('p', 'u', 'c', 'x')
+		ORDER BY con.conname
+	`
+	// pg_index.indnullsnotdistinct is new in Postgres 15; reading it
+	// through to_jsonb keeps the query valid on older servers
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var constraints []*PGConstraint
+	for rows.Next() {
+		var name, conType, columns, definition, indexName string
+		var deferrable, deferred, nullsNotDistinct bool
+		var comment sql.NullString
+
+		if err := rows.Scan(&name, &conType, &columns, &definition, &deferrable, &deferred, &comment, &indexName, &nullsNotDistinct); err != nil {
+			return nil, err
+		}
+
+		con := &PGConstraint{
+			Name:         name,
+			TableName:    &ObjectName{Idents: []string{schemaName, tableName}},
+			Type:         conType,
+			Definition:   definition,
+			Comment:      comment.String,
+			IsDeferrable: deferrable,
+			IsDeferred:   deferred,
+			IndexName:    indexName,
+		}
+		if conType == "u" {
+			con.NullsNotDistinct = nullsNotDistinct
+		}
+		if columns != "" {
+			con.Columns = strings.Split(columns, ",")
+		}
+		constraints = append(constraints, con)
+	}
+	return constraints, nil
+}
+
+// pgReferentialActions maps pg_constraint's confupdtype and confdeltype
+// codes to the actions they stand for. NO ACTION, the default, maps to ""
+// so a key loaded from the catalog matches one declared without ON DELETE
+// or ON UPDATE.
+var pgReferentialActions = map[string]string{"r": "RESTRICT", "c": "CASCADE", "n": "SET NULL", "d": "SET DEFAULT"}
+
+// pgMatchOptions maps pg_constraint's confmatchtype codes. MATCH SIMPLE,
+// the default, maps to "" likewise.
+var pgMatchOptions = map[string]string{"f": "FULL", "p": "PARTIAL"}
+
+// loadPGForeignKeys loads the foreign keys of a table, with their local and
+// referenced columns in key order.
+func loadPGForeignKeys(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*PGForeignKey, error) {
+	query := `
+		SELECT con.conname,
+		       COALESCE((SELECT string_agg(a.attname, ',' ORDER BY k.ord)
+		                 FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		                 JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum), ''),
+		       fn.nspname, fc.relname,
+		       COALESCE((SELECT string_agg(a.attname, ',' ORDER BY k.ord)
+		                 FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+		                 JOIN pg_catalog.pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum), ''),
+		       con.confupdtype, con.confdeltype, con.confmatchtype,
+		       pg_get_constraintdef(con.oid), obj_description(con.oid, 'pg_constraint')
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+		JOIN pg_catalog.pg_class fc ON fc.oid = con.confrelid
+		JOIN pg_catalog.pg_namespace fn ON fn.oid = fc.relnamespace
+		WHERE n.nspname = $1 AND cl.relname = $2 AND con.contype = 'f'
+		ORDER BY con.conname
+	`
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var fks []*PGForeignKey
+	for rows.Next() {
+		var name, local, foreignSchema, foreignTable, foreign, onUpdate, onDelete, match, definition string
+		var comment sql.NullString
+		if err := rows.Scan(&name, &local, &foreignSchema, &foreignTable, &foreign,
+			&onUpdate, &onDelete, &match, &definition, &comment); err != nil {
+			return nil, err
+		}
+		fk := &PGForeignKey{
+			Name:         name,
+			TableName:    &ObjectName{Idents: []string{schemaName, tableName}},
+			ForeignTable: &ObjectName{Idents: []string{foreignSchema, foreignTable}},
+			OnUpdate:     pgReferentialActions[onUpdate],
+			OnDelete:     pgReferentialActions[onDelete],
+			MatchOption:  pgMatchOptions[match],
+			Definition:   definition,
+			Comment:      comment.String,
+		}
+		if local != "" {
+			fk.LocalColumns = strings.Split(local, ",")
+		}
+		if foreign != "" {
+			fk.ForeignColumns = strings.Split(foreign, ",")
+		}
+		fks = append(fks, fk)
+	}
+	return fks, rows.Err()
+}
+
+// loadPGIndexes loads the indexes that do not back a constraint. Expression
+// keys and partial-index predicates are parsed from pg_get_indexdef.
+func loadPGIndexes(ctx context.Context, db *sql.DB, schemaName, tableName string) ([]*PGIndex, error) {
+	query := `
+		SELECT ic.relname, i.indisunique, i.indisprimary, i.indisclustered, i.indisvalid, am.amname,
+		       pg_get_indexdef(i.indexrelid), obj_description(i.indexrelid, 'pg_class')
+		FROM pg_catalog.pg_index i
+		JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid
+		JOIN pg_catalog.pg_class cl ON cl.oid = i.indrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+		JOIN pg_catalog.pg_am am ON am.oid = ic.relam
+		WHERE n.nspname = $1 AND cl.relname = $2
+		  AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conindid = i.indexrelid)
+		ORDER BY ic.relname
+	`
+	rows, err := db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []*PGIndex
+	for rows.Next() {
+		var name, method, definition string
+		var unique, primary, clustered, valid bool
+		var comment sql.NullString
+
+		if err := rows.Scan(&name, &unique, &primary, &clustered, &valid, &method, &definition, &comment); err != nil {
+			return nil, err
+		}
+
+		idx := &PGIndex{
+			Name:         name,
+			TableName:    &ObjectName{Idents: []string{schemaName, tableName}},
+			IsUnique:     unique,
+			IsPrimary:    primary,
+			IsClustered:  clustered,
+			IsValid:      valid,
+			AccessMethod: method,
+			Definition:   definition,
+			Comment:      comment.String,
+		}
+		parsed, err := parseIndexDefinition(definition)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", name, err)
+		}
+		idx.Columns = parsed.Columns
+		idx.Expression = parsed.Expression
+		idx.Predicate = parsed.Predicate
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+// pgIntervalFullPrecision is the precision part of an interval typmod
+// that declares no precision.
+const pgIntervalFullPrecision = 0xFFFF
+
+// applyPGTypmod sets the declared fractional seconds precision of a
+// temporal column from pg_attribute.atttypmod, which is -1 when none was
+// declared, and the fields of an interval from information_schema's
+// interval_type. Array element types are handled alike.
+func applyPGTypmod(dt *DataType, typmod int32, intervalFields string) {
+	if elem := dt.GetArrayData().GetType(); elem != nil {
+		dt = elem
+	}
+	var precision *uint32
+	if typmod >= 0 {
+		p := uint32(typmod)
+		precision = &p
+	}
+	switch t := dt.TypeClause.(type) {
+	case *DataType_TimestampData:
+		t.TimestampData.Precision = precision
+	case *DataType_TimeData:
+		if precision != nil {
+			dt.TypeClause = &DataType_TimeTypeData{TimeTypeData: &TimeType{Precision: precision}}
+		}
+	case *DataType_TimeTypeData:
+		t.TimeTypeData.Precision = precision
+	case *DataType_IntervalData:
+		// An interval typmod packs the fields above the precision
+		t.IntervalData.Fields = strings.ToUpper(intervalFields)
+		if typmod >= 0 && typmod&pgIntervalFullPrecision != pgIntervalFullPrecision {
+			p := uint32(typmod & pgIntervalFullPrecision)
+			t.IntervalData.Precision = &p
+		}
+	}
+}
+
+// applyPGSize sets the declared length of a character column and the
+// precision and scale of a numeric one from information_schema's
+// character_maximum_length, numeric_precision and numeric_scale, which are
+// null when none was declared. Other types are left alone, as
+// numeric_precision is also reported for integer and float columns.
+func applyPGSize(dt *DataType, length, precision, scale int64) {
+	switch t := dt.TypeClause.(type) {
+	case *DataType_VarcharData:
+		t.VarcharData.Size = uint32(length)
+	case *DataType_CharData:
+		t.CharData.Size = uint32(length)
+	case *DataType_DecimalData:
+		t.DecimalData.Precision = uint32(precision)
+		t.DecimalData.Scale = uint32(scale)
+	}
+}
+
+// mapPostgresTypeForProto maps an information_schema data_type. Arrays are
+// reported as "ARRAY" with the element type in udt_name, prefixed by "_".
+func mapPostgresTypeForProto(pgType, udtName string) *DataType {
 	// Simple mapping
 	t := &DataType{}
 	pgType = strings.ToLower(pgType)
 
+	if pgType == "array" && strings.HasPrefix(udtName, "_") {
+		t.TypeClause = &DataType_ArrayData{ArrayData: &ArrayData{
+			Type: mapPostgresTypeForProto(udtName[1:], ""),
+		}}
+		return t
+	}
+
 	switch pgType {
 	case "integer", "int", "int4":
 		t.TypeClause = &DataType_IntData{IntData: &Int{}}
@@ -167,15 +728,30 @@ func mapPostgresTypeForProto(pgType string) *DataType {
 		t.TypeClause = &DataType_SmallIntData{SmallIntData: &SmallInt{}}
 	case "boolean", "bool":
 		t.TypeClause = &DataType_BooleanData{BooleanData: DataTypeSingle_Boolean}
-	case "text", "varchar", "character varying":
+	case "text":
 		t.TypeClause = &DataType_TextData{TextData: DataTypeSingle_Text}
+	case "character varying", "varchar":
+		t.TypeClause = &DataType_VarcharData{VarcharData: &VarcharType{}}
+	case "character", "bpchar":
+		t.TypeClause = &DataType_CharData{CharData: &CharType{}}
+	case "numeric":
+		t.TypeClause = &DataType_DecimalData{DecimalData: &Decimal{}}
 	case "timestamp", "timestamp without time zone":
 		t.TypeClause = &DataType_TimestampData{TimestampData: &Timestamp{WithTimeZone: false}}
 	case "timestamptz", "timestamp with time zone":
 		t.TypeClause = &DataType_TimestampData{TimestampData: &Timestamp{WithTimeZone: true}}
+	case "date":
+		t.TypeClause = &DataType_DateData{DateData: DataTypeSingle_Date}
+	case "time", "time without time zone":
+		t.TypeClause = &DataType_TimeData{TimeData: DataTypeSingle_Time}
+	case "timetz", "time with time zone":
+		t.TypeClause = &DataType_TimeTypeData{TimeTypeData: &TimeType{WithTimeZone: true}}
+	case "interval":
+		t.TypeClause = &DataType_IntervalData{IntervalData: &IntervalType{}}
 	default:
-		// Fallback to custom
-		t.TypeClause = &DataType_CustomData{CustomData: &ObjectName{Idents: []string{pgType}}}
+		// Read the rest as a schema file would spell them, so the two
+		// compare equal; unknown names are kept as custom types
+		return parseSQLDataType(pgType)
 	}
 	return t
 }
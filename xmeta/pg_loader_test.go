@@ -0,0 +1,158 @@
+package xmeta
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMapPostgresTypeForProto_Array(t *testing.T) {
+	dt := mapPostgresTypeForProto("ARRAY", "_int4")
+	if dt.GetArrayData().GetType().GetIntData() == nil {
+		t.Fatalf("Expected integer array, got %v", dt)
+	}
+	if got, _ := dataTypeSQL(dt, DialectPostgres); got != "INTEGER[]" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+
+	// Matches a BigQuery REPEATED field of the same element type
+	repeated := mapBQType(&bigquery.FieldSchema{Name: "tags", Type: bigquery.StringFieldType, Repeated: true})
+	if text := mapPostgresTypeForProto("ARRAY", "_text"); !proto.Equal(text, repeated) {
+		t.Errorf("Expected %v to equal %v", text, repeated)
+	}
+
+	if dt := mapPostgresTypeForProto("integer", "int4"); dt.GetIntData() == nil {
+		t.Errorf("Expected plain integer, got %v", dt)
+	}
+}
+
+func TestResolvePGEnums(t *testing.T) {
+	custom := func(idents ...string) *DataType {
+		return &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: idents}}}
+	}
+	schemas := []*PGSchema{{
+		Name:  "public",
+		Enums: []*PGEnum{{Name: &ObjectName{Idents: []string{"public", "mood"}}, Labels: []string{"sad", "happy"}}},
+		Tables: []*PGTable{{
+			Name: &ObjectName{Idents: []string{"public", "people"}},
+			Columns: []*PGColumn{
+				{Name: "mood", DataType: custom("public", "mood")},
+				{Name: "history", DataType: &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: custom("public", "mood")}}}},
+				{Name: "location", DataType: custom("public", "geometry")},
+			},
+		}},
+	}}
+	resolvePGEnums(schemas)
+
+	cols := schemas[0].Tables[0].Columns
+	enum := cols[0].DataType.GetEnumData()
+	if enum == nil || formatObjectName(enum.TypeName) != "public.mood" || len(enum.Values) != 2 || enum.Values[1] != "happy" {
+		t.Fatalf("Expected the mood enum, got %v", cols[0].DataType)
+	}
+	if cols[1].DataType.GetArrayData().GetType().GetEnumData() == nil {
+		t.Errorf("Expected an enum array, got %v", cols[1].DataType)
+	}
+	if cols[2].DataType.GetCustomData() == nil {
+		t.Errorf("Expected geometry to stay custom, got %v", cols[2].DataType)
+	}
+
+	if got, _ := dataTypeSQL(cols[0].DataType, DialectPostgres); got != "public.mood" {
+		t.Errorf("Unexpected Postgres type %q", got)
+	}
+	if got, _ := dataTypeSQL(cols[0].DataType, DialectMySQL); got != "ENUM('sad', 'happy')" {
+		t.Errorf("Unexpected MySQL type %q", got)
+	}
+}
+
+func TestApplyPGTypmod(t *testing.T) {
+	ts := mapPostgresTypeForProto("timestamp with time zone", "timestamptz")
+	applyPGTypmod(ts, 3, "")
+	if got, _ := dataTypeSQL(ts, DialectPostgres); got != "TIMESTAMP(3) WITH TIME ZONE" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+
+	plain := mapPostgresTypeForProto("time without time zone", "time")
+	applyPGTypmod(plain, -1, "")
+	if plain.GetTimeData() != DataTypeSingle_Time {
+		t.Errorf("Expected a plain time, got %v", plain)
+	}
+
+	// DAY TO SECOND is range bits 0x7c00 above a precision of 3
+	interval := mapPostgresTypeForProto("interval", "interval")
+	applyPGTypmod(interval, 0x7c00<<16|3, "day to second")
+	if got, _ := dataTypeSQL(interval, DialectPostgres); got != "INTERVAL DAY TO SECOND(3)" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+	unbounded := mapPostgresTypeForProto("interval", "interval")
+	applyPGTypmod(unbounded, 0x7fff<<16|pgIntervalFullPrecision, "")
+	if got, _ := dataTypeSQL(unbounded, DialectPostgres); got != "INTERVAL" {
+		t.Errorf("Unexpected SQL type %q", got)
+	}
+}
+
+func TestMapPostgresTypeForProto_JSONAndNetwork(t *testing.T) {
+	if dt := mapPostgresTypeForProto("jsonb", "jsonb"); dt.GetJSONData() != DataTypeSingle_JSONB {
+		t.Errorf("Expected jsonb, got %v", dt)
+	}
+	if dt := mapPostgresTypeForProto("uuid", "uuid"); dt.GetUUIDData() != DataTypeSingle_UUID {
+		t.Errorf("Expected uuid, got %v", dt)
+	}
+	if dt := mapPostgresTypeForProto("inet", "inet"); dt.GetNetworkData().GetKind() != NetworkKind_NetworkKind_Inet {
+		t.Errorf("Expected inet, got %v", dt)
+	}
+
+	// MySQL and SQLite JSON load as the same type as Postgres json
+	pg := mapPostgresTypeForProto("json", "json")
+	if my := mapMySQLTypeForProto("json", "json", 0, 0, 0); !proto.Equal(pg, my) {
+		t.Errorf("Expected MySQL json to equal Postgres json, got %v", my)
+	}
+	if lite := mapSQLiteTypeForProto("JSON"); !proto.Equal(pg, lite) {
+		t.Errorf("Expected SQLite JSON to equal Postgres json, got %v", lite)
+	}
+
+	jsonb := mapPostgresTypeForProto("jsonb", "jsonb")
+	for dialect, want := range map[Dialect]string{DialectPostgres: "JSONB", DialectMySQL: "JSON", DialectSQLite: "TEXT"} {
+		if got, _ := dataTypeSQL(jsonb, dialect); got != want {
+			t.Errorf("Expected %s for %s, got %s", want, dialect, got)
+		}
+	}
+	if got, _ := dataTypeSQL(mapPostgresTypeForProto("cidr", "cidr"), DialectMySQL); got != "VARCHAR(43)" {
+		t.Errorf("Unexpected MySQL type %q", got)
+	}
+}
+
+func TestResolvePGIdentitySequences(t *testing.T) {
+	users := &ObjectName{Idents: []string{"public", "users"}}
+	schemas := []*PGSchema{{
+		Name: "public",
+		Tables: []*PGTable{{
+			Name: users,
+			Columns: []*PGColumn{
+				{Name: "id", IsIdentity: true, IdentityGeneration: "ALWAYS"},
+				{Name: "serial_no"},
+			},
+		}},
+		Sequences: []*PGSequence{
+			{Name: &ObjectName{Idents: []string{"public", "users_id_seq"}}, StartValue: 1000, IncrementBy: 10, OwnerTable: users, OwnerColumn: "id"},
+			{Name: &ObjectName{Idents: []string{"public", "users_serial_no_seq"}}, StartValue: 5, IncrementBy: 1, OwnerTable: users, OwnerColumn: "serial_no"},
+		},
+	}}
+	resolvePGIdentitySequences(schemas)
+
+	cols := schemas[0].Tables[0].Columns
+	if cols[0].IdentityStart != 1000 || cols[0].IdentityIncrement != 10 {
+		t.Errorf("Expected START 1000 INCREMENT 10 on the identity column, got %d, %d", cols[0].IdentityStart, cols[0].IdentityIncrement)
+	}
+	if cols[1].IdentityIncrement != 0 {
+		t.Errorf("Expected a serial column to stay without identity options, got %d", cols[1].IdentityIncrement)
+	}
+
+	col := PGColumnToColumnDef(cols[0])
+	if col.Options["IdentityStart"] != "1000" || col.Options["IdentityIncrement"] != "10" {
+		t.Errorf("Expected identity options in Options, got %v", col.Options)
+	}
+	if got := generatedSQL(col, DialectPostgres); got != "GENERATED ALWAYS AS IDENTITY (START WITH 1000 INCREMENT BY 10)" {
+		t.Errorf("Unexpected identity clause: %s", got)
+	}
+}
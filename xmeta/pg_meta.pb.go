@@ -35,7 +35,9 @@ type PGColumn struct {
 	IsGenerated          bool                   `protobuf:"varint,12,opt,name=IsGenerated,proto3" json:"IsGenerated,omitempty"`
 	GenerationExpression string                 `protobuf:"bytes,13,opt,name=GenerationExpression,proto3" json:"GenerationExpression,omitempty"`
 	Comment              string                 `protobuf:"bytes,14,opt,name=Comment,proto3" json:"Comment,omitempty"`
-	IsPrimaryKey         bool                   `protobuf:"varint,15,opt,name=IsPrimaryKey,proto3" json:"IsPrimaryKey,omitempty"` // Column is part of primary key
+	IsPrimaryKey         bool                   `protobuf:"varint,15,opt,name=IsPrimaryKey,proto3" json:"IsPrimaryKey,omitempty"`           // Column is part of primary key
+	IdentityStart        int64                  `protobuf:"varint,16,opt,name=IdentityStart,proto3" json:"IdentityStart,omitempty"`         // START WITH of the identity sequence
+	IdentityIncrement    int64                  `protobuf:"varint,17,opt,name=IdentityIncrement,proto3" json:"IdentityIncrement,omitempty"` // INCREMENT BY of the identity sequence, 0 when not loaded
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -154,6 +156,20 @@ func (x *PGColumn) GetIsPrimaryKey() bool {
 	return false
 }
 
+func (x *PGColumn) GetIdentityStart() int64 {
+	if x != nil {
+		return x.IdentityStart
+	}
+	return 0
+}
+
+func (x *PGColumn) GetIdentityIncrement() int64 {
+	if x != nil {
+		return x.IdentityIncrement
+	}
+	return 0
+}
+
 // Represents an index on a PostgreSQL table
 type PGIndex struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -167,6 +183,8 @@ type PGIndex struct {
 	Columns       []string               `protobuf:"bytes,9,rep,name=Columns,proto3" json:"Columns,omitempty"`
 	Definition    string                 `protobuf:"bytes,10,opt,name=Definition,proto3" json:"Definition,omitempty"`
 	Comment       string                 `protobuf:"bytes,11,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	Expression    string                 `protobuf:"bytes,12,opt,name=Expression,proto3" json:"Expression,omitempty"` // Key list when it is not plain columns, e.g. "lower(email)"
+	Predicate     string                 `protobuf:"bytes,13,opt,name=Predicate,proto3" json:"Predicate,omitempty"`   // WHERE clause of a partial index
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -271,6 +289,20 @@ func (x *PGIndex) GetComment() string {
 	return ""
 }
 
+func (x *PGIndex) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *PGIndex) GetPredicate() string {
+	if x != nil {
+		return x.Predicate
+	}
+	return ""
+}
+
 // Represents a foreign key constraint
 type PGForeignKey struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -390,17 +422,19 @@ func (x *PGForeignKey) GetComment() string {
 
 // Represents other constraints (Primary Key, Unique, Check, Exclusion)
 type PGConstraint struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	TableName     *ObjectName            `protobuf:"bytes,3,opt,name=TableName,proto3" json:"TableName,omitempty"`
-	Type          string                 `protobuf:"bytes,4,opt,name=Type,proto3" json:"Type,omitempty"` // "p", "u", "c", "x"
-	Columns       []string               `protobuf:"bytes,5,rep,name=Columns,proto3" json:"Columns,omitempty"`
-	Definition    string                 `protobuf:"bytes,6,opt,name=Definition,proto3" json:"Definition,omitempty"`
-	Comment       string                 `protobuf:"bytes,7,opt,name=Comment,proto3" json:"Comment,omitempty"`
-	IsDeferrable  bool                   `protobuf:"varint,8,opt,name=IsDeferrable,proto3" json:"IsDeferrable,omitempty"`
-	IsDeferred    bool                   `protobuf:"varint,9,opt,name=IsDeferred,proto3" json:"IsDeferred,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	TableName        *ObjectName            `protobuf:"bytes,3,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Type             string                 `protobuf:"bytes,4,opt,name=Type,proto3" json:"Type,omitempty"` // "p", "u", "c", "x"
+	Columns          []string               `protobuf:"bytes,5,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	Definition       string                 `protobuf:"bytes,6,opt,name=Definition,proto3" json:"Definition,omitempty"`
+	Comment          string                 `protobuf:"bytes,7,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	IsDeferrable     bool                   `protobuf:"varint,8,opt,name=IsDeferrable,proto3" json:"IsDeferrable,omitempty"`
+	IsDeferred       bool                   `protobuf:"varint,9,opt,name=IsDeferred,proto3" json:"IsDeferred,omitempty"`
+	IndexName        string                 `protobuf:"bytes,10,opt,name=IndexName,proto3" json:"IndexName,omitempty"`                // index backing a "p", "u" or "x" constraint
+	NullsNotDistinct bool                   `protobuf:"varint,11,opt,name=NullsNotDistinct,proto3" json:"NullsNotDistinct,omitempty"` // "u" declared NULLS NOT DISTINCT (Postgres 15)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *PGConstraint) Reset() {
@@ -489,6 +523,20 @@ func (x *PGConstraint) GetIsDeferred() bool {
 	return false
 }
 
+func (x *PGConstraint) GetIndexName() string {
+	if x != nil {
+		return x.IndexName
+	}
+	return ""
+}
+
+func (x *PGConstraint) GetNullsNotDistinct() bool {
+	if x != nil {
+		return x.NullsNotDistinct
+	}
+	return false
+}
+
 // Represents a PostgreSQL Sequence
 type PGSequence struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -622,6 +670,67 @@ func (x *PGSequence) GetComment() string {
 	return ""
 }
 
+// Represents a PostgreSQL enum type (CREATE TYPE ... AS ENUM)
+type PGEnum struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *ObjectName            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Labels        []string               `protobuf:"bytes,2,rep,name=Labels,proto3" json:"Labels,omitempty"` // In sort order
+	Comment       string                 `protobuf:"bytes,3,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PGEnum) Reset() {
+	*x = PGEnum{}
+	mi := &file_pg_meta_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PGEnum) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PGEnum) ProtoMessage() {}
+
+func (x *PGEnum) ProtoReflect() protoreflect.Message {
+	mi := &file_pg_meta_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PGEnum.ProtoReflect.Descriptor instead.
+func (*PGEnum) Descriptor() ([]byte, []int) {
+	return file_pg_meta_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PGEnum) GetName() *ObjectName {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *PGEnum) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *PGEnum) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
 // Represents a PostgreSQL Table
 type PGTable struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
@@ -639,13 +748,18 @@ type PGTable struct {
 	Comment           string                 `protobuf:"bytes,13,opt,name=Comment,proto3" json:"Comment,omitempty"`
 	EstimatedRows     int64                  `protobuf:"varint,14,opt,name=EstimatedRows,proto3" json:"EstimatedRows,omitempty"`
 	TotalBytes        int64                  `protobuf:"varint,15,opt,name=TotalBytes,proto3" json:"TotalBytes,omitempty"`
+	PartitionKey      string                 `protobuf:"bytes,16,opt,name=PartitionKey,proto3" json:"PartitionKey,omitempty"`     // pg_get_partkeydef, e.g. "RANGE (created_at)"
+	PartitionOf       *ObjectName            `protobuf:"bytes,17,opt,name=PartitionOf,proto3" json:"PartitionOf,omitempty"`       // Parent of a partition
+	PartitionBound    string                 `protobuf:"bytes,18,opt,name=PartitionBound,proto3" json:"PartitionBound,omitempty"` // e.g. "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')"
+	InheritsFrom      []*ObjectName          `protobuf:"bytes,19,rep,name=InheritsFrom,proto3" json:"InheritsFrom,omitempty"`     // INHERITS parents, in order
+	Tablespace        string                 `protobuf:"bytes,20,opt,name=Tablespace,proto3" json:"Tablespace,omitempty"`         // Empty for the database default
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *PGTable) Reset() {
 	*x = PGTable{}
-	mi := &file_pg_meta_proto_msgTypes[5]
+	mi := &file_pg_meta_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -657,7 +771,7 @@ func (x *PGTable) String() string {
 func (*PGTable) ProtoMessage() {}
 
 func (x *PGTable) ProtoReflect() protoreflect.Message {
-	mi := &file_pg_meta_proto_msgTypes[5]
+	mi := &file_pg_meta_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,7 +784,7 @@ func (x *PGTable) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PGTable.ProtoReflect.Descriptor instead.
 func (*PGTable) Descriptor() ([]byte, []int) {
-	return file_pg_meta_proto_rawDescGZIP(), []int{5}
+	return file_pg_meta_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PGTable) GetName() *ObjectName {
@@ -771,22 +885,59 @@ func (x *PGTable) GetTotalBytes() int64 {
 	return 0
 }
 
+func (x *PGTable) GetPartitionKey() string {
+	if x != nil {
+		return x.PartitionKey
+	}
+	return ""
+}
+
+func (x *PGTable) GetPartitionOf() *ObjectName {
+	if x != nil {
+		return x.PartitionOf
+	}
+	return nil
+}
+
+func (x *PGTable) GetPartitionBound() string {
+	if x != nil {
+		return x.PartitionBound
+	}
+	return ""
+}
+
+func (x *PGTable) GetInheritsFrom() []*ObjectName {
+	if x != nil {
+		return x.InheritsFrom
+	}
+	return nil
+}
+
+func (x *PGTable) GetTablespace() string {
+	if x != nil {
+		return x.Tablespace
+	}
+	return ""
+}
+
 // Represents a PostgreSQL View
 type PGView struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Name           *ObjectName            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	Owner          string                 `protobuf:"bytes,3,opt,name=Owner,proto3" json:"Owner,omitempty"`
-	Definition     string                 `protobuf:"bytes,4,opt,name=Definition,proto3" json:"Definition,omitempty"`
-	IsMaterialized bool                   `protobuf:"varint,5,opt,name=IsMaterialized,proto3" json:"IsMaterialized,omitempty"`
-	Columns        []*PGColumn            `protobuf:"bytes,6,rep,name=Columns,proto3" json:"Columns,omitempty"`
-	Comment        string                 `protobuf:"bytes,7,opt,name=Comment,proto3" json:"Comment,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            *ObjectName            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Owner           string                 `protobuf:"bytes,3,opt,name=Owner,proto3" json:"Owner,omitempty"`
+	Definition      string                 `protobuf:"bytes,4,opt,name=Definition,proto3" json:"Definition,omitempty"`
+	IsMaterialized  bool                   `protobuf:"varint,5,opt,name=IsMaterialized,proto3" json:"IsMaterialized,omitempty"`
+	Columns         []*PGColumn            `protobuf:"bytes,6,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	Comment         string                 `protobuf:"bytes,7,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	CheckOption     string                 `protobuf:"bytes,8,opt,name=CheckOption,proto3" json:"CheckOption,omitempty"` // LOCAL, CASCADED
+	SecurityBarrier bool                   `protobuf:"varint,9,opt,name=SecurityBarrier,proto3" json:"SecurityBarrier,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *PGView) Reset() {
 	*x = PGView{}
-	mi := &file_pg_meta_proto_msgTypes[6]
+	mi := &file_pg_meta_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -798,7 +949,7 @@ func (x *PGView) String() string {
 func (*PGView) ProtoMessage() {}
 
 func (x *PGView) ProtoReflect() protoreflect.Message {
-	mi := &file_pg_meta_proto_msgTypes[6]
+	mi := &file_pg_meta_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -811,7 +962,7 @@ func (x *PGView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PGView.ProtoReflect.Descriptor instead.
 func (*PGView) Descriptor() ([]byte, []int) {
-	return file_pg_meta_proto_rawDescGZIP(), []int{6}
+	return file_pg_meta_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PGView) GetName() *ObjectName {
@@ -856,6 +1007,20 @@ func (x *PGView) GetComment() string {
 	return ""
 }
 
+func (x *PGView) GetCheckOption() string {
+	if x != nil {
+		return x.CheckOption
+	}
+	return ""
+}
+
+func (x *PGView) GetSecurityBarrier() bool {
+	if x != nil {
+		return x.SecurityBarrier
+	}
+	return false
+}
+
 // Represents a PostgreSQL Schema (Namespace)
 type PGSchema struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -866,13 +1031,14 @@ type PGSchema struct {
 	Sequences     []*PGSequence          `protobuf:"bytes,5,rep,name=Sequences,proto3" json:"Sequences,omitempty"`
 	Domains       []*PGConstraint        `protobuf:"bytes,6,rep,name=Domains,proto3" json:"Domains,omitempty"`
 	Comment       string                 `protobuf:"bytes,7,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	Enums         []*PGEnum              `protobuf:"bytes,8,rep,name=Enums,proto3" json:"Enums,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PGSchema) Reset() {
 	*x = PGSchema{}
-	mi := &file_pg_meta_proto_msgTypes[7]
+	mi := &file_pg_meta_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -884,7 +1050,7 @@ func (x *PGSchema) String() string {
 func (*PGSchema) ProtoMessage() {}
 
 func (x *PGSchema) ProtoReflect() protoreflect.Message {
-	mi := &file_pg_meta_proto_msgTypes[7]
+	mi := &file_pg_meta_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -897,7 +1063,7 @@ func (x *PGSchema) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PGSchema.ProtoReflect.Descriptor instead.
 func (*PGSchema) Descriptor() ([]byte, []int) {
-	return file_pg_meta_proto_rawDescGZIP(), []int{7}
+	return file_pg_meta_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PGSchema) GetName() string {
@@ -949,6 +1115,13 @@ func (x *PGSchema) GetComment() string {
 	return ""
 }
 
+func (x *PGSchema) GetEnums() []*PGEnum {
+	if x != nil {
+		return x.Enums
+	}
+	return nil
+}
+
 type PGDatabase struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
@@ -962,7 +1135,7 @@ type PGDatabase struct {
 
 func (x *PGDatabase) Reset() {
 	*x = PGDatabase{}
-	mi := &file_pg_meta_proto_msgTypes[8]
+	mi := &file_pg_meta_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -974,7 +1147,7 @@ func (x *PGDatabase) String() string {
 func (*PGDatabase) ProtoMessage() {}
 
 func (x *PGDatabase) ProtoReflect() protoreflect.Message {
-	mi := &file_pg_meta_proto_msgTypes[8]
+	mi := &file_pg_meta_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -987,7 +1160,7 @@ func (x *PGDatabase) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PGDatabase.ProtoReflect.Descriptor instead.
 func (*PGDatabase) Descriptor() ([]byte, []int) {
-	return file_pg_meta_proto_rawDescGZIP(), []int{8}
+	return file_pg_meta_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *PGDatabase) GetName() string {
@@ -1029,7 +1202,7 @@ var File_pg_meta_proto protoreflect.FileDescriptor
 
 const file_pg_meta_proto_rawDesc = "" +
 	"\n" +
-	"\rpg_meta.proto\x12\x06pgmeta\x1a\vtypes.proto\"\xcb\x03\n" +
+	"\rpg_meta.proto\x12\x06pgmeta\x1a\vtypes.proto\"\x9f\x04\n" +
 	"\bPGColumn\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12-\n" +
 	"\bDataType\x18\x02 \x01(\v2\x11.sqlmeta.DataTypeR\bDataType\x12\x1e\n" +
@@ -1047,7 +1220,9 @@ const file_pg_meta_proto_rawDesc = "" +
 	"\vIsGenerated\x18\f \x01(\bR\vIsGenerated\x122\n" +
 	"\x14GenerationExpression\x18\r \x01(\tR\x14GenerationExpression\x12\x18\n" +
 	"\aComment\x18\x0e \x01(\tR\aComment\x12\"\n" +
-	"\fIsPrimaryKey\x18\x0f \x01(\bR\fIsPrimaryKey\"\xbe\x02\n" +
+	"\fIsPrimaryKey\x18\x0f \x01(\bR\fIsPrimaryKey\x12$\n" +
+	"\rIdentityStart\x18\x10 \x01(\x03R\rIdentityStart\x12,\n" +
+	"\x11IdentityIncrement\x18\x11 \x01(\x03R\x11IdentityIncrement\"\xfc\x02\n" +
 	"\aPGIndex\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x121\n" +
 	"\tTableName\x18\x02 \x01(\v2\x13.sqlmeta.ObjectNameR\tTableName\x12\x1a\n" +
@@ -1061,7 +1236,11 @@ const file_pg_meta_proto_rawDesc = "" +
 	"Definition\x18\n" +
 	" \x01(\tR\n" +
 	"Definition\x12\x18\n" +
-	"\aComment\x18\v \x01(\tR\aComment\"\xee\x02\n" +
+	"\aComment\x18\v \x01(\tR\aComment\x12\x1e\n" +
+	"\n" +
+	"Expression\x18\f \x01(\tR\n" +
+	"Expression\x12\x1c\n" +
+	"\tPredicate\x18\r \x01(\tR\tPredicate\"\xee\x02\n" +
 	"\fPGForeignKey\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x121\n" +
 	"\tTableName\x18\x02 \x01(\v2\x13.sqlmeta.ObjectNameR\tTableName\x12\"\n" +
@@ -1075,7 +1254,7 @@ const file_pg_meta_proto_rawDesc = "" +
 	"\n" +
 	"Definition\x18\v \x01(\tR\n" +
 	"Definition\x12\x18\n" +
-	"\aComment\x18\f \x01(\tR\aComment\"\x81\x02\n" +
+	"\aComment\x18\f \x01(\tR\aComment\"\xcb\x02\n" +
 	"\fPGConstraint\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x121\n" +
 	"\tTableName\x18\x03 \x01(\v2\x13.sqlmeta.ObjectNameR\tTableName\x12\x12\n" +
@@ -1088,7 +1267,10 @@ const file_pg_meta_proto_rawDesc = "" +
 	"\fIsDeferrable\x18\b \x01(\bR\fIsDeferrable\x12\x1e\n" +
 	"\n" +
 	"IsDeferred\x18\t \x01(\bR\n" +
-	"IsDeferred\"\xa1\x03\n" +
+	"IsDeferred\x12\x1c\n" +
+	"\tIndexName\x18\n" +
+	" \x01(\tR\tIndexName\x12*\n" +
+	"\x10NullsNotDistinct\x18\v \x01(\bR\x10NullsNotDistinct\"\xa1\x03\n" +
 	"\n" +
 	"PGSequence\x12'\n" +
 	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12-\n" +
@@ -1107,7 +1289,11 @@ const file_pg_meta_proto_rawDesc = "" +
 	"OwnerTable\x18\v \x01(\v2\x13.sqlmeta.ObjectNameR\n" +
 	"OwnerTable\x12 \n" +
 	"\vOwnerColumn\x18\f \x01(\tR\vOwnerColumn\x12\x18\n" +
-	"\aComment\x18\r \x01(\tR\aComment\"\xa1\x04\n" +
+	"\aComment\x18\r \x01(\tR\aComment\"c\n" +
+	"\x06PGEnum\x12'\n" +
+	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x16\n" +
+	"\x06Labels\x18\x02 \x03(\tR\x06Labels\x12\x18\n" +
+	"\aComment\x18\x03 \x01(\tR\aComment\"\xfd\x05\n" +
 	"\aPGTable\x12'\n" +
 	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x14\n" +
 	"\x05Owner\x18\x03 \x01(\tR\x05Owner\x12\x1c\n" +
@@ -1125,7 +1311,14 @@ const file_pg_meta_proto_rawDesc = "" +
 	"\rEstimatedRows\x18\x0e \x01(\x03R\rEstimatedRows\x12\x1e\n" +
 	"\n" +
 	"TotalBytes\x18\x0f \x01(\x03R\n" +
-	"TotalBytes\"\xd5\x01\n" +
+	"TotalBytes\x12\"\n" +
+	"\fPartitionKey\x18\x10 \x01(\tR\fPartitionKey\x125\n" +
+	"\vPartitionOf\x18\x11 \x01(\v2\x13.sqlmeta.ObjectNameR\vPartitionOf\x12&\n" +
+	"\x0ePartitionBound\x18\x12 \x01(\tR\x0ePartitionBound\x127\n" +
+	"\fInheritsFrom\x18\x13 \x03(\v2\x13.sqlmeta.ObjectNameR\fInheritsFrom\x12\x1e\n" +
+	"\n" +
+	"Tablespace\x18\x14 \x01(\tR\n" +
+	"Tablespace\"\xa1\x02\n" +
 	"\x06PGView\x12'\n" +
 	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x14\n" +
 	"\x05Owner\x18\x03 \x01(\tR\x05Owner\x12\x1e\n" +
@@ -1134,7 +1327,9 @@ const file_pg_meta_proto_rawDesc = "" +
 	"Definition\x12&\n" +
 	"\x0eIsMaterialized\x18\x05 \x01(\bR\x0eIsMaterialized\x12*\n" +
 	"\aColumns\x18\x06 \x03(\v2\x10.pgmeta.PGColumnR\aColumns\x12\x18\n" +
-	"\aComment\x18\a \x01(\tR\aComment\"\xff\x01\n" +
+	"\aComment\x18\a \x01(\tR\aComment\x12 \n" +
+	"\vCheckOption\x18\b \x01(\tR\vCheckOption\x12(\n" +
+	"\x0fSecurityBarrier\x18\t \x01(\bR\x0fSecurityBarrier\"\xa5\x02\n" +
 	"\bPGSchema\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x14\n" +
 	"\x05Owner\x18\x02 \x01(\tR\x05Owner\x12'\n" +
@@ -1142,7 +1337,8 @@ const file_pg_meta_proto_rawDesc = "" +
 	"\x05Views\x18\x04 \x03(\v2\x0e.pgmeta.PGViewR\x05Views\x120\n" +
 	"\tSequences\x18\x05 \x03(\v2\x12.pgmeta.PGSequenceR\tSequences\x12.\n" +
 	"\aDomains\x18\x06 \x03(\v2\x14.pgmeta.PGConstraintR\aDomains\x12\x18\n" +
-	"\aComment\x18\a \x01(\tR\aComment\"\xa0\x01\n" +
+	"\aComment\x18\a \x01(\tR\aComment\x12$\n" +
+	"\x05Enums\x18\b \x03(\v2\x0e.pgmeta.PGEnumR\x05Enums\"\xa0\x01\n" +
 	"\n" +
 	"PGDatabase\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x18\n" +
@@ -1165,46 +1361,51 @@ func file_pg_meta_proto_rawDescGZIP() []byte {
 	return file_pg_meta_proto_rawDescData
 }
 
-var file_pg_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_pg_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_pg_meta_proto_goTypes = []any{
 	(*PGColumn)(nil),     // 0: pgmeta.PGColumn
 	(*PGIndex)(nil),      // 1: pgmeta.PGIndex
 	(*PGForeignKey)(nil), // 2: pgmeta.PGForeignKey
 	(*PGConstraint)(nil), // 3: pgmeta.PGConstraint
 	(*PGSequence)(nil),   // 4: pgmeta.PGSequence
-	(*PGTable)(nil),      // 5: pgmeta.PGTable
-	(*PGView)(nil),       // 6: pgmeta.PGView
-	(*PGSchema)(nil),     // 7: pgmeta.PGSchema
-	(*PGDatabase)(nil),   // 8: pgmeta.PGDatabase
-	(*DataType)(nil),     // 9: sqlmeta.DataType
-	(*ObjectName)(nil),   // 10: sqlmeta.ObjectName
+	(*PGEnum)(nil),       // 5: pgmeta.PGEnum
+	(*PGTable)(nil),      // 6: pgmeta.PGTable
+	(*PGView)(nil),       // 7: pgmeta.PGView
+	(*PGSchema)(nil),     // 8: pgmeta.PGSchema
+	(*PGDatabase)(nil),   // 9: pgmeta.PGDatabase
+	(*DataType)(nil),     // 10: sqlmeta.DataType
+	(*ObjectName)(nil),   // 11: sqlmeta.ObjectName
 }
 var file_pg_meta_proto_depIdxs = []int32{
-	9,  // 0: pgmeta.PGColumn.DataType:type_name -> sqlmeta.DataType
-	10, // 1: pgmeta.PGIndex.TableName:type_name -> sqlmeta.ObjectName
-	10, // 2: pgmeta.PGForeignKey.TableName:type_name -> sqlmeta.ObjectName
-	10, // 3: pgmeta.PGForeignKey.ForeignTable:type_name -> sqlmeta.ObjectName
-	10, // 4: pgmeta.PGConstraint.TableName:type_name -> sqlmeta.ObjectName
-	10, // 5: pgmeta.PGSequence.Name:type_name -> sqlmeta.ObjectName
-	9,  // 6: pgmeta.PGSequence.DataType:type_name -> sqlmeta.DataType
-	10, // 7: pgmeta.PGSequence.OwnerTable:type_name -> sqlmeta.ObjectName
-	10, // 8: pgmeta.PGTable.Name:type_name -> sqlmeta.ObjectName
-	0,  // 9: pgmeta.PGTable.Columns:type_name -> pgmeta.PGColumn
-	1,  // 10: pgmeta.PGTable.Indexes:type_name -> pgmeta.PGIndex
-	3,  // 11: pgmeta.PGTable.Constraints:type_name -> pgmeta.PGConstraint
-	2,  // 12: pgmeta.PGTable.ForeignKeys:type_name -> pgmeta.PGForeignKey
-	10, // 13: pgmeta.PGView.Name:type_name -> sqlmeta.ObjectName
-	0,  // 14: pgmeta.PGView.Columns:type_name -> pgmeta.PGColumn
-	5,  // 15: pgmeta.PGSchema.Tables:type_name -> pgmeta.PGTable
-	6,  // 16: pgmeta.PGSchema.Views:type_name -> pgmeta.PGView
-	4,  // 17: pgmeta.PGSchema.Sequences:type_name -> pgmeta.PGSequence
-	3,  // 18: pgmeta.PGSchema.Domains:type_name -> pgmeta.PGConstraint
-	7,  // 19: pgmeta.PGDatabase.Schemas:type_name -> pgmeta.PGSchema
-	20, // [20:20] is the sub-list for method output_type
-	20, // [20:20] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	10, // 0: pgmeta.PGColumn.DataType:type_name -> sqlmeta.DataType
+	11, // 1: pgmeta.PGIndex.TableName:type_name -> sqlmeta.ObjectName
+	11, // 2: pgmeta.PGForeignKey.TableName:type_name -> sqlmeta.ObjectName
+	11, // 3: pgmeta.PGForeignKey.ForeignTable:type_name -> sqlmeta.ObjectName
+	11, // 4: pgmeta.PGConstraint.TableName:type_name -> sqlmeta.ObjectName
+	11, // 5: pgmeta.PGSequence.Name:type_name -> sqlmeta.ObjectName
+	10, // 6: pgmeta.PGSequence.DataType:type_name -> sqlmeta.DataType
+	11, // 7: pgmeta.PGSequence.OwnerTable:type_name -> sqlmeta.ObjectName
+	11, // 8: pgmeta.PGEnum.Name:type_name -> sqlmeta.ObjectName
+	11, // 9: pgmeta.PGTable.Name:type_name -> sqlmeta.ObjectName
+	0,  // 10: pgmeta.PGTable.Columns:type_name -> pgmeta.PGColumn
+	1,  // 11: pgmeta.PGTable.Indexes:type_name -> pgmeta.PGIndex
+	3,  // 12: pgmeta.PGTable.Constraints:type_name -> pgmeta.PGConstraint
+	2,  // 13: pgmeta.PGTable.ForeignKeys:type_name -> pgmeta.PGForeignKey
+	11, // 14: pgmeta.PGTable.PartitionOf:type_name -> sqlmeta.ObjectName
+	11, // 15: pgmeta.PGTable.InheritsFrom:type_name -> sqlmeta.ObjectName
+	11, // 16: pgmeta.PGView.Name:type_name -> sqlmeta.ObjectName
+	0,  // 17: pgmeta.PGView.Columns:type_name -> pgmeta.PGColumn
+	6,  // 18: pgmeta.PGSchema.Tables:type_name -> pgmeta.PGTable
+	7,  // 19: pgmeta.PGSchema.Views:type_name -> pgmeta.PGView
+	4,  // 20: pgmeta.PGSchema.Sequences:type_name -> pgmeta.PGSequence
+	3,  // 21: pgmeta.PGSchema.Domains:type_name -> pgmeta.PGConstraint
+	5,  // 22: pgmeta.PGSchema.Enums:type_name -> pgmeta.PGEnum
+	8,  // 23: pgmeta.PGDatabase.Schemas:type_name -> pgmeta.PGSchema
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_pg_meta_proto_init() }
@@ -1219,7 +1420,7 @@ func file_pg_meta_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pg_meta_proto_rawDesc), len(file_pg_meta_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,146 @@
+package xmeta
+
+// rename.go renames tables and columns across a whole MetaDatabase, keeping
+// the foreign keys and other references to them consistent. It is the
+// model-level counterpart of the RenameTable and RenamedTo diff changes.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RenameObject renames the table oldName in db to newName and rewrites
+// every reference to it: foreign keys, partition and INHERITS parents, and
+// triggers. A reference by bare name stays bare. It fails if oldName is
+// not in db or newName is already taken.
+func RenameObject(db *MetaDatabase, oldName, newName *ObjectName) error {
+	oldKey, newKey := objectNameKey(oldName), objectNameKey(newName)
+	if oldKey == "" || newKey == "" {
+		return fmt.Errorf("rename needs both an old and a new table name")
+	}
+	table := findTable(db, oldName)
+	if table == nil {
+		return fmt.Errorf("table %s not found", oldKey)
+	}
+	for _, t := range db.Tables {
+		if t != table && objectNameKey(t.Name) == newKey {
+			return fmt.Errorf("table %s already exists", newKey)
+		}
+	}
+
+	rename := func(ref string) string {
+		switch ref {
+		case oldKey:
+			return newKey
+		case simpleNameKey(oldName):
+			if !strings.Contains(ref, ".") {
+				return simpleNameKey(newName)
+			}
+		}
+		return ref
+	}
+
+	table.Name = &ObjectName{Idents: slices.Clone(newName.Idents)}
+	for _, t := range db.Tables {
+		for _, elem := range t.Elements {
+			if ref := elem.GetTableConstraintElement().GetSpec().GetReferenceItem(); ref != nil && ref.KeyExpr != nil {
+				ref.KeyExpr.TableName = rename(ref.KeyExpr.TableName)
+			}
+			for _, con := range elem.GetColumnDefElement().GetConstraints() {
+				if ref := con.GetSpec().GetReferenceItem(); ref != nil && ref.TableName != nil {
+					ref.TableName.Idents = strings.Split(rename(objectNameKey(ref.TableName)), ".")
+				}
+			}
+		}
+		if parent, ok := t.Options["PartitionOf"]; ok {
+			t.Options["PartitionOf"] = rename(parent)
+		}
+		if parents := optionList(t.Options["InheritsFrom"]); len(parents) > 0 {
+			for i, parent := range parents {
+				parents[i] = rename(parent)
+			}
+			t.Options["InheritsFrom"] = strings.Join(parents, ",")
+		}
+	}
+	for _, trg := range db.Triggers {
+		if trg.TableName != nil {
+			trg.TableName.Idents = strings.Split(rename(objectNameKey(trg.TableName)), ".")
+		}
+	}
+	return nil
+}
+
+// RenameColumnEverywhere renames column oldCol of table to newCol, both in
+// the table itself (keys, foreign keys and indexes) and in the foreign keys
+// of other tables that reference it. It fails if the table or the column
+// does not exist, or the table already has a column newCol.
+func RenameColumnEverywhere(db *MetaDatabase, table *ObjectName, oldCol, newCol string) error {
+	t := findTable(db, table)
+	if t == nil {
+		return fmt.Errorf("table %s not found", objectNameKey(table))
+	}
+	if newCol == "" {
+		return fmt.Errorf("rename of column %s needs a new name", oldCol)
+	}
+	columns := columnsFromElements(t.Elements)
+	col := columns[oldCol]
+	if col == nil {
+		return fmt.Errorf("column %s.%s not found", objectNameKey(t.Name), oldCol)
+	}
+	if _, ok := columns[newCol]; ok && newCol != oldCol {
+		return fmt.Errorf("column %s.%s already exists", objectNameKey(t.Name), newCol)
+	}
+
+	rename := func(names []string) {
+		for i, name := range names {
+			if name == oldCol {
+				names[i] = newCol
+			}
+		}
+	}
+
+	col.Name = newCol
+	for _, elem := range t.Elements {
+		spec := elem.GetTableConstraintElement().GetSpec()
+		if u := spec.GetUniqueItem(); u != nil {
+			rename(u.Columns)
+			rename(u.Include)
+		}
+		if ref := spec.GetReferenceItem(); ref != nil {
+			rename(ref.Columns)
+		}
+		if ex := spec.GetExcludeItem(); ex != nil {
+			rename(ex.Include)
+		}
+	}
+	for _, idx := range t.Indexes {
+		rename(idx.Columns)
+	}
+
+	// Foreign columns of every foreign key pointing at t, t's own included
+	lookup := tableLookup(db.Tables)
+	for _, other := range db.Tables {
+		for _, elem := range other.Elements {
+			if ref := elem.GetTableConstraintElement().GetSpec().GetReferenceItem(); ref != nil && lookup(ref.GetKeyExpr().GetTableName()) == t {
+				rename(ref.KeyExpr.Columns)
+			}
+			for _, con := range elem.GetColumnDefElement().GetConstraints() {
+				if ref := con.GetSpec().GetReferenceItem(); ref != nil && lookup(objectNameKey(ref.TableName)) == t {
+					rename(ref.Columns)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findTable returns the table of db named name, matching a bare name
+// against a qualified one as foreign keys do, or nil.
+func findTable(db *MetaDatabase, name *ObjectName) *MetaTable {
+	key := objectNameKey(name)
+	if db == nil || key == "" {
+		return nil
+	}
+	return tableLookup(db.Tables)(key)
+}
@@ -0,0 +1,71 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestRenameObject(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	if err := RenameObject(db, &ObjectName{Idents: []string{"users"}}, &ObjectName{Idents: []string{"accounts"}}); err != nil {
+		t.Fatalf("RenameObject failed: %v", err)
+	}
+	if got := objectNameKey(db.Tables[0].Name); got != "accounts" {
+		t.Errorf("Expected table renamed to accounts, got %s", got)
+	}
+	ref := db.Tables[1].Elements[2].GetTableConstraintElement().GetSpec().GetReferenceItem()
+	if got := ref.GetKeyExpr().GetTableName(); got != "accounts" {
+		t.Errorf("Expected foreign key to reference accounts, got %s", got)
+	}
+
+	if err := RenameObject(db, &ObjectName{Idents: []string{"users"}}, &ObjectName{Idents: []string{"people"}}); err == nil {
+		t.Error("Expected an error renaming a missing table")
+	}
+	if err := RenameObject(db, &ObjectName{Idents: []string{"accounts"}}, &ObjectName{Idents: []string{"orders"}}); err == nil {
+		t.Error("Expected an error renaming onto an existing table")
+	}
+}
+
+func TestRenameColumnEverywhere(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+CREATE INDEX users_name ON users (name);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	users := &ObjectName{Idents: []string{"users"}}
+	if err := RenameColumnEverywhere(db, users, "id", "user_id"); err != nil {
+		t.Fatalf("RenameColumnEverywhere failed: %v", err)
+	}
+	if cols := columnsFromElements(db.Tables[0].Elements); cols["user_id"] == nil || cols["id"] != nil {
+		t.Errorf("Expected column id renamed to user_id, got %v", cols)
+	}
+	for _, elem := range db.Tables[0].Elements {
+		if u := elem.GetTableConstraintElement().GetSpec().GetUniqueItem(); u != nil && u.Columns[0] != "user_id" {
+			t.Errorf("Expected primary key on user_id, got %v", u.Columns)
+		}
+	}
+	ref := db.Tables[1].Elements[2].GetTableConstraintElement().GetSpec().GetReferenceItem()
+	if got := ref.GetKeyExpr().GetColumns(); len(got) != 1 || got[0] != "user_id" {
+		t.Errorf("Expected foreign key to reference user_id, got %v", got)
+	}
+	if got := ref.Columns; len(got) != 1 || got[0] != "user_id" {
+		t.Errorf("Expected local foreign key column unchanged, got %v", got)
+	}
+
+	if err := RenameColumnEverywhere(db, users, "name", "user_id"); err == nil {
+		t.Error("Expected an error renaming onto an existing column")
+	}
+	if err := RenameColumnEverywhere(db, users, "missing", "other"); err == nil {
+		t.Error("Expected an error renaming a missing column")
+	}
+}
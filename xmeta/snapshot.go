@@ -0,0 +1,140 @@
+package xmeta
+
+// snapshot.go stores a MetaDatabase together with when and where it was
+// taken, for tracking schema drift over time.
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Snapshot is a MetaDatabase with its provenance.
+type Snapshot struct {
+	TakenAt  time.Time
+	Source   string // DSN, host or file the schema came from
+	Dialect  string
+	Database *MetaDatabase
+}
+
+// NewSnapshot returns a snapshot of db taken now. The dialect is the one
+// recorded by the database converters, see SourceDialect.
+func NewSnapshot(db *MetaDatabase, source string) Snapshot {
+	s := Snapshot{TakenAt: time.Now().UTC(), Source: source, Database: db}
+	if dialect := SourceDialect(db); dialect != DialectUnknown {
+		s.Dialect = dialect.String()
+	}
+	return s
+}
+
+func (s Snapshot) toProto() *MetaSnapshot {
+	m := &MetaSnapshot{Source: s.Source, Dialect: s.Dialect, Database: s.Database}
+	if !s.TakenAt.IsZero() {
+		m.TakenAt = timestamppb.New(s.TakenAt)
+	}
+	return m
+}
+
+func snapshotFromProto(m *MetaSnapshot) Snapshot {
+	s := Snapshot{Source: m.Source, Dialect: m.Dialect, Database: m.Database}
+	if m.TakenAt != nil {
+		s.TakenAt = m.TakenAt.AsTime()
+	}
+	return s
+}
+
+// SaveSnapshotToWriter writes a snapshot to a stream in the given format.
+// The SQL format cannot hold the envelope and is not supported.
+func SaveSnapshotToWriter(s Snapshot, w io.Writer, format Format, opts ...SaveOptions) error {
+	if format == FormatSQL {
+		return fmt.Errorf("snapshots cannot be saved as %s", format)
+	}
+	data, err := marshalFormat(s.toProto(), format, saveOptions(opts))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// SaveSnapshot saves a snapshot to a file whose extension gives the format.
+func SaveSnapshot(s Snapshot, path string, opts ...SaveOptions) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := SaveSnapshotToWriter(s, &buf, format, opts...); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// LoadSnapshotFromReader loads a snapshot from a stream in the given format.
+func LoadSnapshotFromReader(r io.Reader, format Format) (Snapshot, error) {
+	if format == FormatSQL {
+		return Snapshot{}, fmt.Errorf("snapshots cannot be loaded from %s", format)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading input: %w", err)
+	}
+
+	m := &MetaSnapshot{}
+	if err := unmarshalFormat(data, format, m); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshotFromProto(m), nil
+}
+
+// LoadSnapshot loads a snapshot from a file. The format is detected as in
+// LoadMetaDatabaseFromFile.
+func LoadSnapshot(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading file: %w", err)
+	}
+	format, err := formatOf(path, data, &MetaSnapshot{})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return LoadSnapshotFromReader(bytes.NewReader(data), format)
+}
+
+// DialectMismatchWarning is returned among the warnings of DiffSnapshots
+// when the snapshots were taken from different dialects, so the changes may
+// reflect type mapping rather than drift.
+type DialectMismatchWarning struct {
+	Old, New string
+}
+
+func (w DialectMismatchWarning) Error() string {
+	return fmt.Sprintf("snapshots have different dialects %q and %q", w.Old, w.New)
+}
+
+// DiffSnapshots returns the changes from old's database to new's, with
+// warnings about how far they can be trusted. When both snapshots name the
+// same dialect, column defaults are compared in that dialect. When they
+// name different dialects the warnings hold a DialectMismatchWarning. The
+// error is only set when the snapshots cannot be diffed.
+func DiffSnapshots(old, new Snapshot) ([]SchemaChange, []DialectMismatchWarning, error) {
+	if old.Database == nil || new.Database == nil {
+		return nil, nil, fmt.Errorf("snapshot without database")
+	}
+	oldDialect, newDialect := ParseDialect(old.Dialect), ParseDialect(new.Dialect)
+	var opts DiffOptions
+	if oldDialect == newDialect {
+		opts.Dialect = oldDialect
+	}
+	changes := DiffDatabaseWithOptions(old.Database, new.Database, opts)
+	var warnings []DialectMismatchWarning
+	if old.Dialect != "" && new.Dialect != "" && oldDialect != newDialect {
+		warnings = append(warnings, DialectMismatchWarning{Old: old.Dialect, New: new.Dialect})
+	}
+	return changes, warnings, nil
+}
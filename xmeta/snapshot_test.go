@@ -0,0 +1,69 @@
+package xmeta
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadSnapshot(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taken := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	snap := Snapshot{TakenAt: taken, Source: "prod-db-1", Dialect: "postgres", Database: db}
+
+	for _, name := range []string{"snap.json", "snap.yaml", "snap.textpb", "snap.pb"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := SaveSnapshot(snap, path); err != nil {
+			t.Fatalf("%s: save failed: %v", name, err)
+		}
+		loaded, err := LoadSnapshot(path)
+		if err != nil {
+			t.Fatalf("%s: load failed: %v", name, err)
+		}
+		if !loaded.TakenAt.Equal(taken) || loaded.Source != "prod-db-1" || loaded.Dialect != "postgres" {
+			t.Errorf("%s: unexpected provenance %v %q %q", name, loaded.TakenAt, loaded.Source, loaded.Dialect)
+		}
+		if changes, warnings, err := DiffSnapshots(snap, loaded); err != nil || len(changes) != 0 || len(warnings) != 0 {
+			t.Errorf("%s: expected no drift, got %v, %v, %v", name, changes, warnings, err)
+		}
+	}
+
+	if err := SaveSnapshot(snap, filepath.Join(t.TempDir(), "snap.sql")); err == nil {
+		t.Error("Expected an error saving a snapshot as SQL")
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, warnings, err := DiffSnapshots(Snapshot{Dialect: "postgres", Database: before}, Snapshot{Dialect: "postgres", Database: after})
+	if err != nil || len(changes) != 1 || len(warnings) != 0 {
+		t.Fatalf("Expected one change, got %v, %v, %v", changes, warnings, err)
+	}
+
+	changes, warnings, err = DiffSnapshots(Snapshot{Dialect: "postgres", Database: before}, Snapshot{Dialect: "mysql", Database: after})
+	if err != nil {
+		t.Errorf("Expected a dialect mismatch to be a warning, not an error, got %v", err)
+	}
+	if want := []DialectMismatchWarning{{Old: "postgres", New: "mysql"}}; !slices.Equal(warnings, want) {
+		t.Errorf("Expected warnings %v, got %v", want, warnings)
+	}
+	if len(changes) != 1 {
+		t.Errorf("Expected the changes along with the warning, got %v", changes)
+	}
+
+	if _, _, err := DiffSnapshots(Snapshot{}, Snapshot{Database: after}); err == nil {
+		t.Error("Expected an error for a snapshot without database")
+	}
+}
@@ -0,0 +1,335 @@
+package xmeta
+
+// spanner_loader.go loads a Cloud Spanner (GoogleSQL) database straight
+// into the unified model from its INFORMATION_SCHEMA.
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+)
+
+// spannerSchemaFilter skips the system schemas in every catalog query.
+const spannerSchemaFilter = "TABLE_SCHEMA NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')"
+
+// LoadSpanner loads the tables, columns, primary keys, foreign keys,
+// secondary indexes and views of the Cloud Spanner database client is
+// connected to into a MetaDatabase, reading every catalog query from the
+// same read-only snapshot. Tables in the default schema have a bare name.
+// An interleaved table records its parent in Options["InterleaveIn"] and
+// the parent's ON DELETE action in Options["InterleaveOnDelete"].
+func LoadSpanner(ctx context.Context, client *spanner.Client) (*MetaDatabase, error) {
+	meta := &MetaDatabase{Options: map[string]string{"SourceDialect": "spanner"}}
+
+	txn := client.ReadOnlyTransaction()
+	defer txn.Close()
+
+	tables, err := loadSpannerTables(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	lookup := tableLookup(tables)
+	if err := loadSpannerColumns(ctx, txn, lookup); err != nil {
+		return nil, err
+	}
+	if err := loadSpannerIndexes(ctx, txn, lookup); err != nil {
+		return nil, err
+	}
+	if err := loadSpannerForeignKeys(ctx, txn, lookup); err != nil {
+		return nil, err
+	}
+	meta.Tables = tables
+
+	views, err := loadSpannerViews(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	meta.Views = views
+	return meta, nil
+}
+
+// spannerName returns the ObjectName of a Spanner object, bare in the
+// default schema "".
+func spannerName(schema, name string) *ObjectName {
+	if schema == "" {
+		return &ObjectName{Idents: []string{name}}
+	}
+	return &ObjectName{Idents: []string{schema, name}}
+}
+
+func loadSpannerTables(ctx context.Context, txn *spanner.ReadOnlyTransaction) ([]*MetaTable, error) {
+	query := `
+		SELECT TABLE_SCHEMA, TABLE_NAME, PARENT_TABLE_NAME, ON_DELETE_ACTION
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_TYPE = 'BASE TABLE' AND ` + spannerSchemaFilter + `
+		ORDER BY TABLE_SCHEMA, TABLE_NAME`
+
+	var tables []*MetaTable
+	err := txn.Query(ctx, spanner.NewStatement(query)).Do(func(row *spanner.Row) error {
+		var schema, name, parent, onDelete spanner.NullString
+		if err := row.Columns(&schema, &name, &parent, &onDelete); err != nil {
+			return err
+		}
+		table := &MetaTable{
+			Name:    spannerName(schema.StringVal, name.StringVal),
+			Type:    "BASE TABLE",
+			Options: make(map[string]string),
+		}
+		if parent.StringVal != "" {
+			table.Options["InterleaveIn"] = objectNameKey(spannerName(schema.StringVal, parent.StringVal))
+			if onDelete.StringVal != "" {
+				table.Options["InterleaveOnDelete"] = onDelete.StringVal
+			}
+		}
+		tables = append(tables, table)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	return tables, nil
+}
+
+func loadSpannerColumns(ctx context.Context, txn *spanner.ReadOnlyTransaction, lookup func(string) *MetaTable) error {
+	query := `
+		SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, SPANNER_TYPE, IS_NULLABLE,
+		       COLUMN_DEFAULT, IS_GENERATED, GENERATION_EXPRESSION, IS_STORED
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE ` + spannerSchemaFilter + `
+		ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION`
+
+	err := txn.Query(ctx, spanner.NewStatement(query)).Do(func(row *spanner.Row) error {
+		var schema, tableName, name, typ, nullable, def, generated, expr, stored spanner.NullString
+		if err := row.Columns(&schema, &tableName, &name, &typ, &nullable, &def, &generated, &expr, &stored); err != nil {
+			return err
+		}
+		table := lookup(objectNameKey(spannerName(schema.StringVal, tableName.StringVal)))
+		if table == nil {
+			return nil // a view column
+		}
+
+		col := &ColumnDef{
+			Name:     name.StringVal,
+			DataType: mapSpannerType(typ.StringVal),
+			Default:  stringToAny(def.StringVal),
+			Options:  make(map[string]string),
+		}
+		if generated.StringVal == "ALWAYS" {
+			col.Options["IsGenerated"] = "true"
+			col.Options["GenerationExpression"] = expr.StringVal
+			if stored.StringVal == "YES" {
+				col.Options["GenerationKind"] = "STORED"
+			} else {
+				col.Options["GenerationKind"] = "VIRTUAL"
+			}
+		}
+		if nullable.StringVal == "NO" {
+			col.Constraints = append(col.Constraints, &ColumnConstraint{
+				Spec: &ColumnConstraintSpec{
+					ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{
+						NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm,
+					},
+				},
+			})
+		}
+		table.Elements = append(table.Elements, &TableElement{
+			TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col},
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query columns: %w", err)
+	}
+	return nil
+}
+
+// loadSpannerIndexes adds the primary key of every table as a constraint
+// and its secondary indexes as MetaIndexes. STORING columns of an index are
+// kept in Options["Storing"].
+func loadSpannerIndexes(ctx context.Context, txn *spanner.ReadOnlyTransaction, lookup func(string) *MetaTable) error {
+	query := `
+		SELECT i.TABLE_SCHEMA, i.TABLE_NAME, i.INDEX_NAME, i.INDEX_TYPE, i.IS_UNIQUE,
+		       i.IS_NULL_FILTERED, c.COLUMN_NAME, c.ORDINAL_POSITION
+		FROM INFORMATION_SCHEMA.INDEXES i
+		JOIN INFORMATION_SCHEMA.INDEX_COLUMNS c
+		  ON c.TABLE_SCHEMA = i.TABLE_SCHEMA AND c.TABLE_NAME = i.TABLE_NAME AND c.INDEX_NAME = i.INDEX_NAME
+		WHERE i.INDEX_TYPE IN ('PRIMARY_KEY', 'INDEX') AND i.` + spannerSchemaFilter + `
+		ORDER BY i.TABLE_SCHEMA, i.TABLE_NAME, i.INDEX_NAME, c.ORDINAL_POSITION`
+
+	keys := make(map[*MetaTable]*UniqueTableConstraint)
+	indexes := make(map[string]*MetaIndex)
+	err := txn.Query(ctx, spanner.NewStatement(query)).Do(func(row *spanner.Row) error {
+		var schema, tableName, name, indexType, column spanner.NullString
+		var unique, nullFiltered spanner.NullBool
+		var position spanner.NullInt64
+		if err := row.Columns(&schema, &tableName, &name, &indexType, &unique, &nullFiltered, &column, &position); err != nil {
+			return err
+		}
+		table := lookup(objectNameKey(spannerName(schema.StringVal, tableName.StringVal)))
+		if table == nil {
+			return nil
+		}
+
+		if indexType.StringVal == "PRIMARY_KEY" {
+			pk := keys[table]
+			if pk == nil {
+				pk = &UniqueTableConstraint{IsPrimary: true}
+				keys[table] = pk
+				table.Elements = append(table.Elements, &TableElement{
+					TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+						Name: name.StringVal,
+						Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{UniqueItem: pk}},
+					}},
+				})
+			}
+			pk.Columns = append(pk.Columns, column.StringVal)
+			return nil
+		}
+
+		key := objectNameKey(table.Name) + "." + name.StringVal
+		idx := indexes[key]
+		if idx == nil {
+			idx = &MetaIndex{Name: name.StringVal, IsUnique: unique.Bool}
+			if nullFiltered.Bool {
+				idx.Options = map[string]string{"NullFiltered": "true"}
+			}
+			indexes[key] = idx
+			table.Indexes = append(table.Indexes, idx)
+		}
+		if !position.Valid {
+			// STORING columns have no key position
+			if idx.Options == nil {
+				idx.Options = make(map[string]string)
+			}
+			idx.Options["Storing"] = strings.Join(append(optionList(idx.Options["Storing"]), column.StringVal), ",")
+			return nil
+		}
+		idx.Columns = append(idx.Columns, column.StringVal)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query indexes: %w", err)
+	}
+	return nil
+}
+
+func loadSpannerForeignKeys(ctx context.Context, txn *spanner.ReadOnlyTransaction, lookup func(string) *MetaTable) error {
+	query := `
+		SELECT k.TABLE_SCHEMA, k.TABLE_NAME, r.CONSTRAINT_NAME, k.COLUMN_NAME,
+		       u.TABLE_SCHEMA, u.TABLE_NAME, u.COLUMN_NAME, r.DELETE_RULE, r.UPDATE_RULE
+		FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS r
+		JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+		  ON k.CONSTRAINT_SCHEMA = r.CONSTRAINT_SCHEMA AND k.CONSTRAINT_NAME = r.CONSTRAINT_NAME
+		JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE u
+		  ON u.CONSTRAINT_SCHEMA = r.UNIQUE_CONSTRAINT_SCHEMA AND u.CONSTRAINT_NAME = r.UNIQUE_CONSTRAINT_NAME
+		 AND u.ORDINAL_POSITION = k.POSITION_IN_UNIQUE_CONSTRAINT
+		WHERE k.` + spannerSchemaFilter + `
+		ORDER BY k.TABLE_SCHEMA, k.TABLE_NAME, r.CONSTRAINT_NAME, k.ORDINAL_POSITION`
+
+	fks := make(map[string]*ReferentialTableConstraint)
+	err := txn.Query(ctx, spanner.NewStatement(query)).Do(func(row *spanner.Row) error {
+		var schema, tableName, name, column, refSchema, refTable, refColumn, onDelete, onUpdate spanner.NullString
+		if err := row.Columns(&schema, &tableName, &name, &column, &refSchema, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			return err
+		}
+		table := lookup(objectNameKey(spannerName(schema.StringVal, tableName.StringVal)))
+		if table == nil {
+			return nil
+		}
+
+		key := objectNameKey(table.Name) + "." + name.StringVal
+		fk := fks[key]
+		if fk == nil {
+			fk = &ReferentialTableConstraint{
+				KeyExpr:  &ReferenceKeyExpr{TableName: objectNameKey(spannerName(refSchema.StringVal, refTable.StringVal))},
+				OnDelete: mapReferentialAction(onDelete.StringVal),
+				OnUpdate: mapReferentialAction(onUpdate.StringVal),
+			}
+			fks[key] = fk
+			table.Elements = append(table.Elements, &TableElement{
+				TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+					Name: name.StringVal,
+					Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{ReferenceItem: fk}},
+				}},
+			})
+		}
+		fk.Columns = append(fk.Columns, column.StringVal)
+		fk.KeyExpr.Columns = append(fk.KeyExpr.Columns, refColumn.StringVal)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	return nil
+}
+
+func loadSpannerViews(ctx context.Context, txn *spanner.ReadOnlyTransaction) ([]*MetaView, error) {
+	query := `
+		SELECT TABLE_SCHEMA, TABLE_NAME, VIEW_DEFINITION
+		FROM INFORMATION_SCHEMA.VIEWS
+		WHERE ` + spannerSchemaFilter + `
+		ORDER BY TABLE_SCHEMA, TABLE_NAME`
+
+	var views []*MetaView
+	err := txn.Query(ctx, spanner.NewStatement(query)).Do(func(row *spanner.Row) error {
+		var schema, name, definition spanner.NullString
+		if err := row.Columns(&schema, &name, &definition); err != nil {
+			return err
+		}
+		views = append(views, &MetaView{
+			Name:       spannerName(schema.StringVal, name.StringVal),
+			Definition: definition.StringVal,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// mapSpannerType maps a SPANNER_TYPE such as "STRING(MAX)", "INT64" or
+// "ARRAY<STRING(64)>" to the unified DataType, using the same types as the
+// BigQuery loader where the two agree. Unknown types are kept as custom.
+func mapSpannerType(s string) *DataType {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	if elem, ok := strings.CutPrefix(upper, "ARRAY<"); ok && strings.HasSuffix(elem, ">") {
+		return &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: mapSpannerType(s[len("ARRAY<") : len(s)-1])}}}
+	}
+
+	base, size := upper, ""
+	if open := strings.IndexByte(upper, '('); open > 0 && strings.HasSuffix(upper, ")") {
+		base, size = upper[:open], upper[open+1:len(upper)-1]
+	}
+
+	switch base {
+	case "INT64":
+		return &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}
+	case "FLOAT64":
+		return &DataType{TypeClause: &DataType_FloatData{FloatData: &Float{}}}
+	case "FLOAT32":
+		return &DataType{TypeClause: &DataType_RealData{RealData: &Real{}}}
+	case "BOOL":
+		return &DataType{TypeClause: &DataType_BooleanData{BooleanData: DataTypeSingle_Boolean}}
+	case "STRING":
+		if n, err := strconv.ParseUint(size, 10, 32); err == nil {
+			return &DataType{TypeClause: &DataType_VarcharData{VarcharData: &VarcharType{Size: uint32(n)}}}
+		}
+		return &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}
+	case "BYTES":
+		return &DataType{TypeClause: &DataType_ByteaData{ByteaData: DataTypeSingle_Bytea}}
+	case "DATE":
+		return &DataType{TypeClause: &DataType_DateData{DateData: DataTypeSingle_Date}}
+	case "TIMESTAMP":
+		return &DataType{TypeClause: &DataType_TimestampData{TimestampData: &Timestamp{WithTimeZone: true}}}
+	case "NUMERIC":
+		return &DataType{TypeClause: &DataType_DecimalData{DecimalData: &Decimal{Precision: 38, Scale: 9}}}
+	case "JSON":
+		return &DataType{TypeClause: &DataType_JSONData{JSONData: DataTypeSingle_JSON}}
+	}
+	return &DataType{TypeClause: &DataType_CustomData{CustomData: &ObjectName{Idents: []string{s}}}}
+}
@@ -0,0 +1,41 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestMapSpannerType(t *testing.T) {
+	tests := []struct {
+		spanner string
+		sql     string
+	}{
+		{"INT64", "INTEGER"},
+		{"STRING(MAX)", "TEXT"},
+		{"STRING(64)", "VARCHAR(64)"},
+		{"BOOL", "BOOLEAN"},
+		{"TIMESTAMP", "TIMESTAMP WITH TIME ZONE"},
+		{"ARRAY<STRING(MAX)>", "TEXT[]"},
+	}
+	for _, tt := range tests {
+		got, err := dataTypeSQL(mapSpannerType(tt.spanner), DialectPostgres)
+		if err != nil || got != tt.sql {
+			t.Errorf("mapSpannerType(%q) = %q (%v), want %q", tt.spanner, got, err, tt.sql)
+		}
+	}
+
+	if dt := mapSpannerType("TOKENLIST"); dt.GetCustomData() == nil {
+		t.Errorf("Expected TOKENLIST to stay custom, got %v", dt)
+	}
+	if dt := mapSpannerType("NUMERIC"); dt.GetDecimalData().GetPrecision() != 38 {
+		t.Errorf("Expected NUMERIC(38, 9), got %v", dt)
+	}
+}
+
+func TestSpannerName(t *testing.T) {
+	if got := objectNameKey(spannerName("", "Singers")); got != "Singers" {
+		t.Errorf("Expected a bare name in the default schema, got %s", got)
+	}
+	if got := objectNameKey(spannerName("music", "Singers")); got != "music.Singers" {
+		t.Errorf("Expected a qualified name, got %s", got)
+	}
+}
@@ -0,0 +1,1934 @@
+package xmeta
+
+// sql_loader.go parses CREATE TABLE DDL into the unified MetaDatabase model.
+//
+// The grammar is Postgres-flavored and shared by the other dialects; the few
+// dialect-specific pieces (identifier quoting, MySQL table options, SQLite
+// WITHOUT ROWID and STRICT) are switched on sqlParser.dialect. Statements
+// are applied in order, as a database would run them: CREATE, ALTER and
+// DROP TABLE, CREATE INDEX and VIEW and COMMENT ON build the model.
+// Statements on objects the model does not hold, such as sequences,
+// functions, grants and session settings, are skipped.
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LoadMetaDatabaseFromSQL parses CREATE TABLE statements, and the ALTER
+// TABLE, DROP TABLE, CREATE INDEX and COMMENT ON statements that amend
+// them, into a MetaDatabase, so a pg_dump or mysqldump schema loads with
+// the keys it adds after the tables. An ALTER TABLE action that would
+// change the model but is not understood is an error rather than being
+// skipped, as is creating a table twice or altering one that is not defined.
+func LoadMetaDatabaseFromSQL(sql string, dialect Dialect) (*MetaDatabase, error) {
+	switch dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return nil, fmt.Errorf("parsing SQL is not supported for %s", dialect)
+	}
+
+	toks, err := tokenizeSQL(sql, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &MetaDatabase{}
+	for _, stmt := range splitSQLStatements(toks) {
+		p := &sqlParser{src: sql, toks: stmt, dialect: dialect}
+		if err := p.parseStatement(db); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// =============================================================================
+// Tokenizer
+// =============================================================================
+
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuotedIdent
+	sqlString
+	sqlNumber
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	// text is the unquoted value for identifiers and strings, the raw text otherwise
+	text       string
+	start, end int
+}
+
+// is reports whether the token is the given keyword or punctuation.
+func (t sqlToken) is(s string) bool {
+	return (t.kind == sqlWord || t.kind == sqlPunct) && strings.EqualFold(t.text, s)
+}
+
+func tokenizeSQL(src string, dialect Dialect) ([]sqlToken, error) {
+	var toks []sqlToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(src[i:], "--"), c == '#' && dialect == DialectMySQL:
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at offset %d", i)
+			}
+			i += end + 4
+		case c == '\'':
+			text, end, err := scanQuoted(src, i, '\'', dialect == DialectMySQL)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, sqlToken{kind: sqlString, text: text, start: i, end: end})
+			i = end
+		case c == '"' || c == '`':
+			text, end, err := scanQuoted(src, i, c, false)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, sqlToken{kind: sqlQuotedIdent, text: text, start: i, end: end})
+			i = end
+		case c == '[' && dialect == DialectSQLite:
+			end := strings.IndexByte(src[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated identifier at offset %d", i)
+			}
+			toks = append(toks, sqlToken{kind: sqlQuotedIdent, text: src[i+1 : i+end], start: i, end: i + end + 1})
+			i += end + 1
+		case c == '$' && dialect == DialectPostgres && dollarTag(src[i:]) != "":
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string at offset %d", i)
+			}
+			stop := i + len(tag) + end + len(tag)
+			toks = append(toks, sqlToken{kind: sqlString, text: src[i+len(tag) : stop-len(tag)], start: i, end: stop})
+			i = stop
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, sqlToken{kind: sqlNumber, text: src[i:j], start: i, end: j})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '$' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, sqlToken{kind: sqlWord, text: src[i:j], start: i, end: j})
+			i = j
+		default:
+			toks = append(toks, sqlToken{kind: sqlPunct, text: src[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+// scanQuoted scans a quoted token starting at src[start] and returns its
+// unescaped value and the offset after the closing quote. A doubled quote
+// is an escaped quote; backslash escapes are honoured when backslash is set.
+func scanQuoted(src string, start int, quote byte, backslash bool) (string, int, error) {
+	var sb strings.Builder
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case backslash && c == '\\' && i+1 < len(src):
+			sb.WriteByte(src[i+1])
+			i += 2
+		case c == quote && i+1 < len(src) && src[i+1] == quote:
+			sb.WriteByte(quote)
+			i += 2
+		case c == quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted text at offset %d", start)
+}
+
+// dollarTag returns the opening tag of a Postgres dollar-quoted string
+// ($$ or $tag$) at the start of s, or "" if there is none.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '$':
+			return s[:i+1]
+		case s[i] != '_' && !unicode.IsLetter(rune(s[i])):
+			return ""
+		}
+	}
+	return ""
+}
+
+// splitSQLStatements splits tokens on semicolons into statements.
+func splitSQLStatements(toks []sqlToken) [][]sqlToken {
+	var stmts [][]sqlToken
+	start := 0
+	for i, t := range toks {
+		if t.kind == sqlPunct && t.text == ";" {
+			if i > start {
+				stmts = append(stmts, toks[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		stmts = append(stmts, toks[start:])
+	}
+	return stmts
+}
+
+// splitTopLevel splits tokens on commas that are not nested in parentheses.
+func splitTopLevel(toks []sqlToken) [][]sqlToken {
+	var parts [][]sqlToken
+	depth, start := 0, 0
+	for i, t := range toks {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case t.is(",") && depth == 0:
+			parts = append(parts, toks[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, toks[start:])
+}
+
+// =============================================================================
+// Parser
+// =============================================================================
+
+type sqlParser struct {
+	src     string
+	toks    []sqlToken
+	pos     int
+	dialect Dialect
+}
+
+func (p *sqlParser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *sqlParser) peek() sqlToken {
+	if p.done() {
+		return sqlToken{kind: sqlPunct, start: len(p.src), end: len(p.src)}
+	}
+	return p.toks[p.pos]
+}
+
+// peekIs reports whether the upcoming tokens are the given keywords.
+func (p *sqlParser) peekIs(kws ...string) bool {
+	for i, kw := range kws {
+		if p.pos+i >= len(p.toks) || !p.toks[p.pos+i].is(kw) {
+			return false
+		}
+	}
+	return true
+}
+
+// accept consumes the given keyword sequence if it is next.
+func (p *sqlParser) accept(kws ...string) bool {
+	if !p.peekIs(kws...) {
+		return false
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *sqlParser) expect(kws ...string) error {
+	if !p.accept(kws...) {
+		return p.errorf("expected %s", strings.Join(kws, " "))
+	}
+	return nil
+}
+
+func (p *sqlParser) errorf(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if p.done() {
+		return fmt.Errorf("%s at end of statement", msg)
+	}
+	return fmt.Errorf("%s near %q", msg, p.src[p.peek().start:p.peek().end])
+}
+
+// ident consumes an identifier (bare or quoted).
+func (p *sqlParser) ident() (string, error) {
+	t := p.peek()
+	if p.done() || (t.kind != sqlWord && t.kind != sqlQuotedIdent) {
+		return "", p.errorf("expected identifier")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+// objectName consumes a possibly qualified name (a.b.c).
+func (p *sqlParser) objectName() (*ObjectName, error) {
+	name := &ObjectName{}
+	for {
+		id, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		name.Idents = append(name.Idents, id)
+		if !p.accept(".") {
+			return name, nil
+		}
+	}
+}
+
+// identList consumes a parenthesized, comma-separated list of column names.
+// MySQL prefix lengths and ASC/DESC modifiers are skipped.
+func (p *sqlParser) identList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		id, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, id)
+		if p.peekIs("(") {
+			p.skipParens()
+		}
+		p.accept("ASC")
+		p.accept("DESC")
+		if p.accept(")") {
+			return names, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipParens consumes a balanced parenthesized group and returns the source
+// text between the outer parentheses.
+func (p *sqlParser) skipParens() string {
+	open := p.peek()
+	depth := 0
+	for !p.done() {
+		t := p.peek()
+		p.pos++
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(p.src[open.end:t.start])
+			}
+		}
+	}
+	return strings.TrimSpace(p.src[open.end:])
+}
+
+// parenExpr consumes "( expr )" and returns the expression text.
+func (p *sqlParser) parenExpr() (string, error) {
+	if !p.peekIs("(") {
+		return "", p.errorf("expected (")
+	}
+	return p.skipParens(), nil
+}
+
+// exprUntil consumes an expression up to (not including) one of the stop
+// keywords at nesting depth zero and returns its source text. The first
+// token is always consumed.
+func (p *sqlParser) exprUntil(stop map[string]bool) string {
+	first := p.peek()
+	last := first
+	depth := 0
+	for i := 0; !p.done(); i++ {
+		t := p.peek()
+		if i > 0 && depth == 0 && t.kind == sqlWord && stop[strings.ToUpper(t.text)] {
+			break
+		}
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		}
+		last = t
+		p.pos++
+	}
+	return strings.TrimSpace(p.src[first.start:last.end])
+}
+
+// =============================================================================
+// Statements
+// =============================================================================
+
+func (p *sqlParser) parseStatement(db *MetaDatabase) error {
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		if !p.accept("GLOBAL") {
+			p.accept("LOCAL")
+		}
+		for _, kw := range []string{"TEMPORARY", "TEMP", "UNLOGGED"} {
+			p.accept(kw)
+		}
+		if p.peekIs("UNIQUE") || p.peekIs("INDEX") || p.peekIs("FULLTEXT") || p.peekIs("SPATIAL") {
+			return p.parseCreateIndex(db)
+		}
+		if view, ok, err := p.parseCreateView(); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			db.Views = append(db.Views, view)
+			return nil
+		}
+		if !p.accept("TABLE") {
+			return nil // CREATE FUNCTION, ... are not table definitions
+		}
+		ifNotExists := p.peekIs("IF", "NOT", "EXISTS")
+		table, err := p.parseCreateTable()
+		if err != nil {
+			return err
+		}
+		if definedTable(db, table.Name) != nil {
+			if ifNotExists {
+				return nil
+			}
+			return fmt.Errorf("parsing CREATE TABLE %s: table is already defined", formatObjectName(table.Name))
+		}
+		db.Tables = append(db.Tables, table)
+	case p.accept("ALTER", "TABLE"):
+		return p.parseAlterTable(db)
+	case p.accept("DROP", "TABLE"):
+		return p.parseDropTable(db)
+	case p.accept("COMMENT", "ON"):
+		return p.parseComment(db)
+	}
+	return nil
+}
+
+// definedTable returns the table of db named exactly name, or nil.
+func definedTable(db *MetaDatabase, name *ObjectName) *MetaTable {
+	key := objectNameKey(name)
+	for _, t := range db.Tables {
+		if objectNameKey(t.Name) == key {
+			return t
+		}
+	}
+	return nil
+}
+
+// parseDropTable removes the tables named by DROP TABLE [IF EXISTS] a, b
+// [CASCADE | RESTRICT].
+func (p *sqlParser) parseDropTable(db *MetaDatabase) error {
+	ifExists := p.accept("IF", "EXISTS")
+	for {
+		name, err := p.objectName()
+		if err != nil {
+			return fmt.Errorf("parsing DROP TABLE: %w", err)
+		}
+		table := findTable(db, name)
+		if table == nil && !ifExists {
+			return fmt.Errorf("parsing DROP TABLE %s: table is not defined", formatObjectName(name))
+		}
+		db.Tables = slices.DeleteFunc(db.Tables, func(t *MetaTable) bool { return t == table })
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// parseCreateView parses a CREATE VIEW statement after CREATE [OR REPLACE].
+// It reports false when the statement does not create a view. The MySQL
+// ALGORITHM and DEFINER clauses are skipped; SQL SECURITY, the Postgres
+// security_barrier and check_option options and WITH CHECK OPTION are kept
+// in Options.
+func (p *sqlParser) parseCreateView() (*MetaView, bool, error) {
+	start := p.pos
+	view := &MetaView{Options: make(map[string]string)}
+	for !p.done() && !p.peekIs("VIEW") && !p.peekIs("MATERIALIZED") {
+		switch {
+		case p.accept("SQL", "SECURITY"):
+			security, err := p.ident()
+			if err != nil {
+				return nil, true, err
+			}
+			view.Options["SqlSecurity"] = strings.ToUpper(security)
+		case p.peekIs("ALGORITHM", "="), p.peekIs("DEFINER", "="):
+			p.pos += 2
+			for !p.done() && !p.peekIs("SQL") && !p.peekIs("VIEW") {
+				p.pos++
+			}
+		default:
+			p.pos = start
+			return nil, false, nil
+		}
+	}
+	if p.accept("MATERIALIZED") {
+		view.Options["Materialized"] = "true"
+	}
+	if !p.accept("VIEW") {
+		p.pos = start
+		return nil, false, nil
+	}
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.objectName()
+	if err != nil {
+		return nil, true, err
+	}
+	view.Name = name
+	if p.peekIs("(") {
+		p.skipParens() // column names
+	}
+	if p.accept("WITH") {
+		opts, err := p.parenExpr()
+		if err != nil {
+			return nil, true, err
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			key, value, _ := strings.Cut(opt, "=")
+			key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+			switch key {
+			case "security_barrier":
+				if value == "" || strings.EqualFold(value, "true") || strings.EqualFold(value, "on") {
+					view.Options["SecurityBarrier"] = "true"
+				}
+			case "check_option":
+				view.Options["CheckOption"] = strings.ToUpper(strings.Trim(value, "'"))
+			}
+		}
+	}
+	if err := p.expect("AS"); err != nil {
+		return nil, true, err
+	}
+
+	// The definition runs to the end of the statement, less WITH CHECK OPTION
+	end := len(p.toks)
+	if end-p.pos > 2 && p.toks[end-2].is("CHECK") && p.toks[end-1].is("OPTION") {
+		checkOption := "CASCADED"
+		end -= 2
+		if p.toks[end-1].is("CASCADED") || p.toks[end-1].is("LOCAL") {
+			checkOption = strings.ToUpper(p.toks[end-1].text)
+			end--
+		}
+		if p.toks[end-1].is("WITH") {
+			end--
+			view.Options["CheckOption"] = checkOption
+		} else {
+			end = len(p.toks)
+		}
+	}
+	if p.pos >= end {
+		return nil, true, p.errorf("expected view definition")
+	}
+	view.Definition = strings.TrimSpace(p.src[p.toks[p.pos].start:p.toks[end-1].end])
+	p.pos = len(p.toks)
+	return view, true, nil
+}
+
+func (p *sqlParser) parseCreateTable() (*MetaTable, error) {
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.objectName()
+	if err != nil {
+		return nil, fmt.Errorf("parsing CREATE TABLE: %w", err)
+	}
+	table := &MetaTable{Name: name, Options: make(map[string]string)}
+
+	wrap := func(err error) error {
+		return fmt.Errorf("parsing CREATE TABLE %s: %w", formatObjectName(name), err)
+	}
+
+	if !p.peekIs("(") {
+		return nil, wrap(p.errorf("expected column list"))
+	}
+	bodyStart := p.pos + 1
+	p.skipParens()
+	if !p.toks[p.pos-1].is(")") {
+		return nil, wrap(p.errorf("unterminated column list"))
+	}
+	body := p.toks[bodyStart : p.pos-1]
+
+	for _, elemToks := range splitTopLevel(body) {
+		if len(elemToks) == 0 {
+			if len(body) == 0 {
+				break
+			}
+			return nil, wrap(p.errorf("empty table element"))
+		}
+		ep := &sqlParser{src: p.src, toks: elemToks, dialect: p.dialect}
+		if p.dialect == DialectMySQL && (ep.peekIs("KEY") || ep.peekIs("INDEX") || ep.peekIs("FULLTEXT") || ep.peekIs("SPATIAL")) {
+			idx, err := ep.parseInlineIndex()
+			if err != nil {
+				return nil, wrap(err)
+			}
+			table.Indexes = append(table.Indexes, idx)
+			continue
+		}
+		elem, err := ep.parseTableElement(name)
+		if err != nil {
+			return nil, wrap(err)
+		}
+		if elem != nil {
+			table.Elements = append(table.Elements, elem)
+		}
+	}
+
+	if err := p.parseTableOptions(table); err != nil {
+		return nil, wrap(err)
+	}
+	if p.dialect == DialectMySQL {
+		nameMySQLKeys(table)
+	}
+	nameUnnamedConstraints(table)
+	return table, nil
+}
+
+// nameMySQLKeys applies MySQL's own naming to unnamed keys: the primary key
+// is always PRIMARY, and a unique key is named after its first column.
+func nameMySQLKeys(table *MetaTable) {
+	for _, elem := range table.Elements {
+		tc := elem.GetTableConstraintElement()
+		u := tc.GetSpec().GetUniqueItem()
+		switch {
+		case u == nil:
+		case u.IsPrimary:
+			tc.Name = "PRIMARY"
+		case tc.Name == "" && len(u.Columns) > 0:
+			tc.Name = u.Columns[0]
+		}
+	}
+}
+
+// parseTableOptions handles what follows the column list: MySQL
+// "ENGINE=InnoDB ROW_FORMAT=COMPRESSED ..." options and SQLite "WITHOUT ROWID" and "STRICT". Other trailing
+// clauses (INHERITS, PARTITION BY, WITH (...), ...) are ignored.
+func (p *sqlParser) parseTableOptions(table *MetaTable) error {
+	for !p.done() {
+		switch {
+		case p.dialect == DialectSQLite && p.accept("WITHOUT", "ROWID"):
+			table.Options["WithoutRowID"] = "true"
+		case p.dialect == DialectSQLite && p.accept("STRICT"):
+			table.Options["Strict"] = "true"
+		case p.dialect == DialectMySQL && p.accept("ENGINE"):
+			p.accept("=")
+			v, err := p.ident()
+			if err != nil {
+				return err
+			}
+			table.Options["Engine"] = v
+		case p.dialect == DialectMySQL && (p.accept("DEFAULT", "CHARSET") || p.accept("CHARSET") ||
+			p.accept("DEFAULT", "CHARACTER", "SET") || p.accept("CHARACTER", "SET")):
+			p.accept("=")
+			v, err := p.ident()
+			if err != nil {
+				return err
+			}
+			table.Options["Charset"] = v
+		case p.dialect == DialectMySQL && (p.accept("DEFAULT", "COLLATE") || p.accept("COLLATE")):
+			p.accept("=")
+			v, err := p.ident()
+			if err != nil {
+				return err
+			}
+			table.Options["Collation"] = v
+		case p.dialect == DialectMySQL && p.accept("ROW_FORMAT"):
+			p.accept("=")
+			v, err := p.ident()
+			if err != nil {
+				return err
+			}
+			table.Options["RowFormat"] = strings.ToUpper(v)
+		case p.dialect == DialectMySQL && p.accept("KEY_BLOCK_SIZE"):
+			p.accept("=")
+			if p.peek().kind != sqlNumber {
+				return p.errorf("expected key block size")
+			}
+			table.Options["KeyBlockSize"] = p.peek().text
+			p.pos++
+		case p.dialect == DialectMySQL && p.accept("COMMENT"):
+			p.accept("=")
+			if p.peek().kind != sqlString {
+				return p.errorf("expected comment string")
+			}
+			table.Comment = p.peek().text
+			p.pos++
+		default:
+			p.pos++
+		}
+	}
+	return nil
+}
+
+// parseComment handles COMMENT ON TABLE t IS '...', COMMENT ON COLUMN t.c
+// IS '...' and COMMENT ON INDEX i IS '...'.
+func (p *sqlParser) parseComment(db *MetaDatabase) error {
+	isColumn := p.accept("COLUMN")
+	isIndex := !isColumn && p.accept("INDEX")
+	if !isColumn && !isIndex && !p.accept("TABLE") {
+		return nil // comments on other objects are not modelled
+	}
+	name, err := p.objectName()
+	if err != nil {
+		return fmt.Errorf("parsing COMMENT ON: %w", err)
+	}
+	if err := p.expect("IS"); err != nil {
+		return fmt.Errorf("parsing COMMENT ON: %w", err)
+	}
+	var comment string
+	if !p.accept("NULL") {
+		if p.peek().kind != sqlString {
+			return fmt.Errorf("parsing COMMENT ON: %w", p.errorf("expected comment string"))
+		}
+		comment = p.peek().text
+	}
+
+	if isIndex {
+		// An index lives in the schema of its table
+		for _, t := range db.Tables {
+			for _, idx := range t.Indexes {
+				if objectNameKey(indexObjectName(t.Name, idx.Name)) == objectNameKey(name) || idx.Name == objectNameKey(name) {
+					idx.Comment = comment
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("parsing COMMENT ON: index %s is not defined", formatObjectName(name))
+	}
+
+	tableName := name
+	var column string
+	if isColumn {
+		if len(name.Idents) < 2 {
+			return fmt.Errorf("parsing COMMENT ON COLUMN %s: expected table.column", formatObjectName(name))
+		}
+		tableName = &ObjectName{Idents: name.Idents[:len(name.Idents)-1]}
+		column = name.Idents[len(name.Idents)-1]
+	}
+
+	for _, t := range db.Tables {
+		if objectNameKey(t.Name) != objectNameKey(tableName) {
+			continue
+		}
+		if !isColumn {
+			t.Comment = comment
+			return nil
+		}
+		for _, elem := range t.Elements {
+			if col := elem.GetColumnDefElement(); col != nil && col.Name == column {
+				col.Comment = comment
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("parsing COMMENT ON: %s is not defined", formatObjectName(name))
+}
+
+// =============================================================================
+// ALTER TABLE
+// =============================================================================
+
+// parseAlterTable applies ALTER TABLE [IF EXISTS] [ONLY] name action, ... to
+// a table defined earlier in the script. The actions that change what the
+// model records are applied: adding and dropping columns, constraints and
+// MySQL indexes, ALTER COLUMN defaults, nullability, types and identity,
+// MySQL MODIFY and CHANGE, and renames. Actions on what it does not record,
+// such as OWNER TO, triggers, row security and storage parameters, are
+// skipped. Any other action is an error.
+func (p *sqlParser) parseAlterTable(db *MetaDatabase) error {
+	ifExists := p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	name, err := p.objectName()
+	if err != nil {
+		return fmt.Errorf("parsing ALTER TABLE: %w", err)
+	}
+	p.accept("*")
+	table := findTable(db, name)
+	if table == nil {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("parsing ALTER TABLE %s: table is not defined", formatObjectName(name))
+	}
+	if p.done() {
+		return fmt.Errorf("parsing ALTER TABLE %s: %w", formatObjectName(name), p.errorf("expected action"))
+	}
+	for _, actionToks := range splitTopLevel(p.toks[p.pos:]) {
+		ap := &sqlParser{src: p.src, toks: actionToks, dialect: p.dialect}
+		if err := ap.parseAlterAction(db, table); err != nil {
+			return fmt.Errorf("parsing ALTER TABLE %s: %w", formatObjectName(name), err)
+		}
+	}
+	return nil
+}
+
+// alterSkipped are the ALTER TABLE actions on what the model does not
+// record. Partitions are not parsed from files.
+var alterSkipped = [][]string{
+	{"OWNER", "TO"}, {"ENABLE"}, {"DISABLE"}, {"FORCE"}, {"NO", "FORCE"},
+	{"REPLICA", "IDENTITY"}, {"CLUSTER", "ON"}, {"SET", "WITHOUT"}, {"SET", "("}, {"RESET", "("},
+	{"SET", "LOGGED"}, {"SET", "UNLOGGED"}, {"SET", "TABLESPACE"}, {"SET", "ACCESS", "METHOD"},
+	{"SET", "SCHEMA"}, {"VALIDATE", "CONSTRAINT"}, {"ATTACH", "PARTITION"}, {"DETACH", "PARTITION"},
+	{"INHERIT"}, {"NO", "INHERIT"}, {"OF"}, {"NOT", "OF"},
+}
+
+func (p *sqlParser) parseAlterAction(db *MetaDatabase, table *MetaTable) error {
+	switch {
+	case p.accept("ADD"):
+		return p.parseAlterAdd(table)
+	case p.accept("DROP"):
+		return p.parseAlterDrop(table)
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		return p.parseAlterColumn(table)
+	case p.dialect == DialectMySQL && p.accept("MODIFY"):
+		p.accept("COLUMN")
+		return p.parseAlterReplaceColumn(db, table, "")
+	case p.dialect == DialectMySQL && p.accept("CHANGE"):
+		p.accept("COLUMN")
+		old, err := p.ident()
+		if err != nil {
+			return err
+		}
+		return p.parseAlterReplaceColumn(db, table, old)
+	case p.accept("RENAME"):
+		return p.parseAlterRename(db, table)
+	}
+	for _, kws := range alterSkipped {
+		if p.peekIs(kws...) {
+			return nil
+		}
+	}
+	return p.errorf("unsupported ALTER TABLE action")
+}
+
+// parseAlterAdd handles ADD of a constraint, a MySQL index or a column.
+func (p *sqlParser) parseAlterAdd(table *MetaTable) error {
+	switch {
+	case p.dialect == DialectMySQL && (p.peekIs("KEY") || p.peekIs("INDEX") || p.peekIs("FULLTEXT") || p.peekIs("SPATIAL")):
+		idx, err := p.parseInlineIndex()
+		if err != nil {
+			return err
+		}
+		table.Indexes = append(table.Indexes, idx)
+		return nil
+	case p.peekIs("CONSTRAINT"), p.peekIs("PRIMARY"), p.peekIs("UNIQUE"), p.peekIs("CHECK"), p.peekIs("FOREIGN"), p.peekIs("EXCLUDE"):
+		elem, err := p.parseTableElement(table.Name)
+		if err != nil {
+			return err
+		}
+		tc := elem.GetTableConstraintElement()
+		if tc.Name != "" && slices.ContainsFunc(table.Elements, func(e *TableElement) bool {
+			return e.GetTableConstraintElement().GetName() == tc.Name
+		}) {
+			return fmt.Errorf("constraint %s is already defined", tc.Name)
+		}
+		table.Elements = append(table.Elements, elem)
+		if p.dialect == DialectMySQL {
+			nameMySQLKeys(table)
+		}
+		nameUnnamedConstraints(table)
+		return nil
+	}
+
+	p.accept("COLUMN")
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	after, first := p.trimColumnPosition()
+	col, err := p.parseColumn(table.Name)
+	if err != nil {
+		return err
+	}
+	if columnsFromElements(table.Elements)[col.Name] != nil {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("column %s is already defined", col.Name)
+	}
+	insertColumn(table, col, after, first)
+	return nil
+}
+
+// trimColumnPosition removes a trailing MySQL FIRST or AFTER column clause
+// and returns the column named by AFTER, or reports FIRST.
+func (p *sqlParser) trimColumnPosition() (after string, first bool) {
+	n := len(p.toks)
+	switch {
+	case n > p.pos+1 && p.toks[n-1].is("FIRST"):
+		p.toks = p.toks[:n-1]
+		return "", true
+	case n > p.pos+2 && p.toks[n-2].is("AFTER"):
+		after = p.toks[n-1].text
+		p.toks = p.toks[:n-2]
+	}
+	return after, false
+}
+
+// insertColumn adds col to table after the column named after, first, or
+// after the last column.
+func insertColumn(table *MetaTable, col *ColumnDef, after string, first bool) {
+	elem := &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}}
+	at := 0
+	for i, e := range table.Elements {
+		if c := e.GetColumnDefElement(); c != nil && !first {
+			at = i + 1
+			if c.Name == after {
+				break
+			}
+		}
+	}
+	table.Elements = slices.Insert(table.Elements, at, elem)
+}
+
+// parseAlterDrop handles DROP of a constraint, a MySQL key or index, or a
+// column. A dropped column takes the constraints and indexes on it along.
+func (p *sqlParser) parseAlterDrop(table *MetaTable) error {
+	dropConstraint := func(match func(*TableConstraint) bool) bool {
+		n := len(table.Elements)
+		table.Elements = slices.DeleteFunc(table.Elements, func(e *TableElement) bool {
+			tc := e.GetTableConstraintElement()
+			return tc != nil && match(tc)
+		})
+		return len(table.Elements) < n
+	}
+
+	switch {
+	case p.accept("CONSTRAINT"), p.dialect == DialectMySQL && (p.accept("FOREIGN", "KEY") || p.accept("CHECK")):
+		ifExists := p.accept("IF", "EXISTS")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if !dropConstraint(func(tc *TableConstraint) bool { return tc.Name == name }) && !ifExists {
+			return fmt.Errorf("constraint %s is not defined", name)
+		}
+		return nil
+	case p.dialect == DialectMySQL && p.accept("PRIMARY", "KEY"):
+		if !dropConstraint(func(tc *TableConstraint) bool { return tc.GetSpec().GetUniqueItem().GetIsPrimary() }) {
+			return fmt.Errorf("primary key is not defined")
+		}
+		return nil
+	case p.dialect == DialectMySQL && (p.accept("INDEX") || p.accept("KEY")):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		n := len(table.Indexes)
+		table.Indexes = slices.DeleteFunc(table.Indexes, func(idx *MetaIndex) bool { return idx.Name == name })
+		if len(table.Indexes) == n && !dropConstraint(func(tc *TableConstraint) bool { return tc.Name == name }) {
+			return fmt.Errorf("index %s is not defined", name)
+		}
+		return nil
+	}
+
+	p.accept("COLUMN")
+	ifExists := p.accept("IF", "EXISTS")
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if columnsFromElements(table.Elements)[name] == nil {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("column %s is not defined", name)
+	}
+	table.Elements = slices.DeleteFunc(table.Elements, func(e *TableElement) bool {
+		return e.GetColumnDefElement().GetName() == name
+	})
+	dropConstraint(func(tc *TableConstraint) bool {
+		spec := tc.GetSpec()
+		return slices.Contains(spec.GetUniqueItem().GetColumns(), name) || slices.Contains(spec.GetReferenceItem().GetColumns(), name)
+	})
+	table.Indexes = slices.DeleteFunc(table.Indexes, func(idx *MetaIndex) bool { return slices.Contains(idx.Columns, name) })
+	return nil
+}
+
+// parseAlterColumn handles ALTER [COLUMN] name and what follows.
+func (p *sqlParser) parseAlterColumn(table *MetaTable) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	col := columnsFromElements(table.Elements)[name]
+	if col == nil {
+		return fmt.Errorf("column %s is not defined", name)
+	}
+	switch {
+	case p.accept("SET", "DEFAULT"):
+		if p.done() {
+			return p.errorf("expected default value")
+		}
+		col.Default = nil
+		if expr := p.exprUntil(nil); !strings.EqualFold(expr, "NULL") {
+			col.Default = stringToAny(expr)
+		}
+	case p.accept("DROP", "DEFAULT"):
+		col.Default = nil
+	case p.accept("SET", "NOT", "NULL"):
+		setNotNull(col, true)
+	case p.accept("DROP", "NOT", "NULL"):
+		setNotNull(col, false)
+	case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+		if p.done() {
+			return p.errorf("expected type for column %s", name)
+		}
+		col.DataType = parseSQLDataType(p.exprUntil(map[string]bool{"USING": true, "COLLATE": true}))
+		if p.accept("COLLATE") {
+			collation, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if col.Options == nil {
+				col.Options = make(map[string]string)
+			}
+			col.Options["Collation"] = collation
+		}
+	case p.accept("ADD"):
+		if col.Options == nil {
+			col.Options = make(map[string]string)
+		}
+		return p.parseGenerated(col)
+	case p.accept("DROP", "IDENTITY"):
+		for _, key := range []string{"IsIdentity", "IdentityGeneration", "IdentityStart", "IdentityIncrement"} {
+			delete(col.Options, key)
+		}
+	case p.peekIs("SET"), p.peekIs("RESET"):
+		// SET STATISTICS, STORAGE, COMPRESSION and attribute options
+	default:
+		return p.errorf("unsupported ALTER COLUMN action")
+	}
+	return nil
+}
+
+// parseAlterReplaceColumn handles MySQL MODIFY and CHANGE: the column old,
+// or the one of the new definition's name, is replaced in place.
+func (p *sqlParser) parseAlterReplaceColumn(db *MetaDatabase, table *MetaTable, old string) error {
+	after, first := p.trimColumnPosition()
+	col, err := p.parseColumn(table.Name)
+	if err != nil {
+		return err
+	}
+	if old == "" {
+		old = col.Name
+	}
+	if old != col.Name {
+		if err := RenameColumnEverywhere(db, table.Name, old, col.Name); err != nil {
+			return err
+		}
+	}
+	i := slices.IndexFunc(table.Elements, func(e *TableElement) bool { return e.GetColumnDefElement().GetName() == col.Name })
+	if i < 0 {
+		return fmt.Errorf("column %s is not defined", old)
+	}
+	if after == "" && !first {
+		table.Elements[i] = &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}}
+		return nil
+	}
+	table.Elements = slices.Delete(table.Elements, i, i+1)
+	insertColumn(table, col, after, first)
+	return nil
+}
+
+// parseAlterRename handles RENAME [TO] table, RENAME [COLUMN] a TO b and
+// RENAME CONSTRAINT a TO b.
+func (p *sqlParser) parseAlterRename(db *MetaDatabase, table *MetaTable) error {
+	switch {
+	case p.accept("TO"), p.accept("AS"):
+		name, err := p.objectName()
+		if err != nil {
+			return err
+		}
+		return RenameObject(db, table.Name, name)
+	case p.accept("CONSTRAINT"):
+		old, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		for _, elem := range table.Elements {
+			if tc := elem.GetTableConstraintElement(); tc.GetName() == old {
+				tc.Name = name
+				return nil
+			}
+		}
+		return fmt.Errorf("constraint %s is not defined", old)
+	}
+	p.accept("COLUMN")
+	old, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("TO"); err != nil {
+		return err
+	}
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	return RenameColumnEverywhere(db, table.Name, old, name)
+}
+
+// =============================================================================
+// Table Elements
+// =============================================================================
+
+func (p *sqlParser) parseTableElement(table *ObjectName) (*TableElement, error) {
+	var name string
+	if p.accept("CONSTRAINT") {
+		var err error
+		if name, err = p.ident(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case p.peekIs("PRIMARY"), p.peekIs("UNIQUE"), p.peekIs("CHECK"), p.peekIs("FOREIGN"), p.peekIs("EXCLUDE"):
+		tc, err := p.parseTableConstraint(name)
+		if err != nil {
+			return nil, err
+		}
+		return &TableElement{TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: tc}}, nil
+	case name != "":
+		return nil, p.errorf("expected constraint definition")
+	case p.peekIs("LIKE"):
+		return nil, p.errorf("LIKE clauses are not supported")
+	}
+
+	col, err := p.parseColumn(table)
+	if err != nil {
+		return nil, err
+	}
+	return &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}}, nil
+}
+
+func (p *sqlParser) parseTableConstraint(name string) (*TableConstraint, error) {
+	tc := &TableConstraint{Name: name}
+
+	switch {
+	case p.peekIs("PRIMARY", "KEY"), p.peekIs("UNIQUE"):
+		isPrimary := p.accept("PRIMARY", "KEY")
+		u := &UniqueTableConstraint{IsPrimary: isPrimary}
+		if !isPrimary {
+			p.accept("UNIQUE")
+			if !p.accept("KEY") {
+				p.accept("INDEX")
+			}
+			// Postgres 15: UNIQUE NULLS [NOT] DISTINCT (cols)
+			if p.accept("NULLS", "NOT", "DISTINCT") {
+				u.NullsNotDistinct = true
+			} else {
+				p.accept("NULLS", "DISTINCT")
+			}
+		}
+		if !p.peekIs("(") {
+			// MySQL: UNIQUE KEY index_name (cols)
+			indexName, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			u.IndexName = indexName
+		}
+		cols, err := p.identList()
+		if err != nil {
+			return nil, err
+		}
+		u.Columns = cols
+		if p.accept("INCLUDE") {
+			if u.Include, err = p.identList(); err != nil {
+				return nil, err
+			}
+		}
+		tc.Spec = &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{UniqueItem: u}}
+	case p.accept("CHECK"):
+		expr, err := p.parenExpr()
+		if err != nil {
+			return nil, err
+		}
+		tc.Spec = &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_CheckItem{CheckItem: stringToAny(expr)}}
+	case p.accept("FOREIGN", "KEY"):
+		if !p.peekIs("(") {
+			if _, err := p.ident(); err != nil { // MySQL index name
+				return nil, err
+			}
+		}
+		cols, err := p.identList()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("REFERENCES"); err != nil {
+			return nil, err
+		}
+		ref, err := p.parseReferences()
+		if err != nil {
+			return nil, err
+		}
+		tc.Spec = &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+			ReferenceItem: &ReferentialTableConstraint{
+				Columns:           cols,
+				KeyExpr:           &ReferenceKeyExpr{TableName: formatObjectName(ref.TableName), Columns: ref.Columns},
+				OnDelete:          ref.OnDelete,
+				OnUpdate:          ref.OnUpdate,
+				Match:             ref.Match,
+				Deferrable:        ref.Deferrable,
+				InitiallyDeferred: ref.InitiallyDeferred,
+			},
+		}}
+	case p.accept("EXCLUDE"):
+		ex, err := p.parseExclude()
+		if err != nil {
+			return nil, err
+		}
+		tc.Spec = &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ExcludeItem{ExcludeItem: ex}}
+	}
+
+	if p.accept("NOT", "ENFORCED") {
+		tc.NotEnforced = true
+	}
+	return tc, nil
+}
+
+// parseReferences parses the part of a foreign key after REFERENCES.
+func (p *sqlParser) parseReferences() (*ReferencesColumnSpec, error) {
+	table, err := p.objectName()
+	if err != nil {
+		return nil, err
+	}
+	ref := &ReferencesColumnSpec{TableName: table}
+	if p.peekIs("(") {
+		if ref.Columns, err = p.identList(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		switch {
+		case p.accept("MATCH"):
+			m, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			ref.Match = mapMatchOption(m)
+		case p.accept("ON", "DELETE"):
+			ref.OnDelete = mapReferentialAction(p.referentialAction())
+		case p.accept("ON", "UPDATE"):
+			ref.OnUpdate = mapReferentialAction(p.referentialAction())
+		case p.accept("NOT", "DEFERRABLE"):
+			ref.Deferrable = false
+		case p.accept("DEFERRABLE"):
+			ref.Deferrable = true
+		case p.accept("INITIALLY", "DEFERRED"):
+			ref.InitiallyDeferred = true
+		case p.accept("INITIALLY", "IMMEDIATE"):
+			ref.InitiallyDeferred = false
+		default:
+			return ref, nil
+		}
+	}
+}
+
+// referentialAction consumes CASCADE, RESTRICT, NO ACTION, SET NULL or SET DEFAULT.
+func (p *sqlParser) referentialAction() string {
+	for _, action := range [][]string{{"NO", "ACTION"}, {"SET", "NULL"}, {"SET", "DEFAULT"}, {"CASCADE"}, {"RESTRICT"}} {
+		if p.accept(action...) {
+			return strings.Join(action, " ")
+		}
+	}
+	return ""
+}
+
+// parseExclude parses a Postgres EXCLUDE [USING method] (expr WITH op, ...) constraint.
+func (p *sqlParser) parseExclude() (*ExcludeTableConstraint, error) {
+	ex := &ExcludeTableConstraint{}
+	if p.accept("USING") {
+		method, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		ex.Method = method
+	}
+	if !p.peekIs("(") {
+		return nil, p.errorf("expected exclusion element list")
+	}
+	listStart := p.pos + 1
+	p.skipParens()
+	for _, elemToks := range splitTopLevel(p.toks[listStart : p.pos-1]) {
+		with := -1
+		for i, t := range elemToks {
+			if t.is("WITH") {
+				with = i
+			}
+		}
+		if with <= 0 || with == len(elemToks)-1 {
+			return nil, fmt.Errorf("exclusion element must have the form \"expr WITH operator\"")
+		}
+		ex.Elements = append(ex.Elements, &ExcludeConstraintElement{
+			Expr:     stringToAny(strings.TrimSpace(p.src[elemToks[0].start:elemToks[with-1].end])),
+			Operator: strings.TrimSpace(p.src[elemToks[with+1].start:elemToks[len(elemToks)-1].end]),
+		})
+	}
+
+	var err error
+	if p.accept("INCLUDE") {
+		if ex.Include, err = p.identList(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("WHERE") {
+		where, err := p.parenExpr()
+		if err != nil {
+			return nil, err
+		}
+		ex.Where = stringToAny(where)
+	}
+	return ex, nil
+}
+
+// parseExclusionDefinition parses a Postgres exclusion constraint definition
+// as returned by pg_get_constraintdef, e.g.
+// "EXCLUDE USING gist (room WITH =, during WITH &&)".
+func parseExclusionDefinition(def string) (*ExcludeTableConstraint, error) {
+	toks, err := tokenizeSQL(def, DialectPostgres)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{src: def, toks: toks, dialect: DialectPostgres}
+	if err := p.expect("EXCLUDE"); err != nil {
+		return nil, err
+	}
+	return p.parseExclude()
+}
+
+// =============================================================================
+// Indexes
+// =============================================================================
+
+// parseCreateIndex parses CREATE [UNIQUE] INDEX and adds the index to its
+// table, which must have been created earlier in the script; indexes on
+// other tables are ignored.
+func (p *sqlParser) parseCreateIndex(db *MetaDatabase) error {
+	tableName, idx, err := p.parseIndex()
+	if err != nil {
+		return err
+	}
+	key := objectNameKey(tableName)
+	for _, t := range db.Tables {
+		if objectNameKey(t.Name) == key {
+			t.Indexes = append(t.Indexes, idx)
+			break
+		}
+	}
+	return nil
+}
+
+// parseIndex parses the rest of a CREATE INDEX statement:
+// [UNIQUE] INDEX [CONCURRENTLY] [IF NOT EXISTS] name ON [ONLY] table
+// [USING method] (keys) [INCLUDE (cols)] [WITH (...)] [WHERE predicate].
+// parseInlineIndex parses a MySQL index declared in CREATE TABLE:
+// [FULLTEXT | SPATIAL] {KEY | INDEX} [name] [USING method] (keys) [USING method].
+// An unnamed index is named after its first column, as MySQL does.
+func (p *sqlParser) parseInlineIndex() (*MetaIndex, error) {
+	idx := &MetaIndex{}
+	for _, kind := range []string{"FULLTEXT", "SPATIAL"} {
+		if p.accept(kind) {
+			idx.Method = kind
+		}
+	}
+	if !p.accept("KEY") {
+		p.accept("INDEX")
+	}
+	if !p.peekIs("(") && !p.peekIs("USING") {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		idx.Name = name
+	}
+	using := func() error {
+		if !p.accept("USING") {
+			return nil
+		}
+		method, err := p.ident()
+		idx.Method = method
+		return err
+	}
+
+	if err := using(); err != nil {
+		return nil, err
+	}
+	start := p.pos
+	cols, err := p.identList()
+	if err != nil {
+		p.pos = start
+		if idx.Expression, err = p.parenExpr(); err != nil {
+			return nil, err
+		}
+	}
+	idx.Columns = cols
+	if err := using(); err != nil {
+		return nil, err
+	}
+	if p.accept("COMMENT") && p.peek().kind == sqlString {
+		idx.Comment = p.peek().text
+		p.pos++
+	}
+	if idx.Name == "" && len(idx.Columns) > 0 {
+		idx.Name = idx.Columns[0]
+	}
+	return idx, nil
+}
+
+func (p *sqlParser) parseIndex() (*ObjectName, *MetaIndex, error) {
+	idx := &MetaIndex{IsUnique: p.accept("UNIQUE")}
+	for _, kind := range []string{"FULLTEXT", "SPATIAL"} {
+		if p.accept(kind) {
+			idx.Method = kind
+		}
+	}
+	if err := p.expect("INDEX"); err != nil {
+		return nil, nil, err
+	}
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	if !p.peekIs("ON") {
+		name, err := p.objectName()
+		if err != nil {
+			return nil, nil, err
+		}
+		idx.Name = name.Idents[len(name.Idents)-1]
+	}
+	if err := p.expect("ON"); err != nil {
+		return nil, nil, err
+	}
+	p.accept("ONLY")
+	tableName, err := p.objectName()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.accept("USING") {
+		if idx.Method, err = p.ident(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !p.peekIs("(") {
+		return nil, nil, p.errorf("expected index key list")
+	}
+	listStart := p.pos + 1
+	keys := p.skipParens()
+	idx.Columns = plainColumns(splitTopLevel(p.toks[listStart : p.pos-1]))
+	if idx.Columns == nil {
+		idx.Expression = keys
+	}
+	if p.accept("USING") { // MySQL puts the method after the key list
+		if idx.Method, err = p.ident(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if p.accept("INCLUDE") {
+		include, err := p.identList()
+		if err != nil {
+			return nil, nil, err
+		}
+		idx.Options = map[string]string{"Include": strings.Join(include, ",")}
+	}
+	if p.accept("WITH") && p.peekIs("(") {
+		p.skipParens()
+	}
+	if p.accept("TABLESPACE") {
+		p.pos++
+	}
+	if p.accept("WHERE") && !p.done() {
+		idx.Predicate = trimOuterParens(p.exprUntil(nil))
+	}
+	return tableName, idx, nil
+}
+
+// plainColumns returns the column names of an index key list whose elements
+// are all bare identifiers, or nil if any element is an expression.
+func plainColumns(elems [][]sqlToken) []string {
+	var cols []string
+	for _, toks := range elems {
+		if len(toks) != 1 || (toks[0].kind != sqlWord && toks[0].kind != sqlQuotedIdent) {
+			return nil
+		}
+		cols = append(cols, toks[0].text)
+	}
+	return cols
+}
+
+// trimOuterParens removes parentheses enclosing the whole of s, as
+// pg_get_indexdef adds around index predicates.
+func trimOuterParens(s string) string {
+	for len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		depth := 0
+		for i := 0; i < len(s)-1; i++ {
+			switch s[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				return s // the first parenthesis closes before the end
+			}
+		}
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+// parseIndexDefinition parses an index definition as returned by
+// pg_get_indexdef, e.g.
+// "CREATE UNIQUE INDEX users_email ON public.users USING btree (lower(email)) WHERE (deleted_at IS NULL)".
+func parseIndexDefinition(def string) (*MetaIndex, error) {
+	toks, err := tokenizeSQL(def, DialectPostgres)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{src: def, toks: toks, dialect: DialectPostgres}
+	if err := p.expect("CREATE"); err != nil {
+		return nil, err
+	}
+	_, idx, err := p.parseIndex()
+	return idx, err
+}
+
+// =============================================================================
+// Columns
+// =============================================================================
+
+// columnStopWords end a column's type or DEFAULT expression.
+var columnStopWords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true,
+	"CHECK": true, "REFERENCES": true, "CONSTRAINT": true, "COLLATE": true,
+	"GENERATED": true, "AUTO_INCREMENT": true, "AUTOINCREMENT": true, "COMMENT": true,
+	"ON": true, "AS": true, "CHARSET": true,
+}
+
+// pgSerialTypes maps the Postgres serial pseudo-types to the integer type
+// of the column they create.
+var pgSerialTypes = map[string]string{
+	"smallserial": "smallint", "serial2": "smallint",
+	"serial": "integer", "serial4": "integer",
+	"bigserial": "bigint", "serial8": "bigint",
+}
+
+// parseColumn parses a column definition of table. A Postgres serial
+// column becomes what the database makes of it, so that it compares equal
+// to the live column: a NOT NULL integer column defaulting to nextval of
+// its table_column_seq sequence. The Serial option keeps the pseudo-type,
+// which creates the sequence when the column is created.
+func (p *sqlParser) parseColumn(table *ObjectName) (*ColumnDef, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	col := &ColumnDef{Name: name, Options: make(map[string]string)}
+	serial := ""
+
+	// The type runs until the first column constraint keyword
+	typeStart := p.pos
+	for !p.done() && !(p.peek().kind == sqlWord && columnStopWords[strings.ToUpper(p.peek().text)]) {
+		if p.pos > typeStart && p.peekIs("CHARACTER", "SET") {
+			break
+		}
+		if p.peekIs("(") {
+			p.skipParens()
+			continue
+		}
+		p.pos++
+	}
+	if p.pos == typeStart {
+		if p.dialect != DialectSQLite {
+			return nil, p.errorf("expected type for column %s", name)
+		}
+		// SQLite columns may omit the type entirely
+	} else {
+		typ := p.src[p.toks[typeStart].start:p.toks[p.pos-1].end]
+		col.DataType = parseSQLDataType(typ)
+		if base, ok := pgSerialTypes[strings.ToLower(typ)]; ok && p.dialect == DialectPostgres {
+			col.DataType = parseSQLDataType(base)
+			serial = strings.ToLower(typ)
+		}
+		if p.dialect == DialectMySQL && strings.Contains(strings.ToLower(typ), "unsigned") {
+			col.Options["IsUnsigned"] = "true" // as MYColumnToColumnDef records it
+		}
+	}
+
+	var conName string
+	addConstraint := func(spec *ColumnConstraintSpec) {
+		col.Constraints = append(col.Constraints, &ColumnConstraint{Name: conName, Spec: spec})
+		conName = ""
+	}
+
+	for !p.done() {
+		switch {
+		case p.accept("CONSTRAINT"):
+			if conName, err = p.ident(); err != nil {
+				return nil, err
+			}
+		case p.accept("NOT", "NULL"):
+			addConstraint(&ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{
+				NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm,
+			}})
+		case p.accept("NULL"):
+		case p.accept("DEFAULT"):
+			if p.done() {
+				return nil, p.errorf("expected default value")
+			}
+			if expr := p.exprUntil(columnStopWords); !strings.EqualFold(expr, "NULL") {
+				col.Default = stringToAny(expr)
+			}
+		case p.accept("PRIMARY", "KEY"):
+			addConstraint(&ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{
+				UniqueItem: &UniqueColumnSpec{IsPrimaryKey: true},
+			}})
+			if !p.accept("ASC") {
+				p.accept("DESC")
+			}
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			addConstraint(&ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_UniqueItem{
+				UniqueItem: &UniqueColumnSpec{},
+			}})
+		case p.accept("CHECK"):
+			expr, err := p.parenExpr()
+			if err != nil {
+				return nil, err
+			}
+			addConstraint(&ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_CheckItem{
+				CheckItem: stringToAny(expr),
+			}})
+		case p.accept("REFERENCES"):
+			ref, err := p.parseReferences()
+			if err != nil {
+				return nil, err
+			}
+			addConstraint(&ColumnConstraintSpec{ColumnConstraintSpecClause: &ColumnConstraintSpec_ReferenceItem{
+				ReferenceItem: ref,
+			}})
+		case p.accept("COLLATE"):
+			v, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			col.Options["Collation"] = v
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+			v, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			col.Options["Charset"] = v
+		case p.accept("AUTO_INCREMENT"), p.accept("AUTOINCREMENT"):
+			col.MyDecos = append(col.MyDecos, AutoIncrement_AutoIncrementConfirm)
+		case p.accept("COMMENT"):
+			if p.peek().kind != sqlString {
+				return nil, p.errorf("expected comment string")
+			}
+			col.Comment = p.peek().text
+			p.pos++
+		case p.accept("ON", "UPDATE"):
+			col.Options["OnUpdate"] = p.exprUntil(columnStopWords)
+		case p.peekIs("GENERATED"), p.peekIs("AS"):
+			if err := p.parseGenerated(col); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.errorf("unexpected token in column %s", name)
+		}
+	}
+
+	if serial != "" {
+		col.Options["Serial"] = serial
+		col.Default = stringToAny(fmt.Sprintf("nextval('%s'::regclass)", pgSerialSequence(table, name)))
+		setNotNull(col, true)
+	}
+	if len(col.Options) == 0 {
+		col.Options = nil
+	}
+	return col, nil
+}
+
+// setNotNull adds or removes the NOT NULL constraint of col.
+func setNotNull(col *ColumnDef, notNull bool) {
+	switch {
+	case notNull == isNotNull(col):
+	case notNull:
+		col.Constraints = append(col.Constraints, &ColumnConstraint{Spec: &ColumnConstraintSpec{
+			ColumnConstraintSpecClause: &ColumnConstraintSpec_NotNullItem{NotNullItem: NotNullColumnSpec_NotNullColumnSpecConfirm},
+		}})
+	default:
+		col.Constraints = slices.DeleteFunc(col.Constraints, func(con *ColumnConstraint) bool {
+			return con.GetSpec().GetNotNullItem() == NotNullColumnSpec_NotNullColumnSpecConfirm
+		})
+	}
+}
+
+// pgSerialSequence returns the sequence Postgres creates for serial column
+// col of table, as a column default names it under the default search
+// path: bare in public and qualified elsewhere.
+func pgSerialSequence(table *ObjectName, col string) string {
+	seq := simpleNameKey(table) + "_" + col + "_seq"
+	if idents := table.GetIdents(); len(idents) > 1 && idents[len(idents)-2] != "public" {
+		return idents[len(idents)-2] + "." + seq
+	}
+	return seq
+}
+
+// parseGenerated handles identity columns (GENERATED ALWAYS|BY DEFAULT AS
+// IDENTITY) and generated columns ([GENERATED ALWAYS] AS (expr) [STORED|VIRTUAL]).
+func (p *sqlParser) parseGenerated(col *ColumnDef) error {
+	generation := ""
+	if p.accept("GENERATED") {
+		switch {
+		case p.accept("ALWAYS"):
+			generation = "ALWAYS"
+		case p.accept("BY", "DEFAULT"):
+			generation = "BY DEFAULT"
+		default:
+			return p.errorf("expected ALWAYS or BY DEFAULT")
+		}
+	}
+	if err := p.expect("AS"); err != nil {
+		return err
+	}
+
+	if p.accept("IDENTITY") {
+		col.Options["IsIdentity"] = "true"
+		col.Options["IdentityGeneration"] = generation
+		if p.peekIs("(") {
+			start, increment := parseIdentityOptions(p.skipParens())
+			setIdentityOptions(col.Options, start, increment)
+		}
+		return nil
+	}
+
+	expr, err := p.parenExpr()
+	if err != nil {
+		return err
+	}
+	col.Options["IsGenerated"] = "true"
+	col.Options["GenerationExpression"] = expr
+	switch {
+	case p.accept("STORED"):
+		col.Options["GenerationKind"] = "STORED"
+	case p.accept("VIRTUAL"):
+		col.Options["GenerationKind"] = "VIRTUAL"
+	}
+	return nil
+}
+
+// =============================================================================
+// Data Types
+// =============================================================================
+
+// splitTypeArgs splits type arguments on commas outside single-quoted strings.
+func splitTypeArgs(s string) []string {
+	var args []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			inQuote = !inQuote // a doubled '' toggles twice
+		case s[i] == ',' && !inQuote:
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(args, strings.TrimSpace(s[start:]))
+}
+
+// parseIdentityOptions returns the START WITH and INCREMENT BY values of
+// the sequence options of an identity column, e.g. "START WITH 100
+// INCREMENT BY 10 CACHE 20", defaulting them as Postgres does.
+func parseIdentityOptions(s string) (start, increment int64) {
+	increment = 1
+	var startSet bool
+	words := strings.Fields(strings.ToUpper(s))
+	value := func(i int, noise string) (int64, bool) {
+		if i < len(words) && words[i] == noise {
+			i++
+		}
+		if i >= len(words) {
+			return 0, false
+		}
+		n, err := strconv.ParseInt(words[i], 10, 64)
+		return n, err == nil
+	}
+	for i, word := range words {
+		switch word {
+		case "START":
+			start, startSet = value(i+1, "WITH")
+		case "INCREMENT":
+			if n, ok := value(i+1, "BY"); ok {
+				increment = n
+			}
+		}
+	}
+	if !startSet {
+		start = identityDefaultStart(increment)
+	}
+	return start, increment
+}
+
+// parseSQLDataType maps a type as written in DDL (e.g. "numeric(10,2)",
+// "character varying(255)", "int[]") to a DataType. Unknown types are kept
+// as CustomData.
+func parseSQLDataType(s string) *DataType {
+	orig := strings.Join(strings.Fields(s), " ")
+	typ := strings.ToLower(orig)
+
+	if strings.HasSuffix(typ, "[]") {
+		elem := parseSQLDataType(strings.TrimSuffix(typ, "[]"))
+		return &DataType{TypeClause: &DataType_ArrayData{ArrayData: &ArrayData{Type: elem}}}
+	}
+
+	unsigned := false
+	for _, suffix := range []string{" zerofill", " unsigned"} {
+		if strings.HasSuffix(typ, suffix) {
+			typ = strings.TrimSuffix(typ, suffix)
+			unsigned = unsigned || suffix == " unsigned"
+		}
+	}
+
+	// Split "name(args) rest" into base name, arguments and trailing words
+	base, rest := typ, ""
+	var args []string
+	if open := strings.Index(typ, "("); open >= 0 {
+		if closing := strings.LastIndex(typ, ")"); closing > open {
+			base = strings.TrimSpace(typ[:open])
+			// Arguments keep their case for ENUM/SET values
+			args = splitTypeArgs(orig[open+1 : closing])
+			rest = strings.TrimSpace(typ[closing+1:])
+		}
+	}
+	size := func(i int) uint32 {
+		if i >= len(args) {
+			return 0
+		}
+		n, _ := strconv.ParseUint(args[i], 10, 32)
+		return uint32(n)
+	}
+	precision := func() *uint32 {
+		if len(args) == 0 {
+			return nil
+		}
+		p := size(0)
+		return &p
+	}
+
+	t := &DataType{}
+	if fields, ok := strings.CutPrefix(base, "interval"); ok && (fields == "" || fields[0] == ' ') {
+		t.TypeClause = &DataType_IntervalData{IntervalData: &IntervalType{
+			Fields:    strings.ToUpper(strings.TrimSpace(fields)),
+			Precision: precision(),
+		}}
+		return t
+	}
+	switch base {
+	case "int", "integer", "int4", "serial", "serial4":
+		t.TypeClause = &DataType_IntData{IntData: &Int{IsUnsigned: unsigned}}
+	case "bigint", "int8", "bigserial", "serial8":
+		t.TypeClause = &DataType_BigIntData{BigIntData: &BigInt{IsUnsigned: unsigned}}
+	case "smallint", "int2", "smallserial", "serial2":
+		t.TypeClause = &DataType_SmallIntData{SmallIntData: &SmallInt{IsUnsigned: unsigned}}
+	case "tinyint":
+		t.TypeClause = &DataType_TinyIntData{TinyIntData: &TinyInt{IsUnsigned: unsigned}}
+	case "mediumint":
+		t.TypeClause = &DataType_MediumIntData{MediumIntData: &MediumInt{IsUnsigned: unsigned}}
+	case "decimal", "numeric", "dec":
+		t.TypeClause = &DataType_DecimalData{DecimalData: &Decimal{Precision: size(0), Scale: size(1), IsUnsigned: unsigned}}
+	case "char", "character", "nchar":
+		t.TypeClause = &DataType_CharData{CharData: &CharType{Size: size(0)}}
+	case "varchar", "character varying", "nvarchar":
+		t.TypeClause = &DataType_VarcharData{VarcharData: &VarcharType{Size: size(0)}}
+	case "text", "tinytext", "mediumtext", "longtext", "clob":
+		t.TypeClause = &DataType_TextData{TextData: DataTypeSingle_Text}
+	case "boolean", "bool":
+		t.TypeClause = &DataType_BooleanData{BooleanData: DataTypeSingle_Boolean}
+	case "timestamp", "timestamp without time zone", "datetime":
+		t.TypeClause = &DataType_TimestampData{TimestampData: &Timestamp{WithTimeZone: rest == "with time zone", Precision: precision()}}
+	case "timestamptz", "timestamp with time zone":
+		t.TypeClause = &DataType_TimestampData{TimestampData: &Timestamp{WithTimeZone: true, Precision: precision()}}
+	case "date":
+		t.TypeClause = &DataType_DateData{DateData: DataTypeSingle_Date}
+	case "time", "time without time zone", "time with time zone", "timetz":
+		// A plain TIME stays TimeData; a zone or precision needs TimeType
+		withTimeZone := base == "time with time zone" || base == "timetz" || rest == "with time zone"
+		if !withTimeZone && len(args) == 0 {
+			t.TypeClause = &DataType_TimeData{TimeData: DataTypeSingle_Time}
+		} else {
+			t.TypeClause = &DataType_TimeTypeData{TimeTypeData: &TimeType{WithTimeZone: withTimeZone, Precision: precision()}}
+		}
+	case "double", "double precision", "float8":
+		t.TypeClause = &DataType_DoubleData{DoubleData: &DoubleType{IsDoublePrecision: base == "double precision"}}
+	case "float":
+		t.TypeClause = &DataType_FloatData{FloatData: &Float{Size: size(0), IsUnsigned: unsigned}}
+	case "real", "float4":
+		t.TypeClause = &DataType_RealData{RealData: &Real{IsUnsigned: unsigned}}
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		t.TypeClause = &DataType_ByteaData{ByteaData: DataTypeSingle_Bytea}
+	case "bit":
+		t.TypeClause = &DataType_BitData{BitData: &BitType{Size: size(0)}}
+	case "bit varying", "varbit":
+		t.TypeClause = &DataType_BitData{BitData: &BitType{Size: size(0), Varying: true}}
+	case "uuid":
+		t.TypeClause = &DataType_UUIDData{UUIDData: DataTypeSingle_UUID}
+	case "json":
+		t.TypeClause = &DataType_JSONData{JSONData: DataTypeSingle_JSON}
+	case "jsonb":
+		t.TypeClause = &DataType_JSONData{JSONData: DataTypeSingle_JSONB}
+	case "inet":
+		t.TypeClause = &DataType_NetworkData{NetworkData: &NetworkType{Kind: NetworkKind_NetworkKind_Inet}}
+	case "cidr":
+		t.TypeClause = &DataType_NetworkData{NetworkData: &NetworkType{Kind: NetworkKind_NetworkKind_Cidr}}
+	case "macaddr":
+		t.TypeClause = &DataType_NetworkData{NetworkData: &NetworkType{Kind: NetworkKind_NetworkKind_Macaddr}}
+	case "macaddr8":
+		t.TypeClause = &DataType_NetworkData{NetworkData: &NetworkType{Kind: NetworkKind_NetworkKind_Macaddr8}}
+	case "xml":
+		t.TypeClause = &DataType_XMLData{XMLData: DataTypeSingle_XML}
+	case "year":
+		t.TypeClause = &DataType_YearData{YearData: DataTypeSingle_Year}
+	case "regclass":
+		t.TypeClause = &DataType_RegclassData{RegclassData: DataTypeSingle_Regclass}
+	case "enum", "set":
+		var values []string
+		for _, a := range args {
+			values = append(values, strings.ReplaceAll(strings.Trim(a, "'"), "''", "'"))
+		}
+		if base == "enum" {
+			t.TypeClause = &DataType_EnumData{EnumData: &EnumType{Values: values}}
+		} else {
+			t.TypeClause = &DataType_SetData{SetData: &SetType{Values: values}}
+		}
+	default:
+		t.TypeClause = &DataType_CustomData{CustomData: &ObjectName{Idents: []string{typ}}}
+	}
+	return t
+}
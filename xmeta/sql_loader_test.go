@@ -0,0 +1,499 @@
+package xmeta
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const testSchemaSQL = `
+-- users and their orders
+CREATE TABLE public.users (
+    id bigserial PRIMARY KEY,
+    email character varying(255) NOT NULL UNIQUE,
+    balance numeric(10,2) DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    tags text[],
+    CONSTRAINT users_balance_check CHECK (balance >= 0)
+);
+
+CREATE INDEX users_email_idx ON public.users (email);
+
+CREATE TABLE IF NOT EXISTS "public"."orders" (
+    id integer GENERATED ALWAYS AS IDENTITY,
+    user_id bigint,
+    total numeric(12, 2) GENERATED ALWAYS AS (balance * 2) STORED,
+    CONSTRAINT orders_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES public.users (id)
+        ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
+
+COMMENT ON TABLE public.users IS 'User accounts';
+COMMENT ON COLUMN public.users.email IS 'Login e-mail';
+`
+
+func TestLoadMetaDatabaseFromSQL(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(testSchemaSQL, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	if len(db.Tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(db.Tables))
+	}
+
+	users := db.Tables[0]
+	if objectNameKey(users.Name) != "public.users" {
+		t.Errorf("Unexpected table name %v", users.Name.Idents)
+	}
+	if users.Comment != "User accounts" {
+		t.Errorf("Expected table comment, got %q", users.Comment)
+	}
+
+	cols := columnsFromElements(users.Elements)
+	if len(cols) != 5 {
+		t.Fatalf("Expected 5 columns, got %d", len(cols))
+	}
+	if !proto.Equal(cols["id"].DataType, &DataType{TypeClause: &DataType_BigIntData{BigIntData: &BigInt{}}}) {
+		t.Errorf("Unexpected id type %v", cols["id"].DataType)
+	}
+	if !cols["id"].Constraints[0].Spec.GetUniqueItem().GetIsPrimaryKey() {
+		t.Error("Expected id to be an inline primary key")
+	}
+	if got := cols["email"].DataType.GetVarcharData().GetSize(); got != 255 {
+		t.Errorf("Expected varchar(255), got size %d", got)
+	}
+	if !isNotNull(cols["email"]) || cols["email"].Comment != "Login e-mail" {
+		t.Errorf("Unexpected email column %v", cols["email"])
+	}
+	if d := cols["balance"].DataType.GetDecimalData(); d.GetPrecision() != 10 || d.GetScale() != 2 {
+		t.Errorf("Expected numeric(10,2), got %v", cols["balance"].DataType)
+	}
+	if anyToString(cols["balance"].Default) != "0" || !isNotNull(cols["balance"]) {
+		t.Errorf("Unexpected balance column %v", cols["balance"])
+	}
+	if anyToString(cols["created_at"].Default) != "now()" || !cols["created_at"].DataType.GetTimestampData().GetWithTimeZone() {
+		t.Errorf("Unexpected created_at column %v", cols["created_at"])
+	}
+	if cols["tags"].DataType.GetArrayData().GetType().GetTextData() != DataTypeSingle_Text {
+		t.Errorf("Expected text[], got %v", cols["tags"].DataType)
+	}
+
+	if len(users.Indexes) != 1 || users.Indexes[0].Name != "users_email_idx" || users.Indexes[0].Columns[0] != "email" {
+		t.Errorf("Unexpected indexes %v", users.Indexes)
+	}
+
+	check := constraintsFromElements(users.Elements)["users_balance_check"]
+	if anyToString(check.GetSpec().GetCheckItem()) != "balance >= 0" {
+		t.Errorf("Unexpected check constraint %v", check)
+	}
+
+	orders := db.Tables[1]
+	ocols := columnsFromElements(orders.Elements)
+	if ocols["id"].Options["IsIdentity"] != "true" || ocols["id"].Options["IdentityGeneration"] != "ALWAYS" {
+		t.Errorf("Expected identity column, got %v", ocols["id"].Options)
+	}
+	if ocols["total"].Options["GenerationExpression"] != "balance * 2" || ocols["total"].Options["GenerationKind"] != "STORED" {
+		t.Errorf("Expected stored generated column, got %v", ocols["total"].Options)
+	}
+
+	constraints := constraintsFromElements(orders.Elements)
+	if !constraints["orders_pkey"].Spec.GetUniqueItem().GetIsPrimary() {
+		t.Error("Expected orders_pkey primary key")
+	}
+	fk := constraints["fk_user"].Spec.GetReferenceItem()
+	if fk.GetKeyExpr().GetTableName() != "public.users" || fk.OnDelete != ReferentialAction_ReferentialAction_Cascade ||
+		!fk.Deferrable || !fk.InitiallyDeferred {
+		t.Errorf("Unexpected foreign key %v", fk)
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_MySQL(t *testing.T) {
+	sql := "CREATE TABLE `shop`.`items` (\n" +
+		"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `status` enum('New','Sold') DEFAULT NULL COMMENT 'state',\n" +
+		"  `name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `uk_name` (`name`),\n" +
+		"  KEY `idx_status` (`status`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Items'"
+
+	db, err := LoadMetaDatabaseFromSQL(sql, DialectMySQL)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	table := db.Tables[0]
+	if table.Options["Engine"] != "InnoDB" || table.Options["Charset"] != "utf8mb4" || table.Comment != "Items" {
+		t.Errorf("Unexpected table options %v / %q", table.Options, table.Comment)
+	}
+	if len(table.Elements) != 5 {
+		t.Fatalf("Expected 3 columns and 2 constraints, got %d elements", len(table.Elements))
+	}
+
+	cols := columnsFromElements(table.Elements)
+	if !cols["id"].DataType.GetIntData().GetIsUnsigned() || !hasAutoIncrement(cols["id"]) {
+		t.Errorf("Unexpected id column %v", cols["id"])
+	}
+	if values := cols["status"].DataType.GetEnumData().GetValues(); len(values) != 2 || values[0] != "New" {
+		t.Errorf("Unexpected enum values %v", values)
+	}
+	if cols["status"].Default != nil || cols["status"].Comment != "state" {
+		t.Errorf("Unexpected status column %v", cols["status"])
+	}
+	if cols["name"].Options["Charset"] != "utf8mb4" || cols["name"].Options["Collation"] != "utf8mb4_bin" || !isNotNull(cols["name"]) {
+		t.Errorf("Unexpected name column %v", cols["name"])
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_Errors(t *testing.T) {
+	for _, sql := range []string{
+		"CREATE TABLE t (id int,",
+		"CREATE TABLE t (id)",
+		"CREATE TABLE t (id int NOT BOGUS)",
+	} {
+		if _, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres); err == nil {
+			t.Errorf("Expected error for %q", sql)
+		}
+	}
+}
+
+func TestSQLFormatRoundTrip(t *testing.T) {
+	// Comments are not rendered by GenerateSQL, so leave them out here
+	schema, _, _ := strings.Cut(testSchemaSQL, "COMMENT ON")
+	db, err := LoadMetaDatabaseFromReader(strings.NewReader(schema), FormatSQL)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var buf strings.Builder
+	if err := SaveMetaDatabaseToWriter(db, &buf, FormatSQL); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `CREATE TABLE "public"."users"`) {
+		t.Errorf("Unexpected SQL output:\n%s", buf.String())
+	}
+
+	reloaded, err := LoadMetaDatabaseFromReader(strings.NewReader(buf.String()), FormatSQL)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if changes := DiffDatabase(db, reloaded); len(changes) != 0 {
+		t.Errorf("Expected no changes after round trip, got %v", changes)
+	}
+}
+
+func TestParseIndexDefinition(t *testing.T) {
+	idx, err := parseIndexDefinition(`CREATE UNIQUE INDEX users_email ON public.users USING btree (lower((email)::text)) WHERE (deleted_at IS NULL)`)
+	if err != nil {
+		t.Fatalf("parseIndexDefinition failed: %v", err)
+	}
+	if !idx.IsUnique || idx.Method != "btree" || idx.Expression != "lower((email)::text)" || idx.Columns != nil {
+		t.Errorf("Unexpected index %v", idx)
+	}
+	if idx.Predicate != "deleted_at IS NULL" {
+		t.Errorf("Unexpected predicate %q", idx.Predicate)
+	}
+
+	idx, err = parseIndexDefinition(`CREATE INDEX "Orders_idx" ON public.orders USING btree (user_id, "createdAt") INCLUDE (total)`)
+	if err != nil {
+		t.Fatalf("parseIndexDefinition failed: %v", err)
+	}
+	if strings.Join(idx.Columns, ",") != "user_id,createdAt" || idx.Expression != "" || idx.Options["Include"] != "total" {
+		t.Errorf("Unexpected index %v", idx)
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_Views(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`CREATE TABLE users (id INTEGER PRIMARY KEY, active BOOLEAN);
+CREATE OR REPLACE VIEW public.active_users WITH (security_barrier) AS
+  SELECT id FROM users WHERE active
+  WITH LOCAL CHECK OPTION;
+CREATE VIEW all_users (user_id) WITH (check_option = cascaded) AS SELECT id FROM users;`, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db.Tables) != 1 || len(db.Views) != 2 {
+		t.Fatalf("Expected one table and two views, got %v", db)
+	}
+	active := db.Views[0]
+	if objectNameKey(active.Name) != "public.active_users" || active.Definition != "SELECT id FROM users WHERE active" {
+		t.Errorf("Unexpected view %v", active)
+	}
+	if active.Options["CheckOption"] != "LOCAL" || active.Options["SecurityBarrier"] != "true" {
+		t.Errorf("Unexpected view options %v", active.Options)
+	}
+	if db.Views[1].Options["CheckOption"] != "CASCADED" {
+		t.Errorf("Unexpected view options %v", db.Views[1].Options)
+	}
+
+	my, err := LoadMetaDatabaseFromSQL("CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`localhost` SQL SECURITY INVOKER VIEW `v` AS select `id` from `users` WITH CHECK OPTION;", DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := my.Views[0]; v.Options["SqlSecurity"] != "INVOKER" || v.Options["CheckOption"] != "CASCADED" || v.Definition != "select `id` from `users`" {
+		t.Errorf("Unexpected MySQL view %v", v)
+	}
+
+	// Views survive a round trip through the SQL format
+	var buf strings.Builder
+	if err := SaveMetaDatabaseToWriter(db, &buf, FormatSQL); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadMetaDatabaseFromReader(strings.NewReader(buf.String()), FormatSQL)
+	if err != nil {
+		t.Fatalf("Reload failed: %v\n%s", err, buf.String())
+	}
+	for i, v := range reloaded.Views {
+		if v.Definition != db.Views[i].Definition || !mapsEqual(v.Options, db.Views[i].Options) {
+			t.Errorf("View %d changed in round trip: %v", i, v)
+		}
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_MySQLIndexes(t *testing.T) {
+	sql := "CREATE TABLE `places` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `body` text,\n" +
+		"  `title` varchar(200),\n" +
+		"  `location` point NOT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_title` (`title`(20)) USING BTREE,\n" +
+		"  FULLTEXT KEY `ft_body` (`body`,`title`),\n" +
+		"  SPATIAL INDEX (`location`)\n" +
+		") ENGINE=InnoDB;\n" +
+		"CREATE FULLTEXT INDEX `ft_title` ON `places` (`title`);"
+
+	db, err := LoadMetaDatabaseFromSQL(sql, DialectMySQL)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, idx := range db.Tables[0].Indexes {
+		got[idx.Name] = idx.Method + " " + strings.Join(idx.Columns, ",")
+	}
+	want := map[string]string{
+		"idx_title": "BTREE title",
+		"ft_body":   "FULLTEXT body,title",
+		"location":  "SPATIAL location",
+		"ft_title":  "FULLTEXT title",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("Unexpected indexes %v", got)
+	}
+
+	stmts, err := GenerateSQL(AddTable{Table: db.Tables[0]}, DialectMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(stmts, "CREATE FULLTEXT INDEX `ft_body` ON `places` (`body`, `title`)") ||
+		!slices.Contains(stmts, "CREATE SPATIAL INDEX `location` ON `places` (`location`)") {
+		t.Errorf("Unexpected DDL %q", stmts)
+	}
+	if _, err := GenerateSQL(AddIndex{TableName: db.Tables[0].Name, Index: db.Tables[0].Indexes[1]}, DialectPostgres); err == nil {
+		t.Error("Expected an error for a FULLTEXT index in Postgres")
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_CommentOnIndex(t *testing.T) {
+	sql := `
+CREATE TABLE public.users (id INTEGER PRIMARY KEY, email TEXT);
+CREATE INDEX users_email_idx ON public.users (email);
+COMMENT ON TABLE public.users IS 'User accounts';
+COMMENT ON INDEX public.users_email_idx IS 'Login lookup';`
+	db, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	if got := db.Tables[0].Indexes[0].Comment; got != "Login lookup" {
+		t.Errorf("Expected the index comment, got %q", got)
+	}
+	if _, err := LoadMetaDatabaseFromSQL(sql+"\nCOMMENT ON INDEX missing_idx IS 'x';", DialectPostgres); err == nil {
+		t.Error("Expected an error commenting on a missing index")
+	}
+
+	// A comment-only change diffs to COMMENT ON TABLE
+	desired, err := LoadMetaDatabaseFromSQL(strings.Replace(sql, "'User accounts'", "'Registered users'", 1), DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	changes := DiffDatabase(db, desired)
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v", changes)
+	}
+	stmts, err := GenerateSQL(changes[0], DialectPostgres)
+	if err != nil || len(stmts) != 1 || stmts[0] != `COMMENT ON TABLE "public"."users" IS 'Registered users'` {
+		t.Errorf("Unexpected DDL: %q (%v)", stmts, err)
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_UniqueNullsNotDistinct(t *testing.T) {
+	sql := `
+CREATE TABLE public.memberships (
+  org_id INTEGER,
+  user_id INTEGER,
+  CONSTRAINT memberships_uq UNIQUE NULLS NOT DISTINCT (org_id, user_id)
+);`
+	db, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	u := db.Tables[0].Elements[2].GetTableConstraintElement().GetSpec().GetUniqueItem()
+	if !u.GetNullsNotDistinct() || len(u.GetColumns()) != 2 {
+		t.Fatalf("Expected a NULLS NOT DISTINCT unique constraint on two columns, got %v", u)
+	}
+
+	// Toggling the flag replaces the constraint
+	desired, err := LoadMetaDatabaseFromSQL(strings.Replace(sql, "NULLS NOT DISTINCT", "NULLS DISTINCT", 1), DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	var stmts []string
+	for _, change := range DiffDatabase(db, desired) {
+		sqls, err := GenerateSQL(change, DialectPostgres)
+		if err != nil {
+			t.Fatalf("GenerateSQL failed: %v", err)
+		}
+		stmts = append(stmts, sqls...)
+	}
+	want := []string{
+		`ALTER TABLE "public"."memberships" DROP CONSTRAINT "memberships_uq"`,
+		`ALTER TABLE "public"."memberships" ADD CONSTRAINT "memberships_uq" UNIQUE ("org_id", "user_id")`,
+	}
+	if !slices.Equal(stmts, want) {
+		t.Errorf("Expected %q, got %q", want, stmts)
+	}
+
+	stmts, err = GenerateSQL(AddConstraint{TableName: db.Tables[0].Name, Constraint: db.Tables[0].Elements[2].GetTableConstraintElement()}, DialectPostgres)
+	if err != nil || len(stmts) != 1 || !strings.Contains(stmts[0], `UNIQUE NULLS NOT DISTINCT ("org_id", "user_id")`) {
+		t.Errorf("Unexpected DDL: %q (%v)", stmts, err)
+	}
+}
+
+const testPGDumpSQL = `
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE TABLE public.users (
+    id bigserial,
+    email character varying(20) NOT NULL,
+    balance numeric(10,2) DEFAULT 0 NOT NULL
+);
+
+ALTER TABLE public.users OWNER TO app;
+
+CREATE TABLE public.orders (
+    id integer NOT NULL,
+    user_id bigint
+);
+
+CREATE SEQUENCE public.orders_id_seq AS integer START WITH 1 INCREMENT BY 1 NO MINVALUE NO MAXVALUE CACHE 1;
+ALTER SEQUENCE public.orders_id_seq OWNED BY public.orders.id;
+
+ALTER TABLE ONLY public.orders ALTER COLUMN id SET DEFAULT nextval('public.orders_id_seq'::regclass);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.orders ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+`
+
+func TestLoadMetaDatabaseFromSQL_PGDump(t *testing.T) {
+	file, err := LoadMetaDatabaseFromSQL(testPGDumpSQL, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	// The same schema as the Postgres loader reports it
+	sized := func(pgType string, length, precision, scale int64) *DataType {
+		dt := mapPostgresTypeForProto(pgType, "")
+		applyPGSize(dt, length, precision, scale)
+		return dt
+	}
+	users := &ObjectName{Idents: []string{"public", "users"}}
+	orders := &ObjectName{Idents: []string{"public", "orders"}}
+	live := &MetaDatabase{Tables: []*MetaTable{
+		PGTableToMetaTable(&PGTable{
+			Name:      users,
+			TableType: "BASE TABLE",
+			Columns: []*PGColumn{
+				{Name: "id", DataType: sized("bigint", 0, 64, 0), DefaultValue: "nextval('users_id_seq'::regclass)", IsPrimaryKey: true},
+				{Name: "email", DataType: sized("character varying", 20, 0, 0)},
+				{Name: "balance", DataType: sized("numeric", 0, 10, 2), DefaultValue: "0"},
+			},
+			Constraints: []*PGConstraint{{Name: "users_pkey", TableName: users, Type: "p", Columns: []string{"id"}, IndexName: "users_pkey"}},
+		}),
+		PGTableToMetaTable(&PGTable{
+			Name:      orders,
+			TableType: "BASE TABLE",
+			Columns: []*PGColumn{
+				{Name: "id", DataType: sized("integer", 0, 32, 0), DefaultValue: "nextval('orders_id_seq'::regclass)", IsPrimaryKey: true},
+				{Name: "user_id", DataType: sized("bigint", 0, 64, 0), IsNullable: true},
+			},
+			Constraints: []*PGConstraint{{Name: "orders_pkey", TableName: orders, Type: "p", Columns: []string{"id"}, IndexName: "orders_pkey"}},
+			ForeignKeys: []*PGForeignKey{{
+				Name: "orders_user_id_fkey", TableName: orders, LocalColumns: []string{"user_id"},
+				ForeignTable: users, ForeignColumns: []string{"id"}, OnDelete: "CASCADE",
+			}},
+		}),
+	}}
+
+	if changes := DiffDatabaseWithOptions(live, file, DiffOptions{Dialect: DialectPostgres}); len(changes) != 0 {
+		t.Errorf("Expected no changes between the dump and the loaded schema, got %v", changes)
+	}
+}
+
+func TestLoadMetaDatabaseFromSQL_AlterTable(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE t (id int, name text, note text);
+ALTER TABLE t ADD COLUMN created_at timestamp DEFAULT now() NOT NULL;
+ALTER TABLE t ALTER COLUMN name SET NOT NULL, ALTER COLUMN note SET DEFAULT 'none';
+ALTER TABLE t ADD CONSTRAINT t_name_key UNIQUE (name);
+ALTER TABLE t RENAME COLUMN note TO remark;
+ALTER TABLE t DROP COLUMN IF EXISTS missing;
+CREATE TABLE gone (id int);
+DROP TABLE gone;
+CREATE TABLE IF NOT EXISTS t (other int);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	if len(db.Tables) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(db.Tables))
+	}
+	cols := orderedColumns(db.Tables[0].Elements)
+	var names []string
+	for _, col := range cols {
+		names = append(names, col.Name)
+	}
+	if want := []string{"id", "name", "remark", "created_at"}; !slices.Equal(names, want) {
+		t.Fatalf("Expected columns %v, got %v", want, names)
+	}
+	if !isNotNull(cols[1]) || !isNotNull(cols[3]) {
+		t.Errorf("Expected name and created_at to be NOT NULL")
+	}
+	if got := anyToString(cols[2].Default); got != "'none'" {
+		t.Errorf("Expected remark default 'none', got %q", got)
+	}
+	var constraints int
+	for _, e := range db.Tables[0].Elements {
+		if e.GetTableConstraintElement() != nil {
+			constraints++
+		}
+	}
+	if constraints != 1 {
+		t.Errorf("Expected 1 table constraint, got %d", constraints)
+	}
+
+	for _, sql := range []string{
+		"CREATE TABLE t (id int); CREATE TABLE t (id int);",
+		"ALTER TABLE t ADD COLUMN id int;",
+		"CREATE TABLE t (id int); ALTER TABLE t FROBNICATE;",
+		"CREATE TABLE t (id int); ALTER TABLE t ALTER COLUMN id FROBNICATE;",
+		"CREATE TABLE t (id int); ALTER TABLE t ADD COLUMN id int;",
+		"DROP TABLE t;",
+	} {
+		if _, err := LoadMetaDatabaseFromSQL(sql, DialectPostgres); err == nil {
+			t.Errorf("Expected error for %q", sql)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package xmeta
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -8,23 +9,112 @@ import (
 
 // LoadSQLite metadata into a SQLiteDatabase structure.
 func LoadSQLite(db *sql.DB) (*SQLiteDatabase, error) {
+	return LoadSQLiteContext(context.Background(), db)
+}
+
+// LoadSQLiteContext is LoadSQLite with a context that cancels the catalog queries.
+func LoadSQLiteContext(ctx context.Context, db *sql.DB) (*SQLiteDatabase, error) {
+	return LoadSQLiteWithFilter(ctx, db, LoadFilter{})
+}
+
+// LoadSQLiteWithFilter is LoadSQLiteContext restricted to the tables
+// selected by filter. Schema patterns do not apply to SQLite.
+func LoadSQLiteWithFilter(ctx context.Context, db *sql.DB, filter LoadFilter) (*SQLiteDatabase, error) {
+	return loadSQLite(ctx, db, filter, nil)
+}
+
+// loadSQLite is LoadSQLiteWithFilter with the column types mapped by mapper
+// first.
+func loadSQLite(ctx context.Context, db *sql.DB, filter LoadFilter, mapper TypeMapper) (*SQLiteDatabase, error) {
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return nil, fmt.Errorf("failed to get sqlite version: %w", err)
+	}
+
 	sqliteDB := &SQLiteDatabase{
-		Name: "main",
+		Name:    "main",
+		Version: version,
 	}
 
 	// List tables
-	tables, err := loadSQLiteTables(db)
+	tables, err := loadSQLiteTables(ctx, db, filter, mapper)
 	if err != nil {
 		return nil, err
 	}
 	sqliteDB.Tables = tables
 
+	views, err := loadSQLiteViews(ctx, db, filter, mapper)
+	if err != nil {
+		return nil, err
+	}
+	sqliteDB.Views = views
+
+	triggers, err := loadSQLiteTriggers(ctx, db, filter)
+	if err != nil {
+		return nil, err
+	}
+	sqliteDB.Triggers = triggers
+
 	return sqliteDB, nil
 }
 
-func loadSQLiteTables(db *sql.DB) ([]*SQLiteTable, error) {
+func loadSQLiteViews(ctx context.Context, db *sql.DB, filter LoadFilter, mapper TypeMapper) ([]*SQLiteView, error) {
+	query := `SELECT name, sql FROM sqlite_schema WHERE type='view'`
+	conds, args := filter.sqlConditions("name", "", nil, questionPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds+" ORDER BY name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*SQLiteView
+	for rows.Next() {
+		var name, sqlDef sql.NullString
+		if err := rows.Scan(&name, &sqlDef); err != nil {
+			return nil, err
+		}
+		views = append(views, &SQLiteView{Name: name.String, Definition: sqlDef.String})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// PRAGMA table_info also reports the columns a view yields
+	for _, v := range views {
+		cols, err := loadSQLiteColumns(ctx, db, v.Name, mapper)
+		if err != nil {
+			return nil, err
+		}
+		v.Columns = cols
+	}
+	return views, nil
+}
+
+// loadSQLiteTriggers loads the triggers of the tables selected by filter.
+func loadSQLiteTriggers(ctx context.Context, db *sql.DB, filter LoadFilter) ([]*SQLiteTrigger, error) {
+	query := `SELECT name, tbl_name, sql FROM sqlite_schema WHERE type='trigger'`
+	conds, args := filter.sqlConditions("tbl_name", "", nil, questionPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds+" ORDER BY name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []*SQLiteTrigger
+	for rows.Next() {
+		var name, table, sqlDef sql.NullString
+		if err := rows.Scan(&name, &table, &sqlDef); err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, &SQLiteTrigger{Name: name.String, TableName: table.String, Definition: sqlDef.String})
+	}
+	return triggers, rows.Err()
+}
+
+func loadSQLiteTables(ctx context.Context, db *sql.DB, filter LoadFilter, mapper TypeMapper) ([]*SQLiteTable, error) {
 	query := `SELECT name, sql FROM sqlite_schema WHERE type='table' AND name NOT LIKE 'sqlite_%'`
-	rows, err := db.Query(query)
+	conds, args := filter.sqlConditions("name", "", nil, questionPlaceholder)
+	rows, err := db.QueryContext(ctx, query+conds, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sqlite_schema: %w", err)
 	}
@@ -44,21 +134,36 @@ func loadSQLiteTables(db *sql.DB) ([]*SQLiteTable, error) {
 		}
 
 		// Load Columns via PRAGMA
-		cols, err := loadSQLiteColumns(db, name.String)
+		cols, err := loadSQLiteColumns(ctx, db, name.String, mapper)
 		if err != nil {
 			return nil, err
 		}
 		table.Columns = cols
 
+		indexes, err := loadSQLiteIndexes(ctx, db, name.String)
+		if err != nil {
+			return nil, err
+		}
+		table.Indexes = indexes
+
+		fks, err := loadSQLiteForeignKeys(ctx, db, name.String)
+		if err != nil {
+			return nil, err
+		}
+		table.ForeignKeys = fks
+
+		// AUTOINCREMENT and WITHOUT ROWID are only visible in the CREATE statement
+		applySQLiteDefinition(table)
+
 		tables = append(tables, table)
 	}
 	return tables, nil
 }
 
-func loadSQLiteColumns(db *sql.DB, tableName string) ([]*SQLiteColumn, error) {
+func loadSQLiteColumns(ctx context.Context, db *sql.DB, tableName string, mapper TypeMapper) ([]*SQLiteColumn, error) {
 	// PRAGMA table_info returns: cid, name, type, notnull, dflt_value, pk
 	query := fmt.Sprintf("PRAGMA table_info(%q)", tableName)
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pragma table_info for %s: %w", tableName, err)
 	}
@@ -74,9 +179,13 @@ func loadSQLiteColumns(db *sql.DB, tableName string) ([]*SQLiteColumn, error) {
 			return nil, err
 		}
 
+		dt := mapType(mapper, typ.String, 0, 0, 0)
+		if dt == nil {
+			dt = mapSQLiteTypeForProto(typ.String)
+		}
 		col := &SQLiteColumn{
 			Name:         name.String,
-			DataType:     mapSQLiteTypeForProto(typ.String),
+			DataType:     dt,
 			IsNullable:   (notnull == 0),
 			DefaultValue: dflt.String,
 			IsPrimaryKey: (pk > 0),
@@ -86,6 +195,92 @@ func loadSQLiteColumns(db *sql.DB, tableName string) ([]*SQLiteColumn, error) {
 	return cols, nil
 }
 
+// loadSQLiteIndexes loads the indexes of a table with PRAGMA index_list
+// and index_info, and the CREATE INDEX statement of those created
+// explicitly.
+func loadSQLiteIndexes(ctx context.Context, db *sql.DB, tableName string) ([]*SQLiteIndex, error) {
+	// PRAGMA index_list returns: seq, name, unique, origin, partial
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_list(%q)", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pragma index_list for %s: %w", tableName, err)
+	}
+	var indexes []*SQLiteIndex
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		indexes = append(indexes, &SQLiteIndex{Name: name, TableName: tableName, IsUnique: unique == 1, Origin: origin})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, idx := range indexes {
+		// PRAGMA index_info returns: seqno, cid, name; name is NULL for an
+		// expression
+		rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_info(%q)", idx.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to pragma index_info for %s: %w", idx.Name, err)
+		}
+		for rows.Next() {
+			var seqno, cid int
+			var col sql.NullString
+			if err := rows.Scan(&seqno, &cid, &col); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			idx.Columns = append(idx.Columns, col.String)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		var def sql.NullString
+		err = db.QueryRowContext(ctx, "SELECT sql FROM sqlite_schema WHERE type='index' AND name=?", idx.Name).Scan(&def)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to query index %s: %w", idx.Name, err)
+		}
+		idx.Definition = def.String
+	}
+	return indexes, nil
+}
+
+// loadSQLiteForeignKeys loads the foreign keys of a table with PRAGMA
+// foreign_key_list, one row per column of each key.
+func loadSQLiteForeignKeys(ctx context.Context, db *sql.DB, tableName string) ([]*SQLiteForeignKey, error) {
+	// PRAGMA foreign_key_list returns: id, seq, table, from, to, on_update,
+	// on_delete, match; to is NULL for a key on the primary key
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA foreign_key_list(%q)", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pragma foreign_key_list for %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	var fks []*SQLiteForeignKey
+	for rows.Next() {
+		var id, seq int32
+		var table, from, onUpdate, onDelete, match string
+		var to sql.NullString
+		if err := rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+		if n := len(fks); n == 0 || fks[n-1].Id != id {
+			fks = append(fks, &SQLiteForeignKey{Id: id, Table: table, OnUpdate: onUpdate, OnDelete: onDelete, Match: match})
+		}
+		fk := fks[len(fks)-1]
+		fk.From = append(fk.From, from)
+		if to.Valid {
+			fk.To = append(fk.To, to.String)
+		}
+	}
+	return fks, rows.Err()
+}
+
 func mapSQLiteTypeForProto(typ string) *DataType {
 	t := &DataType{}
 	typ = strings.ToUpper(typ)
@@ -99,9 +294,131 @@ func mapSQLiteTypeForProto(typ string) *DataType {
 		t.TypeClause = &DataType_ByteaData{ByteaData: DataTypeSingle_Bytea} // Approximate
 	} else if strings.Contains(typ, "REAL") || strings.Contains(typ, "FLOA") || strings.Contains(typ, "DOUB") {
 		t.TypeClause = &DataType_RealData{RealData: &Real{}}
+	} else if sqliteDeclaredTypes[sqliteBaseType(typ)] {
+		// NUMERIC affinity: keep the declared type, with its precision and
+		// scale, for dialects that enforce it
+		return parseSQLDataType(typ)
 	} else {
 		// Fallback
 		t.TypeClause = &DataType_CustomData{CustomData: &ObjectName{Idents: []string{typ}}}
 	}
 	return t
 }
+
+// sqliteDeclaredTypes are the NUMERIC-affinity type names kept as declared
+// rather than reduced to their affinity.
+var sqliteDeclaredTypes = map[string]bool{
+	"NUMERIC": true, "DECIMAL": true, "BOOLEAN": true, "BOOL": true,
+	"DATE": true, "DATETIME": true, "TIMESTAMP": true, "TIME": true, "JSON": true,
+}
+
+// sqliteBaseType returns the type name without its parenthesized arguments.
+func sqliteBaseType(typ string) string {
+	if open := strings.Index(typ, "("); open >= 0 {
+		typ = typ[:open]
+	}
+	return strings.TrimSpace(typ)
+}
+
+// applySQLiteDefinition parses the original CREATE TABLE statement to set
+// flags that PRAGMA table_info does not report: WITHOUT ROWID and STRICT on
+// the table and AUTOINCREMENT on its columns.
+func applySQLiteDefinition(table *SQLiteTable) {
+	open := strings.Index(table.Definition, "(")
+	closing := strings.LastIndex(table.Definition, ")")
+	if open < 0 || closing < open {
+		return
+	}
+
+	// Table options follow the closing parenthesis, e.g. ") WITHOUT ROWID, STRICT"
+	for _, opt := range strings.Split(table.Definition[closing+1:], ",") {
+		switch strings.ToUpper(strings.Join(strings.Fields(strings.TrimSuffix(strings.TrimSpace(opt), ";")), " ")) {
+		case "WITHOUT ROWID":
+			table.WithoutRowId = true
+		case "STRICT":
+			table.Strict = true
+		}
+	}
+
+	autoInc := make(map[string]bool)
+	for _, def := range splitSQLiteColumnDefs(table.Definition[open+1 : closing]) {
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, f := range fields[1:] {
+			if strings.EqualFold(f, "AUTOINCREMENT") {
+				autoInc[strings.ToLower(unquoteSQLiteIdent(fields[0]))] = true
+				break
+			}
+		}
+	}
+	for _, col := range table.Columns {
+		if autoInc[strings.ToLower(col.Name)] {
+			col.AutoIncrement = true
+		}
+	}
+}
+
+// sqliteParsedTable parses the CREATE TABLE statement of t, followed by
+// the CREATE INDEX statements of its explicitly created indexes, for what
+// the PRAGMAs do not report: constraint names and CHECK constraints, and
+// index expressions and predicates. A definition that cannot be parsed
+// yields nil.
+func sqliteParsedTable(t *SQLiteTable) *MetaTable {
+	if t.Definition == "" {
+		return nil
+	}
+	stmts := []string{t.Definition}
+	for _, idx := range t.Indexes {
+		if idx.Origin == "c" && idx.Definition != "" {
+			stmts = append(stmts, idx.Definition)
+		}
+	}
+	db, err := LoadMetaDatabaseFromSQL(strings.Join(stmts, ";\n"), DialectSQLite)
+	if err != nil || len(db.Tables) != 1 {
+		return nil
+	}
+	return db.Tables[0]
+}
+
+// splitSQLiteColumnDefs splits the body of a CREATE TABLE statement on
+// top-level commas, ignoring commas inside parentheses and quotes.
+func splitSQLiteColumnDefs(body string) []string {
+	var defs []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '[':
+			quote = ']'
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			defs = append(defs, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return append(defs, strings.TrimSpace(body[start:]))
+}
+
+// unquoteSQLiteIdent strips SQLite identifier quotes ("x", `x`, [x]).
+func unquoteSQLiteIdent(s string) string {
+	if len(s) >= 2 {
+		switch {
+		case s[0] == '"' && s[len(s)-1] == '"',
+			s[0] == '`' && s[len(s)-1] == '`',
+			s[0] == '[' && s[len(s)-1] == ']':
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
@@ -0,0 +1,270 @@
+package xmeta
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestApplySQLiteDefinition(t *testing.T) {
+	table := &SQLiteTable{
+		Name: "events",
+		Definition: `CREATE TABLE "events" (
+			"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+			payload TEXT CHECK (length(payload) > 0),
+			created_at TEXT DEFAULT (datetime('now'))
+		) WITHOUT ROWID`,
+		Columns: []*SQLiteColumn{
+			{Name: "id", IsPrimaryKey: true},
+			{Name: "payload", IsNullable: true},
+			{Name: "created_at", IsNullable: true},
+		},
+	}
+
+	applySQLiteDefinition(table)
+
+	if !table.WithoutRowId {
+		t.Error("Expected WithoutRowId")
+	}
+	if !table.Columns[0].AutoIncrement {
+		t.Error("Expected id to be AUTOINCREMENT")
+	}
+	if table.Columns[1].AutoIncrement || table.Columns[2].AutoIncrement {
+		t.Error("Expected only id to be AUTOINCREMENT")
+	}
+
+	meta := SQLiteTableToMetaTable(table)
+	if meta.Options["WithoutRowID"] != "true" {
+		t.Error("Expected WithoutRowID option")
+	}
+	colDef := meta.Elements[0].GetColumnDefElement()
+	if len(colDef.MyDecos) != 1 || colDef.MyDecos[0] != AutoIncrement_AutoIncrementConfirm {
+		t.Errorf("Expected AutoIncrement marker, got %v", colDef.MyDecos)
+	}
+}
+
+func TestSQLiteTableToMetaTable_TableConstraints(t *testing.T) {
+	table := &SQLiteTable{
+		Name: "order_items",
+		Definition: `CREATE TABLE order_items (
+			order_id INTEGER NOT NULL,
+			line INTEGER NOT NULL,
+			qty INTEGER,
+			PRIMARY KEY (order_id, line),
+			CONSTRAINT qty_positive CHECK (qty > 0)
+		)`,
+		Columns: []*SQLiteColumn{
+			{Name: "order_id", IsPrimaryKey: true},
+			{Name: "line", IsPrimaryKey: true},
+			{Name: "qty", IsNullable: true},
+		},
+	}
+
+	meta := SQLiteTableToMetaTable(table)
+	constraints := constraintsFromElements(meta.Elements)
+	pk := constraints["order_items_pkey"].GetSpec().GetUniqueItem()
+	if !pk.GetIsPrimary() || len(pk.Columns) != 2 || pk.Columns[1] != "line" {
+		t.Errorf("Expected composite primary key, got %v", constraints)
+	}
+	if anyToString(constraints["qty_positive"].GetSpec().GetCheckItem()) != "qty > 0" {
+		t.Errorf("Expected check constraint, got %v", constraints)
+	}
+	for _, col := range columnsFromElements(meta.Elements) {
+		for _, con := range col.Constraints {
+			if con.GetSpec().GetUniqueItem().GetIsPrimaryKey() {
+				t.Errorf("Expected no inline primary key on %s", col.Name)
+			}
+		}
+	}
+
+	// Without a parsable definition the key comes from the PRAGMA flags
+	table.Definition = ""
+	pk = constraintsFromElements(SQLiteTableToMetaTable(table).Elements)["order_items_pkey"].GetSpec().GetUniqueItem()
+	if !pk.GetIsPrimary() || len(pk.Columns) != 2 {
+		t.Errorf("Expected composite primary key from flags, got %v", pk)
+	}
+}
+
+func TestSQLiteTableToMetaTable_ColumnConstraints(t *testing.T) {
+	const schema = `CREATE TABLE p (id INTEGER PRIMARY KEY);
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY,
+	email TEXT UNIQUE,
+	age INT CHECK (age > 0),
+	p INT REFERENCES p(id) ON DELETE CASCADE
+);
+CREATE INDEX users_email ON users (email) WHERE email IS NOT NULL;`
+	file, err := LoadMetaDatabaseFromSQL(schema, DialectSQLite)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	// The tables as the SQLite loader reads them back
+	text := &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}
+	integer := &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}
+	live := SQLiteDatabaseToMetaDatabase(&SQLiteDatabase{Tables: []*SQLiteTable{
+		{
+			Name:       "p",
+			Type:       "table",
+			Definition: "CREATE TABLE p (id INTEGER PRIMARY KEY)",
+			Columns:    []*SQLiteColumn{{Name: "id", DataType: integer, IsNullable: true, IsPrimaryKey: true}},
+		},
+		{
+			Name:       "users",
+			Type:       "table",
+			Definition: strings.Split(schema, ";\n")[1],
+			Columns: []*SQLiteColumn{
+				{Name: "id", DataType: integer, IsNullable: true, IsPrimaryKey: true},
+				{Name: "email", DataType: text, IsNullable: true},
+				{Name: "age", DataType: integer, IsNullable: true},
+				{Name: "p", DataType: integer, IsNullable: true},
+			},
+			Indexes: []*SQLiteIndex{
+				{Name: "users_email", TableName: "users", Columns: []string{"email"}, Origin: "c",
+					Definition: "CREATE INDEX users_email ON users (email) WHERE email IS NOT NULL"},
+				{Name: "sqlite_autoindex_users_1", TableName: "users", Columns: []string{"email"}, IsUnique: true, Origin: "u"},
+			},
+			ForeignKeys: []*SQLiteForeignKey{{Table: "p", From: []string{"p"}, To: []string{"id"}, OnUpdate: "NO ACTION", OnDelete: "CASCADE", Match: "NONE"}},
+		},
+	}})
+	live.Name, live.Options = "", nil
+
+	if changes := DiffDatabase(live, file); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+	users := live.Tables[1]
+	if len(users.Indexes) != 1 || users.Indexes[0].Predicate == "" {
+		t.Errorf("Expected the partial index users_email, got %v", users.Indexes)
+	}
+	fk := constraintsFromElements(users.Elements)["users_p_fkey"].GetSpec().GetReferenceItem()
+	if fk.GetOnDelete() != ReferentialAction_ReferentialAction_Cascade || fk.GetOnUpdate() != ReferentialAction_ReferentialAction_Unknown {
+		t.Errorf("Expected users_p_fkey ON DELETE CASCADE, got %v", fk)
+	}
+}
+
+func TestMapSQLiteTypeForProto(t *testing.T) {
+	dec := mapSQLiteTypeForProto("decimal(10, 2)").GetDecimalData()
+	if dec == nil || dec.Precision != 10 || dec.Scale != 2 {
+		t.Errorf("Expected DECIMAL(10,2), got %v", dec)
+	}
+	if mapSQLiteTypeForProto("NUMERIC").GetDecimalData() == nil {
+		t.Error("Expected NUMERIC to map to DecimalData")
+	}
+	if mapSQLiteTypeForProto("BOOLEAN").GetBooleanData() != DataTypeSingle_Boolean {
+		t.Error("Expected BOOLEAN to map to BooleanData")
+	}
+	if mapSQLiteTypeForProto("DATE").GetDateData() != DataTypeSingle_Date {
+		t.Error("Expected DATE to map to DateData")
+	}
+	if ts := mapSQLiteTypeForProto("datetime").GetTimestampData(); ts == nil || ts.WithTimeZone {
+		t.Errorf("Expected DATETIME to map to Timestamp, got %v", ts)
+	}
+	if mapSQLiteTypeForProto("BIGINT").GetIntData() == nil {
+		t.Error("Expected INTEGER affinity for BIGINT")
+	}
+	if mapSQLiteTypeForProto("JSONB").GetCustomData() == nil {
+		t.Error("Expected unknown types to stay custom")
+	}
+}
+
+func TestSQLiteStrictTables(t *testing.T) {
+	table := &SQLiteTable{
+		Name:       "events",
+		Definition: "CREATE TABLE events (id INTEGER PRIMARY KEY, payload TEXT) without rowid ,  STRICT",
+		Columns: []*SQLiteColumn{
+			{Name: "id", DataType: &DataType{TypeClause: &DataType_IntData{IntData: &Int{}}}, IsPrimaryKey: true},
+			{Name: "payload", DataType: &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}, IsNullable: true},
+		},
+	}
+	applySQLiteDefinition(table)
+	if !table.WithoutRowId || !table.Strict {
+		t.Fatalf("Expected WITHOUT ROWID and STRICT, got %v", table)
+	}
+	strict := SQLiteTableToMetaTable(table)
+	if strict.Options["Strict"] != "true" || strict.Options["WithoutRowID"] != "true" {
+		t.Errorf("Unexpected options %v", strict.Options)
+	}
+
+	stmts, err := GenerateSQL(AddTable{Table: strict}, DialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(stmts[0], ") WITHOUT ROWID, STRICT") {
+		t.Errorf("Unexpected DDL %q", stmts[0])
+	}
+	parsed, err := LoadMetaDatabaseFromSQL(stmts[0], DialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts := parsed.Tables[0].Options; opts["Strict"] != "true" || opts["WithoutRowID"] != "true" {
+		t.Errorf("Expected the parsed table to keep its options, got %v", opts)
+	}
+
+	// Dropping STRICT is a table option change SQLite cannot apply in place
+	loose := proto.Clone(strict).(*MetaTable)
+	delete(loose.Options, "Strict")
+	changes := DiffDatabase(&MetaDatabase{Tables: []*MetaTable{strict}}, &MetaDatabase{Tables: []*MetaTable{loose}})
+	if len(changes) != 1 {
+		t.Fatalf("Expected one change, got %v", changes)
+	}
+	if _, ok := changes[0].(AlterTableOptions); !ok {
+		t.Fatalf("Expected AlterTableOptions, got %T", changes[0])
+	}
+	if _, err := GenerateSQL(changes[0], DialectSQLite); err == nil {
+		t.Error("Expected an error altering STRICT in SQLite")
+	}
+}
+
+func TestSQLiteViewsAndTriggers(t *testing.T) {
+	trigger := `CREATE TRIGGER users_touch AFTER UPDATE ON users
+BEGIN
+  UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END`
+	current := SQLiteDatabaseToMetaDatabase(&SQLiteDatabase{
+		Name:  "main",
+		Views: []*SQLiteView{{Name: "old_users", Definition: "CREATE VIEW old_users AS SELECT id FROM users WHERE id < 100"}},
+	})
+	desired := SQLiteDatabaseToMetaDatabase(&SQLiteDatabase{
+		Name: "main",
+		Views: []*SQLiteView{{Name: "active_users", Definition: `CREATE VIEW "active_users" (id) AS
+  SELECT id FROM users WHERE active`}},
+		Triggers: []*SQLiteTrigger{{Name: "users_touch", TableName: "users", Definition: trigger}},
+	})
+	if v := desired.Views[0]; v.Definition != "SELECT id FROM users WHERE active" {
+		t.Errorf("Expected the SELECT as view definition, got %q", v.Definition)
+	}
+	if tr := desired.Triggers[0]; formatObjectName(tr.TableName) != "users" || tr.Definition != trigger {
+		t.Errorf("Unexpected trigger %v", tr)
+	}
+
+	changes := DiffDatabase(current, desired)
+	var got []string
+	for _, c := range changes {
+		stmts, err := GenerateSQLWithOptions(c, DialectSQLite, DDLOptions{IfExistsGuards: true})
+		if err != nil {
+			t.Fatalf("GenerateSQL(%v) failed: %v", c, err)
+		}
+		got = append(got, stmts...)
+	}
+	want := []string{
+		`DROP VIEW IF EXISTS "old_users"`,
+		`CREATE VIEW "active_users" AS SELECT id FROM users WHERE active`,
+		trigger,
+	}
+	if strings.Join(got, ";\n") != strings.Join(want, ";\n") {
+		t.Errorf("Unexpected statements:\n%s", strings.Join(got, ";\n"))
+	}
+
+	// Dropping the trigger again, and the Postgres spelling of the drop
+	changes = DiffDatabase(desired, current)
+	drops := FilterChanges(changes, func(c SchemaChange) bool { _, ok := c.(DropTrigger); return ok })
+	if len(changes) != 3 || len(drops) != 1 || drops[0].(DropTrigger).TriggerName != "users_touch" {
+		t.Fatalf("Expected the trigger to be dropped, got %v", changes)
+	}
+	drop := drops[0].(DropTrigger)
+	stmts, err := GenerateSQL(drop, DialectPostgres)
+	if err != nil || stmts[0] != `DROP TRIGGER "users_touch" ON "users"` {
+		t.Errorf("Unexpected Postgres drop %v, %v", stmts, err)
+	}
+}
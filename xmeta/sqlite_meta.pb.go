@@ -215,6 +215,99 @@ func (x *SQLiteIndex) GetDefinition() string {
 	return ""
 }
 
+// Represents a foreign key, as PRAGMA foreign_key_list reports it
+type SQLiteForeignKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=Id,proto3" json:"Id,omitempty"`            // Key id, shared by the rows of one key
+	Table         string                 `protobuf:"bytes,2,opt,name=Table,proto3" json:"Table,omitempty"`       // Referenced table
+	From          []string               `protobuf:"bytes,3,rep,name=From,proto3" json:"From,omitempty"`         // Local columns, in key order
+	To            []string               `protobuf:"bytes,4,rep,name=To,proto3" json:"To,omitempty"`             // Referenced columns, empty for the primary key
+	OnUpdate      string                 `protobuf:"bytes,5,opt,name=OnUpdate,proto3" json:"OnUpdate,omitempty"` // NO ACTION, RESTRICT, SET NULL, SET DEFAULT or CASCADE
+	OnDelete      string                 `protobuf:"bytes,6,opt,name=OnDelete,proto3" json:"OnDelete,omitempty"`
+	Match         string                 `protobuf:"bytes,7,opt,name=Match,proto3" json:"Match,omitempty"` // NONE unless MATCH is given
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SQLiteForeignKey) Reset() {
+	*x = SQLiteForeignKey{}
+	mi := &file_sqlite_meta_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SQLiteForeignKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SQLiteForeignKey) ProtoMessage() {}
+
+func (x *SQLiteForeignKey) ProtoReflect() protoreflect.Message {
+	mi := &file_sqlite_meta_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SQLiteForeignKey.ProtoReflect.Descriptor instead.
+func (*SQLiteForeignKey) Descriptor() ([]byte, []int) {
+	return file_sqlite_meta_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SQLiteForeignKey) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SQLiteForeignKey) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *SQLiteForeignKey) GetFrom() []string {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *SQLiteForeignKey) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *SQLiteForeignKey) GetOnUpdate() string {
+	if x != nil {
+		return x.OnUpdate
+	}
+	return ""
+}
+
+func (x *SQLiteForeignKey) GetOnDelete() string {
+	if x != nil {
+		return x.OnDelete
+	}
+	return ""
+}
+
+func (x *SQLiteForeignKey) GetMatch() string {
+	if x != nil {
+		return x.Match
+	}
+	return ""
+}
+
 // Represents a Table in SQLite
 type SQLiteTable struct {
 	state   protoimpl.MessageState `protogen:"open.v1"`
@@ -223,17 +316,18 @@ type SQLiteTable struct {
 	Columns []*SQLiteColumn        `protobuf:"bytes,3,rep,name=Columns,proto3" json:"Columns,omitempty"`
 	Indexes []*SQLiteIndex         `protobuf:"bytes,4,rep,name=Indexes,proto3" json:"Indexes,omitempty"`
 	// SQLite Specific Options
-	WithoutRowId  bool   `protobuf:"varint,5,opt,name=WithoutRowId,proto3" json:"WithoutRowId,omitempty"` // WITHOUT ROWID optimization
-	Strict        bool   `protobuf:"varint,6,opt,name=Strict,proto3" json:"Strict,omitempty"`             // STRICT tables (SQLite 3.37+)
-	Definition    string `protobuf:"bytes,7,opt,name=Definition,proto3" json:"Definition,omitempty"`      // Original CREATE statement
-	RootPage      int64  `protobuf:"varint,8,opt,name=RootPage,proto3" json:"RootPage,omitempty"`         // Root page number in DB file
+	WithoutRowId  bool                `protobuf:"varint,5,opt,name=WithoutRowId,proto3" json:"WithoutRowId,omitempty"` // WITHOUT ROWID optimization
+	Strict        bool                `protobuf:"varint,6,opt,name=Strict,proto3" json:"Strict,omitempty"`             // STRICT tables (SQLite 3.37+)
+	Definition    string              `protobuf:"bytes,7,opt,name=Definition,proto3" json:"Definition,omitempty"`      // Original CREATE statement
+	RootPage      int64               `protobuf:"varint,8,opt,name=RootPage,proto3" json:"RootPage,omitempty"`         // Root page number in DB file
+	ForeignKeys   []*SQLiteForeignKey `protobuf:"bytes,9,rep,name=ForeignKeys,proto3" json:"ForeignKeys,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SQLiteTable) Reset() {
 	*x = SQLiteTable{}
-	mi := &file_sqlite_meta_proto_msgTypes[2]
+	mi := &file_sqlite_meta_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -245,7 +339,7 @@ func (x *SQLiteTable) String() string {
 func (*SQLiteTable) ProtoMessage() {}
 
 func (x *SQLiteTable) ProtoReflect() protoreflect.Message {
-	mi := &file_sqlite_meta_proto_msgTypes[2]
+	mi := &file_sqlite_meta_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -258,7 +352,7 @@ func (x *SQLiteTable) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SQLiteTable.ProtoReflect.Descriptor instead.
 func (*SQLiteTable) Descriptor() ([]byte, []int) {
-	return file_sqlite_meta_proto_rawDescGZIP(), []int{2}
+	return file_sqlite_meta_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *SQLiteTable) GetName() string {
@@ -317,6 +411,13 @@ func (x *SQLiteTable) GetRootPage() int64 {
 	return 0
 }
 
+func (x *SQLiteTable) GetForeignKeys() []*SQLiteForeignKey {
+	if x != nil {
+		return x.ForeignKeys
+	}
+	return nil
+}
+
 // Represents a View
 type SQLiteView struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -329,7 +430,7 @@ type SQLiteView struct {
 
 func (x *SQLiteView) Reset() {
 	*x = SQLiteView{}
-	mi := &file_sqlite_meta_proto_msgTypes[3]
+	mi := &file_sqlite_meta_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -341,7 +442,7 @@ func (x *SQLiteView) String() string {
 func (*SQLiteView) ProtoMessage() {}
 
 func (x *SQLiteView) ProtoReflect() protoreflect.Message {
-	mi := &file_sqlite_meta_proto_msgTypes[3]
+	mi := &file_sqlite_meta_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -354,7 +455,7 @@ func (x *SQLiteView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SQLiteView.ProtoReflect.Descriptor instead.
 func (*SQLiteView) Descriptor() ([]byte, []int) {
-	return file_sqlite_meta_proto_rawDescGZIP(), []int{3}
+	return file_sqlite_meta_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SQLiteView) GetName() string {
@@ -378,6 +479,67 @@ func (x *SQLiteView) GetColumns() []*SQLiteColumn {
 	return nil
 }
 
+// Represents a Trigger
+type SQLiteTrigger struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	TableName     string                 `protobuf:"bytes,2,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Definition    string                 `protobuf:"bytes,3,opt,name=Definition,proto3" json:"Definition,omitempty"` // Original CREATE statement
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SQLiteTrigger) Reset() {
+	*x = SQLiteTrigger{}
+	mi := &file_sqlite_meta_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SQLiteTrigger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SQLiteTrigger) ProtoMessage() {}
+
+func (x *SQLiteTrigger) ProtoReflect() protoreflect.Message {
+	mi := &file_sqlite_meta_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SQLiteTrigger.ProtoReflect.Descriptor instead.
+func (*SQLiteTrigger) Descriptor() ([]byte, []int) {
+	return file_sqlite_meta_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SQLiteTrigger) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SQLiteTrigger) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *SQLiteTrigger) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
 // Represents a Database File (or Attached DB)
 type SQLiteDatabase struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -385,14 +547,15 @@ type SQLiteDatabase struct {
 	FilePath      string                 `protobuf:"bytes,2,opt,name=FilePath,proto3" json:"FilePath,omitempty"`
 	Tables        []*SQLiteTable         `protobuf:"bytes,3,rep,name=Tables,proto3" json:"Tables,omitempty"`
 	Views         []*SQLiteView          `protobuf:"bytes,4,rep,name=Views,proto3" json:"Views,omitempty"`
-	Triggers      []string               `protobuf:"bytes,5,rep,name=Triggers,proto3" json:"Triggers,omitempty"`
+	Version       string                 `protobuf:"bytes,6,opt,name=Version,proto3" json:"Version,omitempty"` // sqlite_version()
+	Triggers      []*SQLiteTrigger       `protobuf:"bytes,7,rep,name=Triggers,proto3" json:"Triggers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SQLiteDatabase) Reset() {
 	*x = SQLiteDatabase{}
-	mi := &file_sqlite_meta_proto_msgTypes[4]
+	mi := &file_sqlite_meta_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -404,7 +567,7 @@ func (x *SQLiteDatabase) String() string {
 func (*SQLiteDatabase) ProtoMessage() {}
 
 func (x *SQLiteDatabase) ProtoReflect() protoreflect.Message {
-	mi := &file_sqlite_meta_proto_msgTypes[4]
+	mi := &file_sqlite_meta_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -417,7 +580,7 @@ func (x *SQLiteDatabase) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SQLiteDatabase.ProtoReflect.Descriptor instead.
 func (*SQLiteDatabase) Descriptor() ([]byte, []int) {
-	return file_sqlite_meta_proto_rawDescGZIP(), []int{4}
+	return file_sqlite_meta_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SQLiteDatabase) GetName() string {
@@ -448,7 +611,14 @@ func (x *SQLiteDatabase) GetViews() []*SQLiteView {
 	return nil
 }
 
-func (x *SQLiteDatabase) GetTriggers() []string {
+func (x *SQLiteDatabase) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SQLiteDatabase) GetTriggers() []*SQLiteTrigger {
 	if x != nil {
 		return x.Triggers
 	}
@@ -481,7 +651,15 @@ const file_sqlite_meta_proto_rawDesc = "" +
 	"\x06Origin\x18\x06 \x01(\tR\x06Origin\x12\x1e\n" +
 	"\n" +
 	"Definition\x18\a \x01(\tR\n" +
-	"Definition\"\x94\x02\n" +
+	"Definition\"\xaa\x01\n" +
+	"\x10SQLiteForeignKey\x12\x0e\n" +
+	"\x02Id\x18\x01 \x01(\x05R\x02Id\x12\x14\n" +
+	"\x05Table\x18\x02 \x01(\tR\x05Table\x12\x12\n" +
+	"\x04From\x18\x03 \x03(\tR\x04From\x12\x0e\n" +
+	"\x02To\x18\x04 \x03(\tR\x02To\x12\x1a\n" +
+	"\bOnUpdate\x18\x05 \x01(\tR\bOnUpdate\x12\x1a\n" +
+	"\bOnDelete\x18\x06 \x01(\tR\bOnDelete\x12\x14\n" +
+	"\x05Match\x18\a \x01(\tR\x05Match\"\xd4\x02\n" +
 	"\vSQLiteTable\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x12\n" +
 	"\x04Type\x18\x02 \x01(\tR\x04Type\x122\n" +
@@ -492,20 +670,28 @@ const file_sqlite_meta_proto_rawDesc = "" +
 	"\n" +
 	"Definition\x18\a \x01(\tR\n" +
 	"Definition\x12\x1a\n" +
-	"\bRootPage\x18\b \x01(\x03R\bRootPage\"t\n" +
+	"\bRootPage\x18\b \x01(\x03R\bRootPage\x12>\n" +
+	"\vForeignKeys\x18\t \x03(\v2\x1c.sqlitemeta.SQLiteForeignKeyR\vForeignKeys\"t\n" +
 	"\n" +
 	"SQLiteView\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x1e\n" +
 	"\n" +
 	"Definition\x18\x02 \x01(\tR\n" +
 	"Definition\x122\n" +
-	"\aColumns\x18\x03 \x03(\v2\x18.sqlitemeta.SQLiteColumnR\aColumns\"\xbb\x01\n" +
+	"\aColumns\x18\x03 \x03(\v2\x18.sqlitemeta.SQLiteColumnR\aColumns\"a\n" +
+	"\rSQLiteTrigger\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x1c\n" +
+	"\tTableName\x18\x02 \x01(\tR\tTableName\x12\x1e\n" +
+	"\n" +
+	"Definition\x18\x03 \x01(\tR\n" +
+	"Definition\"\xf6\x01\n" +
 	"\x0eSQLiteDatabase\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x1a\n" +
 	"\bFilePath\x18\x02 \x01(\tR\bFilePath\x12/\n" +
 	"\x06Tables\x18\x03 \x03(\v2\x17.sqlitemeta.SQLiteTableR\x06Tables\x12,\n" +
-	"\x05Views\x18\x04 \x03(\v2\x16.sqlitemeta.SQLiteViewR\x05Views\x12\x1a\n" +
-	"\bTriggers\x18\x05 \x03(\tR\bTriggersB\"Z github.com/genelet/sqlmeta/xmetab\x06proto3"
+	"\x05Views\x18\x04 \x03(\v2\x16.sqlitemeta.SQLiteViewR\x05Views\x12\x18\n" +
+	"\aVersion\x18\x06 \x01(\tR\aVersion\x125\n" +
+	"\bTriggers\x18\a \x03(\v2\x19.sqlitemeta.SQLiteTriggerR\bTriggersJ\x04\b\x05\x10\x06B\"Z github.com/genelet/sqlmeta/xmetab\x06proto3"
 
 var (
 	file_sqlite_meta_proto_rawDescOnce sync.Once
@@ -519,27 +705,31 @@ func file_sqlite_meta_proto_rawDescGZIP() []byte {
 	return file_sqlite_meta_proto_rawDescData
 }
 
-var file_sqlite_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_sqlite_meta_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_sqlite_meta_proto_goTypes = []any{
-	(*SQLiteColumn)(nil),   // 0: sqlitemeta.SQLiteColumn
-	(*SQLiteIndex)(nil),    // 1: sqlitemeta.SQLiteIndex
-	(*SQLiteTable)(nil),    // 2: sqlitemeta.SQLiteTable
-	(*SQLiteView)(nil),     // 3: sqlitemeta.SQLiteView
-	(*SQLiteDatabase)(nil), // 4: sqlitemeta.SQLiteDatabase
-	(*DataType)(nil),       // 5: sqlmeta.DataType
+	(*SQLiteColumn)(nil),     // 0: sqlitemeta.SQLiteColumn
+	(*SQLiteIndex)(nil),      // 1: sqlitemeta.SQLiteIndex
+	(*SQLiteForeignKey)(nil), // 2: sqlitemeta.SQLiteForeignKey
+	(*SQLiteTable)(nil),      // 3: sqlitemeta.SQLiteTable
+	(*SQLiteView)(nil),       // 4: sqlitemeta.SQLiteView
+	(*SQLiteTrigger)(nil),    // 5: sqlitemeta.SQLiteTrigger
+	(*SQLiteDatabase)(nil),   // 6: sqlitemeta.SQLiteDatabase
+	(*DataType)(nil),         // 7: sqlmeta.DataType
 }
 var file_sqlite_meta_proto_depIdxs = []int32{
-	5, // 0: sqlitemeta.SQLiteColumn.DataType:type_name -> sqlmeta.DataType
+	7, // 0: sqlitemeta.SQLiteColumn.DataType:type_name -> sqlmeta.DataType
 	0, // 1: sqlitemeta.SQLiteTable.Columns:type_name -> sqlitemeta.SQLiteColumn
 	1, // 2: sqlitemeta.SQLiteTable.Indexes:type_name -> sqlitemeta.SQLiteIndex
-	0, // 3: sqlitemeta.SQLiteView.Columns:type_name -> sqlitemeta.SQLiteColumn
-	2, // 4: sqlitemeta.SQLiteDatabase.Tables:type_name -> sqlitemeta.SQLiteTable
-	3, // 5: sqlitemeta.SQLiteDatabase.Views:type_name -> sqlitemeta.SQLiteView
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	2, // 3: sqlitemeta.SQLiteTable.ForeignKeys:type_name -> sqlitemeta.SQLiteForeignKey
+	0, // 4: sqlitemeta.SQLiteView.Columns:type_name -> sqlitemeta.SQLiteColumn
+	3, // 5: sqlitemeta.SQLiteDatabase.Tables:type_name -> sqlitemeta.SQLiteTable
+	4, // 6: sqlitemeta.SQLiteDatabase.Views:type_name -> sqlitemeta.SQLiteView
+	5, // 7: sqlitemeta.SQLiteDatabase.Triggers:type_name -> sqlitemeta.SQLiteTrigger
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_sqlite_meta_proto_init() }
@@ -554,7 +744,7 @@ func file_sqlite_meta_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sqlite_meta_proto_rawDesc), len(file_sqlite_meta_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
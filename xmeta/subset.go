@@ -0,0 +1,64 @@
+package xmeta
+
+// subset.go extracts a self-contained slice of a MetaDatabase.
+
+import (
+	"slices"
+)
+
+// Subset returns a copy of db holding only the named tables. Names match a
+// table's qualified name ("public.users") or its bare name ("users"). With
+// includeReferenced, the tables referenced by foreign keys of selected
+// tables are added as well, transitively, so no foreign key is left
+// dangling. Tables keep their order in db; views and sequences are dropped.
+func Subset(db *MetaDatabase, tableNames []string, includeReferenced bool) *MetaDatabase {
+	result := &MetaDatabase{}
+	if db == nil {
+		return result
+	}
+	result.Name = db.Name
+	result.Options = mergeOptions(nil, db.Options)
+
+	lookup := tableLookup(db.Tables)
+	selected := make(map[*MetaTable]bool)
+	queue := slices.Clone(tableNames)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		t := lookup(name)
+		if t == nil || selected[t] {
+			continue
+		}
+		selected[t] = true
+		if includeReferenced {
+			queue = append(queue, referencedTables(t)...)
+		}
+	}
+
+	for _, t := range db.Tables {
+		if selected[t] {
+			result.Tables = append(result.Tables, CloneMetaTable(t))
+		}
+	}
+	return result
+}
+
+// referencedTables returns the names of the tables t's foreign keys point
+// to, including a partition's parent and INHERITS parents.
+func referencedTables(t *MetaTable) []string {
+	var names []string
+	for _, elem := range t.Elements {
+		if ref := elem.GetTableConstraintElement().GetSpec().GetReferenceItem(); ref != nil {
+			names = append(names, ref.GetKeyExpr().GetTableName())
+		}
+		for _, con := range elem.GetColumnDefElement().GetConstraints() {
+			if ref := con.GetSpec().GetReferenceItem(); ref != nil {
+				names = append(names, objectNameKey(ref.TableName))
+			}
+		}
+	}
+	if parent := t.Options["PartitionOf"]; parent != "" {
+		names = append(names, parent)
+	}
+	return append(names, optionList(t.Options["InheritsFrom"])...)
+}
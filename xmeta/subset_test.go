@@ -0,0 +1,42 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestSubset(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE orgs (id INTEGER PRIMARY KEY);
+CREATE TABLE users (id INTEGER PRIMARY KEY, org_id INTEGER REFERENCES orgs (id));
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));
+CREATE TABLE audit_log (id INTEGER PRIMARY KEY);`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	names := func(db *MetaDatabase) []string {
+		var out []string
+		for _, t := range db.Tables {
+			out = append(out, objectNameKey(t.Name))
+		}
+		return out
+	}
+
+	if got := names(Subset(db, []string{"orders"}, false)); len(got) != 1 || got[0] != "orders" {
+		t.Errorf("Expected only orders, got %v", got)
+	}
+	got := names(Subset(db, []string{"orders"}, true))
+	if len(got) != 3 || got[0] != "orgs" || got[1] != "users" || got[2] != "orders" {
+		t.Errorf("Expected orders with its referenced tables in schema order, got %v", got)
+	}
+
+	sub := Subset(db, []string{"orgs", "missing"}, true)
+	if got := names(sub); len(got) != 1 {
+		t.Errorf("Expected orgs only, got %v", got)
+	}
+	sub.Tables[0].Comment = "changed"
+	if db.Tables[0].Comment != "" {
+		t.Error("Expected Subset to copy the tables")
+	}
+}
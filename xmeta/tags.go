@@ -0,0 +1,74 @@
+package xmeta
+
+// tags.go attaches free-form tags, such as a PII class or a retention
+// period, to tables and columns. Tags are kept in Options under a prefix,
+// so they survive loading, diffing and saving like any other option.
+
+import (
+	"strings"
+)
+
+// tagPrefix namespaces tag keys in Options.
+const tagPrefix = "tag:"
+
+// Taggable is a model type that carries tags.
+type Taggable interface {
+	*MetaTable | *ColumnDef
+}
+
+// taggedOptions returns the Options map of obj, creating it when create is
+// set.
+func taggedOptions[T Taggable](obj T, create bool) map[string]string {
+	switch o := any(obj).(type) {
+	case *MetaTable:
+		if o.Options == nil && create {
+			o.Options = make(map[string]string)
+		}
+		return o.Options
+	case *ColumnDef:
+		if o.Options == nil && create {
+			o.Options = make(map[string]string)
+		}
+		return o.Options
+	}
+	return nil
+}
+
+// SetTag sets the tag key of obj to value. An empty value removes the tag.
+func SetTag[T Taggable](obj T, key, value string) {
+	if value == "" {
+		delete(taggedOptions(obj, false), tagPrefix+key)
+		return
+	}
+	taggedOptions(obj, true)[tagPrefix+key] = value
+}
+
+// GetTag returns the tag key of obj, or "" if it is not set.
+func GetTag[T Taggable](obj T, key string) string {
+	return taggedOptions(obj, false)[tagPrefix+key]
+}
+
+// Tags returns the tags of obj by key, or nil if it has none.
+func Tags[T Taggable](obj T) map[string]string {
+	_, tags := splitTags(taggedOptions(obj, false))
+	return tags
+}
+
+// splitTags separates the tags in options from the other options, with the
+// tag prefix removed. Either result is nil when empty.
+func splitTags(options map[string]string) (plain, tags map[string]string) {
+	for key, value := range options {
+		if name, ok := strings.CutPrefix(key, tagPrefix); ok {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[name] = value
+			continue
+		}
+		if plain == nil {
+			plain = make(map[string]string)
+		}
+		plain[key] = value
+	}
+	return plain, tags
+}
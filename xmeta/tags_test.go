@@ -0,0 +1,118 @@
+package xmeta
+
+import (
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	col := &ColumnDef{Name: "email"}
+	SetTag(col, "pii", "email")
+	SetTag(col, "retention", "90d")
+	if GetTag(col, "pii") != "email" || len(Tags(col)) != 2 {
+		t.Fatalf("Unexpected tags %v", Tags(col))
+	}
+	SetTag(col, "retention", "")
+	if GetTag(col, "retention") != "" || len(Tags(col)) != 1 {
+		t.Errorf("Expected the retention tag removed, got %v", Tags(col))
+	}
+	if Tags(&MetaTable{}) != nil || GetTag(&MetaTable{}, "pii") != "" {
+		t.Error("Expected no tags on an empty table")
+	}
+
+	table := BQTableToMetaTable(&BQTable{
+		Name:   &ObjectName{Idents: []string{"proj", "ds", "users"}},
+		Labels: map[string]string{"team": "growth"},
+	})
+	if GetTag(table, "team") != "growth" {
+		t.Errorf("Expected BigQuery labels as tags, got %v", table.Options)
+	}
+}
+
+func TestDiffDatabase_Tags(t *testing.T) {
+	build := func(tableTag, columnTag string) *MetaDatabase {
+		col := &ColumnDef{Name: "email", DataType: &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}}
+		SetTag(col, "pii", columnTag)
+		table := &MetaTable{
+			Name:     &ObjectName{Idents: []string{"users"}},
+			Options:  map[string]string{"Engine": "InnoDB"},
+			Elements: []*TableElement{{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}}},
+		}
+		SetTag(table, "retention", tableTag)
+		return &MetaDatabase{Tables: []*MetaTable{table}}
+	}
+
+	changes := DiffDatabase(build("30d", "email"), build("90d", ""))
+	if len(changes) != 2 {
+		t.Fatalf("Expected two tag changes, got %v", changes)
+	}
+	var tableTags, columnTags AlterTags
+	for _, c := range changes {
+		tags, ok := c.(AlterTags)
+		if !ok {
+			t.Fatalf("Expected only AlterTags, got %v", c)
+		}
+		if tags.ColumnName == "" {
+			tableTags = tags
+		} else {
+			columnTags = tags
+		}
+	}
+	if tableTags.OldTags["retention"] != "30d" || tableTags.NewTags["retention"] != "90d" {
+		t.Errorf("Unexpected table tags %v", tableTags)
+	}
+	if columnTags.ColumnName != "email" || columnTags.OldTags["pii"] != "email" || len(columnTags.NewTags) != 0 {
+		t.Errorf("Unexpected column tags %v", columnTags)
+	}
+
+	stmts, err := GenerateSQL(tableTags, DialectBigQuery)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if len(stmts) != 1 || stmts[0] != "ALTER TABLE `users` SET OPTIONS (labels = [('retention', '90d')])" {
+		t.Errorf("Unexpected SQL: %v", stmts)
+	}
+	for _, c := range changes {
+		if stmts, err := GenerateSQL(c, DialectPostgres); err != nil || len(stmts) != 0 {
+			t.Errorf("Expected no Postgres statements for %v, got %v, %v", c, stmts, err)
+		}
+	}
+}
+
+func TestDiffDatabase_TagsApartFromOptions(t *testing.T) {
+	build := func(engine, tag string) *MetaDatabase {
+		table := &MetaTable{
+			Name:    &ObjectName{Idents: []string{"users"}},
+			Options: map[string]string{"Engine": engine},
+		}
+		SetTag(table, "retention", tag)
+		return &MetaDatabase{Tables: []*MetaTable{table}}
+	}
+
+	// Only a tag changes
+	changes := DiffDatabase(build("InnoDB", "30d"), build("InnoDB", "90d"))
+	if len(changes) != 1 {
+		t.Fatalf("Expected only the tag change, got %v", changes)
+	}
+	if _, ok := changes[0].(AlterTags); !ok {
+		t.Errorf("Expected AlterTags, got %v", changes[0])
+	}
+
+	// An option and a tag change
+	changes = DiffDatabase(build("InnoDB", "30d"), build("MyISAM", "90d"))
+	if len(changes) != 2 {
+		t.Fatalf("Expected the option and the tag change, got %v", changes)
+	}
+	for _, c := range changes {
+		alter, ok := c.(AlterTableOptions)
+		if !ok {
+			continue
+		}
+		if !mapsEqual(alter.OldOptions, map[string]string{"Engine": "InnoDB"}) || !mapsEqual(alter.NewOptions, map[string]string{"Engine": "MyISAM"}) {
+			t.Errorf("Expected the options without tags, got %v and %v", alter.OldOptions, alter.NewOptions)
+		}
+		stmts, err := GenerateSQL(alter, DialectMySQL)
+		if err != nil || len(stmts) != 1 || stmts[0] != "ALTER TABLE `users` ENGINE=MyISAM" {
+			t.Errorf("Unexpected SQL: %v, %v", stmts, err)
+		}
+	}
+}
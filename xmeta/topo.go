@@ -0,0 +1,214 @@
+package xmeta
+
+// topo.go orders new tables so that foreign keys point to existing tables.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TopoSortTables returns tables ordered so that every table follows the
+// tables its foreign keys reference, including a partition's parent.
+// Tables that do not depend on each other keep their order. References to
+// tables outside the list and self-references are ignored. A dependency
+// cycle is an error; see DeferForeignKeys for creating such tables.
+func TopoSortTables(tables []*MetaTable) ([]*MetaTable, error) {
+	sorted, cycle := topoSortTables(tables)
+	if len(cycle) > 0 {
+		return nil, fmt.Errorf("foreign key cycle between tables %s", strings.Join(cycle, ", "))
+	}
+	return sorted, nil
+}
+
+// topoSortTables is TopoSortTables that skips the references closing a
+// cycle. It returns the tables of the first cycle found by name.
+func topoSortTables(tables []*MetaTable) ([]*MetaTable, []string) {
+	lookup := tableLookup(tables)
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[*MetaTable]int)
+	var sorted, stack []*MetaTable
+	var cycle []string
+
+	var visit func(t *MetaTable)
+	visit = func(t *MetaTable) {
+		state[t] = visiting
+		stack = append(stack, t)
+		for _, name := range referencedTables(t) {
+			dep := lookup(name)
+			if dep == nil || dep == t {
+				continue
+			}
+			switch state[dep] {
+			case 0:
+				visit(dep)
+			case visiting:
+				if cycle == nil {
+					for _, c := range stack[slices.Index(stack, dep):] {
+						cycle = append(cycle, objectNameKey(c.Name))
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[t] = visited
+		sorted = append(sorted, t)
+	}
+	for _, t := range tables {
+		if state[t] == 0 {
+			visit(t)
+		}
+	}
+	return sorted, cycle
+}
+
+// tableLookup returns a function finding a table of tables by qualified or
+// bare name. A reference whose qualified name is unknown falls back to its
+// bare name.
+func tableLookup(tables []*MetaTable) func(name string) *MetaTable {
+	byName := make(map[string]*MetaTable)
+	for _, t := range tables {
+		byName[simpleNameKey(t.Name)] = t
+	}
+	for _, t := range tables {
+		byName[objectNameKey(t.Name)] = t // qualified names win over bare ones
+	}
+	return func(name string) *MetaTable {
+		if t, ok := byName[name]; ok {
+			return t
+		}
+		return byName[name[strings.LastIndex(name, ".")+1:]]
+	}
+}
+
+// orderAddTables reorders every run of AddTable changes sharing a priority
+// so that referenced tables are created first. Cyclic references are left
+// for DeferForeignKeys.
+func orderAddTables(changes []SchemaChange, policy PriorityPolicy) {
+	for start := 0; start < len(changes); {
+		end := start + 1
+		if _, ok := changes[start].(AddTable); ok {
+			for end < len(changes) {
+				if _, ok := changes[end].(AddTable); !ok || policy.priority(changes[end]) != policy.priority(changes[start]) {
+					break
+				}
+				end++
+			}
+		}
+		if end-start > 1 {
+			byTable := make(map[*MetaTable]AddTable, end-start)
+			tables := make([]*MetaTable, 0, end-start)
+			for _, c := range changes[start:end] {
+				byTable[c.(AddTable).Table] = c.(AddTable)
+				tables = append(tables, c.(AddTable).Table)
+			}
+			sorted, _ := topoSortTables(tables)
+			for i, t := range sorted {
+				changes[start+i] = byTable[t]
+			}
+		}
+		start = end
+	}
+}
+
+// DeferForeignKeys returns sorted changes in which the foreign keys that
+// reference a table created by a later AddTable are moved out of their
+// CREATE TABLE into AddConstraint changes that follow the last AddTable.
+// After SortChanges only the foreign keys of a dependency cycle reference
+// later tables, so this creates tables that reference each other. Unnamed
+// foreign keys are named with GenerateConstraintName. SQLite cannot add
+// constraints to existing tables.
+func DeferForeignKeys(changes []SchemaChange) []SchemaChange {
+	pending := make(map[string]int)
+	last := -1
+	for i, c := range changes {
+		if add, ok := c.(AddTable); ok {
+			pending[objectNameKey(add.Table.Name)]++
+			pending[simpleNameKey(add.Table.Name)]++
+			last = i
+		}
+	}
+	isPending := func(name string) bool {
+		return pending[name] > 0 || pending[name[strings.LastIndex(name, ".")+1:]] > 0
+	}
+
+	var result []SchemaChange
+	var deferred []SchemaChange
+	for i, c := range changes {
+		if add, ok := c.(AddTable); ok {
+			pending[objectNameKey(add.Table.Name)]--
+			pending[simpleNameKey(add.Table.Name)]--
+			if fks := forwardForeignKeys(add.Table, isPending); len(fks) > 0 {
+				table, constraints := detachForeignKeys(add.Table, fks)
+				c = AddTable{Table: table}
+				for _, tc := range constraints {
+					deferred = append(deferred, AddConstraint{TableName: table.Name, Constraint: tc})
+				}
+			}
+		}
+		result = append(result, c)
+		if i == last {
+			result = append(result, deferred...)
+		}
+	}
+	return result
+}
+
+// forwardForeignKeys returns the foreign keys of t, as table or column
+// constraints, that reference a table for which isPending is true.
+func forwardForeignKeys(t *MetaTable, isPending func(string) bool) map[proto.Message]bool {
+	fks := make(map[proto.Message]bool)
+	for _, elem := range t.Elements {
+		if tc := elem.GetTableConstraintElement(); tc != nil {
+			if ref := tc.GetSpec().GetReferenceItem(); ref != nil && isPending(ref.GetKeyExpr().GetTableName()) {
+				fks[tc] = true
+			}
+		}
+		for _, con := range elem.GetColumnDefElement().GetConstraints() {
+			if ref := con.GetSpec().GetReferenceItem(); ref != nil && isPending(objectNameKey(ref.TableName)) {
+				fks[con] = true
+			}
+		}
+	}
+	return fks
+}
+
+// detachForeignKeys returns a copy of t without the foreign keys in fks,
+// and those foreign keys as named table constraints.
+func detachForeignKeys(t *MetaTable, fks map[proto.Message]bool) (*MetaTable, []*TableConstraint) {
+	table := CloneMetaTable(t)
+	table.Elements = nil
+	var detached []*TableConstraint
+	for _, elem := range t.Elements {
+		if tc := elem.GetTableConstraintElement(); tc != nil && fks[tc] {
+			detached = append(detached, proto.Clone(tc).(*TableConstraint))
+			continue
+		}
+		col := elem.GetColumnDefElement()
+		if col == nil || !slices.ContainsFunc(col.Constraints, func(con *ColumnConstraint) bool { return fks[con] }) {
+			table.Elements = append(table.Elements, proto.Clone(elem).(*TableElement))
+			continue
+		}
+		col = proto.Clone(col).(*ColumnDef)
+		col.Constraints = nil
+		for _, con := range elem.GetColumnDefElement().Constraints {
+			if !fks[con] {
+				col.Constraints = append(col.Constraints, proto.Clone(con).(*ColumnConstraint))
+				continue
+			}
+			detached = append(detached, columnForeignKey(col, con))
+		}
+		table.Elements = append(table.Elements, &TableElement{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: col}})
+	}
+	for _, tc := range detached {
+		if tc.Name == "" {
+			tc.Name = GenerateConstraintName(table.Name, tc)
+		}
+	}
+	return table, detached
+}
@@ -0,0 +1,93 @@
+package xmeta
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopoSortTables(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id));
+CREATE TABLE users (id INTEGER PRIMARY KEY, org_id INTEGER,
+  CONSTRAINT fk_org FOREIGN KEY (org_id) REFERENCES public.orgs (id));
+CREATE TABLE audit_log (id INTEGER PRIMARY KEY);
+CREATE TABLE orgs (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES orgs (id));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+
+	sorted, err := TopoSortTables(db.Tables)
+	if err != nil {
+		t.Fatalf("TopoSortTables failed: %v", err)
+	}
+	var names []string
+	for _, table := range sorted {
+		names = append(names, objectNameKey(table.Name))
+	}
+	if got := strings.Join(names, ","); got != "orgs,users,orders,audit_log" {
+		t.Errorf("Unexpected order %s", got)
+	}
+
+	position := make(map[string]int)
+	for i, c := range DiffDatabase(&MetaDatabase{}, db) {
+		position[objectNameKey(c.(AddTable).Table.Name)] = i
+	}
+	if position["orgs"] > position["users"] || position["users"] > position["orders"] {
+		t.Errorf("Expected referenced tables to be created first, got %v", position)
+	}
+}
+
+func TestDeferForeignKeys(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE employees (id INTEGER PRIMARY KEY, dept_id INTEGER REFERENCES departments (id));
+CREATE TABLE departments (id INTEGER PRIMARY KEY, manager_id INTEGER,
+  CONSTRAINT fk_manager FOREIGN KEY (manager_id) REFERENCES employees (id));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	if _, err := TopoSortTables(db.Tables); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("Expected a cycle error, got %v", err)
+	}
+
+	changes := []SchemaChange{AddTable{Table: db.Tables[0]}, AddTable{Table: db.Tables[1]}}
+	SortChanges(changes)
+	deferred := DeferForeignKeys(changes)
+	if len(deferred) != 3 {
+		t.Fatalf("Expected two tables and one constraint, got %v", deferred)
+	}
+	add, ok := deferred[2].(AddConstraint)
+	if !ok {
+		t.Fatalf("Expected AddConstraint last, got %T", deferred[2])
+	}
+	// departments is created first, as employees depends on it
+	if add.Constraint.Name != "fk_manager" || objectNameKey(add.TableName) != "departments" {
+		t.Errorf("Unexpected deferred constraint %v", add)
+	}
+	if len(referencedTables(deferred[0].(AddTable).Table)) != 0 {
+		t.Error("Expected the foreign key to be removed from the created table")
+	}
+	if len(referencedTables(db.Tables[1])) != 1 {
+		t.Error("Expected the input table to be left unchanged")
+	}
+
+	// Inline foreign keys become named table constraints
+	changes = []SchemaChange{AddTable{Table: db.Tables[0]}, AddTable{Table: db.Tables[1]}}
+	deferred = DeferForeignKeys(changes)
+	if add := deferred[2].(AddConstraint); add.Constraint.Name != "employees_dept_id_fkey" ||
+		add.Constraint.Spec.GetReferenceItem().GetKeyExpr().GetTableName() != "departments" {
+		t.Errorf("Unexpected deferred constraint %v", add)
+	}
+
+	var sql []string
+	for _, c := range deferred {
+		stmts, err := GenerateSQL(c, DialectPostgres)
+		if err != nil {
+			t.Fatalf("GenerateSQL failed: %v", err)
+		}
+		sql = append(sql, stmts...)
+	}
+	want := `ALTER TABLE "employees" ADD CONSTRAINT "employees_dept_id_fkey" FOREIGN KEY ("dept_id") REFERENCES "departments" ("id")`
+	if sql[len(sql)-1] != want {
+		t.Errorf("Unexpected SQL %s", sql[len(sql)-1])
+	}
+}
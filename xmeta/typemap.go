@@ -0,0 +1,64 @@
+package xmeta
+
+// typemap.go lets callers override how the live loaders map a column's
+// catalog type to the unified DataType.
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TypeMapper maps a column type as the database catalog reports it to the
+// unified DataType. The loaders consult it before their built-in mapping and
+// fall back to that mapping when Map returns nil. rawType is the full type
+// the catalog reports, such as "tinyint(1)" for MySQL, "character
+// varying(40)" for Postgres or the declared type for SQLite. precision,
+// scale and length are the catalog's numeric precision and scale and
+// character length, 0 when it has none.
+type TypeMapper interface {
+	Map(rawType string, precision, scale, length int64) *DataType
+}
+
+// TypeMapperFunc is a function used as a TypeMapper.
+type TypeMapperFunc func(rawType string, precision, scale, length int64) *DataType
+
+// Map calls f.
+func (f TypeMapperFunc) Map(rawType string, precision, scale, length int64) *DataType {
+	return f(rawType, precision, scale, length)
+}
+
+// TypeMap is a TypeMapper keyed by type name, for example
+//
+//	TypeMap{
+//		"citext":  {TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}},
+//		"tinyint": {TypeClause: &DataType_SmallIntData{SmallIntData: &SmallInt{}}},
+//	}
+//
+// A raw type is matched case-insensitively, first whole and then by its
+// name before any "(": "tinyint(1)" matches a "tinyint(1)" key before a
+// "tinyint" one. Each match returns a copy of the mapped DataType.
+type TypeMap map[string]*DataType
+
+// Map returns a copy of the DataType mapped for rawType, or nil.
+func (m TypeMap) Map(rawType string, precision, scale, length int64) *DataType {
+	rawType = strings.TrimSpace(rawType)
+	base, _, _ := strings.Cut(rawType, "(")
+	for _, name := range []string{rawType, strings.TrimSpace(base)} {
+		for key, dt := range m {
+			if dt != nil && strings.EqualFold(key, name) {
+				return proto.Clone(dt).(*DataType)
+			}
+		}
+	}
+	return nil
+}
+
+// mapType returns mapper's DataType for rawType, or nil when there is no
+// mapper or it leaves the type to the built-in mapping.
+func mapType(mapper TypeMapper, rawType string, precision, scale, length int64) *DataType {
+	if mapper == nil {
+		return nil
+	}
+	return mapper.Map(rawType, precision, scale, length)
+}
@@ -0,0 +1,50 @@
+package xmeta
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTypeMap(t *testing.T) {
+	text := &DataType{TypeClause: &DataType_TextData{TextData: DataTypeSingle_Text}}
+	small := &DataType{TypeClause: &DataType_SmallIntData{SmallIntData: &SmallInt{}}}
+	boolean := &DataType{TypeClause: &DataType_BooleanData{BooleanData: DataTypeSingle_Boolean}}
+	m := TypeMap{"citext": text, "tinyint": small, "tinyint(1)": boolean}
+
+	tests := []struct {
+		rawType string
+		want    *DataType
+	}{
+		{"citext", text},
+		{"CITEXT", text},
+		{"tinyint(4)", small},
+		{"tinyint(1)", boolean},
+		{"varchar(40)", nil},
+	}
+	for _, tt := range tests {
+		got := m.Map(tt.rawType, 0, 0, 0)
+		if (got == nil) != (tt.want == nil) || !proto.Equal(got, tt.want) {
+			t.Errorf("Expected %s to map to %v, got %v", tt.rawType, tt.want, got)
+		}
+	}
+	if got := m.Map("citext", 0, 0, 0); got == text {
+		t.Error("Expected a copy of the mapped type")
+	}
+
+	var mapper TypeMapper = TypeMapperFunc(func(rawType string, precision, scale, length int64) *DataType {
+		if rawType == "numeric" && precision == 0 {
+			return text
+		}
+		return nil
+	})
+	if got := mapType(mapper, "numeric", 0, 0, 0); got != text {
+		t.Errorf("Expected unconstrained numeric mapped to text, got %v", got)
+	}
+	if got := mapType(mapper, "numeric", 10, 2, 0); got != nil {
+		t.Errorf("Expected numeric(10,2) left to the built-in mapping, got %v", got)
+	}
+	if got := mapType(nil, "numeric", 0, 0, 0); got != nil {
+		t.Errorf("Expected no mapping without a mapper, got %v", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	anypb "google.golang.org/protobuf/types/known/anypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -37,6 +38,7 @@ const (
 	DataTypeSingle_Year                  DataTypeSingle = 9
 	DataTypeSingle_JSON                  DataTypeSingle = 10
 	DataTypeSingle_XML                   DataTypeSingle = 11
+	DataTypeSingle_JSONB                 DataTypeSingle = 12 // JSONData stored in binary form (Postgres jsonb)
 )
 
 // Enum value maps for DataTypeSingle.
@@ -54,6 +56,7 @@ var (
 		9:  "Year",
 		10: "JSON",
 		11: "XML",
+		12: "JSONB",
 	}
 	DataTypeSingle_value = map[string]int32{
 		"DataTypeSingleUnknown": 0,
@@ -68,6 +71,7 @@ var (
 		"Year":                  9,
 		"JSON":                  10,
 		"XML":                   11,
+		"JSONB":                 12,
 	}
 )
 
@@ -98,6 +102,62 @@ func (DataTypeSingle) EnumDescriptor() ([]byte, []int) {
 	return file_types_proto_rawDescGZIP(), []int{0}
 }
 
+// Postgres network address types
+type NetworkKind int32
+
+const (
+	NetworkKind_NetworkKind_Unknown  NetworkKind = 0
+	NetworkKind_NetworkKind_Inet     NetworkKind = 1
+	NetworkKind_NetworkKind_Cidr     NetworkKind = 2
+	NetworkKind_NetworkKind_Macaddr  NetworkKind = 3
+	NetworkKind_NetworkKind_Macaddr8 NetworkKind = 4
+)
+
+// Enum value maps for NetworkKind.
+var (
+	NetworkKind_name = map[int32]string{
+		0: "NetworkKind_Unknown",
+		1: "NetworkKind_Inet",
+		2: "NetworkKind_Cidr",
+		3: "NetworkKind_Macaddr",
+		4: "NetworkKind_Macaddr8",
+	}
+	NetworkKind_value = map[string]int32{
+		"NetworkKind_Unknown":  0,
+		"NetworkKind_Inet":     1,
+		"NetworkKind_Cidr":     2,
+		"NetworkKind_Macaddr":  3,
+		"NetworkKind_Macaddr8": 4,
+	}
+)
+
+func (x NetworkKind) Enum() *NetworkKind {
+	p := new(NetworkKind)
+	*p = x
+	return p
+}
+
+func (x NetworkKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NetworkKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_types_proto_enumTypes[1].Descriptor()
+}
+
+func (NetworkKind) Type() protoreflect.EnumType {
+	return &file_types_proto_enumTypes[1]
+}
+
+func (x NetworkKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NetworkKind.Descriptor instead.
+func (NetworkKind) EnumDescriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{1}
+}
+
 // Foreign Key referential actions (shared across dialects)
 type ReferentialAction int32
 
@@ -141,11 +201,11 @@ func (x ReferentialAction) String() string {
 }
 
 func (ReferentialAction) Descriptor() protoreflect.EnumDescriptor {
-	return file_types_proto_enumTypes[1].Descriptor()
+	return file_types_proto_enumTypes[2].Descriptor()
 }
 
 func (ReferentialAction) Type() protoreflect.EnumType {
-	return &file_types_proto_enumTypes[1]
+	return &file_types_proto_enumTypes[2]
 }
 
 func (x ReferentialAction) Number() protoreflect.EnumNumber {
@@ -154,7 +214,7 @@ func (x ReferentialAction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ReferentialAction.Descriptor instead.
 func (ReferentialAction) EnumDescriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{1}
+	return file_types_proto_rawDescGZIP(), []int{2}
 }
 
 // Foreign Key match options (shared across dialects)
@@ -194,11 +254,11 @@ func (x MatchOption) String() string {
 }
 
 func (MatchOption) Descriptor() protoreflect.EnumDescriptor {
-	return file_types_proto_enumTypes[2].Descriptor()
+	return file_types_proto_enumTypes[3].Descriptor()
 }
 
 func (MatchOption) Type() protoreflect.EnumType {
-	return &file_types_proto_enumTypes[2]
+	return &file_types_proto_enumTypes[3]
 }
 
 func (x MatchOption) Number() protoreflect.EnumNumber {
@@ -207,7 +267,7 @@ func (x MatchOption) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MatchOption.Descriptor instead.
 func (MatchOption) EnumDescriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{2}
+	return file_types_proto_rawDescGZIP(), []int{3}
 }
 
 // Null value indicator (for SQL expression tracking)
@@ -241,11 +301,11 @@ func (x NullValue) String() string {
 }
 
 func (NullValue) Descriptor() protoreflect.EnumDescriptor {
-	return file_types_proto_enumTypes[3].Descriptor()
+	return file_types_proto_enumTypes[4].Descriptor()
 }
 
 func (NullValue) Type() protoreflect.EnumType {
-	return &file_types_proto_enumTypes[3]
+	return &file_types_proto_enumTypes[4]
 }
 
 func (x NullValue) Number() protoreflect.EnumNumber {
@@ -254,7 +314,7 @@ func (x NullValue) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NullValue.Descriptor instead.
 func (NullValue) EnumDescriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{3}
+	return file_types_proto_rawDescGZIP(), []int{4}
 }
 
 // Auto-increment indicator (MySQL AUTO_INCREMENT, etc.)
@@ -288,11 +348,11 @@ func (x AutoIncrement) String() string {
 }
 
 func (AutoIncrement) Descriptor() protoreflect.EnumDescriptor {
-	return file_types_proto_enumTypes[4].Descriptor()
+	return file_types_proto_enumTypes[5].Descriptor()
 }
 
 func (AutoIncrement) Type() protoreflect.EnumType {
-	return &file_types_proto_enumTypes[4]
+	return &file_types_proto_enumTypes[5]
 }
 
 func (x AutoIncrement) Number() protoreflect.EnumNumber {
@@ -301,7 +361,7 @@ func (x AutoIncrement) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use AutoIncrement.Descriptor instead.
 func (AutoIncrement) EnumDescriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{4}
+	return file_types_proto_rawDescGZIP(), []int{5}
 }
 
 // NOT NULL constraint indicator
@@ -335,11 +395,11 @@ func (x NotNullColumnSpec) String() string {
 }
 
 func (NotNullColumnSpec) Descriptor() protoreflect.EnumDescriptor {
-	return file_types_proto_enumTypes[5].Descriptor()
+	return file_types_proto_enumTypes[6].Descriptor()
 }
 
 func (NotNullColumnSpec) Type() protoreflect.EnumType {
-	return &file_types_proto_enumTypes[5]
+	return &file_types_proto_enumTypes[6]
 }
 
 func (x NotNullColumnSpec) Number() protoreflect.EnumNumber {
@@ -348,7 +408,7 @@ func (x NotNullColumnSpec) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use NotNullColumnSpec.Descriptor instead.
 func (NotNullColumnSpec) EnumDescriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{5}
+	return file_types_proto_rawDescGZIP(), []int{6}
 }
 
 type ObjectName struct {
@@ -862,6 +922,7 @@ func (x *VarcharType) GetSize() uint32 {
 type Timestamp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WithTimeZone  bool                   `protobuf:"varint,1,opt,name=WithTimeZone,proto3" json:"WithTimeZone,omitempty"`
+	Precision     *uint32                `protobuf:"varint,2,opt,name=Precision,proto3,oneof" json:"Precision,omitempty"` // fractional seconds digits, unset when not declared
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -903,6 +964,120 @@ func (x *Timestamp) GetWithTimeZone() bool {
 	return false
 }
 
+func (x *Timestamp) GetPrecision() uint32 {
+	if x != nil && x.Precision != nil {
+		return *x.Precision
+	}
+	return 0
+}
+
+// TIME with a time zone or a fractional seconds precision; a plain TIME is
+// TimeData.
+type TimeType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WithTimeZone  bool                   `protobuf:"varint,1,opt,name=WithTimeZone,proto3" json:"WithTimeZone,omitempty"`
+	Precision     *uint32                `protobuf:"varint,2,opt,name=Precision,proto3,oneof" json:"Precision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeType) Reset() {
+	*x = TimeType{}
+	mi := &file_types_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeType) ProtoMessage() {}
+
+func (x *TimeType) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeType.ProtoReflect.Descriptor instead.
+func (*TimeType) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TimeType) GetWithTimeZone() bool {
+	if x != nil {
+		return x.WithTimeZone
+	}
+	return false
+}
+
+func (x *TimeType) GetPrecision() uint32 {
+	if x != nil && x.Precision != nil {
+		return *x.Precision
+	}
+	return 0
+}
+
+// Postgres INTERVAL. Fields restricts it, e.g. "DAY TO SECOND".
+type IntervalType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fields        string                 `protobuf:"bytes,1,opt,name=Fields,proto3" json:"Fields,omitempty"`
+	Precision     *uint32                `protobuf:"varint,2,opt,name=Precision,proto3,oneof" json:"Precision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntervalType) Reset() {
+	*x = IntervalType{}
+	mi := &file_types_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntervalType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntervalType) ProtoMessage() {}
+
+func (x *IntervalType) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntervalType.ProtoReflect.Descriptor instead.
+func (*IntervalType) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IntervalType) GetFields() string {
+	if x != nil {
+		return x.Fields
+	}
+	return ""
+}
+
+func (x *IntervalType) GetPrecision() uint32 {
+	if x != nil && x.Precision != nil {
+		return *x.Precision
+	}
+	return 0
+}
+
 type BitType struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Size          uint32                 `protobuf:"varint,1,opt,name=Size,proto3" json:"Size,omitempty"`
@@ -913,7 +1088,7 @@ type BitType struct {
 
 func (x *BitType) Reset() {
 	*x = BitType{}
-	mi := &file_types_proto_msgTypes[12]
+	mi := &file_types_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -925,7 +1100,7 @@ func (x *BitType) String() string {
 func (*BitType) ProtoMessage() {}
 
 func (x *BitType) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[12]
+	mi := &file_types_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -938,7 +1113,7 @@ func (x *BitType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BitType.ProtoReflect.Descriptor instead.
 func (*BitType) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{12}
+	return file_types_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *BitType) GetSize() uint32 {
@@ -955,6 +1130,50 @@ func (x *BitType) GetVarying() bool {
 	return false
 }
 
+type NetworkType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          NetworkKind            `protobuf:"varint,1,opt,name=Kind,proto3,enum=sqlmeta.NetworkKind" json:"Kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkType) Reset() {
+	*x = NetworkType{}
+	mi := &file_types_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkType) ProtoMessage() {}
+
+func (x *NetworkType) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkType.ProtoReflect.Descriptor instead.
+func (*NetworkType) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NetworkType) GetKind() NetworkKind {
+	if x != nil {
+		return x.Kind
+	}
+	return NetworkKind_NetworkKind_Unknown
+}
+
 type DoubleType struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	IsDoublePrecision bool                   `protobuf:"varint,1,opt,name=is_double_precision,json=isDoublePrecision,proto3" json:"is_double_precision,omitempty"`
@@ -964,7 +1183,7 @@ type DoubleType struct {
 
 func (x *DoubleType) Reset() {
 	*x = DoubleType{}
-	mi := &file_types_proto_msgTypes[13]
+	mi := &file_types_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -976,7 +1195,7 @@ func (x *DoubleType) String() string {
 func (*DoubleType) ProtoMessage() {}
 
 func (x *DoubleType) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[13]
+	mi := &file_types_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -989,7 +1208,7 @@ func (x *DoubleType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoubleType.ProtoReflect.Descriptor instead.
 func (*DoubleType) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{13}
+	return file_types_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *DoubleType) GetIsDoublePrecision() bool {
@@ -1009,7 +1228,7 @@ type CollateType struct {
 
 func (x *CollateType) Reset() {
 	*x = CollateType{}
-	mi := &file_types_proto_msgTypes[14]
+	mi := &file_types_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1021,7 +1240,7 @@ func (x *CollateType) String() string {
 func (*CollateType) ProtoMessage() {}
 
 func (x *CollateType) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[14]
+	mi := &file_types_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1034,7 +1253,7 @@ func (x *CollateType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CollateType.ProtoReflect.Descriptor instead.
 func (*CollateType) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{14}
+	return file_types_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *CollateType) GetType() *DataType {
@@ -1060,7 +1279,7 @@ type StructData struct {
 
 func (x *StructData) Reset() {
 	*x = StructData{}
-	mi := &file_types_proto_msgTypes[15]
+	mi := &file_types_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1072,7 +1291,7 @@ func (x *StructData) String() string {
 func (*StructData) ProtoMessage() {}
 
 func (x *StructData) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[15]
+	mi := &file_types_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1085,7 +1304,7 @@ func (x *StructData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StructData.ProtoReflect.Descriptor instead.
 func (*StructData) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{15}
+	return file_types_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *StructData) GetFields() []*ColumnDef {
@@ -1104,7 +1323,7 @@ type ArrayData struct {
 
 func (x *ArrayData) Reset() {
 	*x = ArrayData{}
-	mi := &file_types_proto_msgTypes[16]
+	mi := &file_types_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1116,7 +1335,7 @@ func (x *ArrayData) String() string {
 func (*ArrayData) ProtoMessage() {}
 
 func (x *ArrayData) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[16]
+	mi := &file_types_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1129,7 +1348,7 @@ func (x *ArrayData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ArrayData.ProtoReflect.Descriptor instead.
 func (*ArrayData) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{16}
+	return file_types_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ArrayData) GetType() *DataType {
@@ -1139,17 +1358,19 @@ func (x *ArrayData) GetType() *DataType {
 	return nil
 }
 
-// MySQL ENUM type
+// MySQL ENUM type, or a Postgres enum type when TypeName is set. Values
+// are the labels in their sort order.
 type EnumType struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Values        []string               `protobuf:"bytes,1,rep,name=Values,proto3" json:"Values,omitempty"`
+	TypeName      *ObjectName            `protobuf:"bytes,2,opt,name=TypeName,proto3" json:"TypeName,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *EnumType) Reset() {
 	*x = EnumType{}
-	mi := &file_types_proto_msgTypes[17]
+	mi := &file_types_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1161,7 +1382,7 @@ func (x *EnumType) String() string {
 func (*EnumType) ProtoMessage() {}
 
 func (x *EnumType) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[17]
+	mi := &file_types_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1174,7 +1395,7 @@ func (x *EnumType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EnumType.ProtoReflect.Descriptor instead.
 func (*EnumType) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{17}
+	return file_types_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *EnumType) GetValues() []string {
@@ -1184,6 +1405,13 @@ func (x *EnumType) GetValues() []string {
 	return nil
 }
 
+func (x *EnumType) GetTypeName() *ObjectName {
+	if x != nil {
+		return x.TypeName
+	}
+	return nil
+}
+
 // MySQL SET type
 type SetType struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1194,7 +1422,7 @@ type SetType struct {
 
 func (x *SetType) Reset() {
 	*x = SetType{}
-	mi := &file_types_proto_msgTypes[18]
+	mi := &file_types_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1206,7 +1434,7 @@ func (x *SetType) String() string {
 func (*SetType) ProtoMessage() {}
 
 func (x *SetType) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[18]
+	mi := &file_types_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1219,7 +1447,7 @@ func (x *SetType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetType.ProtoReflect.Descriptor instead.
 func (*SetType) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{18}
+	return file_types_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *SetType) GetValues() []string {
@@ -1239,7 +1467,7 @@ type UniqueColumnSpec struct {
 
 func (x *UniqueColumnSpec) Reset() {
 	*x = UniqueColumnSpec{}
-	mi := &file_types_proto_msgTypes[19]
+	mi := &file_types_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1251,7 +1479,7 @@ func (x *UniqueColumnSpec) String() string {
 func (*UniqueColumnSpec) ProtoMessage() {}
 
 func (x *UniqueColumnSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[19]
+	mi := &file_types_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1264,7 +1492,7 @@ func (x *UniqueColumnSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UniqueColumnSpec.ProtoReflect.Descriptor instead.
 func (*UniqueColumnSpec) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{19}
+	return file_types_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *UniqueColumnSpec) GetIsPrimaryKey() bool {
@@ -1285,7 +1513,7 @@ type ReferenceKeyExpr struct {
 
 func (x *ReferenceKeyExpr) Reset() {
 	*x = ReferenceKeyExpr{}
-	mi := &file_types_proto_msgTypes[20]
+	mi := &file_types_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1297,7 +1525,7 @@ func (x *ReferenceKeyExpr) String() string {
 func (*ReferenceKeyExpr) ProtoMessage() {}
 
 func (x *ReferenceKeyExpr) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[20]
+	mi := &file_types_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1310,7 +1538,7 @@ func (x *ReferenceKeyExpr) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReferenceKeyExpr.ProtoReflect.Descriptor instead.
 func (*ReferenceKeyExpr) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{20}
+	return file_types_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *ReferenceKeyExpr) GetTableName() string {
@@ -1343,7 +1571,7 @@ type ReferencesColumnSpec struct {
 
 func (x *ReferencesColumnSpec) Reset() {
 	*x = ReferencesColumnSpec{}
-	mi := &file_types_proto_msgTypes[21]
+	mi := &file_types_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1355,7 +1583,7 @@ func (x *ReferencesColumnSpec) String() string {
 func (*ReferencesColumnSpec) ProtoMessage() {}
 
 func (x *ReferencesColumnSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[21]
+	mi := &file_types_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1368,7 +1596,7 @@ func (x *ReferencesColumnSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReferencesColumnSpec.ProtoReflect.Descriptor instead.
 func (*ReferencesColumnSpec) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{21}
+	return file_types_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *ReferencesColumnSpec) GetTableName() *ObjectName {
@@ -1422,19 +1650,20 @@ func (x *ReferencesColumnSpec) GetInitiallyDeferred() bool {
 
 // Table-level UNIQUE/PRIMARY KEY constraint
 type UniqueTableConstraint struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsPrimary     bool                   `protobuf:"varint,1,opt,name=IsPrimary,proto3" json:"IsPrimary,omitempty"`
-	Columns       []string               `protobuf:"bytes,2,rep,name=Columns,proto3" json:"Columns,omitempty"`
-	IndexName     string                 `protobuf:"bytes,3,opt,name=IndexName,proto3" json:"IndexName,omitempty"`
-	IsJustIndex   bool                   `protobuf:"varint,4,opt,name=IsJustIndex,proto3" json:"IsJustIndex,omitempty"`
-	Include       []string               `protobuf:"bytes,5,rep,name=Include,proto3" json:"Include,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	IsPrimary        bool                   `protobuf:"varint,1,opt,name=IsPrimary,proto3" json:"IsPrimary,omitempty"`
+	Columns          []string               `protobuf:"bytes,2,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	IndexName        string                 `protobuf:"bytes,3,opt,name=IndexName,proto3" json:"IndexName,omitempty"`
+	IsJustIndex      bool                   `protobuf:"varint,4,opt,name=IsJustIndex,proto3" json:"IsJustIndex,omitempty"`
+	Include          []string               `protobuf:"bytes,5,rep,name=Include,proto3" json:"Include,omitempty"`
+	NullsNotDistinct bool                   `protobuf:"varint,6,opt,name=NullsNotDistinct,proto3" json:"NullsNotDistinct,omitempty"` // Postgres 15 UNIQUE NULLS NOT DISTINCT
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *UniqueTableConstraint) Reset() {
 	*x = UniqueTableConstraint{}
-	mi := &file_types_proto_msgTypes[22]
+	mi := &file_types_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1446,7 +1675,7 @@ func (x *UniqueTableConstraint) String() string {
 func (*UniqueTableConstraint) ProtoMessage() {}
 
 func (x *UniqueTableConstraint) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[22]
+	mi := &file_types_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1459,7 +1688,7 @@ func (x *UniqueTableConstraint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UniqueTableConstraint.ProtoReflect.Descriptor instead.
 func (*UniqueTableConstraint) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{22}
+	return file_types_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *UniqueTableConstraint) GetIsPrimary() bool {
@@ -1497,6 +1726,13 @@ func (x *UniqueTableConstraint) GetInclude() []string {
 	return nil
 }
 
+func (x *UniqueTableConstraint) GetNullsNotDistinct() bool {
+	if x != nil {
+		return x.NullsNotDistinct
+	}
+	return false
+}
+
 type ExcludeConstraintElement struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Expr          *anypb.Any             `protobuf:"bytes,1,opt,name=Expr,proto3" json:"Expr,omitempty"`
@@ -1507,7 +1743,7 @@ type ExcludeConstraintElement struct {
 
 func (x *ExcludeConstraintElement) Reset() {
 	*x = ExcludeConstraintElement{}
-	mi := &file_types_proto_msgTypes[23]
+	mi := &file_types_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1519,7 +1755,7 @@ func (x *ExcludeConstraintElement) String() string {
 func (*ExcludeConstraintElement) ProtoMessage() {}
 
 func (x *ExcludeConstraintElement) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[23]
+	mi := &file_types_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1532,7 +1768,7 @@ func (x *ExcludeConstraintElement) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExcludeConstraintElement.ProtoReflect.Descriptor instead.
 func (*ExcludeConstraintElement) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{23}
+	return file_types_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ExcludeConstraintElement) GetExpr() *anypb.Any {
@@ -1555,13 +1791,14 @@ type ExcludeTableConstraint struct {
 	Elements      []*ExcludeConstraintElement `protobuf:"bytes,2,rep,name=Elements,proto3" json:"Elements,omitempty"`
 	Include       []string                    `protobuf:"bytes,3,rep,name=Include,proto3" json:"Include,omitempty"`
 	Where         *anypb.Any                  `protobuf:"bytes,4,opt,name=Where,proto3" json:"Where,omitempty"`
+	Definition    string                      `protobuf:"bytes,5,opt,name=Definition,proto3" json:"Definition,omitempty"` // Raw definition (pg_get_constraintdef), used when Elements is empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExcludeTableConstraint) Reset() {
 	*x = ExcludeTableConstraint{}
-	mi := &file_types_proto_msgTypes[24]
+	mi := &file_types_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1573,7 +1810,7 @@ func (x *ExcludeTableConstraint) String() string {
 func (*ExcludeTableConstraint) ProtoMessage() {}
 
 func (x *ExcludeTableConstraint) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[24]
+	mi := &file_types_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1586,7 +1823,7 @@ func (x *ExcludeTableConstraint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExcludeTableConstraint.ProtoReflect.Descriptor instead.
 func (*ExcludeTableConstraint) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{24}
+	return file_types_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *ExcludeTableConstraint) GetMethod() string {
@@ -1617,6 +1854,13 @@ func (x *ExcludeTableConstraint) GetWhere() *anypb.Any {
 	return nil
 }
 
+func (x *ExcludeTableConstraint) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
 // Table-level FOREIGN KEY constraint
 type ReferentialTableConstraint struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
@@ -1633,7 +1877,7 @@ type ReferentialTableConstraint struct {
 
 func (x *ReferentialTableConstraint) Reset() {
 	*x = ReferentialTableConstraint{}
-	mi := &file_types_proto_msgTypes[25]
+	mi := &file_types_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1645,7 +1889,7 @@ func (x *ReferentialTableConstraint) String() string {
 func (*ReferentialTableConstraint) ProtoMessage() {}
 
 func (x *ReferentialTableConstraint) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[25]
+	mi := &file_types_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1658,7 +1902,7 @@ func (x *ReferentialTableConstraint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReferentialTableConstraint.ProtoReflect.Descriptor instead.
 func (*ReferentialTableConstraint) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{25}
+	return file_types_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ReferentialTableConstraint) GetColumns() []string {
@@ -1743,6 +1987,9 @@ type DataType struct {
 	//	*DataType_YearData
 	//	*DataType_JSONData
 	//	*DataType_XMLData
+	//	*DataType_TimeTypeData
+	//	*DataType_IntervalData
+	//	*DataType_NetworkData
 	TypeClause    isDataType_TypeClause `protobuf_oneof:"TypeClause"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -1750,7 +1997,7 @@ type DataType struct {
 
 func (x *DataType) Reset() {
 	*x = DataType{}
-	mi := &file_types_proto_msgTypes[26]
+	mi := &file_types_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1762,7 +2009,7 @@ func (x *DataType) String() string {
 func (*DataType) ProtoMessage() {}
 
 func (x *DataType) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[26]
+	mi := &file_types_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1775,7 +2022,7 @@ func (x *DataType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DataType.ProtoReflect.Descriptor instead.
 func (*DataType) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{26}
+	return file_types_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *DataType) GetTypeClause() isDataType_TypeClause {
@@ -2046,6 +2293,33 @@ func (x *DataType) GetXMLData() DataTypeSingle {
 	return DataTypeSingle_DataTypeSingleUnknown
 }
 
+func (x *DataType) GetTimeTypeData() *TimeType {
+	if x != nil {
+		if x, ok := x.TypeClause.(*DataType_TimeTypeData); ok {
+			return x.TimeTypeData
+		}
+	}
+	return nil
+}
+
+func (x *DataType) GetIntervalData() *IntervalType {
+	if x != nil {
+		if x, ok := x.TypeClause.(*DataType_IntervalData); ok {
+			return x.IntervalData
+		}
+	}
+	return nil
+}
+
+func (x *DataType) GetNetworkData() *NetworkType {
+	if x != nil {
+		if x, ok := x.TypeClause.(*DataType_NetworkData); ok {
+			return x.NetworkData
+		}
+	}
+	return nil
+}
+
 type isDataType_TypeClause interface {
 	isDataType_TypeClause()
 }
@@ -2166,6 +2440,18 @@ type DataType_XMLData struct {
 	XMLData DataTypeSingle `protobuf:"varint,32,opt,name=XMLData,proto3,enum=sqlmeta.DataTypeSingle,oneof"`
 }
 
+type DataType_TimeTypeData struct {
+	TimeTypeData *TimeType `protobuf:"bytes,33,opt,name=TimeTypeData,proto3,oneof"`
+}
+
+type DataType_IntervalData struct {
+	IntervalData *IntervalType `protobuf:"bytes,34,opt,name=IntervalData,proto3,oneof"`
+}
+
+type DataType_NetworkData struct {
+	NetworkData *NetworkType `protobuf:"bytes,35,opt,name=NetworkData,proto3,oneof"`
+}
+
 func (*DataType_IntData) isDataType_TypeClause() {}
 
 func (*DataType_SmallIntData) isDataType_TypeClause() {}
@@ -2224,6 +2510,12 @@ func (*DataType_JSONData) isDataType_TypeClause() {}
 
 func (*DataType_XMLData) isDataType_TypeClause() {}
 
+func (*DataType_TimeTypeData) isDataType_TypeClause() {}
+
+func (*DataType_IntervalData) isDataType_TypeClause() {}
+
+func (*DataType_NetworkData) isDataType_TypeClause() {}
+
 type ColumnConstraintSpec struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to ColumnConstraintSpecClause:
@@ -2239,7 +2531,7 @@ type ColumnConstraintSpec struct {
 
 func (x *ColumnConstraintSpec) Reset() {
 	*x = ColumnConstraintSpec{}
-	mi := &file_types_proto_msgTypes[27]
+	mi := &file_types_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2251,7 +2543,7 @@ func (x *ColumnConstraintSpec) String() string {
 func (*ColumnConstraintSpec) ProtoMessage() {}
 
 func (x *ColumnConstraintSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[27]
+	mi := &file_types_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2264,7 +2556,7 @@ func (x *ColumnConstraintSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ColumnConstraintSpec.ProtoReflect.Descriptor instead.
 func (*ColumnConstraintSpec) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{27}
+	return file_types_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *ColumnConstraintSpec) GetColumnConstraintSpecClause() isColumnConstraintSpec_ColumnConstraintSpecClause {
@@ -2349,7 +2641,7 @@ type ColumnConstraint struct {
 
 func (x *ColumnConstraint) Reset() {
 	*x = ColumnConstraint{}
-	mi := &file_types_proto_msgTypes[28]
+	mi := &file_types_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2361,7 +2653,7 @@ func (x *ColumnConstraint) String() string {
 func (*ColumnConstraint) ProtoMessage() {}
 
 func (x *ColumnConstraint) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[28]
+	mi := &file_types_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2374,7 +2666,7 @@ func (x *ColumnConstraint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ColumnConstraint.ProtoReflect.Descriptor instead.
 func (*ColumnConstraint) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{28}
+	return file_types_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *ColumnConstraint) GetName() string {
@@ -2414,7 +2706,7 @@ type ColumnDef struct {
 
 func (x *ColumnDef) Reset() {
 	*x = ColumnDef{}
-	mi := &file_types_proto_msgTypes[29]
+	mi := &file_types_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2426,7 +2718,7 @@ func (x *ColumnDef) String() string {
 func (*ColumnDef) ProtoMessage() {}
 
 func (x *ColumnDef) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[29]
+	mi := &file_types_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2439,7 +2731,7 @@ func (x *ColumnDef) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ColumnDef.ProtoReflect.Descriptor instead.
 func (*ColumnDef) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{29}
+	return file_types_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *ColumnDef) GetName() string {
@@ -2505,13 +2797,14 @@ type MetaTable struct {
 	Elements      []*TableElement        `protobuf:"bytes,3,rep,name=Elements,proto3" json:"Elements,omitempty"`
 	Comment       string                 `protobuf:"bytes,4,opt,name=Comment,proto3" json:"Comment,omitempty"`
 	Options       map[string]string      `protobuf:"bytes,5,rep,name=Options,proto3" json:"Options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Indexes       []*MetaIndex           `protobuf:"bytes,6,rep,name=Indexes,proto3" json:"Indexes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MetaTable) Reset() {
 	*x = MetaTable{}
-	mi := &file_types_proto_msgTypes[30]
+	mi := &file_types_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2523,7 +2816,7 @@ func (x *MetaTable) String() string {
 func (*MetaTable) ProtoMessage() {}
 
 func (x *MetaTable) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[30]
+	mi := &file_types_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2536,7 +2829,7 @@ func (x *MetaTable) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetaTable.ProtoReflect.Descriptor instead.
 func (*MetaTable) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{30}
+	return file_types_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *MetaTable) GetName() *ObjectName {
@@ -2574,6 +2867,115 @@ func (x *MetaTable) GetOptions() map[string]string {
 	return nil
 }
 
+func (x *MetaTable) GetIndexes() []*MetaIndex {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+// A secondary index. Plain column indexes list their Columns; indexes on
+// expressions keep the key list text in Expression instead.
+type MetaIndex struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Columns       []string               `protobuf:"bytes,2,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	Expression    string                 `protobuf:"bytes,3,opt,name=Expression,proto3" json:"Expression,omitempty"` // e.g. "lower(email)"
+	Predicate     string                 `protobuf:"bytes,4,opt,name=Predicate,proto3" json:"Predicate,omitempty"`   // WHERE clause of a partial index
+	IsUnique      bool                   `protobuf:"varint,5,opt,name=IsUnique,proto3" json:"IsUnique,omitempty"`
+	Method        string                 `protobuf:"bytes,6,opt,name=Method,proto3" json:"Method,omitempty"` // e.g. "btree", "gin"
+	Comment       string                 `protobuf:"bytes,7,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	Options       map[string]string      `protobuf:"bytes,8,rep,name=Options,proto3" json:"Options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetaIndex) Reset() {
+	*x = MetaIndex{}
+	mi := &file_types_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetaIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaIndex) ProtoMessage() {}
+
+func (x *MetaIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaIndex.ProtoReflect.Descriptor instead.
+func (*MetaIndex) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *MetaIndex) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetaIndex) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *MetaIndex) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *MetaIndex) GetPredicate() string {
+	if x != nil {
+		return x.Predicate
+	}
+	return ""
+}
+
+func (x *MetaIndex) GetIsUnique() bool {
+	if x != nil {
+		return x.IsUnique
+	}
+	return false
+}
+
+func (x *MetaIndex) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *MetaIndex) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *MetaIndex) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
 type MetaView struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          *ObjectName            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
@@ -2586,7 +2988,7 @@ type MetaView struct {
 
 func (x *MetaView) Reset() {
 	*x = MetaView{}
-	mi := &file_types_proto_msgTypes[31]
+	mi := &file_types_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2598,7 +3000,7 @@ func (x *MetaView) String() string {
 func (*MetaView) ProtoMessage() {}
 
 func (x *MetaView) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[31]
+	mi := &file_types_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2611,7 +3013,7 @@ func (x *MetaView) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetaView.ProtoReflect.Descriptor instead.
 func (*MetaView) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{31}
+	return file_types_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *MetaView) GetName() *ObjectName {
@@ -2642,6 +3044,68 @@ func (x *MetaView) GetOptions() map[string]string {
 	return nil
 }
 
+// A trigger on a table. Definition is the complete, dialect-specific
+// CREATE TRIGGER statement.
+type MetaTrigger struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	TableName     *ObjectName            `protobuf:"bytes,2,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Definition    string                 `protobuf:"bytes,3,opt,name=Definition,proto3" json:"Definition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetaTrigger) Reset() {
+	*x = MetaTrigger{}
+	mi := &file_types_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetaTrigger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaTrigger) ProtoMessage() {}
+
+func (x *MetaTrigger) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaTrigger.ProtoReflect.Descriptor instead.
+func (*MetaTrigger) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *MetaTrigger) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetaTrigger) GetTableName() *ObjectName {
+	if x != nil {
+		return x.TableName
+	}
+	return nil
+}
+
+func (x *MetaTrigger) GetDefinition() string {
+	if x != nil {
+		return x.Definition
+	}
+	return ""
+}
+
 type MetaSequence struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          *ObjectName            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
@@ -2653,7 +3117,7 @@ type MetaSequence struct {
 
 func (x *MetaSequence) Reset() {
 	*x = MetaSequence{}
-	mi := &file_types_proto_msgTypes[32]
+	mi := &file_types_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2665,7 +3129,7 @@ func (x *MetaSequence) String() string {
 func (*MetaSequence) ProtoMessage() {}
 
 func (x *MetaSequence) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[32]
+	mi := &file_types_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2678,7 +3142,7 @@ func (x *MetaSequence) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetaSequence.ProtoReflect.Descriptor instead.
 func (*MetaSequence) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{32}
+	return file_types_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *MetaSequence) GetName() *ObjectName {
@@ -2709,13 +3173,14 @@ type MetaDatabase struct {
 	Views         []*MetaView            `protobuf:"bytes,3,rep,name=Views,proto3" json:"Views,omitempty"`
 	Sequences     []*MetaSequence        `protobuf:"bytes,4,rep,name=Sequences,proto3" json:"Sequences,omitempty"`
 	Options       map[string]string      `protobuf:"bytes,5,rep,name=Options,proto3" json:"Options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Triggers      []*MetaTrigger         `protobuf:"bytes,6,rep,name=Triggers,proto3" json:"Triggers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MetaDatabase) Reset() {
 	*x = MetaDatabase{}
-	mi := &file_types_proto_msgTypes[33]
+	mi := &file_types_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2727,7 +3192,7 @@ func (x *MetaDatabase) String() string {
 func (*MetaDatabase) ProtoMessage() {}
 
 func (x *MetaDatabase) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[33]
+	mi := &file_types_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2740,7 +3205,7 @@ func (x *MetaDatabase) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetaDatabase.ProtoReflect.Descriptor instead.
 func (*MetaDatabase) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{33}
+	return file_types_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *MetaDatabase) GetName() string {
@@ -2778,6 +3243,82 @@ func (x *MetaDatabase) GetOptions() map[string]string {
 	return nil
 }
 
+func (x *MetaDatabase) GetTriggers() []*MetaTrigger {
+	if x != nil {
+		return x.Triggers
+	}
+	return nil
+}
+
+// A MetaDatabase with the provenance of when and where it was taken.
+type MetaSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TakenAt       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=TakenAt,proto3" json:"TakenAt,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=Source,proto3" json:"Source,omitempty"` // DSN, host or file the schema came from
+	Dialect       string                 `protobuf:"bytes,3,opt,name=Dialect,proto3" json:"Dialect,omitempty"`
+	Database      *MetaDatabase          `protobuf:"bytes,4,opt,name=Database,proto3" json:"Database,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetaSnapshot) Reset() {
+	*x = MetaSnapshot{}
+	mi := &file_types_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetaSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaSnapshot) ProtoMessage() {}
+
+func (x *MetaSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaSnapshot.ProtoReflect.Descriptor instead.
+func (*MetaSnapshot) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *MetaSnapshot) GetTakenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TakenAt
+	}
+	return nil
+}
+
+func (x *MetaSnapshot) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *MetaSnapshot) GetDialect() string {
+	if x != nil {
+		return x.Dialect
+	}
+	return ""
+}
+
+func (x *MetaSnapshot) GetDatabase() *MetaDatabase {
+	if x != nil {
+		return x.Database
+	}
+	return nil
+}
+
 type TableConstraintSpec struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to TableConstraintSpecClause:
@@ -2793,7 +3334,7 @@ type TableConstraintSpec struct {
 
 func (x *TableConstraintSpec) Reset() {
 	*x = TableConstraintSpec{}
-	mi := &file_types_proto_msgTypes[34]
+	mi := &file_types_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2805,7 +3346,7 @@ func (x *TableConstraintSpec) String() string {
 func (*TableConstraintSpec) ProtoMessage() {}
 
 func (x *TableConstraintSpec) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[34]
+	mi := &file_types_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2818,7 +3359,7 @@ func (x *TableConstraintSpec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TableConstraintSpec.ProtoReflect.Descriptor instead.
 func (*TableConstraintSpec) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{34}
+	return file_types_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *TableConstraintSpec) GetTableConstraintSpecClause() isTableConstraintSpec_TableConstraintSpecClause {
@@ -2903,7 +3444,7 @@ type TableConstraint struct {
 
 func (x *TableConstraint) Reset() {
 	*x = TableConstraint{}
-	mi := &file_types_proto_msgTypes[35]
+	mi := &file_types_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2915,7 +3456,7 @@ func (x *TableConstraint) String() string {
 func (*TableConstraint) ProtoMessage() {}
 
 func (x *TableConstraint) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[35]
+	mi := &file_types_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2928,7 +3469,7 @@ func (x *TableConstraint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TableConstraint.ProtoReflect.Descriptor instead.
 func (*TableConstraint) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{35}
+	return file_types_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *TableConstraint) GetName() string {
@@ -2965,7 +3506,7 @@ type TableElement struct {
 
 func (x *TableElement) Reset() {
 	*x = TableElement{}
-	mi := &file_types_proto_msgTypes[36]
+	mi := &file_types_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2977,7 +3518,7 @@ func (x *TableElement) String() string {
 func (*TableElement) ProtoMessage() {}
 
 func (x *TableElement) ProtoReflect() protoreflect.Message {
-	mi := &file_types_proto_msgTypes[36]
+	mi := &file_types_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2990,7 +3531,7 @@ func (x *TableElement) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TableElement.ProtoReflect.Descriptor instead.
 func (*TableElement) Descriptor() ([]byte, []int) {
-	return file_types_proto_rawDescGZIP(), []int{36}
+	return file_types_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *TableElement) GetTableElementClause() isTableElement_TableElementClause {
@@ -3038,7 +3579,7 @@ var File_types_proto protoreflect.FileDescriptor
 
 const file_types_proto_rawDesc = "" +
 	"\n" +
-	"\vtypes.proto\x12\asqlmeta\x1a\x19google/protobuf/any.proto\"$\n" +
+	"\vtypes.proto\x12\asqlmeta\x1a\x19google/protobuf/any.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"$\n" +
 	"\n" +
 	"ObjectName\x12\x16\n" +
 	"\x06Idents\x18\x01 \x03(\tR\x06Idents\"(\n" +
@@ -3080,12 +3621,27 @@ const file_types_proto_rawDesc = "" +
 	"\bCharType\x12\x12\n" +
 	"\x04Size\x18\x01 \x01(\rR\x04Size\"!\n" +
 	"\vVarcharType\x12\x12\n" +
-	"\x04Size\x18\x01 \x01(\rR\x04Size\"/\n" +
+	"\x04Size\x18\x01 \x01(\rR\x04Size\"`\n" +
 	"\tTimestamp\x12\"\n" +
-	"\fWithTimeZone\x18\x01 \x01(\bR\fWithTimeZone\"7\n" +
+	"\fWithTimeZone\x18\x01 \x01(\bR\fWithTimeZone\x12!\n" +
+	"\tPrecision\x18\x02 \x01(\rH\x00R\tPrecision\x88\x01\x01B\f\n" +
+	"\n" +
+	"_Precision\"_\n" +
+	"\bTimeType\x12\"\n" +
+	"\fWithTimeZone\x18\x01 \x01(\bR\fWithTimeZone\x12!\n" +
+	"\tPrecision\x18\x02 \x01(\rH\x00R\tPrecision\x88\x01\x01B\f\n" +
+	"\n" +
+	"_Precision\"W\n" +
+	"\fIntervalType\x12\x16\n" +
+	"\x06Fields\x18\x01 \x01(\tR\x06Fields\x12!\n" +
+	"\tPrecision\x18\x02 \x01(\rH\x00R\tPrecision\x88\x01\x01B\f\n" +
+	"\n" +
+	"_Precision\"7\n" +
 	"\aBitType\x12\x12\n" +
 	"\x04Size\x18\x01 \x01(\rR\x04Size\x12\x18\n" +
-	"\aVarying\x18\x02 \x01(\bR\aVarying\"<\n" +
+	"\aVarying\x18\x02 \x01(\bR\aVarying\"7\n" +
+	"\vNetworkType\x12(\n" +
+	"\x04Kind\x18\x01 \x01(\x0e2\x14.sqlmeta.NetworkKindR\x04Kind\"<\n" +
 	"\n" +
 	"DoubleType\x12.\n" +
 	"\x13is_double_precision\x18\x01 \x01(\bR\x11isDoublePrecision\"Z\n" +
@@ -3096,9 +3652,10 @@ const file_types_proto_rawDesc = "" +
 	"StructData\x12*\n" +
 	"\x06Fields\x18\x01 \x03(\v2\x12.sqlmeta.ColumnDefR\x06Fields\"2\n" +
 	"\tArrayData\x12%\n" +
-	"\x04Type\x18\x01 \x01(\v2\x11.sqlmeta.DataTypeR\x04Type\"\"\n" +
+	"\x04Type\x18\x01 \x01(\v2\x11.sqlmeta.DataTypeR\x04Type\"S\n" +
 	"\bEnumType\x12\x16\n" +
-	"\x06Values\x18\x01 \x03(\tR\x06Values\"!\n" +
+	"\x06Values\x18\x01 \x03(\tR\x06Values\x12/\n" +
+	"\bTypeName\x18\x02 \x01(\v2\x13.sqlmeta.ObjectNameR\bTypeName\"!\n" +
 	"\aSetType\x12\x16\n" +
 	"\x06Values\x18\x01 \x03(\tR\x06Values\"6\n" +
 	"\x10UniqueColumnSpec\x12\"\n" +
@@ -3115,21 +3672,25 @@ const file_types_proto_rawDesc = "" +
 	"\n" +
 	"Deferrable\x18\x06 \x01(\bR\n" +
 	"Deferrable\x12,\n" +
-	"\x11InitiallyDeferred\x18\a \x01(\bR\x11InitiallyDeferred\"\xa9\x01\n" +
+	"\x11InitiallyDeferred\x18\a \x01(\bR\x11InitiallyDeferred\"\xd5\x01\n" +
 	"\x15UniqueTableConstraint\x12\x1c\n" +
 	"\tIsPrimary\x18\x01 \x01(\bR\tIsPrimary\x12\x18\n" +
 	"\aColumns\x18\x02 \x03(\tR\aColumns\x12\x1c\n" +
 	"\tIndexName\x18\x03 \x01(\tR\tIndexName\x12 \n" +
 	"\vIsJustIndex\x18\x04 \x01(\bR\vIsJustIndex\x12\x18\n" +
-	"\aInclude\x18\x05 \x03(\tR\aInclude\"`\n" +
+	"\aInclude\x18\x05 \x03(\tR\aInclude\x12*\n" +
+	"\x10NullsNotDistinct\x18\x06 \x01(\bR\x10NullsNotDistinct\"`\n" +
 	"\x18ExcludeConstraintElement\x12(\n" +
 	"\x04Expr\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x04Expr\x12\x1a\n" +
-	"\bOperator\x18\x02 \x01(\tR\bOperator\"\xb5\x01\n" +
+	"\bOperator\x18\x02 \x01(\tR\bOperator\"\xd5\x01\n" +
 	"\x16ExcludeTableConstraint\x12\x16\n" +
 	"\x06Method\x18\x01 \x01(\tR\x06Method\x12=\n" +
 	"\bElements\x18\x02 \x03(\v2!.sqlmeta.ExcludeConstraintElementR\bElements\x12\x18\n" +
 	"\aInclude\x18\x03 \x03(\tR\aInclude\x12*\n" +
-	"\x05Where\x18\x04 \x01(\v2\x14.google.protobuf.AnyR\x05Where\"\xd5\x02\n" +
+	"\x05Where\x18\x04 \x01(\v2\x14.google.protobuf.AnyR\x05Where\x12\x1e\n" +
+	"\n" +
+	"Definition\x18\x05 \x01(\tR\n" +
+	"Definition\"\xd5\x02\n" +
 	"\x1aReferentialTableConstraint\x12\x18\n" +
 	"\aColumns\x18\x01 \x03(\tR\aColumns\x123\n" +
 	"\aKeyExpr\x18\x02 \x01(\v2\x19.sqlmeta.ReferenceKeyExprR\aKeyExpr\x126\n" +
@@ -3139,7 +3700,7 @@ const file_types_proto_rawDesc = "" +
 	"\n" +
 	"Deferrable\x18\x06 \x01(\bR\n" +
 	"Deferrable\x12,\n" +
-	"\x11InitiallyDeferred\x18\a \x01(\bR\x11InitiallyDeferred\"\xae\f\n" +
+	"\x11InitiallyDeferred\x18\a \x01(\bR\x11InitiallyDeferred\"\xde\r\n" +
 	"\bDataType\x12(\n" +
 	"\aIntData\x18\x01 \x01(\v2\f.sqlmeta.IntH\x00R\aIntData\x127\n" +
 	"\fSmallIntData\x18\x02 \x01(\v2\x11.sqlmeta.SmallIntH\x00R\fSmallIntData\x121\n" +
@@ -3178,7 +3739,10 @@ const file_types_proto_rawDesc = "" +
 	"\rMediumIntData\x18\x1d \x01(\v2\x12.sqlmeta.MediumIntH\x00R\rMediumIntData\x125\n" +
 	"\bYearData\x18\x1e \x01(\x0e2\x17.sqlmeta.DataTypeSingleH\x00R\bYearData\x125\n" +
 	"\bJSONData\x18\x1f \x01(\x0e2\x17.sqlmeta.DataTypeSingleH\x00R\bJSONData\x123\n" +
-	"\aXMLData\x18  \x01(\x0e2\x17.sqlmeta.DataTypeSingleH\x00R\aXMLDataB\f\n" +
+	"\aXMLData\x18  \x01(\x0e2\x17.sqlmeta.DataTypeSingleH\x00R\aXMLData\x127\n" +
+	"\fTimeTypeData\x18! \x01(\v2\x11.sqlmeta.TimeTypeH\x00R\fTimeTypeData\x12;\n" +
+	"\fIntervalData\x18\" \x01(\v2\x15.sqlmeta.IntervalTypeH\x00R\fIntervalData\x128\n" +
+	"\vNetworkData\x18# \x01(\v2\x14.sqlmeta.NetworkTypeH\x00R\vNetworkDataB\f\n" +
 	"\n" +
 	"TypeClause\"\xae\x02\n" +
 	"\x14ColumnConstraintSpec\x12;\n" +
@@ -3204,13 +3768,28 @@ const file_types_proto_rawDesc = "" +
 	"\vWithOptions\x18\b \x01(\bR\vWithOptions\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8c\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xba\x02\n" +
 	"\tMetaTable\x12'\n" +
 	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x12\n" +
 	"\x04Type\x18\x02 \x01(\tR\x04Type\x121\n" +
 	"\bElements\x18\x03 \x03(\v2\x15.sqlmeta.TableElementR\bElements\x12\x18\n" +
 	"\aComment\x18\x04 \x01(\tR\aComment\x129\n" +
-	"\aOptions\x18\x05 \x03(\v2\x1f.sqlmeta.MetaTable.OptionsEntryR\aOptions\x1a:\n" +
+	"\aOptions\x18\x05 \x03(\v2\x1f.sqlmeta.MetaTable.OptionsEntryR\aOptions\x12,\n" +
+	"\aIndexes\x18\x06 \x03(\v2\x12.sqlmeta.MetaIndexR\aIndexes\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbc\x02\n" +
+	"\tMetaIndex\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x18\n" +
+	"\aColumns\x18\x02 \x03(\tR\aColumns\x12\x1e\n" +
+	"\n" +
+	"Expression\x18\x03 \x01(\tR\n" +
+	"Expression\x12\x1c\n" +
+	"\tPredicate\x18\x04 \x01(\tR\tPredicate\x12\x1a\n" +
+	"\bIsUnique\x18\x05 \x01(\bR\bIsUnique\x12\x16\n" +
+	"\x06Method\x18\x06 \x01(\tR\x06Method\x12\x18\n" +
+	"\aComment\x18\a \x01(\tR\aComment\x129\n" +
+	"\aOptions\x18\b \x03(\v2\x1f.sqlmeta.MetaIndex.OptionsEntryR\aOptions\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe3\x01\n" +
@@ -3223,23 +3802,35 @@ const file_types_proto_rawDesc = "" +
 	"\aOptions\x18\x04 \x03(\v2\x1e.sqlmeta.MetaView.OptionsEntryR\aOptions\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcb\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"t\n" +
+	"\vMetaTrigger\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x121\n" +
+	"\tTableName\x18\x02 \x01(\v2\x13.sqlmeta.ObjectNameR\tTableName\x12\x1e\n" +
+	"\n" +
+	"Definition\x18\x03 \x01(\tR\n" +
+	"Definition\"\xcb\x01\n" +
 	"\fMetaSequence\x12'\n" +
 	"\x04Name\x18\x01 \x01(\v2\x13.sqlmeta.ObjectNameR\x04Name\x12\x18\n" +
 	"\aComment\x18\x02 \x01(\tR\aComment\x12<\n" +
 	"\aOptions\x18\x03 \x03(\v2\".sqlmeta.MetaSequence.OptionsEntryR\aOptions\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa6\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd8\x02\n" +
 	"\fMetaDatabase\x12\x12\n" +
 	"\x04Name\x18\x01 \x01(\tR\x04Name\x12*\n" +
 	"\x06Tables\x18\x02 \x03(\v2\x12.sqlmeta.MetaTableR\x06Tables\x12'\n" +
 	"\x05Views\x18\x03 \x03(\v2\x11.sqlmeta.MetaViewR\x05Views\x123\n" +
 	"\tSequences\x18\x04 \x03(\v2\x15.sqlmeta.MetaSequenceR\tSequences\x12<\n" +
-	"\aOptions\x18\x05 \x03(\v2\".sqlmeta.MetaDatabase.OptionsEntryR\aOptions\x1a:\n" +
+	"\aOptions\x18\x05 \x03(\v2\".sqlmeta.MetaDatabase.OptionsEntryR\aOptions\x120\n" +
+	"\bTriggers\x18\x06 \x03(\v2\x14.sqlmeta.MetaTriggerR\bTriggers\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbc\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa9\x01\n" +
+	"\fMetaSnapshot\x124\n" +
+	"\aTakenAt\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\aTakenAt\x12\x16\n" +
+	"\x06Source\x18\x02 \x01(\tR\x06Source\x12\x18\n" +
+	"\aDialect\x18\x03 \x01(\tR\aDialect\x121\n" +
+	"\bDatabase\x18\x04 \x01(\v2\x15.sqlmeta.MetaDatabaseR\bDatabase\"\xbc\x02\n" +
 	"\x13TableConstraintSpec\x12K\n" +
 	"\rReferenceItem\x18\x01 \x01(\v2#.sqlmeta.ReferentialTableConstraintH\x00R\rReferenceItem\x124\n" +
 	"\tCheckItem\x18\x02 \x01(\v2\x14.google.protobuf.AnyH\x00R\tCheckItem\x12@\n" +
@@ -3255,7 +3846,7 @@ const file_types_proto_rawDesc = "" +
 	"\fTableElement\x12@\n" +
 	"\x10ColumnDefElement\x18\x01 \x01(\v2\x12.sqlmeta.ColumnDefH\x00R\x10ColumnDefElement\x12R\n" +
 	"\x16TableConstraintElement\x18\x02 \x01(\v2\x18.sqlmeta.TableConstraintH\x00R\x16TableConstraintElementB\x14\n" +
-	"\x12TableElementClause*\xa2\x01\n" +
+	"\x12TableElementClause*\xad\x01\n" +
 	"\x0eDataTypeSingle\x12\x19\n" +
 	"\x15DataTypeSingleUnknown\x10\x00\x12\n" +
 	"\n" +
@@ -3270,7 +3861,14 @@ const file_types_proto_rawDesc = "" +
 	"\x04Year\x10\t\x12\b\n" +
 	"\x04JSON\x10\n" +
 	"\x12\a\n" +
-	"\x03XML\x10\v*\xd2\x01\n" +
+	"\x03XML\x10\v\x12\t\n" +
+	"\x05JSONB\x10\f*\x85\x01\n" +
+	"\vNetworkKind\x12\x17\n" +
+	"\x13NetworkKind_Unknown\x10\x00\x12\x14\n" +
+	"\x10NetworkKind_Inet\x10\x01\x12\x14\n" +
+	"\x10NetworkKind_Cidr\x10\x02\x12\x17\n" +
+	"\x13NetworkKind_Macaddr\x10\x03\x12\x18\n" +
+	"\x14NetworkKind_Macaddr8\x10\x04*\xd2\x01\n" +
 	"\x11ReferentialAction\x12\x1d\n" +
 	"\x19ReferentialAction_Unknown\x10\x00\x12\x1e\n" +
 	"\x1aReferentialAction_NoAction\x10\x01\x12\x1e\n" +
@@ -3305,136 +3903,156 @@ func file_types_proto_rawDescGZIP() []byte {
 	return file_types_proto_rawDescData
 }
 
-var file_types_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_types_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
+var file_types_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_types_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_types_proto_goTypes = []any{
 	(DataTypeSingle)(0),                // 0: sqlmeta.DataTypeSingle
-	(ReferentialAction)(0),             // 1: sqlmeta.ReferentialAction
-	(MatchOption)(0),                   // 2: sqlmeta.MatchOption
-	(NullValue)(0),                     // 3: sqlmeta.NullValue
-	(AutoIncrement)(0),                 // 4: sqlmeta.AutoIncrement
-	(NotNullColumnSpec)(0),             // 5: sqlmeta.NotNullColumnSpec
-	(*ObjectName)(nil),                 // 6: sqlmeta.ObjectName
-	(*BigInt)(nil),                     // 7: sqlmeta.BigInt
-	(*SmallInt)(nil),                   // 8: sqlmeta.SmallInt
-	(*Int)(nil),                        // 9: sqlmeta.Int
-	(*TinyInt)(nil),                    // 10: sqlmeta.TinyInt
-	(*MediumInt)(nil),                  // 11: sqlmeta.MediumInt
-	(*Real)(nil),                       // 12: sqlmeta.Real
-	(*Float)(nil),                      // 13: sqlmeta.Float
-	(*Decimal)(nil),                    // 14: sqlmeta.Decimal
-	(*CharType)(nil),                   // 15: sqlmeta.CharType
-	(*VarcharType)(nil),                // 16: sqlmeta.VarcharType
-	(*Timestamp)(nil),                  // 17: sqlmeta.Timestamp
-	(*BitType)(nil),                    // 18: sqlmeta.BitType
-	(*DoubleType)(nil),                 // 19: sqlmeta.DoubleType
-	(*CollateType)(nil),                // 20: sqlmeta.CollateType
-	(*StructData)(nil),                 // 21: sqlmeta.StructData
-	(*ArrayData)(nil),                  // 22: sqlmeta.ArrayData
-	(*EnumType)(nil),                   // 23: sqlmeta.EnumType
-	(*SetType)(nil),                    // 24: sqlmeta.SetType
-	(*UniqueColumnSpec)(nil),           // 25: sqlmeta.UniqueColumnSpec
-	(*ReferenceKeyExpr)(nil),           // 26: sqlmeta.ReferenceKeyExpr
-	(*ReferencesColumnSpec)(nil),       // 27: sqlmeta.ReferencesColumnSpec
-	(*UniqueTableConstraint)(nil),      // 28: sqlmeta.UniqueTableConstraint
-	(*ExcludeConstraintElement)(nil),   // 29: sqlmeta.ExcludeConstraintElement
-	(*ExcludeTableConstraint)(nil),     // 30: sqlmeta.ExcludeTableConstraint
-	(*ReferentialTableConstraint)(nil), // 31: sqlmeta.ReferentialTableConstraint
-	(*DataType)(nil),                   // 32: sqlmeta.DataType
-	(*ColumnConstraintSpec)(nil),       // 33: sqlmeta.ColumnConstraintSpec
-	(*ColumnConstraint)(nil),           // 34: sqlmeta.ColumnConstraint
-	(*ColumnDef)(nil),                  // 35: sqlmeta.ColumnDef
-	(*MetaTable)(nil),                  // 36: sqlmeta.MetaTable
-	(*MetaView)(nil),                   // 37: sqlmeta.MetaView
-	(*MetaSequence)(nil),               // 38: sqlmeta.MetaSequence
-	(*MetaDatabase)(nil),               // 39: sqlmeta.MetaDatabase
-	(*TableConstraintSpec)(nil),        // 40: sqlmeta.TableConstraintSpec
-	(*TableConstraint)(nil),            // 41: sqlmeta.TableConstraint
-	(*TableElement)(nil),               // 42: sqlmeta.TableElement
-	nil,                                // 43: sqlmeta.ColumnDef.OptionsEntry
-	nil,                                // 44: sqlmeta.MetaTable.OptionsEntry
-	nil,                                // 45: sqlmeta.MetaView.OptionsEntry
-	nil,                                // 46: sqlmeta.MetaSequence.OptionsEntry
-	nil,                                // 47: sqlmeta.MetaDatabase.OptionsEntry
-	(*anypb.Any)(nil),                  // 48: google.protobuf.Any
+	(NetworkKind)(0),                   // 1: sqlmeta.NetworkKind
+	(ReferentialAction)(0),             // 2: sqlmeta.ReferentialAction
+	(MatchOption)(0),                   // 3: sqlmeta.MatchOption
+	(NullValue)(0),                     // 4: sqlmeta.NullValue
+	(AutoIncrement)(0),                 // 5: sqlmeta.AutoIncrement
+	(NotNullColumnSpec)(0),             // 6: sqlmeta.NotNullColumnSpec
+	(*ObjectName)(nil),                 // 7: sqlmeta.ObjectName
+	(*BigInt)(nil),                     // 8: sqlmeta.BigInt
+	(*SmallInt)(nil),                   // 9: sqlmeta.SmallInt
+	(*Int)(nil),                        // 10: sqlmeta.Int
+	(*TinyInt)(nil),                    // 11: sqlmeta.TinyInt
+	(*MediumInt)(nil),                  // 12: sqlmeta.MediumInt
+	(*Real)(nil),                       // 13: sqlmeta.Real
+	(*Float)(nil),                      // 14: sqlmeta.Float
+	(*Decimal)(nil),                    // 15: sqlmeta.Decimal
+	(*CharType)(nil),                   // 16: sqlmeta.CharType
+	(*VarcharType)(nil),                // 17: sqlmeta.VarcharType
+	(*Timestamp)(nil),                  // 18: sqlmeta.Timestamp
+	(*TimeType)(nil),                   // 19: sqlmeta.TimeType
+	(*IntervalType)(nil),               // 20: sqlmeta.IntervalType
+	(*BitType)(nil),                    // 21: sqlmeta.BitType
+	(*NetworkType)(nil),                // 22: sqlmeta.NetworkType
+	(*DoubleType)(nil),                 // 23: sqlmeta.DoubleType
+	(*CollateType)(nil),                // 24: sqlmeta.CollateType
+	(*StructData)(nil),                 // 25: sqlmeta.StructData
+	(*ArrayData)(nil),                  // 26: sqlmeta.ArrayData
+	(*EnumType)(nil),                   // 27: sqlmeta.EnumType
+	(*SetType)(nil),                    // 28: sqlmeta.SetType
+	(*UniqueColumnSpec)(nil),           // 29: sqlmeta.UniqueColumnSpec
+	(*ReferenceKeyExpr)(nil),           // 30: sqlmeta.ReferenceKeyExpr
+	(*ReferencesColumnSpec)(nil),       // 31: sqlmeta.ReferencesColumnSpec
+	(*UniqueTableConstraint)(nil),      // 32: sqlmeta.UniqueTableConstraint
+	(*ExcludeConstraintElement)(nil),   // 33: sqlmeta.ExcludeConstraintElement
+	(*ExcludeTableConstraint)(nil),     // 34: sqlmeta.ExcludeTableConstraint
+	(*ReferentialTableConstraint)(nil), // 35: sqlmeta.ReferentialTableConstraint
+	(*DataType)(nil),                   // 36: sqlmeta.DataType
+	(*ColumnConstraintSpec)(nil),       // 37: sqlmeta.ColumnConstraintSpec
+	(*ColumnConstraint)(nil),           // 38: sqlmeta.ColumnConstraint
+	(*ColumnDef)(nil),                  // 39: sqlmeta.ColumnDef
+	(*MetaTable)(nil),                  // 40: sqlmeta.MetaTable
+	(*MetaIndex)(nil),                  // 41: sqlmeta.MetaIndex
+	(*MetaView)(nil),                   // 42: sqlmeta.MetaView
+	(*MetaTrigger)(nil),                // 43: sqlmeta.MetaTrigger
+	(*MetaSequence)(nil),               // 44: sqlmeta.MetaSequence
+	(*MetaDatabase)(nil),               // 45: sqlmeta.MetaDatabase
+	(*MetaSnapshot)(nil),               // 46: sqlmeta.MetaSnapshot
+	(*TableConstraintSpec)(nil),        // 47: sqlmeta.TableConstraintSpec
+	(*TableConstraint)(nil),            // 48: sqlmeta.TableConstraint
+	(*TableElement)(nil),               // 49: sqlmeta.TableElement
+	nil,                                // 50: sqlmeta.ColumnDef.OptionsEntry
+	nil,                                // 51: sqlmeta.MetaTable.OptionsEntry
+	nil,                                // 52: sqlmeta.MetaIndex.OptionsEntry
+	nil,                                // 53: sqlmeta.MetaView.OptionsEntry
+	nil,                                // 54: sqlmeta.MetaSequence.OptionsEntry
+	nil,                                // 55: sqlmeta.MetaDatabase.OptionsEntry
+	(*anypb.Any)(nil),                  // 56: google.protobuf.Any
+	(*timestamppb.Timestamp)(nil),      // 57: google.protobuf.Timestamp
 }
 var file_types_proto_depIdxs = []int32{
-	32, // 0: sqlmeta.CollateType.Type:type_name -> sqlmeta.DataType
-	35, // 1: sqlmeta.StructData.Fields:type_name -> sqlmeta.ColumnDef
-	32, // 2: sqlmeta.ArrayData.Type:type_name -> sqlmeta.DataType
-	6,  // 3: sqlmeta.ReferencesColumnSpec.TableName:type_name -> sqlmeta.ObjectName
-	1,  // 4: sqlmeta.ReferencesColumnSpec.OnDelete:type_name -> sqlmeta.ReferentialAction
-	1,  // 5: sqlmeta.ReferencesColumnSpec.OnUpdate:type_name -> sqlmeta.ReferentialAction
-	2,  // 6: sqlmeta.ReferencesColumnSpec.Match:type_name -> sqlmeta.MatchOption
-	48, // 7: sqlmeta.ExcludeConstraintElement.Expr:type_name -> google.protobuf.Any
-	29, // 8: sqlmeta.ExcludeTableConstraint.Elements:type_name -> sqlmeta.ExcludeConstraintElement
-	48, // 9: sqlmeta.ExcludeTableConstraint.Where:type_name -> google.protobuf.Any
-	26, // 10: sqlmeta.ReferentialTableConstraint.KeyExpr:type_name -> sqlmeta.ReferenceKeyExpr
-	1,  // 11: sqlmeta.ReferentialTableConstraint.OnDelete:type_name -> sqlmeta.ReferentialAction
-	1,  // 12: sqlmeta.ReferentialTableConstraint.OnUpdate:type_name -> sqlmeta.ReferentialAction
-	2,  // 13: sqlmeta.ReferentialTableConstraint.Match:type_name -> sqlmeta.MatchOption
-	9,  // 14: sqlmeta.DataType.IntData:type_name -> sqlmeta.Int
-	8,  // 15: sqlmeta.DataType.SmallIntData:type_name -> sqlmeta.SmallInt
-	7,  // 16: sqlmeta.DataType.BigIntData:type_name -> sqlmeta.BigInt
-	14, // 17: sqlmeta.DataType.DecimalData:type_name -> sqlmeta.Decimal
-	15, // 18: sqlmeta.DataType.CharData:type_name -> sqlmeta.CharType
-	16, // 19: sqlmeta.DataType.VarcharData:type_name -> sqlmeta.VarcharType
-	6,  // 20: sqlmeta.DataType.CustomData:type_name -> sqlmeta.ObjectName
-	22, // 21: sqlmeta.DataType.ArrayData:type_name -> sqlmeta.ArrayData
-	21, // 22: sqlmeta.DataType.StructData:type_name -> sqlmeta.StructData
-	0,  // 23: sqlmeta.DataType.UUIDData:type_name -> sqlmeta.DataTypeSingle
-	17, // 24: sqlmeta.DataType.TimestampData:type_name -> sqlmeta.Timestamp
-	0,  // 25: sqlmeta.DataType.BooleanData:type_name -> sqlmeta.DataTypeSingle
-	0,  // 26: sqlmeta.DataType.DateData:type_name -> sqlmeta.DataTypeSingle
-	0,  // 27: sqlmeta.DataType.TimeData:type_name -> sqlmeta.DataTypeSingle
-	19, // 28: sqlmeta.DataType.DoubleData:type_name -> sqlmeta.DoubleType
-	13, // 29: sqlmeta.DataType.FloatData:type_name -> sqlmeta.Float
-	12, // 30: sqlmeta.DataType.RealData:type_name -> sqlmeta.Real
-	0,  // 31: sqlmeta.DataType.TextData:type_name -> sqlmeta.DataTypeSingle
-	18, // 32: sqlmeta.DataType.BitData:type_name -> sqlmeta.BitType
-	0,  // 33: sqlmeta.DataType.RegclassData:type_name -> sqlmeta.DataTypeSingle
-	0,  // 34: sqlmeta.DataType.ByteaData:type_name -> sqlmeta.DataTypeSingle
-	20, // 35: sqlmeta.DataType.CollateData:type_name -> sqlmeta.CollateType
-	23, // 36: sqlmeta.DataType.EnumData:type_name -> sqlmeta.EnumType
-	24, // 37: sqlmeta.DataType.SetData:type_name -> sqlmeta.SetType
-	10, // 38: sqlmeta.DataType.TinyIntData:type_name -> sqlmeta.TinyInt
-	11, // 39: sqlmeta.DataType.MediumIntData:type_name -> sqlmeta.MediumInt
-	0,  // 40: sqlmeta.DataType.YearData:type_name -> sqlmeta.DataTypeSingle
-	0,  // 41: sqlmeta.DataType.JSONData:type_name -> sqlmeta.DataTypeSingle
-	0,  // 42: sqlmeta.DataType.XMLData:type_name -> sqlmeta.DataTypeSingle
-	25, // 43: sqlmeta.ColumnConstraintSpec.UniqueItem:type_name -> sqlmeta.UniqueColumnSpec
-	48, // 44: sqlmeta.ColumnConstraintSpec.CheckItem:type_name -> google.protobuf.Any
-	27, // 45: sqlmeta.ColumnConstraintSpec.ReferenceItem:type_name -> sqlmeta.ReferencesColumnSpec
-	5,  // 46: sqlmeta.ColumnConstraintSpec.NotNullItem:type_name -> sqlmeta.NotNullColumnSpec
-	33, // 47: sqlmeta.ColumnConstraint.Spec:type_name -> sqlmeta.ColumnConstraintSpec
-	32, // 48: sqlmeta.ColumnDef.DataType:type_name -> sqlmeta.DataType
-	48, // 49: sqlmeta.ColumnDef.Default:type_name -> google.protobuf.Any
-	4,  // 50: sqlmeta.ColumnDef.MyDecos:type_name -> sqlmeta.AutoIncrement
-	34, // 51: sqlmeta.ColumnDef.Constraints:type_name -> sqlmeta.ColumnConstraint
-	43, // 52: sqlmeta.ColumnDef.Options:type_name -> sqlmeta.ColumnDef.OptionsEntry
-	6,  // 53: sqlmeta.MetaTable.Name:type_name -> sqlmeta.ObjectName
-	42, // 54: sqlmeta.MetaTable.Elements:type_name -> sqlmeta.TableElement
-	44, // 55: sqlmeta.MetaTable.Options:type_name -> sqlmeta.MetaTable.OptionsEntry
-	6,  // 56: sqlmeta.MetaView.Name:type_name -> sqlmeta.ObjectName
-	45, // 57: sqlmeta.MetaView.Options:type_name -> sqlmeta.MetaView.OptionsEntry
-	6,  // 58: sqlmeta.MetaSequence.Name:type_name -> sqlmeta.ObjectName
-	46, // 59: sqlmeta.MetaSequence.Options:type_name -> sqlmeta.MetaSequence.OptionsEntry
-	36, // 60: sqlmeta.MetaDatabase.Tables:type_name -> sqlmeta.MetaTable
-	37, // 61: sqlmeta.MetaDatabase.Views:type_name -> sqlmeta.MetaView
-	38, // 62: sqlmeta.MetaDatabase.Sequences:type_name -> sqlmeta.MetaSequence
-	47, // 63: sqlmeta.MetaDatabase.Options:type_name -> sqlmeta.MetaDatabase.OptionsEntry
-	31, // 64: sqlmeta.TableConstraintSpec.ReferenceItem:type_name -> sqlmeta.ReferentialTableConstraint
-	48, // 65: sqlmeta.TableConstraintSpec.CheckItem:type_name -> google.protobuf.Any
-	28, // 66: sqlmeta.TableConstraintSpec.UniqueItem:type_name -> sqlmeta.UniqueTableConstraint
-	30, // 67: sqlmeta.TableConstraintSpec.ExcludeItem:type_name -> sqlmeta.ExcludeTableConstraint
-	40, // 68: sqlmeta.TableConstraint.Spec:type_name -> sqlmeta.TableConstraintSpec
-	35, // 69: sqlmeta.TableElement.ColumnDefElement:type_name -> sqlmeta.ColumnDef
-	41, // 70: sqlmeta.TableElement.TableConstraintElement:type_name -> sqlmeta.TableConstraint
-	71, // [71:71] is the sub-list for method output_type
-	71, // [71:71] is the sub-list for method input_type
-	71, // [71:71] is the sub-list for extension type_name
-	71, // [71:71] is the sub-list for extension extendee
-	0,  // [0:71] is the sub-list for field type_name
+	1,  // 0: sqlmeta.NetworkType.Kind:type_name -> sqlmeta.NetworkKind
+	36, // 1: sqlmeta.CollateType.Type:type_name -> sqlmeta.DataType
+	39, // 2: sqlmeta.StructData.Fields:type_name -> sqlmeta.ColumnDef
+	36, // 3: sqlmeta.ArrayData.Type:type_name -> sqlmeta.DataType
+	7,  // 4: sqlmeta.EnumType.TypeName:type_name -> sqlmeta.ObjectName
+	7,  // 5: sqlmeta.ReferencesColumnSpec.TableName:type_name -> sqlmeta.ObjectName
+	2,  // 6: sqlmeta.ReferencesColumnSpec.OnDelete:type_name -> sqlmeta.ReferentialAction
+	2,  // 7: sqlmeta.ReferencesColumnSpec.OnUpdate:type_name -> sqlmeta.ReferentialAction
+	3,  // 8: sqlmeta.ReferencesColumnSpec.Match:type_name -> sqlmeta.MatchOption
+	56, // 9: sqlmeta.ExcludeConstraintElement.Expr:type_name -> google.protobuf.Any
+	33, // 10: sqlmeta.ExcludeTableConstraint.Elements:type_name -> sqlmeta.ExcludeConstraintElement
+	56, // 11: sqlmeta.ExcludeTableConstraint.Where:type_name -> google.protobuf.Any
+	30, // 12: sqlmeta.ReferentialTableConstraint.KeyExpr:type_name -> sqlmeta.ReferenceKeyExpr
+	2,  // 13: sqlmeta.ReferentialTableConstraint.OnDelete:type_name -> sqlmeta.ReferentialAction
+	2,  // 14: sqlmeta.ReferentialTableConstraint.OnUpdate:type_name -> sqlmeta.ReferentialAction
+	3,  // 15: sqlmeta.ReferentialTableConstraint.Match:type_name -> sqlmeta.MatchOption
+	10, // 16: sqlmeta.DataType.IntData:type_name -> sqlmeta.Int
+	9,  // 17: sqlmeta.DataType.SmallIntData:type_name -> sqlmeta.SmallInt
+	8,  // 18: sqlmeta.DataType.BigIntData:type_name -> sqlmeta.BigInt
+	15, // 19: sqlmeta.DataType.DecimalData:type_name -> sqlmeta.Decimal
+	16, // 20: sqlmeta.DataType.CharData:type_name -> sqlmeta.CharType
+	17, // 21: sqlmeta.DataType.VarcharData:type_name -> sqlmeta.VarcharType
+	7,  // 22: sqlmeta.DataType.CustomData:type_name -> sqlmeta.ObjectName
+	26, // 23: sqlmeta.DataType.ArrayData:type_name -> sqlmeta.ArrayData
+	25, // 24: sqlmeta.DataType.StructData:type_name -> sqlmeta.StructData
+	0,  // 25: sqlmeta.DataType.UUIDData:type_name -> sqlmeta.DataTypeSingle
+	18, // 26: sqlmeta.DataType.TimestampData:type_name -> sqlmeta.Timestamp
+	0,  // 27: sqlmeta.DataType.BooleanData:type_name -> sqlmeta.DataTypeSingle
+	0,  // 28: sqlmeta.DataType.DateData:type_name -> sqlmeta.DataTypeSingle
+	0,  // 29: sqlmeta.DataType.TimeData:type_name -> sqlmeta.DataTypeSingle
+	23, // 30: sqlmeta.DataType.DoubleData:type_name -> sqlmeta.DoubleType
+	14, // 31: sqlmeta.DataType.FloatData:type_name -> sqlmeta.Float
+	13, // 32: sqlmeta.DataType.RealData:type_name -> sqlmeta.Real
+	0,  // 33: sqlmeta.DataType.TextData:type_name -> sqlmeta.DataTypeSingle
+	21, // 34: sqlmeta.DataType.BitData:type_name -> sqlmeta.BitType
+	0,  // 35: sqlmeta.DataType.RegclassData:type_name -> sqlmeta.DataTypeSingle
+	0,  // 36: sqlmeta.DataType.ByteaData:type_name -> sqlmeta.DataTypeSingle
+	24, // 37: sqlmeta.DataType.CollateData:type_name -> sqlmeta.CollateType
+	27, // 38: sqlmeta.DataType.EnumData:type_name -> sqlmeta.EnumType
+	28, // 39: sqlmeta.DataType.SetData:type_name -> sqlmeta.SetType
+	11, // 40: sqlmeta.DataType.TinyIntData:type_name -> sqlmeta.TinyInt
+	12, // 41: sqlmeta.DataType.MediumIntData:type_name -> sqlmeta.MediumInt
+	0,  // 42: sqlmeta.DataType.YearData:type_name -> sqlmeta.DataTypeSingle
+	0,  // 43: sqlmeta.DataType.JSONData:type_name -> sqlmeta.DataTypeSingle
+	0,  // 44: sqlmeta.DataType.XMLData:type_name -> sqlmeta.DataTypeSingle
+	19, // 45: sqlmeta.DataType.TimeTypeData:type_name -> sqlmeta.TimeType
+	20, // 46: sqlmeta.DataType.IntervalData:type_name -> sqlmeta.IntervalType
+	22, // 47: sqlmeta.DataType.NetworkData:type_name -> sqlmeta.NetworkType
+	29, // 48: sqlmeta.ColumnConstraintSpec.UniqueItem:type_name -> sqlmeta.UniqueColumnSpec
+	56, // 49: sqlmeta.ColumnConstraintSpec.CheckItem:type_name -> google.protobuf.Any
+	31, // 50: sqlmeta.ColumnConstraintSpec.ReferenceItem:type_name -> sqlmeta.ReferencesColumnSpec
+	6,  // 51: sqlmeta.ColumnConstraintSpec.NotNullItem:type_name -> sqlmeta.NotNullColumnSpec
+	37, // 52: sqlmeta.ColumnConstraint.Spec:type_name -> sqlmeta.ColumnConstraintSpec
+	36, // 53: sqlmeta.ColumnDef.DataType:type_name -> sqlmeta.DataType
+	56, // 54: sqlmeta.ColumnDef.Default:type_name -> google.protobuf.Any
+	5,  // 55: sqlmeta.ColumnDef.MyDecos:type_name -> sqlmeta.AutoIncrement
+	38, // 56: sqlmeta.ColumnDef.Constraints:type_name -> sqlmeta.ColumnConstraint
+	50, // 57: sqlmeta.ColumnDef.Options:type_name -> sqlmeta.ColumnDef.OptionsEntry
+	7,  // 58: sqlmeta.MetaTable.Name:type_name -> sqlmeta.ObjectName
+	49, // 59: sqlmeta.MetaTable.Elements:type_name -> sqlmeta.TableElement
+	51, // 60: sqlmeta.MetaTable.Options:type_name -> sqlmeta.MetaTable.OptionsEntry
+	41, // 61: sqlmeta.MetaTable.Indexes:type_name -> sqlmeta.MetaIndex
+	52, // 62: sqlmeta.MetaIndex.Options:type_name -> sqlmeta.MetaIndex.OptionsEntry
+	7,  // 63: sqlmeta.MetaView.Name:type_name -> sqlmeta.ObjectName
+	53, // 64: sqlmeta.MetaView.Options:type_name -> sqlmeta.MetaView.OptionsEntry
+	7,  // 65: sqlmeta.MetaTrigger.TableName:type_name -> sqlmeta.ObjectName
+	7,  // 66: sqlmeta.MetaSequence.Name:type_name -> sqlmeta.ObjectName
+	54, // 67: sqlmeta.MetaSequence.Options:type_name -> sqlmeta.MetaSequence.OptionsEntry
+	40, // 68: sqlmeta.MetaDatabase.Tables:type_name -> sqlmeta.MetaTable
+	42, // 69: sqlmeta.MetaDatabase.Views:type_name -> sqlmeta.MetaView
+	44, // 70: sqlmeta.MetaDatabase.Sequences:type_name -> sqlmeta.MetaSequence
+	55, // 71: sqlmeta.MetaDatabase.Options:type_name -> sqlmeta.MetaDatabase.OptionsEntry
+	43, // 72: sqlmeta.MetaDatabase.Triggers:type_name -> sqlmeta.MetaTrigger
+	57, // 73: sqlmeta.MetaSnapshot.TakenAt:type_name -> google.protobuf.Timestamp
+	45, // 74: sqlmeta.MetaSnapshot.Database:type_name -> sqlmeta.MetaDatabase
+	35, // 75: sqlmeta.TableConstraintSpec.ReferenceItem:type_name -> sqlmeta.ReferentialTableConstraint
+	56, // 76: sqlmeta.TableConstraintSpec.CheckItem:type_name -> google.protobuf.Any
+	32, // 77: sqlmeta.TableConstraintSpec.UniqueItem:type_name -> sqlmeta.UniqueTableConstraint
+	34, // 78: sqlmeta.TableConstraintSpec.ExcludeItem:type_name -> sqlmeta.ExcludeTableConstraint
+	47, // 79: sqlmeta.TableConstraint.Spec:type_name -> sqlmeta.TableConstraintSpec
+	39, // 80: sqlmeta.TableElement.ColumnDefElement:type_name -> sqlmeta.ColumnDef
+	48, // 81: sqlmeta.TableElement.TableConstraintElement:type_name -> sqlmeta.TableConstraint
+	82, // [82:82] is the sub-list for method output_type
+	82, // [82:82] is the sub-list for method input_type
+	82, // [82:82] is the sub-list for extension type_name
+	82, // [82:82] is the sub-list for extension extendee
+	0,  // [0:82] is the sub-list for field type_name
 }
 
 func init() { file_types_proto_init() }
@@ -3442,7 +4060,10 @@ func file_types_proto_init() {
 	if File_types_proto != nil {
 		return
 	}
-	file_types_proto_msgTypes[26].OneofWrappers = []any{
+	file_types_proto_msgTypes[11].OneofWrappers = []any{}
+	file_types_proto_msgTypes[12].OneofWrappers = []any{}
+	file_types_proto_msgTypes[13].OneofWrappers = []any{}
+	file_types_proto_msgTypes[29].OneofWrappers = []any{
 		(*DataType_IntData)(nil),
 		(*DataType_SmallIntData)(nil),
 		(*DataType_BigIntData)(nil),
@@ -3472,20 +4093,23 @@ func file_types_proto_init() {
 		(*DataType_YearData)(nil),
 		(*DataType_JSONData)(nil),
 		(*DataType_XMLData)(nil),
+		(*DataType_TimeTypeData)(nil),
+		(*DataType_IntervalData)(nil),
+		(*DataType_NetworkData)(nil),
 	}
-	file_types_proto_msgTypes[27].OneofWrappers = []any{
+	file_types_proto_msgTypes[30].OneofWrappers = []any{
 		(*ColumnConstraintSpec_UniqueItem)(nil),
 		(*ColumnConstraintSpec_CheckItem)(nil),
 		(*ColumnConstraintSpec_ReferenceItem)(nil),
 		(*ColumnConstraintSpec_NotNullItem)(nil),
 	}
-	file_types_proto_msgTypes[34].OneofWrappers = []any{
+	file_types_proto_msgTypes[40].OneofWrappers = []any{
 		(*TableConstraintSpec_ReferenceItem)(nil),
 		(*TableConstraintSpec_CheckItem)(nil),
 		(*TableConstraintSpec_UniqueItem)(nil),
 		(*TableConstraintSpec_ExcludeItem)(nil),
 	}
-	file_types_proto_msgTypes[36].OneofWrappers = []any{
+	file_types_proto_msgTypes[42].OneofWrappers = []any{
 		(*TableElement_ColumnDefElement)(nil),
 		(*TableElement_TableConstraintElement)(nil),
 	}
@@ -3494,8 +4118,8 @@ func file_types_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_types_proto_rawDesc), len(file_types_proto_rawDesc)),
-			NumEnums:      6,
-			NumMessages:   42,
+			NumEnums:      7,
+			NumMessages:   49,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,228 @@
+package xmeta
+
+// validate.go checks structural invariants of the unified model.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ValidationError describes a structural problem found in a MetaTable.
+type ValidationError struct {
+	// Path locates the offending element, e.g. "users.columns[2].default".
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidateMetaTable checks the structural invariants of a MetaTable:
+//   - every TableElement has its oneof set
+//   - column names are non-empty and unique
+//   - Default values unmarshal cleanly
+//   - referential constraints name a table and have matching column counts
+//   - primary key and unique columns exist in the table
+//
+// It returns nil if the table is valid.
+func ValidateMetaTable(t *MetaTable) []ValidationError {
+	if t == nil {
+		return []ValidationError{{Path: "table", Message: "table is nil"}}
+	}
+
+	var errs []ValidationError
+	add := func(path, format string, args ...any) {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	root := objectNameKey(t.Name)
+	if root == "" {
+		root = "table"
+		add("table.name", "table name is empty")
+	}
+
+	// Columns first, so constraints can be checked against them
+	columns := make(map[string]bool)
+	colIdx, conIdx := 0, 0
+	for _, elem := range t.Elements {
+		col := elem.GetColumnDefElement()
+		if col == nil {
+			continue
+		}
+		path := fmt.Sprintf("%s.columns[%d]", root, colIdx)
+		colIdx++
+
+		if col.Name == "" {
+			add(path+".name", "column name is empty")
+		} else if columns[col.Name] {
+			add(path+".name", "duplicate column %q", col.Name)
+		}
+		columns[col.Name] = true
+
+		if col.Default != nil {
+			if _, err := col.Default.UnmarshalNew(); err != nil {
+				add(path+".default", "default does not unmarshal: %v", err)
+			}
+		}
+
+		for j, con := range col.Constraints {
+			if ref := con.GetSpec().GetReferenceItem(); ref != nil {
+				conPath := fmt.Sprintf("%s.constraints[%d]", path, j)
+				if len(ref.GetTableName().GetIdents()) == 0 {
+					add(conPath+".table_name", "referenced table name is empty")
+				}
+				if len(ref.Columns) > 1 {
+					add(conPath+".columns", "column reference has %d foreign columns, expected 1", len(ref.Columns))
+				}
+			}
+		}
+	}
+
+	for i, elem := range t.Elements {
+		if elem.GetColumnDefElement() != nil {
+			continue
+		}
+		tc := elem.GetTableConstraintElement()
+		if tc == nil {
+			add(fmt.Sprintf("%s.elements[%d]", root, i), "element has neither a column nor a constraint")
+			continue
+		}
+		path := fmt.Sprintf("%s.constraints[%d]", root, conIdx)
+		conIdx++
+
+		if tc.GetSpec().GetTableConstraintSpecClause() == nil {
+			add(path+".spec", "constraint %q has no specification", tc.Name)
+			continue
+		}
+
+		if ref := tc.Spec.GetReferenceItem(); ref != nil {
+			if ref.GetKeyExpr().GetTableName() == "" {
+				add(path+".key_expr.table_name", "referenced table name is empty")
+			}
+			if len(ref.Columns) == 0 {
+				add(path+".columns", "foreign key has no local columns")
+			}
+			if foreign := ref.GetKeyExpr().GetColumns(); len(foreign) > 0 && len(foreign) != len(ref.Columns) {
+				add(path+".key_expr.columns", "foreign key has %d local columns but %d foreign columns", len(ref.Columns), len(foreign))
+			}
+			for j, name := range ref.Columns {
+				if !columns[name] {
+					add(fmt.Sprintf("%s.columns[%d]", path, j), "column %q does not exist", name)
+				}
+			}
+		}
+
+		if u := tc.Spec.GetUniqueItem(); u != nil {
+			if len(u.Columns) == 0 {
+				add(path+".columns", "key has no columns")
+			}
+			for j, name := range u.Columns {
+				if !columns[name] {
+					add(fmt.Sprintf("%s.columns[%d]", path, j), "column %q does not exist", name)
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateReferentialIntegrity checks the foreign keys of every table in db,
+// declared as table constraints or inline on a column:
+//   - the referenced table exists in db, matching a bare name against a
+//     qualified one
+//   - the referenced columns exist in that table
+//   - they are its primary key or a unique key or index, in any order; a
+//     foreign key without referenced columns needs a primary key
+//
+// Paths are as in ValidateMetaTable. It returns nil if every reference
+// resolves.
+func ValidateReferentialIntegrity(db *MetaDatabase) []ValidationError {
+	var errs []ValidationError
+	lookup := tableLookup(db.GetTables())
+	check := func(path, name, tableName string, foreign []string) {
+		add := func(format string, args ...any) {
+			errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+		}
+		if tableName == "" {
+			return // reported by ValidateMetaTable
+		}
+		target := lookup(tableName)
+		if target == nil {
+			add("foreign key %q references table %q, which does not exist", name, tableName)
+			return
+		}
+		if len(foreign) == 0 {
+			if len(tablePrimaryKey(target)) == 0 {
+				add("foreign key %q references the primary key of %s, which has none", name, tableName)
+			}
+			return
+		}
+		columns := columnsFromElements(target.Elements)
+		missing := false
+		for _, col := range foreign {
+			if columns[col] == nil {
+				add("foreign key %q references column %q, which does not exist in %s", name, col, tableName)
+				missing = true
+			}
+		}
+		if !missing && !referencesKey(target, foreign) {
+			add("foreign key %q references (%s) of %s, which is not a primary or unique key", name, strings.Join(foreign, ", "), tableName)
+		}
+	}
+
+	for _, t := range db.GetTables() {
+		root := objectNameKey(t.Name)
+		if root == "" {
+			root = "table"
+		}
+		colIdx, conIdx := 0, 0
+		for _, elem := range t.Elements {
+			if col := elem.GetColumnDefElement(); col != nil {
+				for j, con := range col.Constraints {
+					if ref := con.GetSpec().GetReferenceItem(); ref != nil {
+						check(fmt.Sprintf("%s.columns[%d].constraints[%d]", root, colIdx, j), con.Name,
+							objectNameKey(ref.TableName), ref.Columns)
+					}
+				}
+				colIdx++
+				continue
+			}
+			tc := elem.GetTableConstraintElement()
+			if tc == nil {
+				continue
+			}
+			if ref := tc.GetSpec().GetReferenceItem(); ref != nil {
+				check(fmt.Sprintf("%s.constraints[%d]", root, conIdx), tc.Name,
+					ref.GetKeyExpr().GetTableName(), ref.GetKeyExpr().GetColumns())
+			}
+			conIdx++
+		}
+	}
+	return errs
+}
+
+// referencesKey reports whether columns, in any order, are the primary key
+// of t or one of its unique keys or unique indexes.
+func referencesKey(t *MetaTable, columns []string) bool {
+	sorted := slices.Sorted(slices.Values(columns))
+	sameColumns := func(key []string) bool {
+		return slices.Equal(slices.Sorted(slices.Values(key)), sorted)
+	}
+	if isUniqueKey(t, columns) || sameColumns(tablePrimaryKey(t)) {
+		return true
+	}
+	for _, elem := range t.Elements {
+		if u := elem.GetTableConstraintElement().GetSpec().GetUniqueItem(); u != nil && sameColumns(u.Columns) {
+			return true
+		}
+	}
+	for _, idx := range t.Indexes {
+		if idx.IsUnique && idx.Expression == "" && idx.Predicate == "" && sameColumns(idx.Columns) {
+			return true
+		}
+	}
+	return false
+}
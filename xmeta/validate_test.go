@@ -0,0 +1,120 @@
+package xmeta
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestValidateMetaTable(t *testing.T) {
+	valid := PGTableToMetaTable(&PGTable{
+		Name: &ObjectName{Idents: []string{"public", "orders"}},
+		Columns: []*PGColumn{
+			{Name: "id", DefaultValue: "nextval('seq')"},
+			{Name: "user_id"},
+		},
+		Constraints: []*PGConstraint{
+			{Name: "orders_pkey", Type: "p", Columns: []string{"id"}},
+		},
+		ForeignKeys: []*PGForeignKey{
+			{
+				Name:           "fk_user",
+				LocalColumns:   []string{"user_id"},
+				ForeignTable:   &ObjectName{Idents: []string{"public", "users"}},
+				ForeignColumns: []string{"id"},
+			},
+		},
+	})
+	if errs := ValidateMetaTable(valid); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	invalid := &MetaTable{
+		Name: &ObjectName{Idents: []string{"users"}},
+		Elements: []*TableElement{
+			{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: "id"}}},
+			{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{Name: "id"}}},
+			{TableElementClause: &TableElement_ColumnDefElement{ColumnDefElement: &ColumnDef{
+				Name:    "email",
+				Default: &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Type"},
+			}}},
+			{},
+			{TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+				Name: "pk",
+				Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_UniqueItem{
+					UniqueItem: &UniqueTableConstraint{IsPrimary: true, Columns: []string{"missing"}},
+				}},
+			}}},
+			{TableElementClause: &TableElement_TableConstraintElement{TableConstraintElement: &TableConstraint{
+				Name: "fk",
+				Spec: &TableConstraintSpec{TableConstraintSpecClause: &TableConstraintSpec_ReferenceItem{
+					ReferenceItem: &ReferentialTableConstraint{
+						Columns: []string{"id"},
+						KeyExpr: &ReferenceKeyExpr{Columns: []string{"a", "b"}},
+					},
+				}},
+			}}},
+		},
+	}
+
+	expected := map[string]bool{
+		"users.columns[1].name":                    false,
+		"users.columns[2].default":                 false,
+		"users.elements[3]":                        false,
+		"users.constraints[0].columns[0]":          false,
+		"users.constraints[1].key_expr.table_name": false,
+		"users.constraints[1].key_expr.columns":    false,
+	}
+	errs := ValidateMetaTable(invalid)
+	for _, e := range errs {
+		if _, ok := expected[e.Path]; !ok {
+			t.Errorf("Unexpected error: %v", e)
+		}
+		expected[e.Path] = true
+	}
+	for path, found := range expected {
+		if !found {
+			t.Errorf("Expected error at %s", path)
+		}
+	}
+}
+
+func TestValidateReferentialIntegrity(t *testing.T) {
+	db, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, name TEXT, UNIQUE (email));
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id), email TEXT,
+  CONSTRAINT fk_email FOREIGN KEY (email) REFERENCES users (email));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	if errs := ValidateReferentialIntegrity(db); len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	broken, err := LoadMetaDatabaseFromSQL(`
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER, item_id INTEGER, user_name TEXT, note_id INTEGER REFERENCES notes,
+  CONSTRAINT fk_item FOREIGN KEY (item_id) REFERENCES items (id),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (uid),
+  CONSTRAINT fk_name FOREIGN KEY (user_name) REFERENCES users (name));`, DialectPostgres)
+	if err != nil {
+		t.Fatalf("LoadMetaDatabaseFromSQL failed: %v", err)
+	}
+	want := map[string]string{
+		`foreign key "fk_item" references table "items", which does not exist`:                   "orders.constraints[",
+		`foreign key "fk_user" references column "uid", which does not exist in users`:           "orders.constraints[",
+		`foreign key "fk_name" references (name) of users, which is not a primary or unique key`: "orders.constraints[",
+		`foreign key "" references table "notes", which does not exist`:                          "orders.columns[4].constraints[0]",
+	}
+	errs := ValidateReferentialIntegrity(broken)
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), errs)
+	}
+	for _, e := range errs {
+		prefix, ok := want[e.Message]
+		if !ok || !strings.HasPrefix(e.Path, prefix) {
+			t.Errorf("Unexpected error %v", e)
+		}
+	}
+}